@@ -1,17 +1,22 @@
 package main
 
 import (
+	"context"
 	"time"
 
 	"github.com/robfig/cron/v3"
 	"github.com/tommjj/ql-kho-lua/internal/adapters/auth"
 	"github.com/tommjj/ql-kho-lua/internal/adapters/http"
 	"github.com/tommjj/ql-kho-lua/internal/adapters/http/handlers"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/notify"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/pdf"
 	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/files"
 	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
 	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb/repository"
 	"github.com/tommjj/ql-kho-lua/internal/config"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
 	"github.com/tommjj/ql-kho-lua/internal/core/mapmutex"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
 	"github.com/tommjj/ql-kho-lua/internal/core/services"
 	"github.com/tommjj/ql-kho-lua/internal/core/utils"
 	"github.com/tommjj/ql-kho-lua/internal/logger"
@@ -63,6 +68,21 @@ func main() {
 	}
 	defer fileStorage.CleanupTempFiles()
 
+	// a broken invoice template stops the start instead of failing every print
+	invoiceRenderer, err := pdf.NewInvoiceRenderer(*conf.Invoice)
+	if err != nil {
+		zap.L().Fatal(err.Error())
+	}
+
+	// an upgraded database has invoices but an empty ledger, every warehouse would read as empty until it is built
+	backfilled, err := services.NewStockLedgerService(repository.NewStockLedgerRepository(db)).BackfillStockBalances(context.Background())
+	if err != nil {
+		zap.L().Fatal("backfill stock balances", zap.Error(err))
+	}
+	if len(backfilled) > 0 {
+		zap.L().Info("backfill stock balances", zap.Int("balances", len(backfilled)))
+	}
+
 	// |> Start CRON
 	zap.L().Info("Start CRON")
 
@@ -79,7 +99,7 @@ func main() {
 	// |> Start Repository
 	zap.L().Info("Start create repository")
 
-	keyRepository := repository.NewKeyRepository(db)
+	sessionRepository := repository.NewSessionRepository(db)
 	userRepository := repository.NewUserRepository(db)
 	storehouseRepository := repository.NewWarehouseRepository(db)
 	accessControlRepository := repository.NewAccessControlRepository(db)
@@ -87,20 +107,123 @@ func main() {
 	customerRepository := repository.NewCustomerRepository(db)
 	imInvoiceRepository := repository.NewImInvoicesRepository(db)
 	exInvoiceRepository := repository.NewExInvoicesRepository(db)
+	transferRepository := repository.NewTransferRepository(db)
+	auditRepository := repository.NewAuditRepository(db)
+	reportRepository := repository.NewReportRepository(db)
+	alertRepository := repository.NewAlertRepository(db)
+	notificationRepository := repository.NewNotificationRepository(db)
+	webhookRepository := repository.NewWebhookRepository(db)
+	orderRepository := repository.NewOrderRepository(db)
+	paymentRepository := repository.NewPaymentRepository(db)
+	bulkImportRepository := repository.NewBulkImportRepository(db)
+	weighbridgeRepository := repository.NewWeighbridgeRepository(db)
+	idempotencyRepository := repository.NewIdempotencyRepository(db)
 
 	// |> Start Service
 	zap.L().Info("Start create service")
 
 	uploadService := services.NewUploadService(fileStorage)
-	tokenService := auth.NewJWTTokenService(*conf.Auth, keyRepository)
-	authService := services.NewAuthService(userRepository, tokenService)
-	userService := services.NewUserService(userRepository)
-	accessControlService := services.NewAccessControlService(accessControlRepository)
-	storehouseService := services.NewWarehouseService(storehouseRepository, fileStorage)
-	riceService := services.NewRiceService(riceRepository)
-	customerService := services.NewCustomerService(customerRepository)
-	imInvoiceService := services.NewImInvoicesService(imInvoiceRepository, storehouseRepository, &mapmutex.Mapmutex{})
-	exInvoiceService := services.NewExInvoicesService(exInvoiceRepository, storehouseRepository, &mapmutex.Mapmutex{})
+	tokenService := auth.NewJWTTokenService(*conf.Auth, sessionRepository)
+	authService := services.NewAuthService(userRepository, sessionRepository, tokenService)
+	auditService := services.NewAuditService(auditRepository)
+	notifiers := []ports.INotifier{
+		notify.NewInAppNotifier(notificationRepository),
+		notify.NewWebhookNotifier(conf.Notify.WebhookTimeout),
+	}
+	if conf.Notify.SMTP != nil {
+		notifiers = append(notifiers, notify.NewEmailNotifier(*conf.Notify.SMTP))
+	}
+	notificationService := services.NewNotificationService(notificationRepository, accessControlRepository,
+		conf.Notify.RetryDelay, conf.Notify.RetryTimes, notifiers...)
+	webhookService := services.NewWebhookService(webhookRepository, notify.NewWebhookSender(conf.Webhook.Timeout),
+		conf.Webhook.MaxAttempts, conf.Webhook.Backoff, conf.Webhook.MaxBackoff)
+	userService := services.NewAuditedUserService(services.NewUserService(userRepository), auditService)
+	accessControlService := services.NewAuditedAccessControlService(services.NewAccessControlService(accessControlRepository), auditService)
+	storehouseService := services.NewWebhookWarehouseService(services.NewAuditedWarehouseService(
+		services.NewWarehouseService(storehouseRepository, fileStorage), auditService), webhookService)
+	riceService := services.NewWebhookRiceService(services.NewAuditedRiceService(
+		services.NewRiceService(riceRepository), auditService), webhookService)
+	customerService := services.NewWebhookCustomerService(services.NewAuditedCustomerService(
+		services.NewCustomerService(customerRepository), auditService), webhookService)
+	bulkImportService := services.NewWebhookBulkImportService(services.NewAuditedBulkImportService(
+		services.NewBulkImportService(bulkImportRepository, fileStorage), auditService), webhookService)
+	// import, export and orders share one lock per warehouse so stock checks never race each other
+	warehouseLock := &mapmutex.Mapmutex{}
+	importRule := domain.ApprovalRule{Quantity: conf.Approval.ImportQuantity, TotalPrice: conf.Approval.ImportTotalPrice}
+	exportRule := domain.ApprovalRule{Quantity: conf.Approval.ExportQuantity, TotalPrice: conf.Approval.ExportTotalPrice}
+	imInvoiceService := services.NewNotifiedImInvoiceService(services.NewWebhookImInvoiceService(services.NewAuditedImInvoiceService(
+		services.NewImInvoicesService(imInvoiceRepository, storehouseRepository, customerRepository, warehouseLock, importRule), auditService), webhookService),
+		notificationService)
+	exInvoiceService := services.NewNotifiedExInvoiceService(services.NewWebhookExInvoiceService(services.NewAuditedExInvoiceService(
+		services.NewExInvoicesService(exInvoiceRepository, storehouseRepository, customerRepository, warehouseLock, exportRule), auditService), webhookService),
+		notificationService, conf.Notify.LargeExportQuantity)
+	weighbridgeService := services.NewWeighbridgeService(weighbridgeRepository, storehouseRepository, riceRepository, customerRepository,
+		imInvoiceService, exInvoiceService)
+	transferService := services.NewWebhookTransferService(services.NewAuditedTransferService(
+		services.NewTransferService(transferRepository, storehouseRepository, reportRepository, warehouseLock), auditService), webhookService)
+	orderService := services.NewNotifiedOrderService(services.NewWebhookOrderService(services.NewAuditedOrderService(
+		services.NewOrderService(orderRepository, storehouseRepository, customerRepository, warehouseLock, importRule, exportRule), auditService), webhookService),
+		notificationService, conf.Notify.LargeExportQuantity)
+	reportService := services.NewReportService(reportRepository)
+	paymentService := services.NewAuditedPaymentService(services.NewPaymentService(paymentRepository), auditService)
+	idempotencyService := services.NewIdempotencyService(idempotencyRepository, conf.Idempotency.Window)
+	alertService := services.NewNotifiedAlertService(
+		services.NewAlertService(alertRepository, conf.Alert.CapacityRatio, conf.Alert.ExpiryWindow), notificationService)
+
+	_, err = c.AddFunc(conf.Alert.Schedule, func() {
+		alerts, err := alertService.CheckAlerts(context.Background())
+		if err != nil {
+			zap.L().Error("check alerts", zap.Error(err))
+			return
+		}
+		zap.L().Info("check alerts", zap.Int("raised", len(alerts)))
+	})
+	if err != nil {
+		zap.L().Fatal(err.Error())
+	}
+
+	// a slow run is not overlapped by the next one, so a message is never sent twice at once
+	_, err = c.AddJob(conf.Webhook.Schedule, cron.NewChain(cron.SkipIfStillRunning(cron.DefaultLogger)).Then(cron.FuncJob(func() {
+		delivered, err := webhookService.DeliverDue(context.Background())
+		if err != nil {
+			zap.L().Error("deliver webhooks", zap.Error(err))
+			return
+		}
+		if delivered > 0 {
+			zap.L().Info("deliver webhooks", zap.Int("delivered", delivered))
+		}
+	})))
+	if err != nil {
+		zap.L().Fatal(err.Error())
+	}
+
+	_, err = c.AddFunc(conf.Idempotency.Schedule, func() {
+		deleted, err := idempotencyService.DeleteExpired(context.Background())
+		if err != nil {
+			zap.L().Error("delete expired idempotency keys", zap.Error(err))
+			return
+		}
+		if deleted > 0 {
+			zap.L().Info("delete expired idempotency keys", zap.Int64("deleted", deleted))
+		}
+	})
+	if err != nil {
+		zap.L().Fatal(err.Error())
+	}
+
+	_, err = c.AddFunc(conf.Auth.SessionSchedule, func() {
+		deleted, err := authService.DeleteExpiredSessions(context.Background())
+		if err != nil {
+			zap.L().Error("delete expired sessions", zap.Error(err))
+			return
+		}
+		if deleted > 0 {
+			zap.L().Info("delete expired sessions", zap.Int64("deleted", deleted))
+		}
+	})
+	if err != nil {
+		zap.L().Fatal(err.Error())
+	}
 
 	// auto create a root user
 	err = utils.AutoCreateRootUser(userService, conf.DefaultRootUser)
@@ -117,8 +240,19 @@ func main() {
 	storeHouseHandler := handlers.NewWarehouseHandler(storehouseService, accessControlService)
 	riceHandler := handlers.NewRiceHandler(riceService)
 	customerHandler := handlers.NewCustomerHandler(customerService)
-	imInvoiceHandler := handlers.NewImportInvoiceHandler(imInvoiceService, accessControlService)
-	exInvoiceHandler := handlers.NewExportInvoiceHandler(exInvoiceService, accessControlService)
+	bulkImportHandler := handlers.NewBulkImportHandler(bulkImportService)
+	imInvoiceHandler := handlers.NewImportInvoiceHandler(imInvoiceService, accessControlService, invoiceRenderer)
+	exInvoiceHandler := handlers.NewExportInvoiceHandler(exInvoiceService, accessControlService, invoiceRenderer)
+	weighbridgeHandler := handlers.NewWeighbridgeHandler(weighbridgeService, accessControlService, *conf.Weighbridge)
+	accessControlHandler := handlers.NewAccessControlHandler(accessControlService, userService, storehouseService)
+	transferHandler := handlers.NewTransferHandler(transferService, accessControlService)
+	orderHandler := handlers.NewOrderHandler(orderService, accessControlService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	reportHandler := handlers.NewReportHandler(reportService, accessControlService)
+	paymentHandler := handlers.NewPaymentHandler(paymentService, accessControlService)
+	alertHandler := handlers.NewAlertHandler(alertService, accessControlService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
 
 	// |> Start HTTP Server
 	zap.L().Info("Start create http server")
@@ -128,13 +262,24 @@ func main() {
 		http.RegisterStatic("./public"),
 		http.Group("/v1/api",
 			http.RegisterUploadRoute(uploadHandler),
-			http.RegisterAuthRoute(authHandler),
+			http.RegisterAuthRoute(tokenService, authHandler),
 			http.RegisterUsersRoute(tokenService, userHandler),
 			http.RegisterWarehouseRoute(tokenService, storeHouseHandler),
 			http.RegisterRiceRoute(tokenService, riceHandler),
 			http.RegisterCustomerRoute(tokenService, customerHandler),
-			http.RegisterImportInvoiceRoute(tokenService, imInvoiceHandler),
-			http.RegisterExportInvoiceRoute(tokenService, exInvoiceHandler),
+			http.RegisterBulkImportRoute(tokenService, bulkImportHandler),
+			http.RegisterImportInvoiceRoute(tokenService, idempotencyService, imInvoiceHandler),
+			http.RegisterExportInvoiceRoute(tokenService, idempotencyService, exInvoiceHandler),
+			http.RegisterWeighbridgeRoute(tokenService, weighbridgeHandler),
+			http.RegisterAccessControlRoute(tokenService, accessControlHandler),
+			http.RegisterTransferRoute(tokenService, transferHandler),
+			http.RegisterOrderRoute(tokenService, orderHandler),
+			http.RegisterAuditRoute(tokenService, auditHandler),
+			http.RegisterReportRoute(tokenService, reportHandler),
+			http.RegisterPaymentRoute(tokenService, paymentHandler),
+			http.RegisterAlertRoute(tokenService, alertHandler),
+			http.RegisterNotificationRoute(tokenService, notificationHandler),
+			http.RegisterWebhookRoute(tokenService, webhookHandler),
 		),
 	)
 	if err != nil {
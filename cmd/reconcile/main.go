@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb/repository"
+	"github.com/tommjj/ql-kho-lua/internal/config"
+	"github.com/tommjj/ql-kho-lua/internal/core/services"
+	"github.com/tommjj/ql-kho-lua/internal/logger"
+	"go.uber.org/zap"
+)
+
+// reconcile compare the stock_balances ledger with the import/export invoice history.
+//
+// By default it only reports the drift and exits with status 1 if any drift is found,
+// run it with -apply to rebuild the ledger from the invoice history.
+func main() {
+	apply := flag.Bool("apply", false, "rebuild stock balances from the invoice history")
+	flag.Parse()
+
+	conf, err := config.New()
+	if err != nil {
+		zap.L().Fatal(err.Error())
+	}
+
+	err = logger.Set(*conf.Logger)
+	if err != nil {
+		zap.L().Fatal(err.Error())
+	}
+
+	db, err := mysqldb.NewMysqlDB(*conf.DB)
+	if err != nil {
+		zap.L().Fatal(err.Error())
+	}
+
+	ledgerService := services.NewStockLedgerService(repository.NewStockLedgerRepository(db))
+
+	drifts, err := ledgerService.ReconcileStockBalances(context.Background(), *apply)
+	if err != nil {
+		zap.L().Fatal(err.Error())
+	}
+
+	if len(drifts) == 0 {
+		fmt.Println("stock balances are in sync with the invoice history")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "WAREHOUSE\tRICE\tLEDGER\tEXPECTED\tDRIFT")
+	for _, v := range drifts {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n",
+			v.WarehouseID, v.RiceID, v.LedgerQuantity, v.ExpectedQuantity, v.LedgerQuantity-v.ExpectedQuantity)
+	}
+	w.Flush()
+
+	if *apply {
+		fmt.Printf("rebuilt %v stock balances\n", len(drifts))
+		return
+	}
+	os.Exit(1)
+}
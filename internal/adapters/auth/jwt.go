@@ -14,23 +14,34 @@ import (
 
 var jwtMethod *jwt.SigningMethodHMAC = jwt.SigningMethodHS256
 
+// refreshTokenType is the type claim of refresh tokens, access tokens have no type
+const refreshTokenType = "refresh"
+
 type CustomClaims struct {
 	ID    int         `json:"id"`
 	Name  string      `json:"name"`
 	Email string      `json:"email"`
 	Role  domain.Role `json:"role"`
 	Key   string      `json:"key"`
+	Type  string      `json:"type,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type JWTService struct {
-	key      []byte
-	keyFunc  func(token *jwt.Token) (interface{}, error)
-	duration time.Duration
-	keyRepo  ports.IKeyRepository
+	key             []byte
+	keyFunc         func(token *jwt.Token) (interface{}, error)
+	duration        time.Duration
+	refreshDuration time.Duration
+	sessionRepo     ports.ISessionRepository
 }
 
-func NewJWTTokenService(conf config.Auth, keyRepo ports.IKeyRepository) *JWTService {
+// lastSeenInterval is how often the last seen time of a session is written while it is in use
+const lastSeenInterval = time.Minute
+
+// maxUserAgentLength is the size of the user agent column of sessions
+const maxUserAgentLength = 255
+
+func NewJWTTokenService(conf config.Auth, sessionRepo ports.ISessionRepository) *JWTService {
 	keyFunc := func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, domain.ErrInvalidToken
@@ -40,21 +51,107 @@ func NewJWTTokenService(conf config.Auth, keyRepo ports.IKeyRepository) *JWTServ
 	}
 
 	return &JWTService{
-		key:      []byte(conf.SecretKey),
-		keyFunc:  keyFunc,
-		duration: conf.Duration,
-		keyRepo:  keyRepo,
+		key:             []byte(conf.SecretKey),
+		keyFunc:         keyFunc,
+		duration:        conf.Duration,
+		refreshDuration: conf.RefreshDuration,
+		sessionRepo:     sessionRepo,
+	}
+}
+
+func (j *JWTService) CreateToken(user *domain.User, userAgent, ip string) (*domain.AuthToken, error) {
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	now := time.Now()
+	session, err := j.sessionRepo.CreateSession(context.Background(), &domain.Session{
+		UserID:     user.ID,
+		Key:        utils.GenerateRandomString(64),
+		RefreshKey: utils.GenerateRandomString(32),
+		UserAgent:  userAgent,
+		IP:         ip,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(j.refreshDuration),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return j.signTokenPair(user, session.Key, session.RefreshKey)
+}
+
+func (j *JWTService) RotateToken(user *domain.User, payload *domain.TokenPayload) (*domain.AuthToken, error) {
+	tokenID := utils.GenerateRandomString(32)
+	expiresAt := time.Now().Add(j.refreshDuration)
+
+	err := j.sessionRepo.SwapRefreshKey(context.Background(), payload.SessionID, payload.TokenID, tokenID, expiresAt)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoUpdatedData) {
+			// the refresh token has been rotated before, someone is replaying it so revoke the whole session
+			_ = j.sessionRepo.DeleteSession(context.Background(), payload.ID, payload.SessionID)
+			return nil, domain.ErrRefreshTokenReused
+		}
+		return nil, err
+	}
+
+	return j.signTokenPair(user, payload.Key, tokenID)
+}
+
+func (j *JWTService) RevokeToken(payload *domain.TokenPayload) error {
+	return j.sessionRepo.DeleteSession(context.Background(), payload.ID, payload.SessionID)
+}
+
+// getSession get the session of the key and check that it belongs to the user
+func (j *JWTService) getSession(userID int, key string) (*domain.Session, error) {
+	if key == "" {
+		return nil, domain.ErrInvalidToken
 	}
+
+	session, err := j.sessionRepo.GetSessionByKey(context.Background(), key)
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	if session.UserID != userID {
+		return nil, domain.ErrInvalidToken
+	}
+
+	return session, nil
 }
 
-func (j *JWTService) CreateToken(user *domain.User) (string, error) {
-	key := utils.GenerateRandomString(64)
+func (j *JWTService) signTokenPair(user *domain.User, key, tokenID string) (*domain.AuthToken, error) {
+	accessToken, err := j.signAccessToken(user, key)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	claims := jwt.NewWithClaims(jwtMethod, CustomClaims{
+		ID:   user.ID,
+		Key:  key,
+		Type: refreshTokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(j.refreshDuration)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "ql-kho-api",
+		},
+	})
 
-	err := j.keyRepo.SetKey(context.Background(), user.ID, key)
+	refreshToken, err := claims.SignedString(j.key)
 	if err != nil {
-		return "", err
+		return nil, domain.ErrTokenCreation
 	}
 
+	return &domain.AuthToken{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (j *JWTService) signAccessToken(user *domain.User, key string) (string, error) {
 	claims := jwt.NewWithClaims(jwtMethod, CustomClaims{
 		ID:    user.ID,
 		Name:  user.Name,
@@ -82,27 +179,61 @@ func (j *JWTService) VerifyToken(tokenString string) (*domain.TokenPayload, erro
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, j.keyFunc)
 
+	// a token that can not be parsed is nil, its error is looked at before the token
 	switch {
-	case token.Valid:
-		key, err := j.keyRepo.GetKey(context.Background(), claims.ID)
-		if err != nil {
+	case err == nil && token.Valid:
+		if claims.Type != "" {
 			return nil, domain.ErrInvalidToken
 		}
 
-		if key == "" {
-			return nil, domain.ErrInvalidToken
+		session, err := j.getSession(claims.ID, claims.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		if time.Since(session.LastSeenAt) > lastSeenInterval {
+			_ = j.sessionRepo.UpdateLastSeen(context.Background(), session.ID, time.Now())
 		}
 
-		if key != claims.Key {
+		return &domain.TokenPayload{
+			ID:        claims.ID,
+			Name:      claims.Name,
+			Email:     claims.Email,
+			Role:      claims.Role,
+			Key:       claims.Key,
+			SessionID: session.ID,
+		}, nil
+	case errors.Is(err, jwt.ErrTokenMalformed) || errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return nil, domain.ErrInvalidToken
+	case errors.Is(err, jwt.ErrTokenExpired) || errors.Is(err, jwt.ErrTokenNotValidYet):
+		return nil, domain.ErrExpiredToken
+	default:
+		return nil, err
+	}
+}
+
+func (j *JWTService) VerifyRefreshToken(refreshToken string) (*domain.TokenPayload, error) {
+	claims := &CustomClaims{}
+
+	token, err := jwt.ParseWithClaims(refreshToken, claims, j.keyFunc)
+
+	// a token that can not be parsed is nil, its error is looked at before the token
+	switch {
+	case err == nil && token.Valid:
+		if claims.Type != refreshTokenType || claims.RegisteredClaims.ID == "" {
 			return nil, domain.ErrInvalidToken
 		}
 
+		session, err := j.getSession(claims.ID, claims.Key)
+		if err != nil {
+			return nil, err
+		}
+
 		return &domain.TokenPayload{
-			ID:    claims.ID,
-			Name:  claims.Name,
-			Email: claims.Email,
-			Role:  claims.Role,
-			Key:   claims.Key,
+			ID:        claims.ID,
+			Key:       claims.Key,
+			SessionID: session.ID,
+			TokenID:   claims.RegisteredClaims.ID,
 		}, nil
 	case errors.Is(err, jwt.ErrTokenMalformed) || errors.Is(err, jwt.ErrTokenSignatureInvalid):
 		return nil, domain.ErrInvalidToken
@@ -13,27 +13,64 @@ import (
 	"github.com/tommjj/ql-kho-lua/internal/core/ports"
 )
 
-type MockKeyRepository struct {
+type MockSessionRepository struct {
 	mock.Mock
 }
 
-func (m *MockKeyRepository) SetKey(ctx context.Context, userID int, key string) error {
-	args := m.Called(ctx, userID, key)
-	return args.Error(0)
+func (m *MockSessionRepository) CreateSession(ctx context.Context, session *domain.Session) (*domain.Session, error) {
+	args := m.Called(ctx, session)
+	if s, ok := args.Get(0).(*domain.Session); ok {
+		return s, args.Error(1)
+	}
+	return nil, args.Error(1)
 }
 
-func (m *MockKeyRepository) GetKey(ctx context.Context, userID int) (string, error) {
-	args := m.Called(ctx, userID)
-	return args.String(0), args.Error(1)
+func (m *MockSessionRepository) GetSessionByKey(ctx context.Context, key string) (*domain.Session, error) {
+	args := m.Called(ctx, key)
+	if s, ok := args.Get(0).(*domain.Session); ok {
+		return s, args.Error(1)
+	}
+	return nil, args.Error(1)
 }
 
-func (m *MockKeyRepository) DelKey(ctx context.Context, userID int) error {
+func (m *MockSessionRepository) GetSessionsByUserID(ctx context.Context, userID int) ([]domain.Session, error) {
 	args := m.Called(ctx, userID)
+	if s, ok := args.Get(0).([]domain.Session); ok {
+		return s, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSessionRepository) UpdateLastSeen(ctx context.Context, id int, lastSeenAt time.Time) error {
+	args := m.Called(ctx, id, lastSeenAt)
 	return args.Error(0)
 }
 
+func (m *MockSessionRepository) SwapRefreshKey(ctx context.Context, id int, oldRefreshKey, newRefreshKey string, expiresAt time.Time) error {
+	args := m.Called(ctx, id, oldRefreshKey, newRefreshKey, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) DeleteSession(ctx context.Context, userID, id int) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// onCreateSession make the mock return the created session with id 1 and store it into session
+func onCreateSession(mockRepo *MockSessionRepository, session *domain.Session) {
+	mockRepo.On("CreateSession", mock.Anything, mock.Anything).Return(session, nil).Run(func(args mock.Arguments) {
+		*session = *args.Get(1).(*domain.Session)
+		session.ID = 1
+	})
+}
+
 func TestImplementsIAuthService(t *testing.T) {
-	mockRepo := new(MockKeyRepository)
+	mockRepo := new(MockSessionRepository)
 	conf := config.Auth{SecretKey: "secret", Duration: time.Hour}
 	service := auth.NewJWTTokenService(conf, mockRepo)
 
@@ -41,75 +78,201 @@ func TestImplementsIAuthService(t *testing.T) {
 }
 
 func TestCreateToken(t *testing.T) {
-	mockRepo := new(MockKeyRepository)
-	conf := config.Auth{SecretKey: "secret", Duration: time.Hour}
+	mockRepo := new(MockSessionRepository)
+	conf := config.Auth{SecretKey: "secret", Duration: time.Hour, RefreshDuration: time.Hour}
 	service := auth.NewJWTTokenService(conf, mockRepo)
 
+	session := &domain.Session{}
 	user := &domain.User{ID: 1, Name: "Test", Email: "test@example.com", Role: domain.Root}
-	mockRepo.On("SetKey", mock.Anything, user.ID, mock.Anything).Return(nil)
+	onCreateSession(mockRepo, session)
 
-	token, err := service.CreateToken(user)
+	token, err := service.CreateToken(user, "desktop", "127.0.0.1")
 	assert.NoError(t, err)
-	assert.NotEmpty(t, token)
+	assert.NotEmpty(t, token.AccessToken)
+	assert.NotEmpty(t, token.RefreshToken)
+	assert.Equal(t, user.ID, session.UserID)
+	assert.Equal(t, "desktop", session.UserAgent)
+	assert.Equal(t, "127.0.0.1", session.IP)
 
 	mockRepo.AssertExpectations(t)
 }
 
 func TestVerifyToken(t *testing.T) {
-	mockRepo := new(MockKeyRepository)
-	key := ""
-	conf := config.Auth{SecretKey: "secret", Duration: time.Hour}
+	mockRepo := new(MockSessionRepository)
+	conf := config.Auth{SecretKey: "secret", Duration: time.Hour, RefreshDuration: time.Hour}
 	service := auth.NewJWTTokenService(conf, mockRepo)
 
+	session := &domain.Session{}
 	user := &domain.User{ID: 1, Name: "Test", Email: "test@example.com", Phone: "+5555555555", Role: domain.Root}
-	mockRepo.On("SetKey", mock.Anything, user.ID, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
-		key = args.String(2)
-	})
+	onCreateSession(mockRepo, session)
 
-	token, _ := service.CreateToken(user)
-	mockRepo.On("GetKey", mock.Anything, user.ID).Return(key, nil)
+	token, _ := service.CreateToken(user, "desktop", "127.0.0.1")
+	mockRepo.On("GetSessionByKey", mock.Anything, session.Key).Return(session, nil)
 
-	payload, err := service.VerifyToken(token)
+	payload, err := service.VerifyToken(token.AccessToken)
 
 	assert.NoError(t, err)
 	assert.Equal(t, user.ID, payload.ID)
 	assert.Equal(t, user.Email, payload.Email)
 	assert.Equal(t, user.Name, payload.Name)
 	assert.Equal(t, user.Role, payload.Role)
+	assert.Equal(t, session.ID, payload.SessionID)
+}
+
+func TestVerifyToken_UpdateLastSeen(t *testing.T) {
+	mockRepo := new(MockSessionRepository)
+	conf := config.Auth{SecretKey: "secret", Duration: time.Hour, RefreshDuration: time.Hour}
+	service := auth.NewJWTTokenService(conf, mockRepo)
+
+	session := &domain.Session{}
+	user := &domain.User{ID: 1, Name: "Test", Email: "test@example.com", Role: domain.Root}
+	onCreateSession(mockRepo, session)
+
+	token, _ := service.CreateToken(user, "desktop", "127.0.0.1")
+	session.LastSeenAt = time.Now().Add(-time.Hour)
+	mockRepo.On("GetSessionByKey", mock.Anything, session.Key).Return(session, nil)
+	mockRepo.On("UpdateLastSeen", mock.Anything, session.ID, mock.Anything).Return(nil).Once()
+
+	_, err := service.VerifyToken(token.AccessToken)
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
 }
 
 func TestVerifyToken_InvalidKey(t *testing.T) {
-	mockRepo := new(MockKeyRepository)
-	conf := config.Auth{SecretKey: "secret", Duration: time.Hour}
+	mockRepo := new(MockSessionRepository)
+	conf := config.Auth{SecretKey: "secret", Duration: time.Hour, RefreshDuration: time.Hour}
 	service := auth.NewJWTTokenService(conf, mockRepo)
 
+	session := &domain.Session{}
 	user := &domain.User{ID: 1, Name: "Test", Email: "test@example.com", Phone: "+5555555555", Role: domain.Root}
-	mockRepo.On("SetKey", mock.Anything, user.ID, mock.Anything).Return(nil)
-	mockRepo.On("GetKey", mock.Anything, user.ID).Return("wrong-key", nil)
+	onCreateSession(mockRepo, session)
 
-	token, _ := service.CreateToken(user)
-	mockRepo.On("GetKey", mock.Anything, user.ID).Return("wrong-key", nil)
+	token, _ := service.CreateToken(user, "desktop", "127.0.0.1")
+	mockRepo.On("GetSessionByKey", mock.Anything, session.Key).Return(nil, domain.ErrDataNotFound)
 
-	_, err := service.VerifyToken(token)
+	_, err := service.VerifyToken(token.AccessToken)
 	assert.Error(t, err)
 	assert.Equal(t, domain.ErrInvalidToken, err)
 }
 
+func TestVerifyToken_SessionOfOtherUser(t *testing.T) {
+	mockRepo := new(MockSessionRepository)
+	conf := config.Auth{SecretKey: "secret", Duration: time.Hour, RefreshDuration: time.Hour}
+	service := auth.NewJWTTokenService(conf, mockRepo)
+
+	session := &domain.Session{}
+	user := &domain.User{ID: 1, Name: "Test", Email: "test@example.com", Role: domain.Root}
+	onCreateSession(mockRepo, session)
+
+	token, _ := service.CreateToken(user, "desktop", "127.0.0.1")
+	mockRepo.On("GetSessionByKey", mock.Anything, session.Key).Return(&domain.Session{ID: 2, UserID: 2, Key: session.Key}, nil)
+
+	_, err := service.VerifyToken(token.AccessToken)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+}
+
 func TestVerifyToken_Expired(t *testing.T) {
-	mockRepo := new(MockKeyRepository)
-	key := ""
-	conf := config.Auth{SecretKey: "secret", Duration: -time.Hour}
+	mockRepo := new(MockSessionRepository)
+	conf := config.Auth{SecretKey: "secret", Duration: -time.Hour, RefreshDuration: time.Hour}
 	service := auth.NewJWTTokenService(conf, mockRepo)
 
+	session := &domain.Session{}
 	user := &domain.User{ID: 1, Name: "Test", Email: "test@example.com", Phone: "+5555555555", Role: domain.Root}
-	mockRepo.On("SetKey", mock.Anything, user.ID, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
-		key = args.String(2)
-	})
+	onCreateSession(mockRepo, session)
 
-	token, _ := service.CreateToken(user)
-	mockRepo.On("GetKey", mock.Anything, user.ID).Return(key, nil)
+	token, _ := service.CreateToken(user, "desktop", "127.0.0.1")
+	mockRepo.On("GetSessionByKey", mock.Anything, session.Key).Return(session, nil)
 
-	_, err := service.VerifyToken(token)
+	_, err := service.VerifyToken(token.AccessToken)
 	assert.Error(t, err)
 	assert.Equal(t, domain.ErrExpiredToken, err)
 }
+
+func TestVerifyToken_RefreshTokenRejected(t *testing.T) {
+	mockRepo := new(MockSessionRepository)
+	conf := config.Auth{SecretKey: "secret", Duration: time.Hour, RefreshDuration: time.Hour}
+	service := auth.NewJWTTokenService(conf, mockRepo)
+
+	session := &domain.Session{}
+	user := &domain.User{ID: 1, Name: "Test", Email: "test@example.com", Role: domain.Root}
+	onCreateSession(mockRepo, session)
+
+	token, err := service.CreateToken(user, "desktop", "127.0.0.1")
+	assert.NoError(t, err)
+
+	_, err = service.VerifyToken(token.RefreshToken)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+
+	_, err = service.VerifyRefreshToken(token.AccessToken)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+}
+
+func TestRotateToken(t *testing.T) {
+	mockRepo := new(MockSessionRepository)
+	conf := config.Auth{SecretKey: "secret", Duration: time.Hour, RefreshDuration: time.Hour}
+	service := auth.NewJWTTokenService(conf, mockRepo)
+
+	session := &domain.Session{}
+	user := &domain.User{ID: 1, Name: "Test", Email: "test@example.com", Role: domain.Root}
+	onCreateSession(mockRepo, session)
+
+	token, err := service.CreateToken(user, "desktop", "127.0.0.1")
+	assert.NoError(t, err)
+
+	mockRepo.On("GetSessionByKey", mock.Anything, session.Key).Return(session, nil)
+
+	payload, err := service.VerifyRefreshToken(token.RefreshToken)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, payload.ID)
+	assert.Equal(t, session.ID, payload.SessionID)
+	assert.Equal(t, session.RefreshKey, payload.TokenID)
+
+	mockRepo.On("SwapRefreshKey", mock.Anything, session.ID, session.RefreshKey, mock.Anything, mock.Anything).Return(nil).Once()
+
+	rotated, err := service.RotateToken(user, payload)
+	assert.NoError(t, err)
+
+	newPayload, err := service.VerifyRefreshToken(rotated.RefreshToken)
+	assert.NoError(t, err)
+	assert.NotEqual(t, payload.TokenID, newPayload.TokenID)
+
+	accessPayload, err := service.VerifyToken(rotated.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, user.Email, accessPayload.Email)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRotateToken_Reused(t *testing.T) {
+	mockRepo := new(MockSessionRepository)
+	conf := config.Auth{SecretKey: "secret", Duration: time.Hour, RefreshDuration: time.Hour}
+	service := auth.NewJWTTokenService(conf, mockRepo)
+
+	user := &domain.User{ID: 1, Name: "Test", Email: "test@example.com", Role: domain.Root}
+	payload := &domain.TokenPayload{ID: user.ID, Key: "key", SessionID: 3, TokenID: "already-rotated"}
+
+	mockRepo.On("SwapRefreshKey", mock.Anything, payload.SessionID, payload.TokenID, mock.Anything, mock.Anything).Return(domain.ErrNoUpdatedData)
+	mockRepo.On("DeleteSession", mock.Anything, user.ID, payload.SessionID).Return(nil)
+
+	_, err := service.RotateToken(user, payload)
+	assert.Equal(t, domain.ErrRefreshTokenReused, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestVerifyToken_Malformed(t *testing.T) {
+	mockRepo := new(MockSessionRepository)
+	conf := config.Auth{SecretKey: "secret", Duration: time.Hour, RefreshDuration: time.Hour}
+	service := auth.NewJWTTokenService(conf, mockRepo)
+
+	for _, token := range []string{"", "abc", "a.b", "a.b.c"} {
+		_, err := service.VerifyToken(token)
+		assert.Equal(t, domain.ErrInvalidToken, err, token)
+
+		_, err = service.VerifyRefreshToken(token)
+		assert.Equal(t, domain.ErrInvalidToken, err, token)
+	}
+
+	mockRepo.AssertNotCalled(t, "GetSessionByKey", mock.Anything, mock.Anything)
+}
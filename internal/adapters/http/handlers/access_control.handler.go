@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+type AccessControlHandler struct {
+	acc       ports.IAccessControlService
+	user      ports.IUserService
+	warehouse ports.IWarehouseService
+}
+
+func NewAccessControlHandler(acc ports.IAccessControlService, userService ports.IUserService, warehouseService ports.IWarehouseService) *AccessControlHandler {
+	return &AccessControlHandler{
+		acc:       acc,
+		user:      userService,
+		warehouse: warehouseService,
+	}
+}
+
+type accessLevelRequest struct {
+	AccessLevel domain.AccessLevel `json:"access_level" binding:"omitempty,oneof=read_only import_only export_only full" example:"full" enums:"read_only,import_only,export_only,full"`
+}
+
+// GrantAccess ql-kho-lua
+//
+//	@Summary		Grant warehouse access
+//	@Description	Grant a user access to a warehouse, the access level defaults to full
+//	@Tags			access
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int					true	"Warehouse id"
+//	@Param			user_id	path		int					true	"User id"
+//	@Param			request	body		accessLevelRequest	false	"Access level"
+//	@Success		200		{object}	response		"Granted"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		401		{object}	errorResponse	"Unauthorized error"
+//	@Failure		403		{object}	errorResponse	"Forbidden error"
+//	@Failure		404		{object}	errorResponse	"Data not found error"
+//	@Failure		409		{object}	errorResponse	"Conflicting data error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/warehouses/{id}/users/{user_id}  [post]
+//	@Security		JWTAuth
+func (a *AccessControlHandler) GrantAccess(ctx *gin.Context) {
+	warehouseID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	userID, err := strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		validationError(ctx, errors.New("user_id must be a number"))
+		return
+	}
+
+	req := accessLevelRequest{
+		AccessLevel: domain.AccessFull,
+	}
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		validationError(ctx, err)
+		return
+	}
+
+	err = a.acc.SetAccess(ctx, warehouseID, userID, req.AccessLevel)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// UpdateAccess ql-kho-lua
+//
+//	@Summary		Update warehouse access level
+//	@Description	Change the access level of a user on a warehouse
+//	@Tags			access
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int					true	"Warehouse id"
+//	@Param			user_id	path		int					true	"User id"
+//	@Param			request	body		accessLevelRequest	true	"Access level"
+//	@Success		200		{object}	response			"Updated"
+//	@Failure		400		{object}	errorResponse		"Validation error"
+//	@Failure		401		{object}	errorResponse		"Unauthorized error"
+//	@Failure		403		{object}	errorResponse		"Forbidden error"
+//	@Failure		404		{object}	errorResponse		"Data not found error"
+//	@Failure		500		{object}	errorResponse		"Internal server error"
+//	@Router			/warehouses/{id}/users/{user_id}  [patch]
+//	@Security		JWTAuth
+func (a *AccessControlHandler) UpdateAccess(ctx *gin.Context) {
+	var req accessLevelRequest
+
+	warehouseID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	userID, err := strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		validationError(ctx, errors.New("user_id must be a number"))
+		return
+	}
+
+	err = ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	if req.AccessLevel == "" {
+		validationError(ctx, errors.New("access_level is required"))
+		return
+	}
+
+	err = a.acc.UpdateAccess(ctx, warehouseID, userID, req.AccessLevel)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// RevokeAccess ql-kho-lua
+//
+//	@Summary		Revoke warehouse access
+//	@Description	Revoke a user access to a warehouse
+//	@Tags			access
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int				true	"Warehouse id"
+//	@Param			user_id	path		int				true	"User id"
+//	@Success		200		{object}	response		"Revoked"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		401		{object}	errorResponse	"Unauthorized error"
+//	@Failure		403		{object}	errorResponse	"Forbidden error"
+//	@Failure		404		{object}	errorResponse	"Data not found error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/warehouses/{id}/users/{user_id}  [delete]
+//	@Security		JWTAuth
+func (a *AccessControlHandler) RevokeAccess(ctx *gin.Context) {
+	warehouseID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	userID, err := strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		validationError(ctx, errors.New("user_id must be a number"))
+		return
+	}
+
+	err = a.acc.DelAccess(ctx, warehouseID, userID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+type getListAccessRequest struct {
+	Query string `form:"q" binding:"" example:"store 01"`
+	Skip  int    `form:"skip" binding:"min=1" example:"1"`
+	Limit int    `form:"limit" binding:"min=5" example:"5"`
+}
+
+// GetWarehouseUsers ql-kho-lua
+//
+//	@Summary		Get authorized users
+//	@Description	Get users authorized to access a warehouse with pagination
+//	@Tags			access
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int											true	"Warehouse id"
+//	@Param			q		query		string										false	"Query"
+//	@Param			skip	query		int											false	"Skip"	default(1)	minimum(1)
+//	@Param			limit	query		int											false	"Limit"	default(5)	minimum(5)
+//	@Success		200		{object}	responseWithPagination{data=[]userResponse}	"Users data"
+//	@Failure		400		{object}	errorResponse								"Validation error"
+//	@Failure		401		{object}	errorResponse								"Unauthorized error"
+//	@Failure		403		{object}	errorResponse								"Forbidden error"
+//	@Failure		404		{object}	errorResponse								"Data not found error"
+//	@Failure		500		{object}	errorResponse								"Internal server error"
+//	@Router			/warehouses/{id}/users  [get]
+//	@Security		JWTAuth
+func (a *AccessControlHandler) GetWarehouseUsers(ctx *gin.Context) {
+	warehouseID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	req := getListAccessRequest{
+		Skip:  1,
+		Limit: 5,
+	}
+	err = ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	_, err = a.warehouse.GetWarehouseByID(ctx, warehouseID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	count, err := a.user.CountAuthorizedUsers(ctx, warehouseID, req.Query)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	if isPageOutOfRange(count, req.Limit, req.Skip) {
+		handleError(ctx, domain.ErrDataNotFound)
+		return
+	}
+
+	users, err := a.user.GetAuthorizedUsers(ctx, warehouseID, req.Query, req.Limit, req.Skip)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]userResponse, 0, len(users))
+	for _, user := range users {
+		res = append(res, newUserResponse(&user))
+	}
+
+	pagination := newPagination(count, len(users), req.Limit, req.Skip)
+	handleSuccessPagination(ctx, pagination, res)
+}
+
+// GetUserWarehouses ql-kho-lua
+//
+//	@Summary		Get authorized warehouses of user
+//	@Description	Get warehouses a user is authorized to access with pagination
+//	@Tags			access
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int													true	"User id"
+//	@Param			q		query		string												false	"Query"
+//	@Param			skip	query		int													false	"Skip"	default(1)	minimum(1)
+//	@Param			limit	query		int													false	"Limit"	default(5)	minimum(5)
+//	@Success		200		{object}	responseWithPagination{data=[]warehouseResponse}	"Warehouses data"
+//	@Failure		400		{object}	errorResponse										"Validation error"
+//	@Failure		401		{object}	errorResponse										"Unauthorized error"
+//	@Failure		403		{object}	errorResponse										"Forbidden error"
+//	@Failure		404		{object}	errorResponse										"Data not found error"
+//	@Failure		500		{object}	errorResponse										"Internal server error"
+//	@Router			/users/{id}/warehouses  [get]
+//	@Security		JWTAuth
+func (a *AccessControlHandler) GetUserWarehouses(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	req := getListAccessRequest{
+		Skip:  1,
+		Limit: 5,
+	}
+	err = ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	_, err = a.user.GetUserByID(ctx, userID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	count, err := a.warehouse.CountAuthorizedWarehouses(ctx, userID, req.Query)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	if isPageOutOfRange(count, req.Limit, req.Skip) {
+		handleError(ctx, domain.ErrDataNotFound)
+		return
+	}
+
+	stores, err := a.warehouse.GetAuthorizedWarehouses(ctx, userID, req.Query, req.Limit, req.Skip)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]warehouseResponse, 0, len(stores))
+	for _, store := range stores {
+		res = append(res, newWarehouseResponse(&store))
+	}
+
+	pagination := newPagination(count, len(stores), req.Limit, req.Skip)
+	handleSuccessPagination(ctx, pagination, res)
+}
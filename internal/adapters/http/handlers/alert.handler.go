@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+type AlertHandler struct {
+	svc ports.IAlertService
+	acc ports.IAccessControlService
+}
+
+func NewAlertHandler(alertService ports.IAlertService, acc ports.IAccessControlService) *AlertHandler {
+	return &AlertHandler{
+		svc: alertService,
+		acc: acc,
+	}
+}
+
+// checkReadAccess check the user can read the warehouse, root can read every warehouse
+func (a *AlertHandler) checkReadAccess(ctx *gin.Context, warehouseID int) bool {
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	isRootUser := token.Role == domain.Root
+	if !isRootUser {
+		err := a.acc.HasAccess(ctx, warehouseID, token.ID, domain.ActionRead)
+		if err != nil {
+			handleError(ctx, err)
+			return false
+		}
+	}
+	return true
+}
+
+// GetThresholds ql-kho-lua
+//
+//	@Summary		Get stock thresholds
+//	@Description	Get the minimum stock of every rice with a threshold in a warehouse
+//	@Tags			alerts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int										true	"Warehouse id"
+//	@Success		200	{object}	response{data=[]stockThresholdResponse}	"Thresholds data"
+//	@Failure		400	{object}	errorResponse							"Validation error"
+//	@Failure		401	{object}	errorResponse							"Unauthorized error"
+//	@Failure		403	{object}	errorResponse							"Forbidden error"
+//	@Failure		500	{object}	errorResponse							"Internal server error"
+//	@Router			/warehouses/{id}/thresholds  [get]
+//	@Security		JWTAuth
+func (a *AlertHandler) GetThresholds(ctx *gin.Context) {
+	warehouseID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	if !a.checkReadAccess(ctx, warehouseID) {
+		return
+	}
+
+	thresholds, err := a.svc.GetThresholds(ctx, warehouseID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]stockThresholdResponse, 0, len(thresholds))
+	for _, v := range thresholds {
+		res = append(res, newStockThresholdResponse(&v))
+	}
+
+	handleSuccess(ctx, res)
+}
+
+type setThresholdRequest struct {
+	MinQuantity int `json:"min_quantity" binding:"required,min=1" example:"100"`
+}
+
+// SetThreshold ql-kho-lua
+//
+//	@Summary		Set a stock threshold
+//	@Description	Set the minimum stock of a rice in a warehouse, a low stock alert is raised when the stock falls below it
+//	@Tags			alerts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int										true	"Warehouse id"
+//	@Param			rice_id	path		int										true	"Rice id"
+//	@Param			request	body		setThresholdRequest						true	"Threshold body"
+//	@Success		200		{object}	response{data=stockThresholdResponse}	"Threshold data"
+//	@Failure		400		{object}	errorResponse							"Validation error"
+//	@Failure		401		{object}	errorResponse							"Unauthorized error"
+//	@Failure		403		{object}	errorResponse							"Forbidden error"
+//	@Failure		404		{object}	errorResponse							"Data not found error"
+//	@Failure		500		{object}	errorResponse							"Internal server error"
+//	@Router			/warehouses/{id}/thresholds/{rice_id}  [put]
+//	@Security		JWTAuth
+func (a *AlertHandler) SetThreshold(ctx *gin.Context) {
+	var req setThresholdRequest
+
+	warehouseID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	riceID, err := strconv.Atoi(ctx.Param("rice_id"))
+	if err != nil {
+		validationError(ctx, errors.New("rice_id must be a number"))
+		return
+	}
+
+	err = ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	if !a.checkReadAccess(ctx, warehouseID) {
+		return
+	}
+
+	threshold, err := a.svc.SetThreshold(ctx, &domain.StockThreshold{
+		WarehouseID: warehouseID,
+		RiceID:      riceID,
+		MinQuantity: req.MinQuantity,
+	})
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newStockThresholdResponse(threshold)
+	handleSuccess(ctx, res)
+}
+
+// DeleteThreshold ql-kho-lua
+//
+//	@Summary		Delete a stock threshold
+//	@Description	Stop watching the stock of a rice in a warehouse
+//	@Tags			alerts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int				true	"Warehouse id"
+//	@Param			rice_id	path		int				true	"Rice id"
+//	@Success		200		{object}	response		"Deleted"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		401		{object}	errorResponse	"Unauthorized error"
+//	@Failure		403		{object}	errorResponse	"Forbidden error"
+//	@Failure		404		{object}	errorResponse	"Data not found error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/warehouses/{id}/thresholds/{rice_id}  [delete]
+//	@Security		JWTAuth
+func (a *AlertHandler) DeleteThreshold(ctx *gin.Context) {
+	warehouseID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	riceID, err := strconv.Atoi(ctx.Param("rice_id"))
+	if err != nil {
+		validationError(ctx, errors.New("rice_id must be a number"))
+		return
+	}
+
+	if !a.checkReadAccess(ctx, warehouseID) {
+		return
+	}
+
+	err = a.svc.DeleteThreshold(ctx, warehouseID, riceID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+type getListAlertsRequest struct {
+	WarehouseID  int              `form:"warehouse_id" binding:"omitempty,min=0" example:"1"`
+	Type         domain.AlertType `form:"type" binding:"omitempty,oneof=low_stock near_capacity lot_expiry" example:"low_stock"`
+	Acknowledged *bool            `form:"acknowledged" binding:"omitempty" example:"false"`
+	Skip         int              `form:"skip" binding:"min=1" example:"1"`
+	Limit        int              `form:"limit" binding:"min=5" example:"5"`
+}
+
+// GetListAlerts ql-kho-lua
+//
+//	@Summary		Get alerts
+//	@Description	Get low stock, near-capacity and lot expiry alerts, newest first
+//	@Tags			alerts
+//	@Accept			json
+//	@Produce		json
+//	@Param			warehouse_id	query		int											false	"Warehouse id, required for users other than root"
+//	@Param			type			query		string										false	"Alert type"	Enums(low_stock, near_capacity, lot_expiry)
+//	@Param			acknowledged	query		bool										false	"Acknowledged"
+//	@Param			skip			query		int											false	"Skip"	default(1)	minimum(1)
+//	@Param			limit			query		int											false	"Limit"	default(5)	minimum(5)
+//	@Success		200				{object}	responseWithPagination{data=[]alertResponse}	"Alerts data"
+//	@Failure		400				{object}	errorResponse								"Validation error"
+//	@Failure		401				{object}	errorResponse								"Unauthorized error"
+//	@Failure		403				{object}	errorResponse								"Forbidden error"
+//	@Failure		404				{object}	errorResponse								"Data not found error"
+//	@Failure		500				{object}	errorResponse								"Internal server error"
+//	@Router			/alerts  [get]
+//	@Security		JWTAuth
+func (a *AlertHandler) GetListAlerts(ctx *gin.Context) {
+	req := getListAlertsRequest{
+		Skip:  1,
+		Limit: 5,
+	}
+	err := ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	isRootUser := token.Role == domain.Root
+	if !isRootUser {
+		if req.WarehouseID == 0 {
+			handleError(ctx, domain.ErrForbidden)
+			return
+		}
+		err := a.acc.HasAccess(ctx, req.WarehouseID, token.ID, domain.ActionRead)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+	}
+
+	filter := domain.AlertFilter{
+		WarehouseID:  req.WarehouseID,
+		Type:         req.Type,
+		Acknowledged: req.Acknowledged,
+	}
+
+	count, err := a.svc.CountAlerts(ctx, filter)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	if isPageOutOfRange(count, req.Limit, req.Skip) {
+		handleError(ctx, domain.ErrDataNotFound)
+		return
+	}
+
+	alerts, err := a.svc.GetListAlerts(ctx, filter, req.Limit, req.Skip)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]alertResponse, 0, len(alerts))
+	for _, v := range alerts {
+		res = append(res, newAlertResponse(&v))
+	}
+
+	pagination := newPagination(count, len(alerts), req.Limit, req.Skip)
+	handleSuccessPagination(ctx, pagination, res)
+}
+
+// AcknowledgeAlert ql-kho-lua
+//
+//	@Summary		Acknowledge an alert
+//	@Description	Acknowledge an open alert, the same condition can be raised again once it is acknowledged
+//	@Tags			alerts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int								true	"Alert id"
+//	@Success		200	{object}	response{data=alertResponse}	"Alert data"
+//	@Failure		400	{object}	errorResponse					"Validation error"
+//	@Failure		401	{object}	errorResponse					"Unauthorized error"
+//	@Failure		403	{object}	errorResponse					"Forbidden error"
+//	@Failure		404	{object}	errorResponse					"Data not found error"
+//	@Failure		409	{object}	errorResponse					"Conflicting data error"
+//	@Failure		500	{object}	errorResponse					"Internal server error"
+//	@Router			/alerts/{id}/acknowledge  [post]
+//	@Security		JWTAuth
+func (a *AlertHandler) AcknowledgeAlert(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	alert, err := a.svc.GetAlertByID(ctx, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	if !a.checkReadAccess(ctx, alert.WarehouseID) {
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	acknowledged, err := a.svc.AcknowledgeAlert(ctx, id, token.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newAlertResponse(acknowledged)
+	handleSuccess(ctx, res)
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+type AuditHandler struct {
+	svc ports.IAuditService
+}
+
+func NewAuditHandler(auditService ports.IAuditService) *AuditHandler {
+	return &AuditHandler{
+		svc: auditService,
+	}
+}
+
+type getListAuditLogsRequest struct {
+	ActorID  int                `form:"actor_id" binding:"omitempty,min=1" example:"1"`
+	Entity   domain.AuditEntity `form:"entity" binding:"omitempty,oneof=user warehouse rice customer import_invoice export_invoice transfer order payment access" example:"warehouse"`
+	EntityID int                `form:"entity_id" binding:"omitempty,min=1" example:"1"`
+	Start    *time.Time         `form:"start" binding:"omitempty"`
+	End      *time.Time         `form:"end" binding:"omitempty"`
+	Skip     int                `form:"skip" binding:"min=1" example:"1"`
+	Limit    int                `form:"limit" binding:"min=5" example:"5"`
+}
+
+// GetListAuditLogs ql-kho-lua
+//
+//	@Summary		Get audit logs
+//	@Description	Get audit logs of mutating operations, newest first
+//	@Tags			audit
+//	@Accept			json
+//	@Produce		json
+//	@Param			actor_id	query		int												false	"Actor user id"
+//	@Param			entity		query		string											false	"Entity"	Enums(user, warehouse, rice, customer, import_invoice, export_invoice, transfer, access)
+//	@Param			entity_id	query		int												false	"Entity id"
+//	@Param			start		query		string											false	"Start"	format(date-time)
+//	@Param			end			query		string											false	"End"	format(date-time)
+//	@Param			skip		query		int												false	"Skip"	default(1)	minimum(1)
+//	@Param			limit		query		int												false	"Limit"	default(5)	minimum(5)
+//	@Success		200			{object}	responseWithPagination{data=[]auditLogResponse}	"Audit logs data"
+//	@Failure		400			{object}	errorResponse									"Validation error"
+//	@Failure		401			{object}	errorResponse									"Unauthorized error"
+//	@Failure		403			{object}	errorResponse									"Forbidden error"
+//	@Failure		404			{object}	errorResponse									"Data not found error"
+//	@Failure		500			{object}	errorResponse									"Internal server error"
+//	@Router			/audit  [get]
+//	@Security		JWTAuth
+func (a *AuditHandler) GetListAuditLogs(ctx *gin.Context) {
+	req := getListAuditLogsRequest{
+		Skip:  1,
+		Limit: 5,
+	}
+	err := ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	filter := domain.AuditFilter{
+		ActorID:  req.ActorID,
+		Entity:   req.Entity,
+		EntityID: req.EntityID,
+		Start:    req.Start,
+		End:      req.End,
+	}
+
+	count, err := a.svc.CountAuditLogs(ctx, filter)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	if isPageOutOfRange(count, req.Limit, req.Skip) {
+		handleError(ctx, domain.ErrDataNotFound)
+		return
+	}
+
+	logs, err := a.svc.GetListAuditLogs(ctx, filter, req.Limit, req.Skip)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]auditLogResponse, 0, len(logs))
+	for _, log := range logs {
+		res = append(res, newAuditLogResponse(&log))
+	}
+
+	pagination := newPagination(count, len(logs), req.Limit, req.Skip)
+	handleSuccessPagination(ctx, pagination, res)
+}
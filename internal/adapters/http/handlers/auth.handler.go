@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"errors"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
 	"github.com/tommjj/ql-kho-lua/internal/core/ports"
 )
@@ -23,7 +26,7 @@ type loginRequest struct {
 // Login ql-kho-lua
 //
 //	@Summary		Login and get an access token
-//	@Description	Logs in a registered user and returns an access token if the credentials are valid.
+//	@Description	Logs in a registered user and returns an access token and a refresh token if the credentials are valid.
 //	@Tags			auth
 //	@Accept			json
 //	@Produce		json
@@ -42,7 +45,7 @@ func (auth AuthHandler) Login(ctx *gin.Context) {
 		return
 	}
 
-	token, err := auth.svc.Login(ctx, req.Email, req.Password)
+	token, err := auth.svc.Login(ctx, req.Email, req.Password, ctx.Request.UserAgent(), ctx.ClientIP())
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -51,3 +54,126 @@ func (auth AuthHandler) Login(ctx *gin.Context) {
 
 	handleSuccess(ctx, res)
 }
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required,jwt" example:"eyJJ9.eyJpEzNDR9.aBkEx1"`
+}
+
+// Refresh ql-kho-lua
+//
+//	@Summary		Refresh an access token
+//	@Description	Exchanges a refresh token for a new access token and refresh token. A refresh token can only be used once, reusing it revokes the session.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		refreshRequest				true	"Refresh request body"
+//	@Success		200		{object}	response{data=authResponse}	"Successfully refreshed"
+//	@Failure		400		{object}	errorResponse				"Validation error"
+//	@Failure		401		{object}	errorResponse				"Unauthorized error"
+//	@Failure		500		{object}	errorResponse				"Internal server error"
+//	@Router			/auth/refresh [post]
+func (auth AuthHandler) Refresh(ctx *gin.Context) {
+	var req refreshRequest
+
+	err := ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token, err := auth.svc.Refresh(ctx, req.RefreshToken)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	res := newAuthResponse(token)
+
+	handleSuccess(ctx, res)
+}
+
+// Logout ql-kho-lua
+//
+//	@Summary		Logout
+//	@Description	Revokes the current session, the access token and refresh token can no longer be used.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response		"Successfully logged out"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/auth/logout [post]
+//	@Security		JWTAuth
+func (auth AuthHandler) Logout(ctx *gin.Context) {
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	err := auth.svc.Logout(ctx, token)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// GetSessions ql-kho-lua
+//
+//	@Summary		Get active sessions
+//	@Description	Get the active sessions of the current user, one per logged in device
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response{data=[]sessionResponse}	"Sessions data"
+//	@Failure		401	{object}	errorResponse						"Unauthorized error"
+//	@Failure		500	{object}	errorResponse						"Internal server error"
+//	@Router			/auth/sessions [get]
+//	@Security		JWTAuth
+func (auth AuthHandler) GetSessions(ctx *gin.Context) {
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	sessions, err := auth.svc.GetSessions(ctx, token.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]sessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		res = append(res, newSessionResponse(&session, session.ID == token.SessionID))
+	}
+
+	handleSuccess(ctx, res)
+}
+
+// RevokeSession ql-kho-lua
+//
+//	@Summary		Revoke a session
+//	@Description	Revoke a session of the current user, tokens of the session can no longer be used
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int				true	"Session id"
+//	@Success		200	{object}	response		"Revoked"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/auth/sessions/{id} [delete]
+//	@Security		JWTAuth
+func (auth AuthHandler) RevokeSession(ctx *gin.Context) {
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	err = auth.svc.RevokeSession(ctx, token.ID, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
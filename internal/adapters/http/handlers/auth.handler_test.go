@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+// fakeAuthService counts the refresh calls that reach the service
+type fakeAuthService struct {
+	ports.IAuthService
+	refreshed int
+}
+
+func (f *fakeAuthService) Refresh(ctx context.Context, refreshToken string) (*domain.AuthToken, error) {
+	f.refreshed++
+	return nil, domain.ErrInvalidToken
+}
+
+func TestRefresh_MalformedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	svc := &fakeAuthService{}
+	handler := NewAuthHandler(svc)
+
+	r := gin.New()
+	r.POST("/refresh", handler.Refresh)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/refresh", strings.NewReader(`{"refresh_token":"abc"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, 0, svc.refreshed)
+}
@@ -0,0 +1,431 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/sheet"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+const (
+	// maxImportFileSize is the largest file a bulk import reads
+	maxImportFileSize = 10 << 20
+	// maxImportRows is the most data rows of a bulk import file
+	maxImportRows = 5000
+)
+
+type BulkImportHandler struct {
+	svc ports.IBulkImportService
+}
+
+func NewBulkImportHandler(svc ports.IBulkImportService) *BulkImportHandler {
+	return &BulkImportHandler{
+		svc: svc,
+	}
+}
+
+type bulkImportRequest struct {
+	File *multipart.FileHeader `form:"file" binding:"required"`
+}
+
+type bulkImportQuery struct {
+	DryRun bool `form:"dry_run" binding:"omitempty" example:"true"`
+}
+
+// importSpec is how the columns of a bulk import file fill the create request of a row, column names are lower case
+type importSpec[R any] struct {
+	columns map[string]func(req *R, value string) error
+	// fields is the columns of a request field, its validation errors name them
+	fields map[string][]string
+}
+
+// readImportFile read the uploaded file of a bulk import, it answers with a validation error and returns false when it can not
+func readImportFile(ctx *gin.Context) ([][]string, bool) {
+	var req bulkImportRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return nil, false
+	}
+
+	rows, err := readSheetFile(req.File, ',')
+	if err != nil {
+		validationError(ctx, err)
+		return nil, false
+	}
+	return rows, true
+}
+
+// readSheetFile read every row of an uploaded csv or xlsx file within the import limits, csv cells are split by comma
+func readSheetFile(file *multipart.FileHeader, comma rune) ([][]string, error) {
+	format, err := sheet.FormatOf(file.Filename)
+	if err != nil {
+		return nil, err
+	}
+	if file.Size > maxImportFileSize {
+		return nil, fmt.Errorf("file must be at most %d MB", maxImportFileSize>>20)
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rows [][]string
+	if format == sheet.CSV {
+		rows, err = sheet.ReadCSV(f, comma)
+	} else {
+		rows, err = sheet.ReadAll(format, f, file.Size)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) < 2 {
+		return nil, errors.New("file must have a header row and at least one data row")
+	}
+	if len(rows)-1 > maxImportRows {
+		return nil, fmt.Errorf("file must have at most %d data rows", maxImportRows)
+	}
+	return rows, nil
+}
+
+// readImportRows read the uploaded file into one create request per row, validated with the same binding rules as
+// the create endpoint. The first row is the header, columns the spec does not know (such as id) and blank rows are skipped
+func readImportRows[R any, T any](ctx *gin.Context, spec importSpec[R], convert func(req *R) T) ([]domain.ImportRow[T], bool) {
+	rows, ok := readImportFile(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	header := make([]string, len(rows[0]))
+	for i, name := range rows[0] {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+
+	result := make([]domain.ImportRow[T], 0, len(rows)-1)
+	for i, cells := range rows[1:] {
+		line := i + 2
+		if isBlankRow(cells) {
+			continue
+		}
+
+		req := new(R)
+		row := domain.ImportRow[T]{Line: line}
+		failed := map[string]bool{}
+
+		for j, cell := range cells {
+			if j >= len(header) {
+				break
+			}
+			set, ok := spec.columns[header[j]]
+			value := strings.TrimSpace(cell)
+			if !ok || value == "" {
+				continue
+			}
+
+			err := set(req, value)
+			if err != nil {
+				failed[header[j]] = true
+				row.Errors = append(row.Errors, domain.ImportRowError{Line: line, Field: header[j], Message: err.Error()})
+			}
+		}
+
+		err := binding.Validator.ValidateStruct(req)
+		var fieldErrors validator.ValidationErrors
+		if errors.As(err, &fieldErrors) {
+			for _, fe := range fieldErrors {
+				columns, ok := spec.fields[fe.StructField()]
+				if !ok {
+					columns = []string{strings.ToLower(fe.StructField())}
+				}
+				// a column that could not be read already has its error
+				if slices.ContainsFunc(columns, func(c string) bool { return failed[c] }) {
+					continue
+				}
+				row.Errors = append(row.Errors, domain.ImportRowError{Line: line, Field: strings.Join(columns, ", "), Message: describeFieldError(fe)})
+			}
+		} else if err != nil {
+			row.Errors = append(row.Errors, domain.ImportRowError{Line: line, Message: err.Error()})
+		}
+
+		if len(row.Errors) == 0 {
+			row.Data = convert(req)
+		}
+		result = append(result, row)
+	}
+	return result, true
+}
+
+func isBlankRow(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// describeFieldError write the failed binding rule of a field for people filling a spreadsheet
+func describeFieldError(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		if fe.Kind().String() == "string" {
+			return fmt.Sprintf("must be at least %s characters", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max":
+		if fe.Kind().String() == "string" {
+			return fmt.Sprintf("must be at most %s characters", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case "email":
+		return "must be an email address"
+	case "e164":
+		return "must be a phone number in E.164 format such as +84123456789"
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "printascii":
+		return "must only have printable ascii characters"
+	case "location":
+		return "latitude must be between -90 and 90 and longitude between -180 and 180"
+	case "image_file":
+		return "must be the file name of an uploaded image"
+	default:
+		return fmt.Sprintf("failed the %s rule", fe.Tag())
+	}
+}
+
+// missingCoordinate fill the side of a location whose column is empty, it is out of range so the location rule fails
+const missingCoordinate = 1000.0
+
+// setCoordinate set the latitude (i = 0) or longitude (i = 1) of a location
+func setCoordinate(location *[]float64, i int, value string) error {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return errors.New("must be a number")
+	}
+
+	if *location == nil {
+		*location = []float64{missingCoordinate, missingCoordinate}
+	}
+	(*location)[i] = v
+	return nil
+}
+
+var riceImportSpec = importSpec[createRiceRequest]{
+	columns: map[string]func(req *createRiceRequest, value string) error{
+		"name": func(req *createRiceRequest, value string) error { req.Name = value; return nil },
+	},
+	fields: map[string][]string{"Name": {"name"}},
+}
+
+var customerImportSpec = importSpec[createCustomerRequest]{
+	columns: map[string]func(req *createCustomerRequest, value string) error{
+		"name":    func(req *createCustomerRequest, value string) error { req.Name = value; return nil },
+		"email":   func(req *createCustomerRequest, value string) error { req.Email = value; return nil },
+		"phone":   func(req *createCustomerRequest, value string) error { req.Phone = value; return nil },
+		"address": func(req *createCustomerRequest, value string) error { req.Address = value; return nil },
+		"role": func(req *createCustomerRequest, value string) error {
+			req.Role = domain.PartnerRole(strings.ToLower(value))
+			return nil
+		},
+		"tax_code": func(req *createCustomerRequest, value string) error { req.TaxCode = value; return nil },
+	},
+	fields: map[string][]string{
+		"Name": {"name"}, "Email": {"email"}, "Phone": {"phone"}, "Address": {"address"}, "Role": {"role"}, "TaxCode": {"tax_code"},
+	},
+}
+
+var warehouseImportSpec = importSpec[createWarehouseRequest]{
+	columns: map[string]func(req *createWarehouseRequest, value string) error{
+		"name":      func(req *createWarehouseRequest, value string) error { req.Name = value; return nil },
+		"latitude":  func(req *createWarehouseRequest, value string) error { return setCoordinate(&req.Location, 0, value) },
+		"longitude": func(req *createWarehouseRequest, value string) error { return setCoordinate(&req.Location, 1, value) },
+		"image":     func(req *createWarehouseRequest, value string) error { req.Image = value; return nil },
+		"capacity": func(req *createWarehouseRequest, value string) error {
+			v, err := strconv.Atoi(value)
+			if err != nil {
+				return errors.New("must be a whole number")
+			}
+			req.Capacity = v
+			return nil
+		},
+	},
+	fields: map[string][]string{
+		"Name": {"name"}, "Location": {"latitude", "longitude"}, "Image": {"image"}, "Capacity": {"capacity"},
+	},
+}
+
+// bulkImportResponse is the outcome of a bulk import, created has the created rows when it is committed
+type bulkImportResponse struct {
+	Total     int                     `json:"total" example:"120"`
+	Valid     int                     `json:"valid" example:"118"`
+	Committed bool                    `json:"committed" example:"false"`
+	Errors    []domain.ImportRowError `json:"errors"`
+	Created   any                     `json:"created"`
+}
+
+func newBulkImportResponse[T any, R any](result *domain.ImportResult[T], convert func(v *T) R) bulkImportResponse {
+	created := make([]R, 0, len(result.Created))
+	for i := range result.Created {
+		created = append(created, convert(&result.Created[i]))
+	}
+
+	return bulkImportResponse{
+		Total:     result.Total,
+		Valid:     result.Valid,
+		Committed: result.Committed,
+		Errors:    result.Errors,
+		Created:   created,
+	}
+}
+
+// ImportRice ql-kho-lua
+//
+//	@Summary		Import rice from a file
+//	@Description	Create rice from a CSV or XLSX file with a name column. Every row is checked with the rules of POST /rice and against the existing names,
+//	@Description	the rows are created in one transaction only when none has an error and it is not a dry run
+//	@Tags			rice
+//	@Accept			mpfd
+//	@Produce		json
+//	@Param			file	formData	file												true	"CSV or XLSX file, the first row is the header"
+//	@Param			dry_run	query		bool												false	"Check the rows without creating them"
+//	@Success		200		{object}	response{data=bulkImportResponse{created=[]riceResponse}}	"Import result"
+//	@Failure		400		{object}	errorResponse										"Validation error"
+//	@Failure		401		{object}	errorResponse										"Unauthorized error"
+//	@Failure		403		{object}	errorResponse										"Forbidden error"
+//	@Failure		500		{object}	errorResponse										"Internal server error"
+//	@Router			/rice/import [post]
+//	@Security		JWTAuth
+func (b *BulkImportHandler) ImportRice(ctx *gin.Context) {
+	var query bulkImportQuery
+	err := ctx.BindQuery(&query)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	rows, ok := readImportRows(ctx, riceImportSpec, func(req *createRiceRequest) domain.Rice {
+		return domain.Rice{Name: req.Name}
+	})
+	if !ok {
+		return
+	}
+
+	result, err := b.svc.ImportRice(ctx, rows, query.DryRun)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newBulkImportResponse(result, newRiceResponse)
+	handleSuccess(ctx, res)
+}
+
+// ImportCustomers ql-kho-lua
+//
+//	@Summary		Import customers from a file
+//	@Description	Create customers from a CSV or XLSX file with name, email, phone, address, role and tax_code columns. Every row is checked with the rules of POST /customers,
+//	@Description	the rows are created in one transaction only when none has an error and it is not a dry run
+//	@Tags			customers
+//	@Accept			mpfd
+//	@Produce		json
+//	@Param			file	formData	file													true	"CSV or XLSX file, the first row is the header"
+//	@Param			dry_run	query		bool													false	"Check the rows without creating them"
+//	@Success		200		{object}	response{data=bulkImportResponse{created=[]customerResponse}}	"Import result"
+//	@Failure		400		{object}	errorResponse											"Validation error"
+//	@Failure		401		{object}	errorResponse											"Unauthorized error"
+//	@Failure		403		{object}	errorResponse											"Forbidden error"
+//	@Failure		500		{object}	errorResponse											"Internal server error"
+//	@Router			/customers/import [post]
+//	@Security		JWTAuth
+func (b *BulkImportHandler) ImportCustomers(ctx *gin.Context) {
+	var query bulkImportQuery
+	err := ctx.BindQuery(&query)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	rows, ok := readImportRows(ctx, customerImportSpec, func(req *createCustomerRequest) domain.Customer {
+		return domain.Customer{
+			Name:    req.Name,
+			Email:   req.Email,
+			Phone:   req.Phone,
+			Address: req.Address,
+			Role:    req.Role,
+			TaxCode: req.TaxCode,
+		}
+	})
+	if !ok {
+		return
+	}
+
+	result, err := b.svc.ImportCustomers(ctx, rows, query.DryRun)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newBulkImportResponse(result, newCustomerResponse)
+	handleSuccess(ctx, res)
+}
+
+// ImportWarehouses ql-kho-lua
+//
+//	@Summary		Import warehouses from a file
+//	@Description	Create warehouses from a CSV or XLSX file with name, latitude, longitude, capacity and image columns, images are uploaded first with POST /upload.
+//	@Description	Every row is checked with the rules of POST /warehouses and against the existing names, the rows are created in one transaction only when none has an error and it is not a dry run
+//	@Tags			warehouses
+//	@Accept			mpfd
+//	@Produce		json
+//	@Param			file	formData	file														true	"CSV or XLSX file, the first row is the header"
+//	@Param			dry_run	query		bool														false	"Check the rows without creating them"
+//	@Success		200		{object}	response{data=bulkImportResponse{created=[]warehouseResponse}}	"Import result"
+//	@Failure		400		{object}	errorResponse												"Validation error"
+//	@Failure		401		{object}	errorResponse												"Unauthorized error"
+//	@Failure		403		{object}	errorResponse												"Forbidden error"
+//	@Failure		500		{object}	errorResponse												"Internal server error"
+//	@Router			/warehouses/import [post]
+//	@Security		JWTAuth
+func (b *BulkImportHandler) ImportWarehouses(ctx *gin.Context) {
+	var query bulkImportQuery
+	err := ctx.BindQuery(&query)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	rows, ok := readImportRows(ctx, warehouseImportSpec, func(req *createWarehouseRequest) domain.Warehouse {
+		return domain.Warehouse{
+			Name:     req.Name,
+			Location: fmt.Sprintf("%v, %v", req.Location[0], req.Location[1]),
+			Capacity: req.Capacity,
+			Image:    req.Image,
+		}
+	})
+	if !ok {
+		return
+	}
+
+	result, err := b.svc.ImportWarehouses(ctx, rows, query.DryRun)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newBulkImportResponse(result, newWarehouseResponse)
+	handleSuccess(ctx, res)
+}
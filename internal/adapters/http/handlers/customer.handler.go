@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/sheet"
 	"github.com/tommjj/ql-kho-lua/internal/core/domain"
 	"github.com/tommjj/ql-kho-lua/internal/core/ports"
 )
@@ -20,10 +21,12 @@ func NewCustomerHandler(customerService ports.ICustomerService) *CustomerHandler
 }
 
 type createCustomerRequest struct {
-	Name    string `json:"name" binding:"required,min=3,max=255" example:"Sentenced"`
-	Email   string `json:"email" binding:"required,email" example:"example@exp.com"`
-	Phone   string `json:"phone" binding:"required,e164" example:"+84123456789"`
-	Address string `json:"address" binding:"required,min=1,max=255" example:"abc, xyz"`
+	Name    string             `json:"name" binding:"required,min=3,max=255" example:"Sentenced"`
+	Email   string             `json:"email" binding:"required,email" example:"example@exp.com"`
+	Phone   string             `json:"phone" binding:"required,e164" example:"+84123456789"`
+	Address string             `json:"address" binding:"required,min=1,max=255" example:"abc, xyz"`
+	Role    domain.PartnerRole `json:"role" binding:"omitempty,oneof=supplier customer both" example:"supplier"`
+	TaxCode string             `json:"tax_code" binding:"omitempty,printascii,max=20" example:"0101234567"`
 }
 
 // CreateCustomer ql-kho-lua
@@ -56,6 +59,8 @@ func (c *CustomerHandler) CreateCustomer(ctx *gin.Context) {
 		Email:   req.Email,
 		Phone:   req.Phone,
 		Address: req.Address,
+		Role:    req.Role,
+		TaxCode: req.TaxCode,
 	})
 	if err != nil {
 		handleError(ctx, err)
@@ -102,21 +107,25 @@ func (c *CustomerHandler) GetCustomerByID(ctx *gin.Context) {
 }
 
 type getListCustomerRequest struct {
-	Query string `form:"q" binding:"" example:"teo"`
-	Skip  int    `form:"skip" binding:"min=1" example:"1"`
-	Limit int    `form:"limit" binding:"min=5" example:"5"`
+	Query  string             `form:"q" binding:"" example:"teo"`
+	Role   domain.PartnerRole `form:"role" binding:"omitempty,oneof=supplier customer both" example:"customer"`
+	Skip   int                `form:"skip" binding:"min=1" example:"1"`
+	Limit  int                `form:"limit" binding:"min=5" example:"5"`
+	Format sheet.Format       `form:"format" binding:"omitempty,oneof=csv xlsx" example:"csv"`
 }
 
 // GetListCustomers ql-kho-lua
 //
 //	@Summary		get customers
-//	@Description	get customers with pagination
+//	@Description	get customers with pagination, a role lists the partners that can act as it
 //	@Tags			customers
 //	@Accept			json
 //	@Produce		json
 //	@Param			q		query		string											false	"Query"
+//	@Param			role	query		string											false	"Partner role"	Enums(supplier, customer, both)
 //	@Param			skip	query		int												false	"Skip"	default(1)	minimum(1)
 //	@Param			limit	query		int												false	"Limit"	default(5)	minimum(5)
+//	@Param			format	query		string											false	"Export every matching row as a file instead of a page"	Enums(csv, xlsx)
 //	@Success		200		{object}	responseWithPagination{data=[]customerResponse}	"Customers data"
 //	@Failure		400		{object}	errorResponse									"Validation error"
 //	@Failure		401		{object}	errorResponse									"Unauthorized error"
@@ -136,7 +145,67 @@ func (c *CustomerHandler) GetListCustomers(ctx *gin.Context) {
 		return
 	}
 
-	count, err := c.svc.CountCustomers(ctx, req.Query)
+	c.listCustomers(ctx, req)
+}
+
+// GetListSuppliers ql-kho-lua
+//
+//	@Summary		get suppliers
+//	@Description	get the partners that can supply rice (suppliers and partners with both roles) with pagination
+//	@Tags			customers
+//	@Accept			json
+//	@Produce		json
+//	@Param			q		query		string											false	"Query"
+//	@Param			skip	query		int												false	"Skip"	default(1)	minimum(1)
+//	@Param			limit	query		int												false	"Limit"	default(5)	minimum(5)
+//	@Param			format	query		string											false	"Export every matching row as a file instead of a page"	Enums(csv, xlsx)
+//	@Success		200		{object}	responseWithPagination{data=[]customerResponse}	"Suppliers data"
+//	@Failure		400		{object}	errorResponse									"Validation error"
+//	@Failure		401		{object}	errorResponse									"Unauthorized error"
+//	@Failure		403		{object}	errorResponse									"Forbidden error"
+//	@Failure		404		{object}	errorResponse									"Data not found error"
+//	@Failure		500		{object}	errorResponse									"Internal server error"
+//	@Router			/suppliers [get]
+//	@Security		JWTAuth
+func (c *CustomerHandler) GetListSuppliers(ctx *gin.Context) {
+	req := getListCustomerRequest{
+		Skip:  1,
+		Limit: 5,
+	}
+	err := ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	req.Role = domain.PartnerSupplier
+	c.listCustomers(ctx, req)
+}
+
+// listCustomers write a page of the partners matching the request, or all of them as a file when a format is set
+func (c *CustomerHandler) listCustomers(ctx *gin.Context, req getListCustomerRequest) {
+	if req.Format != "" {
+		name := "customers"
+		if req.Role == domain.PartnerSupplier {
+			name = "suppliers"
+		}
+
+		exportList(ctx, req.Format, name, customerExportHeader, func(afterID, limit int) ([][]any, int, error) {
+			customers, err := c.svc.GetListCustomersAfter(ctx, req.Query, req.Role, afterID, limit)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			rows := make([][]any, 0, len(customers))
+			for _, customer := range customers {
+				rows = append(rows, newCustomerExportRow(&customer))
+			}
+			return rows, customers[len(customers)-1].ID, nil
+		})
+		return
+	}
+
+	count, err := c.svc.CountCustomers(ctx, req.Query, req.Role)
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -147,7 +216,7 @@ func (c *CustomerHandler) GetListCustomers(ctx *gin.Context) {
 		return
 	}
 
-	customers, err := c.svc.GetListCustomers(ctx, req.Query, req.Limit, req.Skip)
+	customers, err := c.svc.GetListCustomers(ctx, req.Query, req.Role, req.Limit, req.Skip)
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -163,10 +232,12 @@ func (c *CustomerHandler) GetListCustomers(ctx *gin.Context) {
 }
 
 type updateCustomerRequest struct {
-	Name    string `json:"name" binding:"omitempty,min=3,max=255" example:"Sentenced"`
-	Email   string `json:"email" binding:"omitempty,email" example:"example@exp.com"`
-	Phone   string `json:"phone" binding:"omitempty,e164" example:"+84123456789"`
-	Address string `json:"address" binding:"omitempty,min=1,max=255" example:"abc, xyz"`
+	Name    string             `json:"name" binding:"omitempty,min=3,max=255" example:"Sentenced"`
+	Email   string             `json:"email" binding:"omitempty,email" example:"example@exp.com"`
+	Phone   string             `json:"phone" binding:"omitempty,e164" example:"+84123456789"`
+	Address string             `json:"address" binding:"omitempty,min=1,max=255" example:"abc, xyz"`
+	Role    domain.PartnerRole `json:"role" binding:"omitempty,oneof=supplier customer both" example:"both"`
+	TaxCode string             `json:"tax_code" binding:"omitempty,printascii,max=20" example:"0101234567"`
 }
 
 // UpdateCustomer ql-kho-lua
@@ -209,6 +280,8 @@ func (c *CustomerHandler) UpdateCustomer(ctx *gin.Context) {
 		Email:   req.Email,
 		Phone:   req.Phone,
 		Address: req.Address,
+		Role:    req.Role,
+		TaxCode: req.TaxCode,
 	})
 	if err != nil {
 		handleError(ctx, err)
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/sheet"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+// exportPageSize is the number of records read per query while a list is exported
+const exportPageSize = 500
+
+// exportPage return the rows of up to limit records with an id greater than afterID in id order,
+// and the id of the last record read
+type exportPage func(afterID, limit int) (rows [][]any, lastID int, err error)
+
+// exportList write every page of a list as a csv or xlsx attachment, there is no page limit.
+// Pages are read after the last id of the previous one, so rows created or deleted during
+// the export do not shift the next page and no row is written twice or skipped.
+// The first page is read before anything is written so its error is still answered with json,
+// a later error can only end the file early and is logged
+func exportList(ctx *gin.Context, format sheet.Format, name string, header []any, next exportPage) {
+	rows, lastID, err := next(0, exportPageSize)
+	if err != nil && err != domain.ErrDataNotFound {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Type", format.ContentType())
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.%s"`, name, time.Now().Format("20060102"), format))
+	ctx.Status(http.StatusOK)
+
+	w, err := sheet.NewWriter(format, ctx.Writer, name)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	err = w.Write(header)
+	for err == nil && len(rows) > 0 {
+		for _, row := range rows {
+			err = w.Write(row)
+			if err != nil {
+				break
+			}
+		}
+		if err != nil {
+			break
+		}
+
+		rows, lastID, err = next(lastID, exportPageSize)
+		if err == domain.ErrDataNotFound {
+			rows, err = nil, nil
+		}
+	}
+	if err != nil {
+		_ = ctx.Error(err)
+	}
+
+	err = w.Close()
+	if err != nil {
+		_ = ctx.Error(err)
+	}
+}
+
+var userExportHeader = []any{"id", "name", "email", "phone", "role"}
+
+func newUserExportRow(user *domain.User) []any {
+	return []any{user.ID, user.Name, user.Email, user.Phone, string(user.Role)}
+}
+
+var warehouseExportHeader = []any{"id", "name", "latitude", "longitude", "capacity", "image"}
+
+func newWarehouseExportRow(store *domain.Warehouse) []any {
+	latitude, longitude, _ := store.ParseLocation()
+	return []any{store.ID, store.Name, latitude, longitude, store.Capacity, store.Image}
+}
+
+var riceExportHeader = []any{"id", "name"}
+
+func newRiceExportRow(rice *domain.Rice) []any {
+	return []any{rice.ID, rice.Name}
+}
+
+var customerExportHeader = []any{"id", "name", "role", "email", "phone", "address", "tax_code"}
+
+func newCustomerExportRow(customer *domain.Customer) []any {
+	return []any{customer.ID, customer.Name, string(customer.Role), customer.Email, customer.Phone, customer.Address, customer.TaxCode}
+}
+
+var invoiceExportHeader = []any{
+	"id", "created_at", "status", "warehouse_id", "warehouse_name", "customer_id", "customer_name", "user_id", "user_name",
+	"quantity", "total_price", "paid_amount", "balance", "payment_status", "cancel_reason",
+}
+
+// invoiceDetailExportHeader is the header of invoice exports with one row per detail line
+var invoiceDetailExportHeader = append(append([]any{}, invoiceExportHeader...),
+	"line", "rice_id", "rice_name", "line_quantity", "price", "amount",
+)
+
+// newInvoiceExportRows return one row of the invoice, or one row per detail line with the invoice
+// columns repeated when details is set. An invoice without details still has one row
+func newInvoiceExportRows(invoice *domain.Invoice, details bool) [][]any {
+	var warehouseName, customerName, userName string
+	if invoice.Warehouse != nil {
+		warehouseName = invoice.Warehouse.Name
+	}
+	if invoice.Customer != nil {
+		customerName = invoice.Customer.Name
+	}
+	if invoice.CreatedBy != nil {
+		userName = invoice.CreatedBy.Name
+	}
+
+	row := []any{
+		invoice.ID, invoice.CreatedAt, string(invoice.Status),
+		invoice.WarehouseID, warehouseName, invoice.CustomerID, customerName, invoice.UserID, userName,
+		invoice.Quantity(), invoice.TotalPrice, invoice.PaidAmount, invoice.Balance(), string(invoice.PaymentStatus()), invoice.CancelReason,
+	}
+	if !details {
+		return [][]any{row}
+	}
+	if len(invoice.Details) == 0 {
+		return [][]any{append(row, nil, nil, nil, nil, nil, nil)}
+	}
+
+	rows := make([][]any, 0, len(invoice.Details))
+	for i, v := range invoice.Details {
+		riceName := ""
+		if v.Rice != nil {
+			riceName = v.Rice.Name
+		}
+
+		line := append(append(make([]any, 0, len(row)+6), row...),
+			i+1, v.RiceID, riceName, v.Quantity, v.Price, v.Price*float64(v.Quantity),
+		)
+		rows = append(rows, line)
+	}
+	return rows
+}
+
+// newInvoiceExportPage read the pages of an invoice export from list
+func newInvoiceExportPage(list func(afterID, limit int) ([]domain.Invoice, error), details bool) exportPage {
+	return func(afterID, limit int) ([][]any, int, error) {
+		invoices, err := list(afterID, limit)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		rows := make([][]any, 0, len(invoices))
+		for _, v := range invoices {
+			rows = append(rows, newInvoiceExportRows(&v, details)...)
+		}
+		return rows, invoices[len(invoices)-1].ID, nil
+	}
+}
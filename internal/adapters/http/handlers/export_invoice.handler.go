@@ -6,26 +6,36 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/sheet"
 	"github.com/tommjj/ql-kho-lua/internal/core/domain"
 	"github.com/tommjj/ql-kho-lua/internal/core/ports"
 )
 
 type ExportInvoiceHandler struct {
-	svc ports.IExportInvoiceService
-	acc ports.IAccessControlService
+	svc      ports.IExportInvoiceService
+	acc      ports.IAccessControlService
+	renderer ports.IInvoiceRenderer
 }
 
-func NewExportInvoiceHandler(svc ports.IExportInvoiceService, acc ports.IAccessControlService) *ExportInvoiceHandler {
+func NewExportInvoiceHandler(svc ports.IExportInvoiceService, acc ports.IAccessControlService, renderer ports.IInvoiceRenderer) *ExportInvoiceHandler {
 	return &ExportInvoiceHandler{
-		svc: svc,
-		acc: acc,
+		svc:      svc,
+		acc:      acc,
+		renderer: renderer,
 	}
 }
 
+type lotAllocationRequest struct {
+	LotID    int `json:"lot_id" binding:"required,min=1" example:"1"`
+	Quantity int `json:"quantity" binding:"required,min=1" example:"5"`
+}
+
 type detailExInvoiceRequest struct {
 	RiceID   int     `json:"rice_id" binding:"required"`
 	Price    float64 `json:"price" binding:"required,min=1"`
 	Quantity int     `json:"quantity" binding:"required,min=1"`
+	// Lots name the lots to take the quantity from, they are chosen first-expiry-first-out when empty
+	Lots []lotAllocationRequest `json:"lots" binding:"omitempty,unique=LotID,dive"`
 }
 
 type createExInvoiceRequest struct {
@@ -38,16 +48,20 @@ type createExInvoiceRequest struct {
 //
 //	@Summary		Create a new export invoice and get created invoice data
 //	@Description	Create a new export invoice and get created invoice data
+//	@Description	A request sent again with the same Idempotency-Key gets the first response back with the Idempotent-Replayed header instead of creating another invoice
 //	@Tags			exportInvoices
 //	@Accept			json
 //	@Produce		json
-//	@Param			request	body		CreateExInvoiceRequest			true	"Create invoice body"
+//	@Param			Idempotency-Key	header		string							false	"Key of the request, at most 255 characters, the response is replayed for IDEMPOTENCY_WINDOW"
+//	@Param			request			body		CreateExInvoiceRequest			true	"Create invoice body"
 //	@Success		200		{object}	response{data=invoiceResponse}	"Created invoice data"
 //	@Failure		400		{object}	errorResponse					"Validation error"
 //	@Failure		401		{object}	errorResponse					"Unauthorized error"
 //	@Failure		403		{object}	errorResponse					"Forbidden error"
 //	@Failure		404		{object}	errorResponse					"Data not found error"
 //	@Failure		409		{object}	errorResponse					"Conflicting data error"
+//	@Failure		413		{object}	errorResponse					"Request body too large"
+//	@Failure		422		{object}	errorResponse					"Idempotency key reused with a different request"
 //	@Failure		500		{object}	errorResponse					"Internal server error"
 //	@Router			/export_invoices  [post]
 //	@Security		JWTAuth
@@ -63,7 +77,7 @@ func (e *ExportInvoiceHandler) CreateExInvoice(ctx *gin.Context) {
 
 	isRootUser := token.Role == domain.Root
 	if !isRootUser {
-		err := e.acc.HasAccess(ctx, req.WarehouseID, token.ID)
+		err := e.acc.HasAccess(ctx, req.WarehouseID, token.ID, domain.ActionExport)
 		if err != nil {
 			handleError(ctx, err)
 			return
@@ -77,11 +91,15 @@ func (e *ExportInvoiceHandler) CreateExInvoice(ctx *gin.Context) {
 		Details:     make([]domain.InvoiceItem, 0, len(req.Details)),
 	}
 	for _, v := range req.Details {
-		createInvData.Details = append(createInvData.Details, domain.InvoiceItem{
+		item := domain.InvoiceItem{
 			Price:    v.Price,
 			Quantity: v.Quantity,
 			RiceID:   v.RiceID,
-		})
+		}
+		for _, lot := range v.Lots {
+			item.Lots = append(item.Lots, domain.LotAllocation{LotID: lot.LotID, Quantity: lot.Quantity})
+		}
+		createInvData.Details = append(createInvData.Details, item)
 	}
 
 	created, err := e.svc.CreateExInvoice(ctx, createInvData)
@@ -94,6 +112,149 @@ func (e *ExportInvoiceHandler) CreateExInvoice(ctx *gin.Context) {
 	handleSuccess(ctx, res)
 }
 
+// CancelExInvoice ql-kho-lua
+//
+//	@Summary		Cancel a export invoice
+//	@Description	Cancel a export invoice and roll back its stock movement
+//	@Tags			exportInvoices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int								true	"Invoice id"
+//	@Param			request	body		cancelInvoiceRequest			true	"Cancel invoice body"
+//	@Success		200		{object}	response{data=invoiceResponse}	"Cancelled invoice data"
+//	@Failure		400		{object}	errorResponse					"Validation error"
+//	@Failure		401		{object}	errorResponse					"Unauthorized error"
+//	@Failure		403		{object}	errorResponse					"Forbidden error"
+//	@Failure		404		{object}	errorResponse					"Data not found error"
+//	@Failure		409		{object}	errorResponse					"Conflicting data error"
+//	@Failure		500		{object}	errorResponse					"Internal server error"
+//	@Router			/export_invoices/{id}/cancel  [post]
+//	@Security		JWTAuth
+func (e *ExportInvoiceHandler) CancelExInvoice(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	numID, err := strconv.Atoi(id)
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	var req cancelInvoiceRequest
+	err = ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	isRootUser := token.Role == domain.Root
+	if !isRootUser {
+		inv, err := e.svc.GetExInvoiceByID(ctx, numID)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+
+		err = e.acc.HasAccess(ctx, inv.WarehouseID, token.ID, domain.ActionExport)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+	}
+
+	inv, err := e.svc.CancelExInvoice(ctx, numID, req.Reason)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newInvoiceResponse(inv)
+	handleSuccess(ctx, res)
+}
+
+// ApproveExInvoice ql-kho-lua
+//
+//	@Summary		Approve a export invoice
+//	@Description	Approve a export invoice waiting for approval and apply its stock movement
+//	@Tags			exportInvoices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int								true	"Invoice id"
+//	@Success		200	{object}	response{data=invoiceResponse}	"Approved invoice data"
+//	@Failure		400	{object}	errorResponse					"Validation error"
+//	@Failure		401	{object}	errorResponse					"Unauthorized error"
+//	@Failure		403	{object}	errorResponse					"Forbidden error"
+//	@Failure		404	{object}	errorResponse					"Data not found error"
+//	@Failure		409	{object}	errorResponse					"Conflicting data error"
+//	@Failure		500	{object}	errorResponse					"Internal server error"
+//	@Router			/export_invoices/{id}/approve  [post]
+//	@Security		JWTAuth
+func (e *ExportInvoiceHandler) ApproveExInvoice(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	numID, err := strconv.Atoi(id)
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	inv, err := e.svc.ApproveExInvoice(ctx, numID, token.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newInvoiceResponse(inv)
+	handleSuccess(ctx, res)
+}
+
+// RejectExInvoice ql-kho-lua
+//
+//	@Summary		Reject a export invoice
+//	@Description	Reject a export invoice waiting for approval, it never moves stock
+//	@Tags			exportInvoices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int								true	"Invoice id"
+//	@Param			request	body		cancelInvoiceRequest			true	"Reject invoice body"
+//	@Success		200		{object}	response{data=invoiceResponse}	"Rejected invoice data"
+//	@Failure		400		{object}	errorResponse					"Validation error"
+//	@Failure		401		{object}	errorResponse					"Unauthorized error"
+//	@Failure		403		{object}	errorResponse					"Forbidden error"
+//	@Failure		404		{object}	errorResponse					"Data not found error"
+//	@Failure		409		{object}	errorResponse					"Conflicting data error"
+//	@Failure		500		{object}	errorResponse					"Internal server error"
+//	@Router			/export_invoices/{id}/reject  [post]
+//	@Security		JWTAuth
+func (e *ExportInvoiceHandler) RejectExInvoice(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	numID, err := strconv.Atoi(id)
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	var req cancelInvoiceRequest
+	err = ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	inv, err := e.svc.RejectExInvoice(ctx, numID, req.Reason)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newInvoiceResponse(inv)
+	handleSuccess(ctx, res)
+}
+
 // GetExInvoiceByID ql-kho-lua
 //
 //	@Summary		Get a export invoice by id
@@ -129,7 +290,7 @@ func (e *ExportInvoiceHandler) GetExInvoiceByID(ctx *gin.Context) {
 
 	isRootUser := token.Role == domain.Root
 	if !isRootUser {
-		err := e.acc.HasAccess(ctx, inv.WarehouseID, token.ID)
+		err := e.acc.HasAccess(ctx, inv.WarehouseID, token.ID, domain.ActionRead)
 		if err != nil {
 			handleError(ctx, err)
 			return
@@ -140,12 +301,57 @@ func (e *ExportInvoiceHandler) GetExInvoiceByID(ctx *gin.Context) {
 	handleSuccess(ctx, res)
 }
 
+// GetExInvoicePDF ql-kho-lua
+//
+//	@Summary		Print an export invoice
+//	@Description	Render an export invoice as a PDF with the company header, warehouse, customer, lines, totals and creating user
+//	@Tags			exportInvoices
+//	@Accept			json
+//	@Produce		application/pdf
+//	@Param			id	path		int				true	"Invoice id"
+//	@Success		200	{file}		file			"Invoice PDF"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		403	{object}	errorResponse	"Forbidden error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/export_invoices/{id}/pdf  [get]
+//	@Security		JWTAuth
+func (e *ExportInvoiceHandler) GetExInvoicePDF(ctx *gin.Context) {
+	numID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	inv, err := e.svc.GetExInvoiceByID(ctx, numID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	isRootUser := token.Role == domain.Root
+	if !isRootUser {
+		err := e.acc.HasAccess(ctx, inv.WarehouseID, token.ID, domain.ActionRead)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+	}
+
+	writeInvoicePDF(ctx, e.renderer, domain.InvoiceKindExport, inv)
+}
+
 type getListExInvoiceRequest struct {
-	WarehouseID int        `form:"warehouse_id" binding:"omitempty,min=0"`
-	Start       *time.Time `form:"start" binding:"omitempty"`
-	End         *time.Time `form:"end" binding:"omitempty"`
-	Skip        int        `form:"skip" binding:"min=1" example:"1"`
-	Limit       int        `form:"limit" binding:"min=5" example:"5"`
+	WarehouseID int          `form:"warehouse_id" binding:"omitempty,min=0"`
+	Start       *time.Time   `form:"start" binding:"omitempty"`
+	End         *time.Time   `form:"end" binding:"omitempty"`
+	Skip        int          `form:"skip" binding:"min=1" example:"1"`
+	Limit       int          `form:"limit" binding:"min=5" example:"5"`
+	Format      sheet.Format `form:"format" binding:"omitempty,oneof=csv xlsx" example:"csv"`
+	Details     bool         `form:"details" binding:"omitempty" example:"true"`
 }
 
 // GetListExInvoices ql-kho-lua
@@ -160,6 +366,8 @@ type getListExInvoiceRequest struct {
 //	@Param			limit			query		int												false	"Limit"	default(5)	minimum(5)
 //	@Param			start			query		string											false	"Start"	format(date-time)
 //	@Param			end				query		string											false	"End"	format(date-time)
+//	@Param			format			query		string											false	"Export every matching invoice as a file instead of a page"	Enums(csv, xlsx)
+//	@Param			details			query		bool											false	"Export one row per detail line"
 //	@Success		200				{object}	responseWithPagination{data=[]invoiceResponse}	"Invoice data"
 //	@Failure		400				{object}	errorResponse									"Validation error"
 //	@Failure		401				{object}	errorResponse									"Unauthorized error"
@@ -187,13 +395,26 @@ func (e *ExportInvoiceHandler) GetListExInvoices(ctx *gin.Context) {
 			handleError(ctx, domain.ErrForbidden)
 			return
 		}
-		err := e.acc.HasAccess(ctx, req.WarehouseID, token.ID)
+		err := e.acc.HasAccess(ctx, req.WarehouseID, token.ID, domain.ActionRead)
 		if err != nil {
 			handleError(ctx, err)
 			return
 		}
 	}
 
+	if req.Format != "" {
+		list := func(afterID, limit int) ([]domain.Invoice, error) {
+			return e.svc.GetListExInvoicesWithAssociations(ctx, req.WarehouseID, req.Start, req.End, afterID, limit)
+		}
+
+		header := invoiceExportHeader
+		if req.Details {
+			header = invoiceDetailExportHeader
+		}
+		exportList(ctx, req.Format, "export_invoices", header, newInvoiceExportPage(list, req.Details))
+		return
+	}
+
 	count, err := e.svc.CountExInvoices(ctx, req.WarehouseID, req.Start, req.End)
 	if err != nil {
 		handleError(ctx, err)
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/sheet"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+func TestExportList_PagesAfterLastID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ids := []int{}
+	for i := 1; i <= exportPageSize+10; i++ {
+		ids = append(ids, i)
+	}
+
+	pages := 0
+	next := func(afterID, limit int) ([][]any, int, error) {
+		pages++
+		if pages == 2 {
+			// a row of the first page is deleted while the file is written
+			ids = ids[1:]
+		}
+
+		rows := [][]any{}
+		last := 0
+		for _, id := range ids {
+			if id > afterID && len(rows) < limit {
+				rows = append(rows, []any{id})
+				last = id
+			}
+		}
+		if len(rows) == 0 {
+			return nil, 0, domain.ErrDataNotFound
+		}
+		return rows, last, nil
+	}
+
+	r := gin.New()
+	r.GET("/", func(ctx *gin.Context) {
+		exportList(ctx, sheet.CSV, "test", []any{"id"}, next)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	lines := strings.Split(strings.TrimSpace(strings.TrimPrefix(w.Body.String(), "\ufeff")), "\n")
+	assert.Equal(t, exportPageSize+11, len(lines))
+	assert.Equal(t, "501", strings.TrimSpace(lines[exportPageSize+1]))
+	assert.Equal(t, 3, pages)
+}
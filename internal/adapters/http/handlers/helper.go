@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"bytes"
+	"fmt"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
 )
 
 // getAuthPayload is a helper function to get the auth payload from the context
@@ -21,3 +26,17 @@ func isPageOutOfRange(count int64, limit, skip int) bool {
 
 	return count < int64(start)
 }
+
+// writeInvoicePDF render the invoice before writing anything, so a failed render is still answered with a json error
+func writeInvoicePDF(ctx *gin.Context, renderer ports.IInvoiceRenderer, kind domain.InvoiceKind, invoice *domain.Invoice) {
+	buf := &bytes.Buffer{}
+	err := renderer.RenderInvoice(buf, kind, invoice)
+	if err != nil {
+		_ = ctx.Error(err)
+		handleError(ctx, domain.ErrInternal)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s-invoice-%d.pdf"`, kind, invoice.ID))
+	ctx.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
@@ -6,26 +6,34 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/sheet"
 	"github.com/tommjj/ql-kho-lua/internal/core/domain"
 	"github.com/tommjj/ql-kho-lua/internal/core/ports"
 )
 
 type ImportInvoiceHandler struct {
-	svc ports.IImportInvoicesService
-	acc ports.IAccessControlService
+	svc      ports.IImportInvoicesService
+	acc      ports.IAccessControlService
+	renderer ports.IInvoiceRenderer
 }
 
-func NewImportInvoiceHandler(svc ports.IImportInvoicesService, acc ports.IAccessControlService) *ImportInvoiceHandler {
+func NewImportInvoiceHandler(svc ports.IImportInvoicesService, acc ports.IAccessControlService, renderer ports.IInvoiceRenderer) *ImportInvoiceHandler {
 	return &ImportInvoiceHandler{
-		svc: svc,
-		acc: acc,
+		svc:      svc,
+		acc:      acc,
+		renderer: renderer,
 	}
 }
 
 type DetailImInvoiceRequest struct {
-	RiceID   int     `json:"rice_id" binding:"required"`
-	Price    float64 `json:"price" binding:"required,min=1"`
-	Quantity int     `json:"quantity" binding:"required,min=1"`
+	RiceID      int        `json:"rice_id" binding:"required"`
+	Price       float64    `json:"price" binding:"required,min=1"`
+	Quantity    int        `json:"quantity" binding:"required,min=1"`
+	LotNumber   string     `json:"lot_number" binding:"omitempty,max=50" example:"ST25-2409-A"`
+	HarvestDate *time.Time `json:"harvest_date" binding:"omitempty" example:"2024-09-01T00:00:00Z"`
+	ExpiryDate  *time.Time `json:"expiry_date" binding:"omitempty" example:"2025-09-01T00:00:00Z"`
+	Grade       string     `json:"grade" binding:"omitempty,max=20" example:"A"`
+	Moisture    float64    `json:"moisture" binding:"omitempty,min=0,max=100" example:"14.5"`
 }
 
 type CreateImInvoiceRequest struct {
@@ -38,16 +46,20 @@ type CreateImInvoiceRequest struct {
 //
 //	@Summary		Create a new import invoice and get created invoice data
 //	@Description	Create a new import invoice and get created invoice data
+//	@Description	A request sent again with the same Idempotency-Key gets the first response back with the Idempotent-Replayed header instead of creating another invoice
 //	@Tags			importInvoices
 //	@Accept			json
 //	@Produce		json
-//	@Param			request	body		CreateImInvoiceRequest			true	"Create invoice body"
+//	@Param			Idempotency-Key	header		string							false	"Key of the request, at most 255 characters, the response is replayed for IDEMPOTENCY_WINDOW"
+//	@Param			request			body		CreateImInvoiceRequest			true	"Create invoice body"
 //	@Success		200		{object}	response{data=invoiceResponse}	"Created invoice data"
 //	@Failure		400		{object}	errorResponse					"Validation error"
 //	@Failure		401		{object}	errorResponse					"Unauthorized error"
 //	@Failure		403		{object}	errorResponse					"Forbidden error"
 //	@Failure		404		{object}	errorResponse					"Data not found error"
 //	@Failure		409		{object}	errorResponse					"Conflicting data error"
+//	@Failure		413		{object}	errorResponse					"Request body too large"
+//	@Failure		422		{object}	errorResponse					"Idempotency key reused with a different request"
 //	@Failure		500		{object}	errorResponse					"Internal server error"
 //	@Router			/import_invoices  [post]
 //	@Security		JWTAuth
@@ -63,7 +75,7 @@ func (i *ImportInvoiceHandler) CreateImInvoice(ctx *gin.Context) {
 
 	isRootUser := token.Role == domain.Root
 	if !isRootUser {
-		err := i.acc.HasAccess(ctx, req.WarehouseID, token.ID)
+		err := i.acc.HasAccess(ctx, req.WarehouseID, token.ID, domain.ActionImport)
 		if err != nil {
 			handleError(ctx, err)
 			return
@@ -81,6 +93,13 @@ func (i *ImportInvoiceHandler) CreateImInvoice(ctx *gin.Context) {
 			Price:    v.Price,
 			Quantity: v.Quantity,
 			RiceID:   v.RiceID,
+			Lot: &domain.Lot{
+				LotNumber:   v.LotNumber,
+				HarvestDate: v.HarvestDate,
+				ExpiryDate:  v.ExpiryDate,
+				Grade:       v.Grade,
+				Moisture:    v.Moisture,
+			},
 		})
 	}
 
@@ -94,6 +113,153 @@ func (i *ImportInvoiceHandler) CreateImInvoice(ctx *gin.Context) {
 	handleSuccess(ctx, res)
 }
 
+type cancelInvoiceRequest struct {
+	Reason string `json:"reason" binding:"required,min=1,max=255" example:"wrong quantity"`
+}
+
+// CancelImInvoice ql-kho-lua
+//
+//	@Summary		Cancel a import invoice
+//	@Description	Cancel a import invoice and roll back its stock movement
+//	@Tags			importInvoices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int								true	"Invoice id"
+//	@Param			request	body		cancelInvoiceRequest			true	"Cancel invoice body"
+//	@Success		200		{object}	response{data=invoiceResponse}	"Cancelled invoice data"
+//	@Failure		400		{object}	errorResponse					"Validation error"
+//	@Failure		401		{object}	errorResponse					"Unauthorized error"
+//	@Failure		403		{object}	errorResponse					"Forbidden error"
+//	@Failure		404		{object}	errorResponse					"Data not found error"
+//	@Failure		409		{object}	errorResponse					"Conflicting data error"
+//	@Failure		500		{object}	errorResponse					"Internal server error"
+//	@Router			/import_invoices/{id}/cancel  [post]
+//	@Security		JWTAuth
+func (i *ImportInvoiceHandler) CancelImInvoice(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	numID, err := strconv.Atoi(id)
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	var req cancelInvoiceRequest
+	err = ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	isRootUser := token.Role == domain.Root
+	if !isRootUser {
+		inv, err := i.svc.GetImInvoiceByID(ctx, numID)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+
+		err = i.acc.HasAccess(ctx, inv.WarehouseID, token.ID, domain.ActionImport)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+	}
+
+	inv, err := i.svc.CancelImInvoice(ctx, numID, req.Reason)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newInvoiceResponse(inv)
+	handleSuccess(ctx, res)
+}
+
+// ApproveImInvoice ql-kho-lua
+//
+//	@Summary		Approve a import invoice
+//	@Description	Approve a import invoice waiting for approval and apply its stock movement
+//	@Tags			importInvoices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int								true	"Invoice id"
+//	@Success		200	{object}	response{data=invoiceResponse}	"Approved invoice data"
+//	@Failure		400	{object}	errorResponse					"Validation error"
+//	@Failure		401	{object}	errorResponse					"Unauthorized error"
+//	@Failure		403	{object}	errorResponse					"Forbidden error"
+//	@Failure		404	{object}	errorResponse					"Data not found error"
+//	@Failure		409	{object}	errorResponse					"Conflicting data error"
+//	@Failure		500	{object}	errorResponse					"Internal server error"
+//	@Router			/import_invoices/{id}/approve  [post]
+//	@Security		JWTAuth
+func (i *ImportInvoiceHandler) ApproveImInvoice(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	numID, err := strconv.Atoi(id)
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	inv, err := i.svc.ApproveImInvoice(ctx, numID, token.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newInvoiceResponse(inv)
+	handleSuccess(ctx, res)
+}
+
+// RejectImInvoice ql-kho-lua
+//
+//	@Summary		Reject a import invoice
+//	@Description	Reject a import invoice waiting for approval, it never moves stock
+//	@Tags			importInvoices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int								true	"Invoice id"
+//	@Param			request	body		cancelInvoiceRequest			true	"Reject invoice body"
+//	@Success		200		{object}	response{data=invoiceResponse}	"Rejected invoice data"
+//	@Failure		400		{object}	errorResponse					"Validation error"
+//	@Failure		401		{object}	errorResponse					"Unauthorized error"
+//	@Failure		403		{object}	errorResponse					"Forbidden error"
+//	@Failure		404		{object}	errorResponse					"Data not found error"
+//	@Failure		409		{object}	errorResponse					"Conflicting data error"
+//	@Failure		500		{object}	errorResponse					"Internal server error"
+//	@Router			/import_invoices/{id}/reject  [post]
+//	@Security		JWTAuth
+func (i *ImportInvoiceHandler) RejectImInvoice(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	numID, err := strconv.Atoi(id)
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	var req cancelInvoiceRequest
+	err = ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	inv, err := i.svc.RejectImInvoice(ctx, numID, req.Reason)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newInvoiceResponse(inv)
+	handleSuccess(ctx, res)
+}
+
 // GetImInvoiceByID ql-kho-lua
 //
 //	@Summary		Get a import invoice by id
@@ -129,7 +295,7 @@ func (i *ImportInvoiceHandler) GetImInvoiceByID(ctx *gin.Context) {
 
 	isRootUser := token.Role == domain.Root
 	if !isRootUser {
-		err := i.acc.HasAccess(ctx, inv.WarehouseID, token.ID)
+		err := i.acc.HasAccess(ctx, inv.WarehouseID, token.ID, domain.ActionRead)
 		if err != nil {
 			handleError(ctx, err)
 			return
@@ -140,12 +306,57 @@ func (i *ImportInvoiceHandler) GetImInvoiceByID(ctx *gin.Context) {
 	handleSuccess(ctx, res)
 }
 
+// GetImInvoicePDF ql-kho-lua
+//
+//	@Summary		Print an import invoice
+//	@Description	Render an import invoice as a PDF with the company header, warehouse, supplier, lines, totals and creating user
+//	@Tags			importInvoices
+//	@Accept			json
+//	@Produce		application/pdf
+//	@Param			id	path		int				true	"Invoice id"
+//	@Success		200	{file}		file			"Invoice PDF"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		403	{object}	errorResponse	"Forbidden error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/import_invoices/{id}/pdf  [get]
+//	@Security		JWTAuth
+func (i *ImportInvoiceHandler) GetImInvoicePDF(ctx *gin.Context) {
+	numID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	inv, err := i.svc.GetImInvoiceByID(ctx, numID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	isRootUser := token.Role == domain.Root
+	if !isRootUser {
+		err := i.acc.HasAccess(ctx, inv.WarehouseID, token.ID, domain.ActionRead)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+	}
+
+	writeInvoicePDF(ctx, i.renderer, domain.InvoiceKindImport, inv)
+}
+
 type getListImInvoiceRequest struct {
-	WarehouseID int        `form:"warehouse_id" binding:"omitempty,min=0"`
-	Start       *time.Time `form:"start" binding:"omitempty"`
-	End         *time.Time `form:"end" binding:"omitempty"`
-	Skip        int        `form:"skip" binding:"min=1" example:"1"`
-	Limit       int        `form:"limit" binding:"min=5" example:"5"`
+	WarehouseID int          `form:"warehouse_id" binding:"omitempty,min=0"`
+	Start       *time.Time   `form:"start" binding:"omitempty"`
+	End         *time.Time   `form:"end" binding:"omitempty"`
+	Skip        int          `form:"skip" binding:"min=1" example:"1"`
+	Limit       int          `form:"limit" binding:"min=5" example:"5"`
+	Format      sheet.Format `form:"format" binding:"omitempty,oneof=csv xlsx" example:"csv"`
+	Details     bool         `form:"details" binding:"omitempty" example:"true"`
 }
 
 // GetListImInvoices ql-kho-lua
@@ -160,6 +371,8 @@ type getListImInvoiceRequest struct {
 //	@Param			limit			query		int												false	"Limit"	default(5)	minimum(5)
 //	@Param			start			query		string											false	"Start"	format(date-time)
 //	@Param			end				query		string											false	"End"	format(date-time)
+//	@Param			format			query		string											false	"Export every matching invoice as a file instead of a page"	Enums(csv, xlsx)
+//	@Param			details			query		bool											false	"Export one row per detail line"
 //	@Success		200				{object}	responseWithPagination{data=[]invoiceResponse}	"Invoice data"
 //	@Failure		400				{object}	errorResponse									"Validation error"
 //	@Failure		401				{object}	errorResponse									"Unauthorized error"
@@ -187,13 +400,26 @@ func (i *ImportInvoiceHandler) GetListImInvoices(ctx *gin.Context) {
 			handleError(ctx, domain.ErrForbidden)
 			return
 		}
-		err := i.acc.HasAccess(ctx, req.WarehouseID, token.ID)
+		err := i.acc.HasAccess(ctx, req.WarehouseID, token.ID, domain.ActionRead)
 		if err != nil {
 			handleError(ctx, err)
 			return
 		}
 	}
 
+	if req.Format != "" {
+		list := func(afterID, limit int) ([]domain.Invoice, error) {
+			return i.svc.GetListImInvoicesWithAssociations(ctx, req.WarehouseID, req.Start, req.End, afterID, limit)
+		}
+
+		header := invoiceExportHeader
+		if req.Details {
+			header = invoiceDetailExportHeader
+		}
+		exportList(ctx, req.Format, "import_invoices", header, newInvoiceExportPage(list, req.Details))
+		return
+	}
+
 	count, err := i.svc.CountImInvoices(ctx, req.WarehouseID, req.Start, req.End)
 	if err != nil {
 		handleError(ctx, err)
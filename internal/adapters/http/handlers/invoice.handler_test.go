@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	"github.com/tommjj/ql-kho-lua/internal/core/services"
+	mockRepo "github.com/tommjj/ql-kho-lua/internal/core/services/mock"
+)
+
+// fakeImInvoicesService serves a single invoice and records whether it has been cancelled
+type fakeImInvoicesService struct {
+	ports.IImportInvoicesService
+	invoice   *domain.Invoice
+	cancelled bool
+}
+
+func (f *fakeImInvoicesService) GetImInvoiceByID(ctx context.Context, id int) (*domain.Invoice, error) {
+	return f.invoice, nil
+}
+
+func (f *fakeImInvoicesService) CancelImInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	f.cancelled = true
+	return f.invoice, nil
+}
+
+// fakeExInvoiceService serves a single invoice and records whether it has been cancelled
+type fakeExInvoiceService struct {
+	ports.IExportInvoiceService
+	invoice   *domain.Invoice
+	cancelled bool
+}
+
+func (f *fakeExInvoiceService) GetExInvoiceByID(ctx context.Context, id int) (*domain.Invoice, error) {
+	return f.invoice, nil
+}
+
+func (f *fakeExInvoiceService) CancelExInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	f.cancelled = true
+	return f.invoice, nil
+}
+
+// newCancelTestRouter serve handler on POST /:id/cancel as the user of payload
+func newCancelTestRouter(payload *domain.TokenPayload, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.POST("/:id/cancel", func(ctx *gin.Context) {
+		ctx.Set(authorizationPayloadKey, payload)
+		ctx.Next()
+	}, handler)
+	return r
+}
+
+func cancelRequest(r *gin.Engine) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/1/cancel", strings.NewReader(`{"reason":"wrong quantity"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCancelImInvoice_ForbiddenOtherWarehouse(t *testing.T) {
+	accRepo := new(mockRepo.MockAccessControlRepository)
+	accRepo.On("GetAccessLevel", mock.Anything, 2, 7).Return(domain.AccessLevel(""), domain.ErrForbidden)
+
+	svc := &fakeImInvoicesService{invoice: &domain.Invoice{ID: 1, WarehouseID: 2}}
+	handler := NewImportInvoiceHandler(svc, services.NewAccessControlService(accRepo), nil)
+
+	r := newCancelTestRouter(&domain.TokenPayload{ID: 7, Role: domain.WarehouseManager}, handler.CancelImInvoice)
+	w := cancelRequest(r)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.False(t, svc.cancelled)
+	accRepo.AssertExpectations(t)
+}
+
+func TestCancelImInvoice_RootSkipsAccessCheck(t *testing.T) {
+	accRepo := new(mockRepo.MockAccessControlRepository)
+
+	svc := &fakeImInvoicesService{invoice: &domain.Invoice{ID: 1, WarehouseID: 2}}
+	handler := NewImportInvoiceHandler(svc, services.NewAccessControlService(accRepo), nil)
+
+	r := newCancelTestRouter(&domain.TokenPayload{ID: 1, Role: domain.Root}, handler.CancelImInvoice)
+	w := cancelRequest(r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, svc.cancelled)
+	accRepo.AssertNotCalled(t, "GetAccessLevel", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCancelExInvoice_ForbiddenOtherWarehouse(t *testing.T) {
+	accRepo := new(mockRepo.MockAccessControlRepository)
+	accRepo.On("GetAccessLevel", mock.Anything, 2, 7).Return(domain.AccessImportOnly, nil)
+
+	svc := &fakeExInvoiceService{invoice: &domain.Invoice{ID: 1, WarehouseID: 2}}
+	handler := NewExportInvoiceHandler(svc, services.NewAccessControlService(accRepo), nil)
+
+	r := newCancelTestRouter(&domain.TokenPayload{ID: 7, Role: domain.WarehouseManager}, handler.CancelExInvoice)
+	w := cancelRequest(r)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.False(t, svc.cancelled)
+	accRepo.AssertExpectations(t)
+}
@@ -1,6 +1,15 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -16,6 +25,17 @@ var (
 	authorizationType = "bearer"
 	// authorizationPayloadKey is the key for authorization payload in the context
 	authorizationPayloadKey = "authorization_payload"
+	// idempotencyKeyHeader is the header a client sends to make a create request safe to retry
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayedHeader is set on a response that is replayed for an idempotency key
+	idempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+const (
+	// maxIdempotencyKeyLength is the longest idempotency key that is stored
+	maxIdempotencyKeyLength = 255
+	// maxCreateBodySize is the largest body of a create request, it is read in memory to fingerprint the request
+	maxCreateBodySize = 1 << 20
 )
 
 func AuthMiddleware(token ports.ITokenService) gin.HandlerFunc {
@@ -62,23 +82,141 @@ func AuthMiddleware(token ports.ITokenService) gin.HandlerFunc {
 		}
 
 		ctx.Set(authorizationPayloadKey, payload)
+		// services read the actor of the request for the audit log
+		ctx.Request = ctx.Request.WithContext(domain.ContextWithActor(ctx.Request.Context(), &domain.Actor{
+			ID:   payload.ID,
+			Name: payload.Name,
+			IP:   ctx.ClientIP(),
+		}))
 		ctx.Next()
 	}
 }
 
-// RoleRootMiddleware is a middleware to check if the user is a root
-func RoleRootMiddleware() gin.HandlerFunc {
+// RequirePermission is a middleware to check if the role of the user is granted all of the permissions
+func RequirePermission(perms ...domain.Permission) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		token := getAuthPayload(ctx, authorizationPayloadKey)
 
-		isRoot := token.Role == domain.Root
+		for _, perm := range perms {
+			if !token.Role.HasPermission(perm) {
+				handleError(ctx, domain.ErrForbidden)
+				ctx.Abort()
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
+
+// responseRecorder keep a copy of the body written to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
 
-		if !isRoot {
-			handleError(ctx, domain.ErrForbidden)
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// requestFingerprint hash the method, path and body of a request, a json body is compacted
+// with sorted keys first so the same data sent with another layout is the same request
+func requestFingerprint(method, path string, body []byte) string {
+	var data any
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	if err := d.Decode(&data); err == nil {
+		if canonical, err := json.Marshal(data); err == nil {
+			body = canonical
+		}
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", method, path)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// IdempotencyMiddleware is a middleware to make a create request safe to send again. When the request has an
+// Idempotency-Key header the successful response is stored and a retry with the same key and body gets it back
+// instead of running again, a retry with the same key and another body is rejected. Requests that fail are not
+// stored, so they can be retried with the same key. The body is limited to maxCreateBodySize with or without a key
+func IdempotencyMiddleware(svc ports.IIdempotencyService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxCreateBodySize)
+
+		key := ctx.GetHeader(idempotencyKeyHeader)
+		if key == "" {
+			ctx.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			validationError(ctx, fmt.Errorf("%s must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLength))
 			ctx.Abort()
 			return
 		}
 
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				res := newErrorResponse([]string{fmt.Sprintf("request body must be at most %d KB", maxCreateBodySize>>10)})
+				ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, res)
+				return
+			}
+			validationError(ctx, err)
+			ctx.Abort()
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		token := getAuthPayload(ctx, authorizationPayloadKey)
+		fingerprint := requestFingerprint(ctx.Request.Method, ctx.Request.URL.Path, body)
+
+		record, err := svc.Begin(ctx, token.ID, key, fingerprint)
+		if err != nil {
+			handleError(ctx, err)
+			ctx.Abort()
+			return
+		}
+
+		if record.Completed {
+			ctx.Header(idempotentReplayedHeader, "true")
+			ctx.Data(record.StatusCode, "application/json; charset=utf-8", record.Response)
+			ctx.Abort()
+			return
+		}
+
+		// the key is stored or released even when the client is gone, else it stays claimed until the lock times out
+		storeCtx := context.WithoutCancel(ctx.Request.Context())
+		recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+
+		finished := false
+		defer func() {
+			if !finished {
+				// the handler panicked
+				_ = svc.Release(storeCtx, record)
+			}
+		}()
+
 		ctx.Next()
+		finished = true
+
+		status := recorder.Status()
+		if status >= http.StatusOK && status < http.StatusMultipleChoices {
+			err = svc.Complete(storeCtx, record, status, recorder.body.Bytes())
+		} else {
+			err = svc.Release(storeCtx, record)
+		}
+		if err != nil {
+			_ = ctx.Error(err)
+		}
 	}
 }
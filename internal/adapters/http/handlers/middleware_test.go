@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+// fakeIdempotencyService never finds a stored request and counts the keys it claims
+type fakeIdempotencyService struct {
+	ports.IIdempotencyService
+	begun int
+}
+
+func (f *fakeIdempotencyService) Begin(ctx context.Context, userID int, key string, fingerprint string) (*domain.IdempotencyRecord, error) {
+	f.begun++
+	return &domain.IdempotencyRecord{}, nil
+}
+
+func (f *fakeIdempotencyService) Release(ctx context.Context, record *domain.IdempotencyRecord) error {
+	return nil
+}
+
+func (f *fakeIdempotencyService) Complete(ctx context.Context, record *domain.IdempotencyRecord, statusCode int, response []byte) error {
+	return nil
+}
+
+func newIdempotencyTestRouter(svc ports.IIdempotencyService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.POST("/", func(ctx *gin.Context) {
+		ctx.Set(authorizationPayloadKey, &domain.TokenPayload{ID: 1})
+		ctx.Next()
+	}, IdempotencyMiddleware(svc), func(ctx *gin.Context) {
+		_, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			validationError(ctx, err)
+			return
+		}
+		ctx.Status(http.StatusCreated)
+	})
+	return r
+}
+
+func TestIdempotencyMiddleware_BodyTooLarge(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		size     int
+		expected int
+		begun    int
+	}{
+		{"SuccessWithKey", "k1", maxCreateBodySize, http.StatusCreated, 1},
+		{"FailWithKey", "k1", maxCreateBodySize + 1, http.StatusRequestEntityTooLarge, 0},
+		{"FailWithoutKey", "", maxCreateBodySize + 1, http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeIdempotencyService{}
+			r := newIdempotencyTestRouter(svc)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(make([]byte, tt.size)))
+			if tt.key != "" {
+				req.Header.Set(idempotencyKeyHeader, tt.key)
+			}
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			assert.Equal(t, tt.begun, svc.begun)
+		})
+	}
+}
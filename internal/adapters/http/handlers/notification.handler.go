@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+type NotificationHandler struct {
+	svc ports.INotificationService
+}
+
+func NewNotificationHandler(notificationService ports.INotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		svc: notificationService,
+	}
+}
+
+// GetSubscriptions ql-kho-lua
+//
+//	@Summary		Get my subscriptions
+//	@Description	Get the event subscriptions of the logged in user
+//	@Tags			notifications
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response{data=[]subscriptionResponse}	"Subscriptions data"
+//	@Failure		401	{object}	errorResponse							"Unauthorized error"
+//	@Failure		500	{object}	errorResponse							"Internal server error"
+//	@Router			/notifications/subscriptions  [get]
+//	@Security		JWTAuth
+func (n *NotificationHandler) GetSubscriptions(ctx *gin.Context) {
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	subs, err := n.svc.GetSubscriptions(ctx, token.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]subscriptionResponse, 0, len(subs))
+	for _, v := range subs {
+		res = append(res, newSubscriptionResponse(&v))
+	}
+
+	handleSuccess(ctx, res)
+}
+
+type subscribeRequest struct {
+	EventType domain.EventType           `json:"event_type" binding:"required,oneof=import_invoice.created export_invoice.created export_invoice.large alert.low_stock alert.near_capacity alert.lot_expiry" example:"export_invoice.large"`
+	Channel   domain.NotificationChannel `json:"channel" binding:"required,oneof=email webhook in_app" example:"email"`
+	Target    string                     `json:"target" binding:"omitempty,url,max=255" example:"https://example.com/hooks/kho"`
+}
+
+// Subscribe ql-kho-lua
+//
+//	@Summary		Subscribe to an event
+//	@Description	Subscribe the logged in user to an event type, emails are sent to the user email and webhooks are posted to the target url
+//	@Tags			notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		subscribeRequest					true	"Subscription body"
+//	@Success		200		{object}	response{data=subscriptionResponse}	"Subscription data"
+//	@Failure		400		{object}	errorResponse						"Validation error"
+//	@Failure		401		{object}	errorResponse						"Unauthorized error"
+//	@Failure		409		{object}	errorResponse						"Conflicting data error"
+//	@Failure		500		{object}	errorResponse						"Internal server error"
+//	@Router			/notifications/subscriptions  [post]
+//	@Security		JWTAuth
+func (n *NotificationHandler) Subscribe(ctx *gin.Context) {
+	var req subscribeRequest
+
+	err := ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	sub, err := n.svc.Subscribe(ctx, &domain.Subscription{
+		UserID:    token.ID,
+		EventType: req.EventType,
+		Channel:   req.Channel,
+		Target:    req.Target,
+	})
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newSubscriptionResponse(sub)
+	handleSuccess(ctx, res)
+}
+
+// Unsubscribe ql-kho-lua
+//
+//	@Summary		Unsubscribe
+//	@Description	Delete a subscription of the logged in user
+//	@Tags			notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int				true	"Subscription id"
+//	@Success		200	{object}	response		"Deleted"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/notifications/subscriptions/{id}  [delete]
+//	@Security		JWTAuth
+func (n *NotificationHandler) Unsubscribe(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	err = n.svc.Unsubscribe(ctx, id, token.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+type getListNotificationsRequest struct {
+	Unread bool `form:"unread" binding:"omitempty" example:"true"`
+	Skip   int  `form:"skip" binding:"min=1" example:"1"`
+	Limit  int  `form:"limit" binding:"min=5" example:"5"`
+}
+
+// GetListNotifications ql-kho-lua
+//
+//	@Summary		Get my notifications
+//	@Description	Get the in-app notifications of the logged in user, newest first
+//	@Tags			notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			unread	query		bool												false	"Only unread notifications"
+//	@Param			skip	query		int													false	"Skip"	default(1)	minimum(1)
+//	@Param			limit	query		int													false	"Limit"	default(5)	minimum(5)
+//	@Success		200		{object}	responseWithPagination{data=[]notificationResponse}	"Notifications data"
+//	@Failure		400		{object}	errorResponse										"Validation error"
+//	@Failure		401		{object}	errorResponse										"Unauthorized error"
+//	@Failure		404		{object}	errorResponse										"Data not found error"
+//	@Failure		500		{object}	errorResponse										"Internal server error"
+//	@Router			/notifications  [get]
+//	@Security		JWTAuth
+func (n *NotificationHandler) GetListNotifications(ctx *gin.Context) {
+	req := getListNotificationsRequest{
+		Skip:  1,
+		Limit: 5,
+	}
+	err := ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	count, err := n.svc.CountNotifications(ctx, token.ID, req.Unread)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	if isPageOutOfRange(count, req.Limit, req.Skip) {
+		handleError(ctx, domain.ErrDataNotFound)
+		return
+	}
+
+	notifications, err := n.svc.GetListNotifications(ctx, token.ID, req.Unread, req.Limit, req.Skip)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]notificationResponse, 0, len(notifications))
+	for _, v := range notifications {
+		res = append(res, newNotificationResponse(&v))
+	}
+
+	pagination := newPagination(count, len(notifications), req.Limit, req.Skip)
+	handleSuccessPagination(ctx, pagination, res)
+}
+
+// ReadNotification ql-kho-lua
+//
+//	@Summary		Read a notification
+//	@Description	Mark an in-app notification of the logged in user as read
+//	@Tags			notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int									true	"Notification id"
+//	@Success		200	{object}	response{data=notificationResponse}	"Notification data"
+//	@Failure		400	{object}	errorResponse						"Validation error"
+//	@Failure		401	{object}	errorResponse						"Unauthorized error"
+//	@Failure		404	{object}	errorResponse						"Data not found error"
+//	@Failure		500	{object}	errorResponse						"Internal server error"
+//	@Router			/notifications/{id}/read  [post]
+//	@Security		JWTAuth
+func (n *NotificationHandler) ReadNotification(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	notification, err := n.svc.ReadNotification(ctx, id, token.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newNotificationResponse(notification)
+	handleSuccess(ctx, res)
+}
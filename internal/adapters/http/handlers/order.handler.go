@@ -0,0 +1,352 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+type OrderHandler struct {
+	svc ports.IOrderService
+	acc ports.IAccessControlService
+}
+
+func NewOrderHandler(svc ports.IOrderService, acc ports.IAccessControlService) *OrderHandler {
+	return &OrderHandler{
+		svc: svc,
+		acc: acc,
+	}
+}
+
+// orderAction is the warehouse action an order leads to, a purchase order imports and a sales order exports
+func orderAction(orderType domain.OrderType) domain.AccessAction {
+	if orderType == domain.PurchaseOrder {
+		return domain.ActionImport
+	}
+	return domain.ActionExport
+}
+
+// checkAccess check the user can take the action on the warehouse, root can take every action
+func (o *OrderHandler) checkAccess(ctx *gin.Context, warehouseID int, action domain.AccessAction) bool {
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	isRootUser := token.Role == domain.Root
+	if !isRootUser {
+		err := o.acc.HasAccess(ctx, warehouseID, token.ID, action)
+		if err != nil {
+			handleError(ctx, err)
+			return false
+		}
+	}
+	return true
+}
+
+// getOrder get the order of the id param and check the user can take the action of the order on its warehouse
+func (o *OrderHandler) getOrder(ctx *gin.Context) (*domain.Order, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return nil, false
+	}
+
+	order, err := o.svc.GetOrderByID(ctx, id)
+	if err != nil {
+		handleError(ctx, err)
+		return nil, false
+	}
+
+	if !o.checkAccess(ctx, order.WarehouseID, orderAction(order.Type)) {
+		return nil, false
+	}
+	return order, true
+}
+
+type detailOrderRequest struct {
+	RiceID   int     `json:"rice_id" binding:"required"`
+	Price    float64 `json:"price" binding:"required,min=1"`
+	Quantity int     `json:"quantity" binding:"required,min=1"`
+}
+
+type createOrderRequest struct {
+	Type        domain.OrderType     `json:"type" binding:"required,oneof=purchase sales" example:"purchase"`
+	WarehouseID int                  `json:"warehouse_id" binding:"required"`
+	CustomerID  int                  `json:"customer_id" binding:"required"`
+	Details     []detailOrderRequest `json:"details" binding:"required,min=1,unique=RiceID"`
+}
+
+// CreateOrder ql-kho-lua
+//
+//	@Summary		Create a draft order
+//	@Description	Create a draft purchase or sales order, it reserves nothing until it is confirmed
+//	@Tags			orders
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		createOrderRequest			true	"Create order body"
+//	@Success		200		{object}	response{data=orderResponse}	"Created order data"
+//	@Failure		400		{object}	errorResponse					"Validation error"
+//	@Failure		401		{object}	errorResponse					"Unauthorized error"
+//	@Failure		403		{object}	errorResponse					"Forbidden error"
+//	@Failure		404		{object}	errorResponse					"Data not found error"
+//	@Failure		500		{object}	errorResponse					"Internal server error"
+//	@Router			/orders  [post]
+//	@Security		JWTAuth
+func (o *OrderHandler) CreateOrder(ctx *gin.Context) {
+	var req createOrderRequest
+	err := ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	if !o.checkAccess(ctx, req.WarehouseID, orderAction(req.Type)) {
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	order := &domain.Order{
+		Type:        req.Type,
+		WarehouseID: req.WarehouseID,
+		CustomerID:  req.CustomerID,
+		UserID:      token.ID,
+		Details:     make([]domain.InvoiceItem, 0, len(req.Details)),
+	}
+	for _, v := range req.Details {
+		order.Details = append(order.Details, domain.InvoiceItem{
+			Price:    v.Price,
+			Quantity: v.Quantity,
+			RiceID:   v.RiceID,
+		})
+	}
+
+	created, err := o.svc.CreateOrder(ctx, order)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newOrderResponse(created)
+	handleSuccess(ctx, res)
+}
+
+type getListOrdersRequest struct {
+	WarehouseID int                `form:"warehouse_id" binding:"omitempty,min=0" example:"1"`
+	Type        domain.OrderType   `form:"type" binding:"omitempty,oneof=purchase sales" example:"purchase"`
+	Status      domain.OrderStatus `form:"status" binding:"omitempty,oneof=draft confirmed cancelled converted" example:"confirmed"`
+	Skip        int                `form:"skip" binding:"min=1" example:"1"`
+	Limit       int                `form:"limit" binding:"min=5" example:"5"`
+}
+
+// GetListOrders ql-kho-lua
+//
+//	@Summary		Get orders
+//	@Description	Get purchase and sales orders, newest first
+//	@Tags			orders
+//	@Accept			json
+//	@Produce		json
+//	@Param			warehouse_id	query		int												false	"Warehouse id, required for users other than root"
+//	@Param			type			query		string											false	"Order type"	Enums(purchase, sales)
+//	@Param			status			query		string											false	"Order status"	Enums(draft, confirmed, cancelled, converted)
+//	@Param			skip			query		int												false	"Skip"			default(1)	minimum(1)
+//	@Param			limit			query		int												false	"Limit"			default(5)	minimum(5)
+//	@Success		200				{object}	responseWithPagination{data=[]orderResponse}	"Orders data"
+//	@Failure		400				{object}	errorResponse									"Validation error"
+//	@Failure		401				{object}	errorResponse									"Unauthorized error"
+//	@Failure		403				{object}	errorResponse									"Forbidden error"
+//	@Failure		404				{object}	errorResponse									"Data not found error"
+//	@Failure		500				{object}	errorResponse									"Internal server error"
+//	@Router			/orders  [get]
+//	@Security		JWTAuth
+func (o *OrderHandler) GetListOrders(ctx *gin.Context) {
+	req := getListOrdersRequest{
+		Skip:  1,
+		Limit: 5,
+	}
+	err := ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	if token.Role != domain.Root && req.WarehouseID == 0 {
+		handleError(ctx, domain.ErrForbidden)
+		return
+	}
+	if !o.checkAccess(ctx, req.WarehouseID, domain.ActionRead) {
+		return
+	}
+
+	filter := domain.OrderFilter{
+		WarehouseID: req.WarehouseID,
+		Type:        req.Type,
+		Status:      req.Status,
+	}
+
+	count, err := o.svc.CountOrders(ctx, filter)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	if isPageOutOfRange(count, req.Limit, req.Skip) {
+		handleError(ctx, domain.ErrDataNotFound)
+		return
+	}
+
+	orders, err := o.svc.GetListOrders(ctx, filter, req.Limit, req.Skip)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]orderResponse, 0, len(orders))
+	for _, v := range orders {
+		res = append(res, newOrderResponse(&v))
+	}
+
+	pagination := newPagination(count, len(orders), req.Limit, req.Skip)
+	handleSuccessPagination(ctx, pagination, res)
+}
+
+// GetOrderByID ql-kho-lua
+//
+//	@Summary		Get an order by id
+//	@Description	Get a purchase or sales order by id
+//	@Tags			orders
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int								true	"Order id"
+//	@Success		200	{object}	response{data=orderResponse}	"Order data"
+//	@Failure		400	{object}	errorResponse					"Validation error"
+//	@Failure		401	{object}	errorResponse					"Unauthorized error"
+//	@Failure		403	{object}	errorResponse					"Forbidden error"
+//	@Failure		404	{object}	errorResponse					"Data not found error"
+//	@Failure		500	{object}	errorResponse					"Internal server error"
+//	@Router			/orders/{id}  [get]
+//	@Security		JWTAuth
+func (o *OrderHandler) GetOrderByID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	order, err := o.svc.GetOrderByID(ctx, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	if !o.checkAccess(ctx, order.WarehouseID, domain.ActionRead) {
+		return
+	}
+
+	res := newOrderResponse(order)
+	handleSuccess(ctx, res)
+}
+
+// ConfirmOrder ql-kho-lua
+//
+//	@Summary		Confirm an order
+//	@Description	Confirm a draft order, a purchase order reserves warehouse capacity and a sales order reserves stock
+//	@Tags			orders
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int								true	"Order id"
+//	@Success		200	{object}	response{data=orderResponse}	"Confirmed order data"
+//	@Failure		400	{object}	errorResponse					"Validation error"
+//	@Failure		401	{object}	errorResponse					"Unauthorized error"
+//	@Failure		403	{object}	errorResponse					"Forbidden error"
+//	@Failure		404	{object}	errorResponse					"Data not found error"
+//	@Failure		409	{object}	errorResponse					"Conflicting data error"
+//	@Failure		500	{object}	errorResponse					"Internal server error"
+//	@Router			/orders/{id}/confirm  [post]
+//	@Security		JWTAuth
+func (o *OrderHandler) ConfirmOrder(ctx *gin.Context) {
+	order, ok := o.getOrder(ctx)
+	if !ok {
+		return
+	}
+
+	confirmed, err := o.svc.ConfirmOrder(ctx, order.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newOrderResponse(confirmed)
+	handleSuccess(ctx, res)
+}
+
+// CancelOrder ql-kho-lua
+//
+//	@Summary		Cancel an order
+//	@Description	Cancel a draft or confirmed order and release its reservation
+//	@Tags			orders
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int								true	"Order id"
+//	@Success		200	{object}	response{data=orderResponse}	"Cancelled order data"
+//	@Failure		400	{object}	errorResponse					"Validation error"
+//	@Failure		401	{object}	errorResponse					"Unauthorized error"
+//	@Failure		403	{object}	errorResponse					"Forbidden error"
+//	@Failure		404	{object}	errorResponse					"Data not found error"
+//	@Failure		409	{object}	errorResponse					"Conflicting data error"
+//	@Failure		500	{object}	errorResponse					"Internal server error"
+//	@Router			/orders/{id}/cancel  [post]
+//	@Security		JWTAuth
+func (o *OrderHandler) CancelOrder(ctx *gin.Context) {
+	order, ok := o.getOrder(ctx)
+	if !ok {
+		return
+	}
+
+	cancelled, err := o.svc.CancelOrder(ctx, order.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newOrderResponse(cancelled)
+	handleSuccess(ctx, res)
+}
+
+// ConvertOrder ql-kho-lua
+//
+//	@Summary		Convert an order into an invoice
+//	@Description	Convert a confirmed order when the goods move, a purchase order becomes an import invoice and a sales order an export invoice
+//	@Tags			orders
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int								true	"Order id"
+//	@Success		200	{object}	response{data=invoiceResponse}	"Created invoice data"
+//	@Failure		400	{object}	errorResponse					"Validation error"
+//	@Failure		401	{object}	errorResponse					"Unauthorized error"
+//	@Failure		403	{object}	errorResponse					"Forbidden error"
+//	@Failure		404	{object}	errorResponse					"Data not found error"
+//	@Failure		409	{object}	errorResponse					"Conflicting data error"
+//	@Failure		500	{object}	errorResponse					"Internal server error"
+//	@Router			/orders/{id}/convert  [post]
+//	@Security		JWTAuth
+func (o *OrderHandler) ConvertOrder(ctx *gin.Context) {
+	order, ok := o.getOrder(ctx)
+	if !ok {
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	invoice, err := o.svc.ConvertOrder(ctx, order.ID, token.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newInvoiceResponse(invoice)
+	handleSuccess(ctx, res)
+}
@@ -0,0 +1,382 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+type PaymentHandler struct {
+	svc ports.IPaymentService
+	acc ports.IAccessControlService
+}
+
+func NewPaymentHandler(svc ports.IPaymentService, acc ports.IAccessControlService) *PaymentHandler {
+	return &PaymentHandler{
+		svc: svc,
+		acc: acc,
+	}
+}
+
+// checkWarehouseAccess check the user can do the action in the warehouse, root can do every action
+func (p *PaymentHandler) checkWarehouseAccess(ctx *gin.Context, warehouseID int, action domain.AccessAction) bool {
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	isRootUser := token.Role == domain.Root
+	if !isRootUser {
+		err := p.acc.HasAccess(ctx, warehouseID, token.ID, action)
+		if err != nil {
+			handleError(ctx, err)
+			return false
+		}
+	}
+	return true
+}
+
+// checkWarehouseFilter check the user can read the warehouse of a list or report,
+// users other than root must give a warehouse
+func (p *PaymentHandler) checkWarehouseFilter(ctx *gin.Context, warehouseID int) bool {
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	if token.Role != domain.Root && warehouseID == 0 {
+		handleError(ctx, domain.ErrForbidden)
+		return false
+	}
+	return p.checkWarehouseAccess(ctx, warehouseID, domain.ActionRead)
+}
+
+type createPaymentRequest struct {
+	InvoiceKind domain.InvoiceKind   `json:"invoice_kind" binding:"required,oneof=import export" example:"export"`
+	InvoiceID   int                  `json:"invoice_id" binding:"required,min=1" example:"1"`
+	Amount      float64              `json:"amount" binding:"required,gt=0" example:"200"`
+	Method      domain.PaymentMethod `json:"method" binding:"required,oneof=cash bank_transfer card other" example:"bank_transfer"`
+	Reference   string               `json:"reference" binding:"omitempty,max=100" example:"FT24001"`
+	PaidAt      *time.Time           `json:"paid_at" binding:"omitempty" example:"2021-09-01T00:00:00Z"`
+}
+
+// CreatePayment ql-kho-lua
+//
+//	@Summary		Record a payment
+//	@Description	Record a full or partial payment of a completed import or export invoice, the payment can not be more than the invoice balance, users other than root need import or export access to the warehouse of the invoice
+//	@Tags			payments
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		createPaymentRequest			true	"Create payment body"
+//	@Success		200		{object}	response{data=paymentResponse}	"Created payment data"
+//	@Failure		400		{object}	errorResponse					"Validation error"
+//	@Failure		401		{object}	errorResponse					"Unauthorized error"
+//	@Failure		403		{object}	errorResponse					"Forbidden error"
+//	@Failure		404		{object}	errorResponse					"Data not found error"
+//	@Failure		409		{object}	errorResponse					"Invoice can not be paid error"
+//	@Failure		500		{object}	errorResponse					"Internal server error"
+//	@Router			/payments  [post]
+//	@Security		JWTAuth
+func (p *PaymentHandler) CreatePayment(ctx *gin.Context) {
+	var req createPaymentRequest
+	err := ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	isRootUser := token.Role == domain.Root
+	if !isRootUser {
+		warehouseID, err := p.svc.GetInvoiceWarehouseID(ctx, req.InvoiceKind, req.InvoiceID)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+
+		action := domain.ActionExport
+		if req.InvoiceKind == domain.InvoiceKindImport {
+			action = domain.ActionImport
+		}
+		if !p.checkWarehouseAccess(ctx, warehouseID, action) {
+			return
+		}
+	}
+
+	payment := &domain.Payment{
+		InvoiceKind: req.InvoiceKind,
+		InvoiceID:   req.InvoiceID,
+		UserID:      token.ID,
+		Amount:      req.Amount,
+		Method:      req.Method,
+		Reference:   req.Reference,
+	}
+	if req.PaidAt != nil {
+		payment.PaidAt = *req.PaidAt
+	}
+
+	created, err := p.svc.CreatePayment(ctx, payment)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newPaymentResponse(created)
+	handleSuccess(ctx, res)
+}
+
+type getListPaymentsRequest struct {
+	InvoiceKind domain.InvoiceKind `form:"invoice_kind" binding:"omitempty,oneof=import export" example:"export"`
+	InvoiceID   int                `form:"invoice_id" binding:"omitempty,min=0" example:"1"`
+	CustomerID  int                `form:"customer_id" binding:"omitempty,min=0" example:"1"`
+	WarehouseID int                `form:"warehouse_id" binding:"omitempty,min=0" example:"1"`
+	Start       *time.Time         `form:"start" binding:"omitempty"`
+	End         *time.Time         `form:"end" binding:"omitempty"`
+	Skip        int                `form:"skip" binding:"min=1" example:"1"`
+	Limit       int                `form:"limit" binding:"min=5" example:"5"`
+}
+
+// GetListPayments ql-kho-lua
+//
+//	@Summary		Get payments
+//	@Description	Get payments, latest first
+//	@Tags			payments
+//	@Accept			json
+//	@Produce		json
+//	@Param			invoice_kind	query		string											false	"Invoice kind"	Enums(import, export)
+//	@Param			invoice_id		query		int												false	"Invoice id"
+//	@Param			customer_id		query		int												false	"Customer or supplier id"
+//	@Param			warehouse_id	query		int												false	"Warehouse id, required for users other than root"
+//	@Param			start			query		string											false	"Paid from"	format(date-time)
+//	@Param			end				query		string											false	"Paid to"	format(date-time)
+//	@Param			skip			query		int												false	"Skip"		default(1)	minimum(1)
+//	@Param			limit			query		int												false	"Limit"		default(5)	minimum(5)
+//	@Success		200				{object}	responseWithPagination{data=[]paymentResponse}	"Payments data"
+//	@Failure		400				{object}	errorResponse									"Validation error"
+//	@Failure		401				{object}	errorResponse									"Unauthorized error"
+//	@Failure		403				{object}	errorResponse									"Forbidden error"
+//	@Failure		404				{object}	errorResponse									"Data not found error"
+//	@Failure		500				{object}	errorResponse									"Internal server error"
+//	@Router			/payments  [get]
+//	@Security		JWTAuth
+func (p *PaymentHandler) GetListPayments(ctx *gin.Context) {
+	req := getListPaymentsRequest{
+		Skip:  1,
+		Limit: 5,
+	}
+	err := ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	if !p.checkWarehouseFilter(ctx, req.WarehouseID) {
+		return
+	}
+
+	filter := domain.PaymentFilter{
+		InvoiceKind: req.InvoiceKind,
+		InvoiceID:   req.InvoiceID,
+		CustomerID:  req.CustomerID,
+		WarehouseID: req.WarehouseID,
+		Start:       req.Start,
+		End:         req.End,
+	}
+
+	count, err := p.svc.CountPayments(ctx, filter)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	if isPageOutOfRange(count, req.Limit, req.Skip) {
+		handleError(ctx, domain.ErrDataNotFound)
+		return
+	}
+
+	payments, err := p.svc.GetListPayments(ctx, filter, req.Limit, req.Skip)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]paymentResponse, 0, len(payments))
+	for _, v := range payments {
+		res = append(res, newPaymentResponse(&v))
+	}
+
+	pagination := newPagination(count, len(payments), req.Limit, req.Skip)
+	handleSuccessPagination(ctx, pagination, res)
+}
+
+// GetPaymentByID ql-kho-lua
+//
+//	@Summary		Get a payment by id
+//	@Description	Get a payment by id
+//	@Tags			payments
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int								true	"Payment id"
+//	@Success		200	{object}	response{data=paymentResponse}	"Payment data"
+//	@Failure		400	{object}	errorResponse					"Validation error"
+//	@Failure		401	{object}	errorResponse					"Unauthorized error"
+//	@Failure		403	{object}	errorResponse					"Forbidden error"
+//	@Failure		404	{object}	errorResponse					"Data not found error"
+//	@Failure		500	{object}	errorResponse					"Internal server error"
+//	@Router			/payments/{id}  [get]
+//	@Security		JWTAuth
+func (p *PaymentHandler) GetPaymentByID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	payment, err := p.svc.GetPaymentByID(ctx, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	if !p.checkWarehouseAccess(ctx, payment.WarehouseID, domain.ActionRead) {
+		return
+	}
+
+	res := newPaymentResponse(payment)
+	handleSuccess(ctx, res)
+}
+
+type getBalancesRequest struct {
+	WarehouseID int `form:"warehouse_id" binding:"omitempty,min=0" example:"1"`
+}
+
+// getBalances write the partner balances of the invoices of a kind
+func (p *PaymentHandler) getBalances(ctx *gin.Context, kind domain.InvoiceKind) {
+	var req getBalancesRequest
+	err := ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	if !p.checkWarehouseFilter(ctx, req.WarehouseID) {
+		return
+	}
+
+	balances, err := p.svc.GetBalances(ctx, kind, req.WarehouseID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, balances)
+}
+
+// GetReceivables ql-kho-lua
+//
+//	@Summary		Get receivables
+//	@Description	Get the invoiced, paid and outstanding amounts of every customer on completed export invoices, transfers are excluded
+//	@Tags			reports
+//	@Accept			json
+//	@Produce		json
+//	@Param			warehouse_id	query		int										false	"Warehouse id, required for users other than root"
+//	@Success		200	{object}	response{data=[]domain.PartnerBalance}	"Customer balances data"
+//	@Failure		400	{object}	errorResponse							"Validation error"
+//	@Failure		401	{object}	errorResponse							"Unauthorized error"
+//	@Failure		403	{object}	errorResponse							"Forbidden error"
+//	@Failure		500	{object}	errorResponse							"Internal server error"
+//	@Router			/reports/receivables  [get]
+//	@Security		JWTAuth
+func (p *PaymentHandler) GetReceivables(ctx *gin.Context) {
+	p.getBalances(ctx, domain.InvoiceKindExport)
+}
+
+// GetPayables ql-kho-lua
+//
+//	@Summary		Get payables
+//	@Description	Get the invoiced, paid and outstanding amounts of every supplier on completed import invoices, transfers are excluded
+//	@Tags			reports
+//	@Accept			json
+//	@Produce		json
+//	@Param			warehouse_id	query		int										false	"Warehouse id, required for users other than root"
+//	@Success		200	{object}	response{data=[]domain.PartnerBalance}	"Supplier balances data"
+//	@Failure		400	{object}	errorResponse							"Validation error"
+//	@Failure		401	{object}	errorResponse							"Unauthorized error"
+//	@Failure		403	{object}	errorResponse							"Forbidden error"
+//	@Failure		500	{object}	errorResponse							"Internal server error"
+//	@Router			/reports/payables  [get]
+//	@Security		JWTAuth
+func (p *PaymentHandler) GetPayables(ctx *gin.Context) {
+	p.getBalances(ctx, domain.InvoiceKindImport)
+}
+
+type getAgingRequest struct {
+	WarehouseID int        `form:"warehouse_id" binding:"omitempty,min=0" example:"1"`
+	At          *time.Time `form:"at" binding:"omitempty"`
+}
+
+// getAging write the aging report of the invoices of a kind, the report is at now when no time is given
+func (p *PaymentHandler) getAging(ctx *gin.Context, kind domain.InvoiceKind) {
+	var req getAgingRequest
+	err := ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	if !p.checkWarehouseFilter(ctx, req.WarehouseID) {
+		return
+	}
+
+	at := time.Now()
+	if req.At != nil {
+		at = *req.At
+	}
+
+	report, err := p.svc.GetAging(ctx, kind, req.WarehouseID, at)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, report)
+}
+
+// GetReceivablesAging ql-kho-lua
+//
+//	@Summary		Get receivables aging
+//	@Description	Get the outstanding balances of every customer split in 0-30, 31-60, 61-90 and over 90 days by the age of their export invoices
+//	@Tags			reports
+//	@Accept			json
+//	@Produce		json
+//	@Param			warehouse_id	query		int									false	"Warehouse id, required for users other than root"
+//	@Param			at				query		string								false	"Report time, default now"	format(date-time)
+//	@Success		200	{object}	response{data=domain.AgingReport}	"Aging report data"
+//	@Failure		400	{object}	errorResponse						"Validation error"
+//	@Failure		401	{object}	errorResponse						"Unauthorized error"
+//	@Failure		403	{object}	errorResponse						"Forbidden error"
+//	@Failure		500	{object}	errorResponse						"Internal server error"
+//	@Router			/reports/receivables/aging  [get]
+//	@Security		JWTAuth
+func (p *PaymentHandler) GetReceivablesAging(ctx *gin.Context) {
+	p.getAging(ctx, domain.InvoiceKindExport)
+}
+
+// GetPayablesAging ql-kho-lua
+//
+//	@Summary		Get payables aging
+//	@Description	Get the outstanding balances of every supplier split in 0-30, 31-60, 61-90 and over 90 days by the age of their import invoices
+//	@Tags			reports
+//	@Accept			json
+//	@Produce		json
+//	@Param			warehouse_id	query		int									false	"Warehouse id, required for users other than root"
+//	@Param			at				query		string								false	"Report time, default now"	format(date-time)
+//	@Success		200	{object}	response{data=domain.AgingReport}	"Aging report data"
+//	@Failure		400	{object}	errorResponse						"Validation error"
+//	@Failure		401	{object}	errorResponse						"Unauthorized error"
+//	@Failure		403	{object}	errorResponse						"Forbidden error"
+//	@Failure		500	{object}	errorResponse						"Internal server error"
+//	@Router			/reports/payables/aging  [get]
+//	@Security		JWTAuth
+func (p *PaymentHandler) GetPayablesAging(ctx *gin.Context) {
+	p.getAging(ctx, domain.InvoiceKindImport)
+}
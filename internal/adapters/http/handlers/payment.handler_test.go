@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	"github.com/tommjj/ql-kho-lua/internal/core/services"
+	mockRepo "github.com/tommjj/ql-kho-lua/internal/core/services/mock"
+)
+
+// fakePaymentService keeps every invoice in warehouse 2 and records the payments created
+type fakePaymentService struct {
+	ports.IPaymentService
+	created int
+}
+
+func (f *fakePaymentService) GetInvoiceWarehouseID(ctx context.Context, kind domain.InvoiceKind, invoiceID int) (int, error) {
+	return 2, nil
+}
+
+func (f *fakePaymentService) CreatePayment(ctx context.Context, payment *domain.Payment) (*domain.Payment, error) {
+	f.created++
+	payment.WarehouseID = 2
+	return payment, nil
+}
+
+func (f *fakePaymentService) GetBalances(ctx context.Context, kind domain.InvoiceKind, warehouseID int) ([]domain.PartnerBalance, error) {
+	return []domain.PartnerBalance{}, nil
+}
+
+func newPaymentTestRouter(payload *domain.TokenPayload, handler *PaymentHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(func(ctx *gin.Context) {
+		ctx.Set(authorizationPayloadKey, payload)
+		ctx.Next()
+	})
+	r.POST("/payments", handler.CreatePayment)
+	r.GET("/receivables", handler.GetReceivables)
+	return r
+}
+
+func postPayment(r *gin.Engine, kind domain.InvoiceKind) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/payments",
+		strings.NewReader(`{"invoice_kind":"`+string(kind)+`","invoice_id":1,"amount":100,"method":"cash"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreatePayment_WarehouseAccess(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     domain.InvoiceKind
+		level    domain.AccessLevel
+		levelErr error
+		expected int
+		created  int
+	}{
+		{"ForbiddenOtherWarehouse", domain.InvoiceKindExport, "", domain.ErrForbidden, http.StatusForbidden, 0},
+		{"ForbiddenImportOnlyOnExport", domain.InvoiceKindExport, domain.AccessImportOnly, nil, http.StatusForbidden, 0},
+		{"SuccessImportOnlyOnImport", domain.InvoiceKindImport, domain.AccessImportOnly, nil, http.StatusOK, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accRepo := new(mockRepo.MockAccessControlRepository)
+			accRepo.On("GetAccessLevel", mock.Anything, 2, 7).Return(tt.level, tt.levelErr)
+
+			svc := &fakePaymentService{}
+			handler := NewPaymentHandler(svc, services.NewAccessControlService(accRepo))
+
+			r := newPaymentTestRouter(&domain.TokenPayload{ID: 7, Role: domain.Accountant}, handler)
+			w := postPayment(r, tt.kind)
+
+			assert.Equal(t, tt.expected, w.Code)
+			assert.Equal(t, tt.created, svc.created)
+			accRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetReceivables_RequireWarehouse(t *testing.T) {
+	accRepo := new(mockRepo.MockAccessControlRepository)
+	accRepo.On("GetAccessLevel", mock.Anything, 2, 7).Return(domain.AccessImportOnly, nil)
+
+	handler := NewPaymentHandler(&fakePaymentService{}, services.NewAccessControlService(accRepo))
+
+	tests := []struct {
+		name     string
+		payload  *domain.TokenPayload
+		query    string
+		expected int
+	}{
+		{"ForbiddenWithoutWarehouse", &domain.TokenPayload{ID: 7, Role: domain.Accountant}, "", http.StatusForbidden},
+		{"SuccessWithWarehouse", &domain.TokenPayload{ID: 7, Role: domain.Accountant}, "?warehouse_id=2", http.StatusOK},
+		{"SuccessRootWithoutWarehouse", &domain.TokenPayload{ID: 1, Role: domain.Root}, "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newPaymentTestRouter(tt.payload, handler)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/receivables"+tt.query, nil))
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+type ReportHandler struct {
+	svc ports.IReportService
+	acc ports.IAccessControlService
+}
+
+func NewReportHandler(reportService ports.IReportService, acc ports.IAccessControlService) *ReportHandler {
+	return &ReportHandler{
+		svc: reportService,
+		acc: acc,
+	}
+}
+
+type getStockMovementRequest struct {
+	Start time.Time `form:"start" binding:"required"`
+	End   time.Time `form:"end" binding:"required"`
+}
+
+// GetStockMovement ql-kho-lua
+//
+//	@Summary		Get stock movement report
+//	@Description	Get opening balance, total imported, total exported and closing balance of every rice in a warehouse over a period
+//	@Tags			reports
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int										true	"Warehouse id"
+//	@Param			start	query		string									true	"Start"	format(date-time)
+//	@Param			end		query		string									true	"End"	format(date-time)
+//	@Success		200		{object}	response{data=[]stockMovementResponse}	"Stock movement data"
+//	@Failure		400		{object}	errorResponse							"Validation error"
+//	@Failure		401		{object}	errorResponse							"Unauthorized error"
+//	@Failure		403		{object}	errorResponse							"Forbidden error"
+//	@Failure		404		{object}	errorResponse							"Data not found error"
+//	@Failure		500		{object}	errorResponse							"Internal server error"
+//	@Router			/warehouses/{id}/reports/movement  [get]
+//	@Security		JWTAuth
+func (r *ReportHandler) GetStockMovement(ctx *gin.Context) {
+	var req getStockMovementRequest
+
+	warehouseID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	err = ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	if !r.checkReadAccess(ctx, warehouseID) {
+		return
+	}
+
+	movements, err := r.svc.GetStockMovement(ctx, warehouseID, req.Start, req.End)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]stockMovementResponse, 0, len(movements))
+	for _, v := range movements {
+		res = append(res, newStockMovementResponse(&v))
+	}
+
+	handleSuccess(ctx, res)
+}
+
+type getInvoiceAnalyticsRequest struct {
+	GroupBy     domain.ReportPeriod `form:"group_by" binding:"omitempty,oneof=day week month" example:"day"`
+	WarehouseID int                 `form:"warehouse_id" binding:"omitempty,min=0" example:"1"`
+	CustomerID  int                 `form:"customer_id" binding:"omitempty,min=0" example:"1"`
+	RiceID      int                 `form:"rice_id" binding:"omitempty,min=0" example:"1"`
+	Start       *time.Time          `form:"start" binding:"omitempty"`
+	End         *time.Time          `form:"end" binding:"omitempty"`
+}
+
+// bindInvoiceAnalytics bind the analytics query and check the warehouse access of the user,
+// users other than root have to choose a warehouse they can read
+func (r *ReportHandler) bindInvoiceAnalytics(ctx *gin.Context) (*domain.InvoiceAnalyticsFilter, bool) {
+	req := getInvoiceAnalyticsRequest{
+		GroupBy: domain.PeriodDay,
+	}
+	err := ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return nil, false
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	isRootUser := token.Role == domain.Root
+	if !isRootUser {
+		if req.WarehouseID == 0 {
+			handleError(ctx, domain.ErrForbidden)
+			return nil, false
+		}
+		err := r.acc.HasAccess(ctx, req.WarehouseID, token.ID, domain.ActionRead)
+		if err != nil {
+			handleError(ctx, err)
+			return nil, false
+		}
+	}
+
+	return &domain.InvoiceAnalyticsFilter{
+		GroupBy:     req.GroupBy,
+		WarehouseID: req.WarehouseID,
+		CustomerID:  req.CustomerID,
+		RiceID:      req.RiceID,
+		Start:       req.Start,
+		End:         req.End,
+	}, true
+}
+
+// GetRevenue ql-kho-lua
+//
+//	@Summary		Get revenue analytics
+//	@Description	Get the revenue of completed export invoices grouped by day, week or month, transfers are excluded
+//	@Tags			reports
+//	@Accept			json
+//	@Produce		json
+//	@Param			group_by		query		string									false	"Group by"	Enums(day, week, month)	default(day)
+//	@Param			warehouse_id	query		int										false	"Warehouse id, required for users other than root"
+//	@Param			customer_id		query		int										false	"Customer id"
+//	@Param			rice_id			query		int										false	"Rice id"
+//	@Param			start			query		string									false	"Start"	format(date-time)
+//	@Param			end				query		string									false	"End"	format(date-time)
+//	@Success		200				{object}	response{data=[]invoiceTotalResponse}	"Revenue data"
+//	@Failure		400				{object}	errorResponse							"Validation error"
+//	@Failure		401				{object}	errorResponse							"Unauthorized error"
+//	@Failure		403				{object}	errorResponse							"Forbidden error"
+//	@Failure		500				{object}	errorResponse							"Internal server error"
+//	@Router			/reports/revenue  [get]
+//	@Security		JWTAuth
+func (r *ReportHandler) GetRevenue(ctx *gin.Context) {
+	filter, ok := r.bindInvoiceAnalytics(ctx)
+	if !ok {
+		return
+	}
+
+	totals, err := r.svc.GetRevenue(ctx, *filter)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]invoiceTotalResponse, 0, len(totals))
+	for _, v := range totals {
+		res = append(res, newInvoiceTotalResponse(&v))
+	}
+
+	handleSuccess(ctx, res)
+}
+
+// GetPurchases ql-kho-lua
+//
+//	@Summary		Get purchase analytics
+//	@Description	Get the purchase cost of completed import invoices grouped by day, week or month, transfers are excluded
+//	@Tags			reports
+//	@Accept			json
+//	@Produce		json
+//	@Param			group_by		query		string									false	"Group by"	Enums(day, week, month)	default(day)
+//	@Param			warehouse_id	query		int										false	"Warehouse id, required for users other than root"
+//	@Param			customer_id		query		int										false	"Customer id"
+//	@Param			rice_id			query		int										false	"Rice id"
+//	@Param			start			query		string									false	"Start"	format(date-time)
+//	@Param			end				query		string									false	"End"	format(date-time)
+//	@Success		200				{object}	response{data=[]invoiceTotalResponse}	"Purchase data"
+//	@Failure		400				{object}	errorResponse							"Validation error"
+//	@Failure		401				{object}	errorResponse							"Unauthorized error"
+//	@Failure		403				{object}	errorResponse							"Forbidden error"
+//	@Failure		500				{object}	errorResponse							"Internal server error"
+//	@Router			/reports/purchases  [get]
+//	@Security		JWTAuth
+func (r *ReportHandler) GetPurchases(ctx *gin.Context) {
+	filter, ok := r.bindInvoiceAnalytics(ctx)
+	if !ok {
+		return
+	}
+
+	totals, err := r.svc.GetPurchases(ctx, *filter)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]invoiceTotalResponse, 0, len(totals))
+	for _, v := range totals {
+		res = append(res, newInvoiceTotalResponse(&v))
+	}
+
+	handleSuccess(ctx, res)
+}
+
+// checkReadAccess check the user can read the warehouse, root can read every warehouse
+func (r *ReportHandler) checkReadAccess(ctx *gin.Context, warehouseID int) bool {
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	isRootUser := token.Role == domain.Root
+	if !isRootUser {
+		err := r.acc.HasAccess(ctx, warehouseID, token.ID, domain.ActionRead)
+		if err != nil {
+			handleError(ctx, err)
+			return false
+		}
+	}
+	return true
+}
+
+type getInventoryValuationRequest struct {
+	Method domain.ValuationMethod `form:"method" binding:"omitempty,oneof=fifo weighted_average" example:"fifo"`
+	At     *time.Time             `form:"at" binding:"omitempty"`
+}
+
+// GetInventoryValuation ql-kho-lua
+//
+//	@Summary		Get inventory valuation
+//	@Description	Get the quantity and cost basis of every rice in a warehouse, valued with FIFO or weighted-average cost
+//	@Tags			reports
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int										true	"Warehouse id"
+//	@Param			method	query		string									false	"Valuation method"	Enums(fifo, weighted_average)	default(fifo)
+//	@Param			at		query		string									false	"Value the stock at this time, default now"	format(date-time)
+//	@Success		200		{object}	response{data=[]inventoryValueResponse}	"Inventory value data"
+//	@Failure		400		{object}	errorResponse							"Validation error"
+//	@Failure		401		{object}	errorResponse							"Unauthorized error"
+//	@Failure		403		{object}	errorResponse							"Forbidden error"
+//	@Failure		404		{object}	errorResponse							"Data not found error"
+//	@Failure		500		{object}	errorResponse							"Internal server error"
+//	@Router			/warehouses/{id}/reports/valuation  [get]
+//	@Security		JWTAuth
+func (r *ReportHandler) GetInventoryValuation(ctx *gin.Context) {
+	warehouseID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	req := getInventoryValuationRequest{
+		Method: domain.ValuationFIFO,
+	}
+	err = ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	if !r.checkReadAccess(ctx, warehouseID) {
+		return
+	}
+
+	at := time.Now()
+	if req.At != nil {
+		at = *req.At
+	}
+
+	values, err := r.svc.GetInventoryValuation(ctx, warehouseID, req.Method, at)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]inventoryValueResponse, 0, len(values))
+	for _, v := range values {
+		res = append(res, newInventoryValueResponse(&v))
+	}
+
+	handleSuccess(ctx, res)
+}
+
+type getGrossMarginRequest struct {
+	Method domain.ValuationMethod `form:"method" binding:"omitempty,oneof=fifo weighted_average" example:"fifo"`
+	Start  time.Time              `form:"start" binding:"required"`
+	End    time.Time              `form:"end" binding:"required"`
+}
+
+// GetGrossMargin ql-kho-lua
+//
+//	@Summary		Get gross margin report
+//	@Description	Get the revenue, cost of goods sold and gross margin of the export invoices of a warehouse over a period, by rice and by invoice
+//	@Tags			reports
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int										true	"Warehouse id"
+//	@Param			method	query		string									false	"Valuation method"	Enums(fifo, weighted_average)	default(fifo)
+//	@Param			start	query		string									true	"Start"	format(date-time)
+//	@Param			end		query		string									true	"End"	format(date-time)
+//	@Success		200		{object}	response{data=grossMarginResponse}		"Gross margin data"
+//	@Failure		400		{object}	errorResponse							"Validation error"
+//	@Failure		401		{object}	errorResponse							"Unauthorized error"
+//	@Failure		403		{object}	errorResponse							"Forbidden error"
+//	@Failure		404		{object}	errorResponse							"Data not found error"
+//	@Failure		500		{object}	errorResponse							"Internal server error"
+//	@Router			/warehouses/{id}/reports/gross_margin  [get]
+//	@Security		JWTAuth
+func (r *ReportHandler) GetGrossMargin(ctx *gin.Context) {
+	warehouseID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	req := getGrossMarginRequest{
+		Method: domain.ValuationFIFO,
+	}
+	err = ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	if !r.checkReadAccess(ctx, warehouseID) {
+		return
+	}
+
+	report, err := r.svc.GetGrossMargin(ctx, warehouseID, req.Method, req.Start, req.End)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newGrossMarginResponse(report)
+	handleSuccess(ctx, res)
+}
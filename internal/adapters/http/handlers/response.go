@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"errors"
 	"math"
 	"net/http"
@@ -82,13 +83,18 @@ func newResponse(success bool, message string, data any) response {
 
 // authResponse represents a auth response body
 type authResponse struct {
+	AccessToken  string `json:"access_token" example:"eyJJ9.eyJpEzNDR9.fUjDw0"`
+	RefreshToken string `json:"refresh_token" example:"eyJJ9.eyJpEzNDR9.aBkEx1"`
+	// Token is the access token, kept for clients that read it from before refresh tokens
 	Token string `json:"token" example:"eyJJ9.eyJpEzNDR9.fUjDw0"`
 }
 
-// newAuthResponse create a auth response for login handler
-func newAuthResponse(token string) authResponse {
+// newAuthResponse create a auth response for login and refresh handler
+func newAuthResponse(token *domain.AuthToken) authResponse {
 	return authResponse{
-		Token: token,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		Token:        token.AccessToken,
 	}
 }
 
@@ -160,16 +166,20 @@ func newUsedCapacityResponse(v int64) usedCapacityResponse {
 
 // warehouseItemResponse represents a item in warehouse
 type warehouseItemResponse struct {
-	ID       int    `json:"id" example:"1"`
-	RiceName string `json:"rice_name" example:"name"`
-	Capacity int    `json:"capacity" example:"500"`
+	ID        int    `json:"id" example:"1"`
+	RiceName  string `json:"rice_name" example:"name"`
+	Capacity  int    `json:"capacity" example:"500"`
+	Reserved  int    `json:"reserved" example:"100"`
+	Available int    `json:"available" example:"400"`
 }
 
 // newWarehouseItemResponse is a helper function to create a response body for handling warehouse item data
 func newWarehouseItemResponse(v *domain.WarehouseItem) warehouseItemResponse {
 	w := warehouseItemResponse{
-		ID:       v.RiceID,
-		Capacity: v.Quantity,
+		ID:        v.RiceID,
+		Capacity:  v.Quantity,
+		Reserved:  v.Reserved,
+		Available: v.Available(),
 	}
 
 	if v.Rice != nil {
@@ -195,11 +205,13 @@ func newRiceResponse(rice *domain.Rice) riceResponse {
 
 // customerResponse represents a customer response body
 type customerResponse struct {
-	ID      int    `json:"id" example:"1"`
-	Name    string `json:"name" example:"Ascalon"`
-	Email   string `json:"email" example:"ascalon@exp.com"`
-	Phone   string `json:"phone" example:"+84123456789"`
-	Address string `json:"address" example:"abc, eyz"`
+	ID      int                `json:"id" example:"1"`
+	Name    string             `json:"name" example:"Ascalon"`
+	Email   string             `json:"email" example:"ascalon@exp.com"`
+	Phone   string             `json:"phone" example:"+84123456789"`
+	Address string             `json:"address" example:"abc, eyz"`
+	Role    domain.PartnerRole `json:"role" example:"supplier"`
+	TaxCode string             `json:"tax_code" example:"0101234567"`
 }
 
 // newCustomerResponse is a helper function to create a response body for handling customer data
@@ -210,15 +222,63 @@ func newCustomerResponse(customer *domain.Customer) customerResponse {
 		Email:   customer.Email,
 		Phone:   customer.Phone,
 		Address: customer.Address,
+		Role:    customer.Role,
+		TaxCode: customer.TaxCode,
 	}
 }
 
+// lotResponse represents a lot response body
+type lotResponse struct {
+	ID              int        `json:"id" example:"1"`
+	RiceID          int        `json:"rice_id" example:"1"`
+	RiceName        string     `json:"rice_name,omitempty" example:"ST25"`
+	ImportInvoiceID int        `json:"import_invoice_id" example:"1"`
+	LotNumber       string     `json:"lot_number" example:"ST25-2409-A"`
+	HarvestDate     *time.Time `json:"harvest_date,omitempty" example:"2024-09-01T00:00:00Z"`
+	ExpiryDate      *time.Time `json:"expiry_date,omitempty" example:"2025-09-01T00:00:00Z"`
+	Grade           string     `json:"grade,omitempty" example:"A"`
+	Moisture        float64    `json:"moisture,omitempty" example:"14.5"`
+	Quantity        int        `json:"quantity" example:"100"`
+	Remaining       int        `json:"remaining" example:"40"`
+}
+
+// newLotResponse is a helper function to create a response body for handling lot data
+func newLotResponse(lot *domain.Lot) lotResponse {
+	res := lotResponse{
+		ID:              lot.ID,
+		RiceID:          lot.RiceID,
+		ImportInvoiceID: lot.ImportInvoiceID,
+		LotNumber:       lot.LotNumber,
+		HarvestDate:     lot.HarvestDate,
+		ExpiryDate:      lot.ExpiryDate,
+		Grade:           lot.Grade,
+		Moisture:        lot.Moisture,
+		Quantity:        lot.Quantity,
+		Remaining:       lot.Remaining,
+	}
+
+	if lot.Rice != nil {
+		res.RiceName = lot.Rice.Name
+	}
+	return res
+}
+
+// lotAllocationResponse represents the quantity an export line took from a lot
+type lotAllocationResponse struct {
+	LotID      int        `json:"lot_id" example:"1"`
+	LotNumber  string     `json:"lot_number" example:"ST25-2409-A"`
+	ExpiryDate *time.Time `json:"expiry_date,omitempty" example:"2025-09-01T00:00:00Z"`
+	Quantity   int        `json:"quantity" example:"5"`
+}
+
 // invoiceDetailResponse represents a invoice detail response body
 type invoiceDetailResponse struct {
-	RiceID   int     `json:"rice_id" example:"1"`
-	Name     string  `json:"name" example:"name"`
-	Price    float64 `json:"price" example:"500"`
-	Quantity int     `json:"quantity" example:"5"`
+	RiceID   int                     `json:"rice_id" example:"1"`
+	Name     string                  `json:"name" example:"name"`
+	Price    float64                 `json:"price" example:"500"`
+	Quantity int                     `json:"quantity" example:"5"`
+	Lot      *lotResponse            `json:"lot,omitempty"`
+	Lots     []lotAllocationResponse `json:"lots,omitempty"`
 }
 
 // NewInvoiceDetail is a helper function to create a invoice Detail response for handling invoice data
@@ -232,6 +292,18 @@ func newInvoiceDetail(invoiceDetail *domain.InvoiceItem) invoiceDetailResponse {
 	if invoiceDetail.Rice != nil {
 		res.Name = invoiceDetail.Rice.Name
 	}
+	if invoiceDetail.Lot != nil {
+		lot := newLotResponse(invoiceDetail.Lot)
+		res.Lot = &lot
+	}
+	for _, v := range invoiceDetail.Lots {
+		res.Lots = append(res.Lots, lotAllocationResponse{
+			LotID:      v.LotID,
+			LotNumber:  v.LotNumber,
+			ExpiryDate: v.ExpiryDate,
+			Quantity:   v.Quantity,
+		})
+	}
 	return res
 }
 
@@ -246,24 +318,44 @@ type invoiceResponse struct {
 	UserName      string                  `json:"user_name,omitempty" example:"vertin"`
 	CreatedAt     time.Time               `json:"created_at" example:"2021-09-01T00:00:00Z"`
 	TotalPrice    float64                 `json:"total_price" example:"500"`
+	Status        domain.InvoiceStatus    `json:"status" example:"completed"`
+	CancelReason  string                  `json:"cancel_reason,omitempty" example:"wrong quantity"`
+	CancelledAt   *time.Time              `json:"cancelled_at,omitempty" example:"2021-09-02T00:00:00Z"`
+	ApprovedBy    *int                    `json:"approved_by,omitempty" example:"1"`
+	ApproverName  string                  `json:"approver_name,omitempty" example:"root"`
+	ApprovedAt    *time.Time              `json:"approved_at,omitempty" example:"2021-09-02T00:00:00Z"`
+	PaidAmount    float64                 `json:"paid_amount" example:"200"`
+	PaymentStatus domain.PaymentStatus    `json:"payment_status" example:"partially_paid"`
+	Reference     string                  `json:"reference,omitempty" example:"weighbridge:1:T-2409-0012"`
 	Details       []invoiceDetailResponse `json:"details,omitempty"`
 }
 
 // newInvoiceResponse is a helper function to create a invoice response for handling invoice data
 func newInvoiceResponse(invoice *domain.Invoice) invoiceResponse {
 	res := invoiceResponse{
-		ID:          invoice.ID,
-		CustomerID:  invoice.CustomerID,
-		WarehouseID: invoice.WarehouseID,
-		UserID:      invoice.UserID,
-		CreatedAt:   invoice.CreatedAt,
-		TotalPrice:  invoice.TotalPrice,
-		Details:     make([]invoiceDetailResponse, 0, len(invoice.Details)),
+		ID:            invoice.ID,
+		CustomerID:    invoice.CustomerID,
+		WarehouseID:   invoice.WarehouseID,
+		UserID:        invoice.UserID,
+		CreatedAt:     invoice.CreatedAt,
+		TotalPrice:    invoice.TotalPrice,
+		Status:        invoice.Status,
+		CancelReason:  invoice.CancelReason,
+		CancelledAt:   invoice.CancelledAt,
+		ApprovedBy:    invoice.ApprovedBy,
+		ApprovedAt:    invoice.ApprovedAt,
+		PaidAmount:    invoice.PaidAmount,
+		PaymentStatus: invoice.PaymentStatus(),
+		Reference:     invoice.Reference,
+		Details:       make([]invoiceDetailResponse, 0, len(invoice.Details)),
 	}
 
 	if invoice.CreatedBy != nil {
 		res.UserName = invoice.CreatedBy.Name
 	}
+	if invoice.Approver != nil {
+		res.ApproverName = invoice.Approver.Name
+	}
 	if invoice.Customer != nil {
 		res.CustomerName = invoice.Customer.Name
 	}
@@ -277,6 +369,436 @@ func newInvoiceResponse(invoice *domain.Invoice) invoiceResponse {
 	return res
 }
 
+// transferResponse represents a transfer response body
+type transferResponse struct {
+	ID              int              `json:"id" example:"1"`
+	FromWarehouseID int              `json:"from_warehouse_id" example:"1"`
+	ToWarehouseID   int              `json:"to_warehouse_id" example:"2"`
+	UserID          int              `json:"user_id" example:"1"`
+	ExportInvoiceID int              `json:"export_invoice_id" example:"1"`
+	ImportInvoiceID int              `json:"import_invoice_id" example:"1"`
+	CreatedAt       time.Time        `json:"created_at" example:"2021-09-01T00:00:00Z"`
+	ExportInvoice   *invoiceResponse `json:"export_invoice,omitempty"`
+	ImportInvoice   *invoiceResponse `json:"import_invoice,omitempty"`
+}
+
+// newTransferResponse is a helper function to create a transfer response for handling transfer data
+func newTransferResponse(transfer *domain.Transfer) transferResponse {
+	res := transferResponse{
+		ID:              transfer.ID,
+		FromWarehouseID: transfer.FromWarehouseID,
+		ToWarehouseID:   transfer.ToWarehouseID,
+		UserID:          transfer.UserID,
+		ExportInvoiceID: transfer.ExportInvoiceID,
+		ImportInvoiceID: transfer.ImportInvoiceID,
+		CreatedAt:       transfer.CreatedAt,
+	}
+
+	if transfer.ExportInvoice != nil {
+		res.ExportInvoice = newPtr(newInvoiceResponse(transfer.ExportInvoice))
+	}
+	if transfer.ImportInvoice != nil {
+		res.ImportInvoice = newPtr(newInvoiceResponse(transfer.ImportInvoice))
+	}
+	return res
+}
+
+// orderResponse represents an order response body
+type orderResponse struct {
+	ID            int                     `json:"id" example:"1"`
+	Type          domain.OrderType        `json:"type" example:"purchase"`
+	CustomerID    int                     `json:"customer_id" example:"1"`
+	CustomerName  string                  `json:"customer_name,omitempty" example:"Ascalon"`
+	WarehouseID   int                     `json:"warehouse_id" example:"1"`
+	WarehouseName string                  `json:"warehouse_name,omitempty" example:"store 01"`
+	UserID        int                     `json:"user_id" example:"1"`
+	UserName      string                  `json:"user_name,omitempty" example:"vertin"`
+	Status        domain.OrderStatus      `json:"status" example:"confirmed"`
+	TotalPrice    float64                 `json:"total_price" example:"500"`
+	InvoiceID     *int                    `json:"invoice_id,omitempty" example:"1"`
+	CreatedAt     time.Time               `json:"created_at" example:"2021-09-01T00:00:00Z"`
+	ConfirmedAt   *time.Time              `json:"confirmed_at,omitempty" example:"2021-09-01T00:00:00Z"`
+	ClosedAt      *time.Time              `json:"closed_at,omitempty" example:"2021-09-02T00:00:00Z"`
+	Details       []invoiceDetailResponse `json:"details,omitempty"`
+}
+
+// newOrderResponse is a helper function to create an order response for handling order data
+func newOrderResponse(order *domain.Order) orderResponse {
+	res := orderResponse{
+		ID:          order.ID,
+		Type:        order.Type,
+		CustomerID:  order.CustomerID,
+		WarehouseID: order.WarehouseID,
+		UserID:      order.UserID,
+		Status:      order.Status,
+		TotalPrice:  order.TotalPrice,
+		InvoiceID:   order.InvoiceID,
+		CreatedAt:   order.CreatedAt,
+		ConfirmedAt: order.ConfirmedAt,
+		ClosedAt:    order.ClosedAt,
+		Details:     make([]invoiceDetailResponse, 0, len(order.Details)),
+	}
+
+	if order.CreatedBy != nil {
+		res.UserName = order.CreatedBy.Name
+	}
+	if order.Customer != nil {
+		res.CustomerName = order.Customer.Name
+	}
+	if order.Warehouse != nil {
+		res.WarehouseName = order.Warehouse.Name
+	}
+
+	for _, v := range order.Details {
+		res.Details = append(res.Details, newInvoiceDetail(&v))
+	}
+	return res
+}
+
+// sessionResponse represents a session response body
+type sessionResponse struct {
+	ID         int       `json:"id" example:"1"`
+	UserAgent  string    `json:"user_agent" example:"Mozilla/5.0 (Windows NT 10.0; Win64; x64)"`
+	IP         string    `json:"ip" example:"127.0.0.1"`
+	CreatedAt  time.Time `json:"created_at" example:"2021-09-01T00:00:00Z"`
+	LastSeenAt time.Time `json:"last_seen_at" example:"2021-09-01T00:00:00Z"`
+	Current    bool      `json:"current" example:"true"`
+}
+
+// newSessionResponse is a helper function to create a session response, current marks the session of the request
+func newSessionResponse(session *domain.Session, current bool) sessionResponse {
+	return sessionResponse{
+		ID:         session.ID,
+		UserAgent:  session.UserAgent,
+		IP:         session.IP,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		Current:    current,
+	}
+}
+
+// auditLogResponse represents an audit log response body
+type auditLogResponse struct {
+	ID        int                `json:"id" example:"1"`
+	ActorID   *int               `json:"actor_id" example:"1"`
+	ActorName string             `json:"actor_name" example:"vertin"`
+	Action    domain.AuditAction `json:"action" example:"update"`
+	Entity    domain.AuditEntity `json:"entity" example:"warehouse"`
+	EntityID  int                `json:"entity_id" example:"1"`
+	Before    json.RawMessage    `json:"before" swaggertype:"object"`
+	After     json.RawMessage    `json:"after" swaggertype:"object"`
+	IP        string             `json:"ip" example:"127.0.0.1"`
+	CreatedAt time.Time          `json:"created_at" example:"2021-09-01T00:00:00Z"`
+}
+
+// newAuditLogResponse is a helper function to create a response body for handling audit log data
+func newAuditLogResponse(log *domain.AuditLog) auditLogResponse {
+	return auditLogResponse{
+		ID:        log.ID,
+		ActorID:   log.ActorID,
+		ActorName: log.ActorName,
+		Action:    log.Action,
+		Entity:    log.Entity,
+		EntityID:  log.EntityID,
+		Before:    log.Before,
+		After:     log.After,
+		IP:        log.IP,
+		CreatedAt: log.CreatedAt,
+	}
+}
+
+// stockMovementResponse represents a stock movement of a rice in a warehouse
+type stockMovementResponse struct {
+	RiceID   int    `json:"rice_id" example:"1"`
+	RiceName string `json:"rice_name" example:"ST25"`
+	Opening  int    `json:"opening" example:"100"`
+	Imported int    `json:"imported" example:"50"`
+	Exported int    `json:"exported" example:"30"`
+	Closing  int    `json:"closing" example:"120"`
+}
+
+// newStockMovementResponse is a helper function to create a response body for handling stock movement data
+func newStockMovementResponse(m *domain.StockMovement) stockMovementResponse {
+	return stockMovementResponse{
+		RiceID:   m.RiceID,
+		RiceName: m.RiceName,
+		Opening:  m.Opening,
+		Imported: m.Imported,
+		Exported: m.Exported,
+		Closing:  m.Closing,
+	}
+}
+
+// invoiceTotalResponse represents the invoice totals of a period
+type invoiceTotalResponse struct {
+	Period   time.Time `json:"period" example:"2024-09-01T00:00:00Z"`
+	Amount   float64   `json:"amount" example:"15000"`
+	Quantity int       `json:"quantity" example:"300"`
+	Invoices int       `json:"invoices" example:"4"`
+}
+
+// newInvoiceTotalResponse is a helper function to create a response body for handling invoice total data
+func newInvoiceTotalResponse(t *domain.InvoiceTotal) invoiceTotalResponse {
+	return invoiceTotalResponse{
+		Period:   t.Period,
+		Amount:   t.Amount,
+		Quantity: t.Quantity,
+		Invoices: t.Invoices,
+	}
+}
+
+// inventoryValueResponse represents the cost basis of a rice in a warehouse
+type inventoryValueResponse struct {
+	RiceID   int     `json:"rice_id" example:"1"`
+	RiceName string  `json:"rice_name" example:"ST25"`
+	Quantity int     `json:"quantity" example:"120"`
+	UnitCost float64 `json:"unit_cost" example:"12.5"`
+	Value    float64 `json:"value" example:"1500"`
+}
+
+// newInventoryValueResponse is a helper function to create a response body for handling inventory value data
+func newInventoryValueResponse(v *domain.InventoryValue) inventoryValueResponse {
+	return inventoryValueResponse{
+		RiceID:   v.RiceID,
+		RiceName: v.RiceName,
+		Quantity: v.Quantity,
+		UnitCost: v.UnitCost,
+		Value:    v.Value,
+	}
+}
+
+// grossMarginResponse represents a gross margin report response body
+type grossMarginResponse struct {
+	Method  domain.ValuationMethod `json:"method" example:"fifo"`
+	Revenue float64                `json:"revenue" example:"2000"`
+	COGS    float64                `json:"cogs" example:"1500"`
+	Margin  float64                `json:"margin" example:"500"`
+	Rice    []domain.RiceMargin    `json:"rice"`
+	Exports []domain.ExportCost    `json:"exports"`
+}
+
+// newGrossMarginResponse is a helper function to create a response body for handling gross margin data
+func newGrossMarginResponse(r *domain.GrossMarginReport) grossMarginResponse {
+	return grossMarginResponse{
+		Method:  r.Method,
+		Revenue: r.Revenue,
+		COGS:    r.COGS,
+		Margin:  r.Margin,
+		Rice:    r.Rice,
+		Exports: r.Exports,
+	}
+}
+
+// stockThresholdResponse represents a stock threshold response body
+type stockThresholdResponse struct {
+	WarehouseID int       `json:"warehouse_id" example:"1"`
+	RiceID      int       `json:"rice_id" example:"1"`
+	RiceName    string    `json:"rice_name,omitempty" example:"ST25"`
+	MinQuantity int       `json:"min_quantity" example:"100"`
+	UpdatedAt   time.Time `json:"updated_at" example:"2021-09-01T00:00:00Z"`
+}
+
+// newStockThresholdResponse is a helper function to create a response body for handling stock threshold data
+func newStockThresholdResponse(t *domain.StockThreshold) stockThresholdResponse {
+	res := stockThresholdResponse{
+		WarehouseID: t.WarehouseID,
+		RiceID:      t.RiceID,
+		MinQuantity: t.MinQuantity,
+		UpdatedAt:   t.UpdatedAt,
+	}
+
+	if t.Rice != nil {
+		res.RiceName = t.Rice.Name
+	}
+	return res
+}
+
+// alertResponse represents an alert response body
+type alertResponse struct {
+	ID             int              `json:"id" example:"1"`
+	Type           domain.AlertType `json:"type" example:"low_stock"`
+	WarehouseID    int              `json:"warehouse_id" example:"1"`
+	RiceID         *int             `json:"rice_id,omitempty" example:"1"`
+	LotID          *int             `json:"lot_id,omitempty" example:"1"`
+	Message        string           `json:"message" example:"ST25 is low in stock: 20 left, minimum 100"`
+	Value          int              `json:"value" example:"20"`
+	Threshold      int              `json:"threshold" example:"100"`
+	CreatedAt      time.Time        `json:"created_at" example:"2021-09-01T00:00:00Z"`
+	AcknowledgedAt *time.Time       `json:"acknowledged_at,omitempty" example:"2021-09-02T00:00:00Z"`
+	AcknowledgedBy *int             `json:"acknowledged_by,omitempty" example:"1"`
+}
+
+// newAlertResponse is a helper function to create a response body for handling alert data
+func newAlertResponse(a *domain.Alert) alertResponse {
+	return alertResponse{
+		ID:             a.ID,
+		Type:           a.Type,
+		WarehouseID:    a.WarehouseID,
+		RiceID:         a.RiceID,
+		LotID:          a.LotID,
+		Message:        a.Message,
+		Value:          a.Value,
+		Threshold:      a.Threshold,
+		CreatedAt:      a.CreatedAt,
+		AcknowledgedAt: a.AcknowledgedAt,
+		AcknowledgedBy: a.AcknowledgedBy,
+	}
+}
+
+// subscriptionResponse represents a subscription response body
+type subscriptionResponse struct {
+	ID        int                        `json:"id" example:"1"`
+	EventType domain.EventType           `json:"event_type" example:"export_invoice.large"`
+	Channel   domain.NotificationChannel `json:"channel" example:"webhook"`
+	Target    string                     `json:"target,omitempty" example:"https://example.com/hooks/kho"`
+	CreatedAt time.Time                  `json:"created_at" example:"2021-09-01T00:00:00Z"`
+}
+
+// newSubscriptionResponse is a helper function to create a response body for handling subscription data
+func newSubscriptionResponse(s *domain.Subscription) subscriptionResponse {
+	return subscriptionResponse{
+		ID:        s.ID,
+		EventType: s.EventType,
+		Channel:   s.Channel,
+		Target:    s.Target,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+// notificationResponse represents an in-app notification response body
+type notificationResponse struct {
+	ID          int              `json:"id" example:"1"`
+	EventType   domain.EventType `json:"event_type" example:"alert.low_stock"`
+	WarehouseID int              `json:"warehouse_id" example:"1"`
+	Subject     string           `json:"subject" example:"low_stock alert in warehouse 1"`
+	Message     string           `json:"message" example:"ST25 is low in stock: 20 left, minimum 100"`
+	CreatedAt   time.Time        `json:"created_at" example:"2021-09-01T00:00:00Z"`
+	ReadAt      *time.Time       `json:"read_at,omitempty" example:"2021-09-02T00:00:00Z"`
+}
+
+// newNotificationResponse is a helper function to create a response body for handling notification data
+func newNotificationResponse(n *domain.Notification) notificationResponse {
+	return notificationResponse{
+		ID:          n.ID,
+		EventType:   n.EventType,
+		WarehouseID: n.WarehouseID,
+		Subject:     n.Subject,
+		Message:     n.Message,
+		CreatedAt:   n.CreatedAt,
+		ReadAt:      n.ReadAt,
+	}
+}
+
+// webhookResponse represents a webhook response body
+type webhookResponse struct {
+	ID        int                   `json:"id" example:"1"`
+	URL       string                `json:"url" example:"https://erp.example.com/hooks/kho"`
+	Events    []domain.WebhookEvent `json:"events" example:"stock.changed"`
+	Active    bool                  `json:"active" example:"true"`
+	CreatedAt time.Time             `json:"created_at" example:"2021-09-01T00:00:00Z"`
+	UpdatedAt time.Time             `json:"updated_at" example:"2021-09-01T00:00:00Z"`
+}
+
+// newWebhookResponse is a helper function to create a response body for handling webhook data
+func newWebhookResponse(w *domain.Webhook) webhookResponse {
+	return webhookResponse{
+		ID:        w.ID,
+		URL:       w.URL,
+		Events:    w.Events,
+		Active:    w.Active,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+// webhookSecretResponse represents a created webhook response body, the only one carrying the secret
+type webhookSecretResponse struct {
+	webhookResponse
+	Secret string `json:"secret" example:"9d713ed406bb7076d4123f0dc2c39d2df5c654ed4b0cd56b52c8b4c940bd63ae"`
+}
+
+// newWebhookSecretResponse is a helper function to create a response body for handling created webhook data
+func newWebhookSecretResponse(w *domain.Webhook) webhookSecretResponse {
+	return webhookSecretResponse{
+		webhookResponse: newWebhookResponse(w),
+		Secret:          w.Secret,
+	}
+}
+
+// outboxResponse represents a webhook outbox message response body
+type outboxResponse struct {
+	ID            int                 `json:"id" example:"1"`
+	WebhookID     int                 `json:"webhook_id" example:"1"`
+	EventID       string              `json:"event_id" example:"2f1c4c1e-8f0e-4a5b-9d0c-3b8f0e6a7c11"`
+	Event         domain.WebhookEvent `json:"event" example:"stock.changed"`
+	Payload       json.RawMessage     `json:"payload" swaggertype:"object"`
+	Status        domain.OutboxStatus `json:"status" example:"dead"`
+	Attempts      int                 `json:"attempts" example:"8"`
+	NextAttemptAt time.Time           `json:"next_attempt_at" example:"2021-09-01T00:00:00Z"`
+	LastError     string              `json:"last_error,omitempty" example:"webhook responded 500 Internal Server Error"`
+	CreatedAt     time.Time           `json:"created_at" example:"2021-09-01T00:00:00Z"`
+	DeliveredAt   *time.Time          `json:"delivered_at,omitempty" example:"2021-09-01T00:00:00Z"`
+}
+
+// newOutboxResponse is a helper function to create a response body for handling outbox message data
+func newOutboxResponse(o *domain.OutboxMessage) outboxResponse {
+	return outboxResponse{
+		ID:            o.ID,
+		WebhookID:     o.WebhookID,
+		EventID:       o.EventID,
+		Event:         o.Event,
+		Payload:       o.Payload,
+		Status:        o.Status,
+		Attempts:      o.Attempts,
+		NextAttemptAt: o.NextAttemptAt,
+		LastError:     o.LastError,
+		CreatedAt:     o.CreatedAt,
+		DeliveredAt:   o.DeliveredAt,
+	}
+}
+
+// paymentResponse represents a payment response body
+type paymentResponse struct {
+	ID           int                  `json:"id" example:"1"`
+	InvoiceKind  domain.InvoiceKind   `json:"invoice_kind" example:"export"`
+	InvoiceID    int                  `json:"invoice_id" example:"1"`
+	CustomerID   int                  `json:"customer_id" example:"1"`
+	CustomerName string               `json:"customer_name,omitempty" example:"Ascalon"`
+	WarehouseID  int                  `json:"warehouse_id" example:"1"`
+	UserID       int                  `json:"user_id" example:"1"`
+	UserName     string               `json:"user_name,omitempty" example:"vertin"`
+	Amount       float64              `json:"amount" example:"200"`
+	Method       domain.PaymentMethod `json:"method" example:"bank_transfer"`
+	Reference    string               `json:"reference,omitempty" example:"FT24001"`
+	PaidAt       time.Time            `json:"paid_at" example:"2021-09-01T00:00:00Z"`
+	CreatedAt    time.Time            `json:"created_at" example:"2021-09-01T00:00:00Z"`
+}
+
+// newPaymentResponse is a helper function to create a response body for handling payment data
+func newPaymentResponse(p *domain.Payment) paymentResponse {
+	res := paymentResponse{
+		ID:          p.ID,
+		InvoiceKind: p.InvoiceKind,
+		InvoiceID:   p.InvoiceID,
+		CustomerID:  p.CustomerID,
+		WarehouseID: p.WarehouseID,
+		UserID:      p.UserID,
+		Amount:      p.Amount,
+		Method:      p.Method,
+		Reference:   p.Reference,
+		PaidAt:      p.PaidAt,
+		CreatedAt:   p.CreatedAt,
+	}
+
+	if p.Customer != nil {
+		res.CustomerName = p.Customer.Name
+	}
+	if p.CreatedBy != nil {
+		res.UserName = p.CreatedBy.Name
+	}
+	return res
+}
+
 // errorStatusMap is a map of defined error messages and their corresponding http status codes
 var errorStatusMap = map[error]int{
 	domain.ErrInternal:                   http.StatusInternalServerError,
@@ -289,10 +811,33 @@ var errorStatusMap = map[error]int{
 	domain.ErrInvalidAuthorizationType:   http.StatusUnauthorized,
 	domain.ErrInvalidToken:               http.StatusUnauthorized,
 	domain.ErrExpiredToken:               http.StatusUnauthorized,
+	domain.ErrRefreshTokenReused:         http.StatusUnauthorized,
 	domain.ErrForbidden:                  http.StatusForbidden,
 	domain.ErrNoUpdatedData:              http.StatusBadRequest,
 	domain.ErrWarehouseFull:              http.StatusBadRequest,
 	domain.ErrInsufficientStock:          http.StatusBadRequest,
+	domain.ErrInvoiceCancelled:           http.StatusConflict,
+	domain.ErrSameWarehouseTransfer:      http.StatusBadRequest,
+	domain.ErrLotConsumed:                http.StatusConflict,
+	domain.ErrInvoiceNotPending:          http.StatusConflict,
+	domain.ErrInvoiceNotCompleted:        http.StatusConflict,
+	domain.ErrInvalidLotAllocation:       http.StatusBadRequest,
+	domain.ErrAlertAcknowledged:          http.StatusConflict,
+	domain.ErrChannelUnavailable:         http.StatusBadRequest,
+	domain.ErrWebhookTargetRequired:      http.StatusBadRequest,
+	domain.ErrOutboxNotDead:              http.StatusConflict,
+	domain.ErrInvalidOrderStatus:         http.StatusConflict,
+	domain.ErrInvalidPartnerRole:         http.StatusBadRequest,
+	domain.ErrInvoiceNotPayable:          http.StatusConflict,
+	domain.ErrOverpayment:                http.StatusBadRequest,
+	domain.ErrInvoicePaid:                http.StatusConflict,
+	domain.ErrTransferInvoice:            http.StatusConflict,
+	domain.ErrTransferReversed:           http.StatusConflict,
+	domain.ErrInvalidDateRange:           http.StatusBadRequest,
+	domain.ErrInvalidReportPeriod:        http.StatusBadRequest,
+	domain.ErrInvalidValuationMethod:     http.StatusBadRequest,
+	domain.ErrIdempotencyKeyReused:       http.StatusUnprocessableEntity,
+	domain.ErrIdempotencyInProgress:      http.StatusConflict,
 }
 
 // handleSuccess write success response with status code 200 mess Success and data
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/sheet"
 	"github.com/tommjj/ql-kho-lua/internal/core/domain"
 	"github.com/tommjj/ql-kho-lua/internal/core/ports"
 )
@@ -95,9 +96,10 @@ func (r *RiceHandler) GetRiceByID(ctx *gin.Context) {
 }
 
 type getListRiceRequest struct {
-	Query string `form:"q" binding:"" example:"teo"`
-	Skip  int    `form:"skip" binding:"min=1" example:"1"`
-	Limit int    `form:"limit" binding:"min=5" example:"5"`
+	Query  string       `form:"q" binding:"" example:"teo"`
+	Skip   int          `form:"skip" binding:"min=1" example:"1"`
+	Limit  int          `form:"limit" binding:"min=5" example:"5"`
+	Format sheet.Format `form:"format" binding:"omitempty,oneof=csv xlsx" example:"csv"`
 }
 
 // GetListRice ql-kho-lua
@@ -110,6 +112,7 @@ type getListRiceRequest struct {
 //	@Param			q		query		string										false	"Query"
 //	@Param			skip	query		int											false	"Skip"	default(1)	minimum(1)
 //	@Param			limit	query		int											false	"Limit"	default(5)	minimum(5)
+//	@Param			format	query		string										false	"Export every matching row as a file instead of a page"	Enums(csv, xlsx)
 //	@Success		200		{object}	responseWithPagination{data=[]riceResponse}	"Rice data"
 //	@Failure		400		{object}	errorResponse								"Validation error"
 //	@Failure		401		{object}	errorResponse								"Unauthorized error"
@@ -128,6 +131,22 @@ func (r *RiceHandler) GetListRice(ctx *gin.Context) {
 		return
 	}
 
+	if req.Format != "" {
+		exportList(ctx, req.Format, "rice", riceExportHeader, func(afterID, limit int) ([][]any, int, error) {
+			rice, err := r.svc.GetListRiceAfter(ctx, req.Query, afterID, limit)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			rows := make([][]any, 0, len(rice))
+			for _, item := range rice {
+				rows = append(rows, newRiceExportRow(&item))
+			}
+			return rows, rice[len(rice)-1].ID, nil
+		})
+		return
+	}
+
 	count, err := r.svc.CountRice(ctx, req.Query)
 	if err != nil {
 		handleError(ctx, err)
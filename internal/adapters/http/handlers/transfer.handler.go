@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+type TransferHandler struct {
+	svc ports.ITransferService
+	acc ports.IAccessControlService
+}
+
+func NewTransferHandler(svc ports.ITransferService, acc ports.IAccessControlService) *TransferHandler {
+	return &TransferHandler{
+		svc: svc,
+		acc: acc,
+	}
+}
+
+type detailTransferRequest struct {
+	RiceID   int `json:"rice_id" binding:"required"`
+	Quantity int `json:"quantity" binding:"required,min=1"`
+	// Lots name the lots to take the quantity from, they are chosen first-expiry-first-out when empty
+	Lots []lotAllocationRequest `json:"lots" binding:"omitempty,unique=LotID,dive"`
+}
+
+type createTransferRequest struct {
+	FromWarehouseID int                     `json:"from_warehouse_id" binding:"required"`
+	ToWarehouseID   int                     `json:"to_warehouse_id" binding:"required,nefield=FromWarehouseID"`
+	Details         []detailTransferRequest `json:"details" binding:"required,min=1,unique=RiceID"`
+}
+
+// CreateTransfer ql-kho-lua
+//
+//	@Summary		Transfer rice between warehouses
+//	@Description	Create an export invoice on the source warehouse and an import invoice on the destination warehouse in one transaction.
+//	@Description	The invoices have no partner and are priced at the weighted average cost of the rice in the source warehouse
+//	@Tags			transfers
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		createTransferRequest				true	"Create transfer body"
+//	@Success		200		{object}	response{data=transferResponse}	"Created transfer data"
+//	@Failure		400		{object}	errorResponse						"Validation error"
+//	@Failure		401		{object}	errorResponse						"Unauthorized error"
+//	@Failure		403		{object}	errorResponse						"Forbidden error"
+//	@Failure		404		{object}	errorResponse						"Data not found error"
+//	@Failure		500		{object}	errorResponse						"Internal server error"
+//	@Router			/transfers  [post]
+//	@Security		JWTAuth
+func (t *TransferHandler) CreateTransfer(ctx *gin.Context) {
+	var req createTransferRequest
+	err := ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	isRootUser := token.Role == domain.Root
+	if !isRootUser {
+		err := t.acc.HasAccess(ctx, req.FromWarehouseID, token.ID, domain.ActionExport)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+
+		err = t.acc.HasAccess(ctx, req.ToWarehouseID, token.ID, domain.ActionImport)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+	}
+
+	transfer := &domain.Transfer{
+		FromWarehouseID: req.FromWarehouseID,
+		ToWarehouseID:   req.ToWarehouseID,
+		UserID:          token.ID,
+		Details:         make([]domain.InvoiceItem, 0, len(req.Details)),
+	}
+	for _, v := range req.Details {
+		item := domain.InvoiceItem{
+			Quantity: v.Quantity,
+			RiceID:   v.RiceID,
+		}
+		for _, lot := range v.Lots {
+			item.Lots = append(item.Lots, domain.LotAllocation{LotID: lot.LotID, Quantity: lot.Quantity})
+		}
+		transfer.Details = append(transfer.Details, item)
+	}
+
+	created, err := t.svc.CreateTransfer(ctx, transfer)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newTransferResponse(created)
+	handleSuccess(ctx, res)
+}
+
+// GetTransferByID ql-kho-lua
+//
+//	@Summary		Get a transfer by id
+//	@Description	Get a transfer with its export and import invoices by id
+//	@Tags			transfers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int								true	"Transfer id"
+//	@Success		200	{object}	response{data=transferResponse}	"Transfer data"
+//	@Failure		400	{object}	errorResponse					"Validation error"
+//	@Failure		401	{object}	errorResponse					"Unauthorized error"
+//	@Failure		403	{object}	errorResponse					"Forbidden error"
+//	@Failure		404	{object}	errorResponse					"Data not found error"
+//	@Failure		500	{object}	errorResponse					"Internal server error"
+//	@Router			/transfers/{id}  [get]
+//	@Security		JWTAuth
+func (t *TransferHandler) GetTransferByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	numID, err := strconv.Atoi(id)
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	transfer, err := t.svc.GetTransferByID(ctx, numID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	isRootUser := token.Role == domain.Root
+	if !isRootUser {
+		errFrom := t.acc.HasAccess(ctx, transfer.FromWarehouseID, token.ID, domain.ActionRead)
+		errTo := t.acc.HasAccess(ctx, transfer.ToWarehouseID, token.ID, domain.ActionRead)
+		if errFrom != nil && errTo != nil {
+			handleError(ctx, errFrom)
+			return
+		}
+	}
+
+	res := newTransferResponse(transfer)
+	handleSuccess(ctx, res)
+}
+
+// ReverseTransfer ql-kho-lua
+//
+//	@Summary		Reverse a transfer
+//	@Description	Cancel the export and import invoice of a transfer together and move the rice back to the source warehouse.
+//	@Description	The invoices of a transfer can not be cancelled on their own
+//	@Tags			transfers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int								true	"Transfer id"
+//	@Param			request	body		cancelInvoiceRequest			true	"Reverse transfer body"
+//	@Success		200		{object}	response{data=transferResponse}	"Reversed transfer data"
+//	@Failure		400		{object}	errorResponse					"Validation error"
+//	@Failure		401		{object}	errorResponse					"Unauthorized error"
+//	@Failure		403		{object}	errorResponse					"Forbidden error"
+//	@Failure		404		{object}	errorResponse					"Data not found error"
+//	@Failure		409		{object}	errorResponse					"Conflicting data error"
+//	@Failure		500		{object}	errorResponse					"Internal server error"
+//	@Router			/transfers/{id}/reverse  [post]
+//	@Security		JWTAuth
+func (t *TransferHandler) ReverseTransfer(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	numID, err := strconv.Atoi(id)
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	var req cancelInvoiceRequest
+	err = ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	isRootUser := token.Role == domain.Root
+	if !isRootUser {
+		transfer, err := t.svc.GetTransferByID(ctx, numID)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+
+		// the rice is taken out of the destination and put back into the source
+		err = t.acc.HasAccess(ctx, transfer.ToWarehouseID, token.ID, domain.ActionExport)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+
+		err = t.acc.HasAccess(ctx, transfer.FromWarehouseID, token.ID, domain.ActionImport)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+	}
+
+	reversed, err := t.svc.ReverseTransfer(ctx, numID, req.Reason)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newTransferResponse(reversed)
+	handleSuccess(ctx, res)
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/sheet"
 	"github.com/tommjj/ql-kho-lua/internal/core/domain"
 	"github.com/tommjj/ql-kho-lua/internal/core/ports"
 )
@@ -20,10 +21,11 @@ func NewUserHandler(userService ports.IUserService) *UserHandler {
 }
 
 type createUserRequest struct {
-	Name     string `json:"name" binding:"required,min=3,max=32" example:"vertin"`
-	Email    string `json:"email" binding:"required,email" example:"example@exm.com"`
-	Phone    string `json:"phone" binding:"required,e164" example:"+84123456788"`
-	Password string `json:"password" binding:"required,min=8,max=12" example:"password"`
+	Name     string      `json:"name" binding:"required,min=3,max=32" example:"vertin"`
+	Email    string      `json:"email" binding:"required,email" example:"example@exm.com"`
+	Phone    string      `json:"phone" binding:"required,e164" example:"+84123456788"`
+	Password string      `json:"password" binding:"required,min=8,max=12" example:"password"`
+	Role     domain.Role `json:"role" binding:"omitempty,user_role" example:"member" enums:"member,warehouse_manager,accountant,viewer"`
 }
 
 // CreateUser ql-kho-lua
@@ -51,12 +53,20 @@ func (u *UserHandler) CreateUser(ctx *gin.Context) {
 		return
 	}
 
+	if req.Role == "" {
+		req.Role = domain.Member
+	}
+	if req.Role == domain.Root {
+		validationError(ctx, errors.New("role root can not be assigned"))
+		return
+	}
+
 	createdUser, err := u.svc.CreateUser(ctx, &domain.User{
 		Name:     req.Name,
 		Email:    req.Email,
 		Phone:    req.Phone,
 		Password: req.Password,
-		Role:     domain.Member,
+		Role:     req.Role,
 	})
 	if err != nil {
 		handleError(ctx, err)
@@ -94,8 +104,8 @@ func (u *UserHandler) GetUserByID(ctx *gin.Context) {
 
 	token := getAuthPayload(ctx, authorizationPayloadKey)
 
-	isRootUser := token.Role == domain.Root
-	if !isRootUser {
+	canManageUsers := token.Role.HasPermission(domain.PermUserManage)
+	if !canManageUsers {
 		if token.ID != numID {
 			handleError(ctx, domain.ErrForbidden)
 			return
@@ -113,9 +123,10 @@ func (u *UserHandler) GetUserByID(ctx *gin.Context) {
 }
 
 type getListUserRequest struct {
-	Query string `form:"q" binding:"" example:"teo"`
-	Skip  int    `form:"skip" binding:"min=1" example:"1"`
-	Limit int    `form:"limit" binding:"min=5" example:"5"`
+	Query  string       `form:"q" binding:"" example:"teo"`
+	Skip   int          `form:"skip" binding:"min=1" example:"1"`
+	Limit  int          `form:"limit" binding:"min=5" example:"5"`
+	Format sheet.Format `form:"format" binding:"omitempty,oneof=csv xlsx" example:"csv"`
 }
 
 // GetListUsers ql-kho-lua
@@ -128,6 +139,7 @@ type getListUserRequest struct {
 //	@Param			q		query		string										false	"Query"
 //	@Param			skip	query		int											false	"Skip"	default(1)	minimum(1)
 //	@Param			limit	query		int											false	"Limit"	default(5)	minimum(5)
+//	@Param			format	query		string										false	"Export every matching row as a file instead of a page"	Enums(csv, xlsx)
 //	@Success		200		{object}	responseWithPagination{data=[]userResponse}	"Users data"
 //	@Failure		400		{object}	errorResponse								"Validation error"
 //	@Failure		401		{object}	errorResponse								"Unauthorized error"
@@ -147,6 +159,22 @@ func (u *UserHandler) GetListUsers(ctx *gin.Context) {
 		return
 	}
 
+	if req.Format != "" {
+		exportList(ctx, req.Format, "users", userExportHeader, func(afterID, limit int) ([][]any, int, error) {
+			users, err := u.svc.GetListUsersAfter(ctx, req.Query, afterID, limit)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			rows := make([][]any, 0, len(users))
+			for _, user := range users {
+				rows = append(rows, newUserExportRow(&user))
+			}
+			return rows, users[len(users)-1].ID, nil
+		})
+		return
+	}
+
 	count, err := u.svc.CountUsers(ctx, req.Query)
 	if err != nil {
 		handleError(ctx, err)
@@ -211,8 +239,8 @@ func (u *UserHandler) UpdateUser(ctx *gin.Context) {
 
 	token := getAuthPayload(ctx, authorizationPayloadKey)
 
-	isRootUser := token.Role == domain.Root
-	if !isRootUser {
+	canManageUsers := token.Role.HasPermission(domain.PermUserManage)
+	if !canManageUsers {
 		if token.ID != numID {
 			handleError(ctx, domain.ErrForbidden)
 			return
@@ -247,6 +275,71 @@ func (u *UserHandler) UpdateUser(ctx *gin.Context) {
 	handleSuccess(ctx, res)
 }
 
+type updateUserRoleRequest struct {
+	Role domain.Role `json:"role" binding:"required,user_role" example:"viewer" enums:"member,warehouse_manager,accountant,viewer"`
+}
+
+// UpdateUserRole ql-kho-lua
+//
+//	@Summary		Update user role
+//	@Description	Update the role of a user, the role decides the permissions of the user. Changes take effect when the user logs in or refreshes the token.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int							true	"User id"
+//	@Param			request	body		updateUserRoleRequest		true	"Update user role body"
+//	@Success		200		{object}	response{data=userResponse}	"Updated user data"
+//	@Failure		400		{object}	errorResponse				"Validation error"
+//	@Failure		401		{object}	errorResponse				"Unauthorized error"
+//	@Failure		403		{object}	errorResponse				"Forbidden error"
+//	@Failure		404		{object}	errorResponse				"Data not found error"
+//	@Failure		500		{object}	errorResponse				"Internal server error"
+//	@Router			/users/{id}/role [patch]
+//	@Security		JWTAuth
+func (u *UserHandler) UpdateUserRole(ctx *gin.Context) {
+	var req updateUserRoleRequest
+
+	numID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	err = ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	if req.Role == domain.Root {
+		validationError(ctx, errors.New("role root can not be assigned"))
+		return
+	}
+
+	user, err := u.svc.GetUserByID(ctx, numID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	if user.Role == domain.Root {
+		handleError(ctx, domain.ErrForbidden)
+		return
+	}
+
+	updatedUser, err := u.svc.UpdateUser(ctx, &domain.User{
+		ID:   numID,
+		Role: req.Role,
+	})
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newUserResponse(updatedUser)
+	handleSuccess(ctx, res)
+}
+
 // DeleteUserByID ql-kho-lua
 //
 //	@Summary		delete user
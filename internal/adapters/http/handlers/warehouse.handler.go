@@ -6,16 +6,17 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/sheet"
 	"github.com/tommjj/ql-kho-lua/internal/core/domain"
 	"github.com/tommjj/ql-kho-lua/internal/core/ports"
 )
 
 type WarehouseHandler struct {
 	scv ports.IWarehouseService
-	acc ports.IAccessControlRepository
+	acc ports.IAccessControlService
 }
 
-func NewWarehouseHandler(warehouseService ports.IWarehouseService, accessControl ports.IAccessControlRepository) *WarehouseHandler {
+func NewWarehouseHandler(warehouseService ports.IWarehouseService, accessControl ports.IAccessControlService) *WarehouseHandler {
 	return &WarehouseHandler{
 		scv: warehouseService,
 		acc: accessControl,
@@ -105,7 +106,7 @@ func (w *WarehouseHandler) GetWarehouseByID(ctx *gin.Context) {
 	isRoot := token.Role == domain.Root
 
 	if !isRoot {
-		err := w.acc.HasAccess(ctx, numID, token.ID)
+		err := w.acc.HasAccess(ctx, numID, token.ID, domain.ActionRead)
 		if err != nil {
 			handleError(ctx, err)
 			return
@@ -123,9 +124,10 @@ func (w *WarehouseHandler) GetWarehouseByID(ctx *gin.Context) {
 }
 
 type getListWarehouseRequest struct {
-	Query string `form:"q" binding:"" example:"store 01"`
-	Skip  int    `form:"skip" binding:"min=1" example:"1"`
-	Limit int    `form:"limit" binding:"min=5" example:"5"`
+	Query  string       `form:"q" binding:"" example:"store 01"`
+	Skip   int          `form:"skip" binding:"min=1" example:"1"`
+	Limit  int          `form:"limit" binding:"min=5" example:"5"`
+	Format sheet.Format `form:"format" binding:"omitempty,oneof=csv xlsx" example:"csv"`
 }
 
 // GetListWarehouses ql-kho-lua
@@ -138,6 +140,7 @@ type getListWarehouseRequest struct {
 //	@Param			q		query		string										false	"Query"
 //	@Param			skip	query		int											false	"Skip"	default(1)	minimum(1)
 //	@Param			limit	query		int											false	"Limit"	default(5)	minimum(5)
+//	@Param			format	query		string										false	"Export every matching row as a file instead of a page"	Enums(csv, xlsx)
 //	@Success		200		{object}	responseWithPagination{data=[]warehouseResponse}	"Warehouses data"
 //	@Failure		400		{object}	errorResponse								"Validation error"
 //	@Failure		401		{object}	errorResponse								"Unauthorized error"
@@ -161,6 +164,28 @@ func (w *WarehouseHandler) GetListWarehouses(ctx *gin.Context) {
 
 	isRoot := token.Role == domain.Root
 
+	if req.Format != "" {
+		exportList(ctx, req.Format, "warehouses", warehouseExportHeader, func(afterID, limit int) ([][]any, int, error) {
+			var stores []domain.Warehouse
+			var err error
+			if isRoot {
+				stores, err = w.scv.GetListWarehousesAfter(ctx, req.Query, afterID, limit)
+			} else {
+				stores, err = w.scv.GetAuthorizedWarehousesAfter(ctx, token.ID, req.Query, afterID, limit)
+			}
+			if err != nil {
+				return nil, 0, err
+			}
+
+			rows := make([][]any, 0, len(stores))
+			for _, store := range stores {
+				rows = append(rows, newWarehouseExportRow(&store))
+			}
+			return rows, stores[len(stores)-1].ID, nil
+		})
+		return
+	}
+
 	var count int64
 
 	if isRoot {
@@ -231,7 +256,7 @@ func (w *WarehouseHandler) GetUsedCapacityByID(ctx *gin.Context) {
 	isRoot := token.Role == domain.Root
 
 	if !isRoot {
-		err := w.acc.HasAccess(ctx, numID, token.ID)
+		err := w.acc.HasAccess(ctx, numID, token.ID, domain.ActionRead)
 		if err != nil {
 			handleError(ctx, err)
 			return
@@ -248,20 +273,25 @@ func (w *WarehouseHandler) GetUsedCapacityByID(ctx *gin.Context) {
 	handleSuccess(ctx, res)
 }
 
+type getInventoryRequest struct {
+	ByLot bool `form:"by_lot" binding:"omitempty" example:"true"`
+}
+
 // GetInventory ql-kho-lua
 //
 //	@Summary		Get inventory
-//	@Description	Get inventory by warehouse id
+//	@Description	Get inventory by warehouse id, with by_lot the stock is broken down by lot first-expiry-first-out
 //	@Tags			warehouses
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		int										true	"Warehouse id"
-//	@Success		200	{object}	response{data=[]warehouseItemResponse}	"Inventory data"
-//	@Failure		400	{object}	errorResponse							"Validation error"
-//	@Failure		401	{object}	errorResponse							"Unauthorized error"
-//	@Failure		403	{object}	errorResponse							"Forbidden error"
-//	@Failure		404	{object}	errorResponse							"Data not found error"
-//	@Failure		500	{object}	errorResponse							"Internal server error"
+//	@Param			id		path		int										true	"Warehouse id"
+//	@Param			by_lot	query		bool									false	"Break the stock down by lot, the data is then []lotResponse"
+//	@Success		200		{object}	response{data=[]warehouseItemResponse}	"Inventory data"
+//	@Failure		400		{object}	errorResponse							"Validation error"
+//	@Failure		401		{object}	errorResponse							"Unauthorized error"
+//	@Failure		403		{object}	errorResponse							"Forbidden error"
+//	@Failure		404		{object}	errorResponse							"Data not found error"
+//	@Failure		500		{object}	errorResponse							"Internal server error"
 //	@Router			/warehouses/{id}/inventory  [get]
 //	@Security		JWTAuth
 func (w *WarehouseHandler) GetInventory(ctx *gin.Context) {
@@ -273,17 +303,40 @@ func (w *WarehouseHandler) GetInventory(ctx *gin.Context) {
 		return
 	}
 
+	var req getInventoryRequest
+	err = ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
 	token := getAuthPayload(ctx, authorizationPayloadKey)
 
 	isRoot := token.Role == domain.Root
 	if !isRoot {
-		err := w.acc.HasAccess(ctx, numID, token.ID)
+		err := w.acc.HasAccess(ctx, numID, token.ID, domain.ActionRead)
 		if err != nil {
 			handleError(ctx, err)
 			return
 		}
 	}
 
+	if req.ByLot {
+		lots, err := w.scv.GetInventoryByLot(ctx, numID)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+
+		res := make([]lotResponse, 0, len(lots))
+		for _, v := range lots {
+			res = append(res, newLotResponse(&v))
+		}
+
+		handleSuccess(ctx, res)
+		return
+	}
+
 	inventory, err := w.scv.GetInventory(ctx, numID)
 	if err != nil {
 		handleError(ctx, err)
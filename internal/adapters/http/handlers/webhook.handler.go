@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+type WebhookHandler struct {
+	svc ports.IWebhookService
+}
+
+func NewWebhookHandler(webhookService ports.IWebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		svc: webhookService,
+	}
+}
+
+type createWebhookRequest struct {
+	URL    string                `json:"url" binding:"required,url,max=255" example:"https://erp.example.com/hooks/kho"`
+	Events []domain.WebhookEvent `json:"events" binding:"required,min=1,unique,dive,oneof=import_invoice.created import_invoice.cancelled import_invoice.approved import_invoice.rejected export_invoice.created export_invoice.cancelled export_invoice.approved export_invoice.rejected stock.changed customer.created customer.updated customer.deleted rice.created rice.updated rice.deleted warehouse.created warehouse.updated warehouse.deleted" example:"stock.changed"`
+}
+
+// CreateWebhook ql-kho-lua
+//
+//	@Summary		Create a webhook
+//	@Description	Register an url to post the chosen events to, the secret used to sign the payloads is only returned here
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		createWebhookRequest					true	"Webhook body"
+//	@Success		200		{object}	response{data=webhookSecretResponse}	"Webhook data"
+//	@Failure		400		{object}	errorResponse							"Validation error"
+//	@Failure		401		{object}	errorResponse							"Unauthorized error"
+//	@Failure		403		{object}	errorResponse							"Forbidden error"
+//	@Failure		500		{object}	errorResponse							"Internal server error"
+//	@Router			/webhooks  [post]
+//	@Security		JWTAuth
+func (w *WebhookHandler) CreateWebhook(ctx *gin.Context) {
+	var req createWebhookRequest
+
+	err := ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	webhook, err := w.svc.CreateWebhook(ctx, &domain.Webhook{
+		URL:    req.URL,
+		Events: req.Events,
+	})
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newWebhookSecretResponse(webhook)
+	handleSuccess(ctx, res)
+}
+
+// GetListWebhooks ql-kho-lua
+//
+//	@Summary		Get webhooks
+//	@Description	Get every webhook
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response{data=[]webhookResponse}	"Webhooks data"
+//	@Failure		401	{object}	errorResponse						"Unauthorized error"
+//	@Failure		403	{object}	errorResponse						"Forbidden error"
+//	@Failure		500	{object}	errorResponse						"Internal server error"
+//	@Router			/webhooks  [get]
+//	@Security		JWTAuth
+func (w *WebhookHandler) GetListWebhooks(ctx *gin.Context) {
+	webhooks, err := w.svc.GetListWebhooks(ctx)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]webhookResponse, 0, len(webhooks))
+	for _, v := range webhooks {
+		res = append(res, newWebhookResponse(&v))
+	}
+
+	handleSuccess(ctx, res)
+}
+
+type updateWebhookRequest struct {
+	URL    string                `json:"url" binding:"omitempty,url,max=255" example:"https://erp.example.com/hooks/kho"`
+	Events []domain.WebhookEvent `json:"events" binding:"omitempty,min=1,unique,dive,oneof=import_invoice.created import_invoice.cancelled import_invoice.approved import_invoice.rejected export_invoice.created export_invoice.cancelled export_invoice.approved export_invoice.rejected stock.changed customer.created customer.updated customer.deleted rice.created rice.updated rice.deleted warehouse.created warehouse.updated warehouse.deleted" example:"stock.changed"`
+	Active *bool                 `json:"active" binding:"omitempty" example:"false"`
+}
+
+// UpdateWebhook ql-kho-lua
+//
+//	@Summary		Update a webhook
+//	@Description	Change the url or events of a webhook, or pause it with active false
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int							true	"Webhook id"
+//	@Param			request	body		updateWebhookRequest		true	"Webhook body"
+//	@Success		200		{object}	response{data=webhookResponse}	"Webhook data"
+//	@Failure		400		{object}	errorResponse				"Validation error"
+//	@Failure		401		{object}	errorResponse				"Unauthorized error"
+//	@Failure		403		{object}	errorResponse				"Forbidden error"
+//	@Failure		404		{object}	errorResponse				"Data not found error"
+//	@Failure		500		{object}	errorResponse				"Internal server error"
+//	@Router			/webhooks/{id}  [patch]
+//	@Security		JWTAuth
+func (w *WebhookHandler) UpdateWebhook(ctx *gin.Context) {
+	var req updateWebhookRequest
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	err = ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	webhook, err := w.svc.GetWebhookByID(ctx, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	if req.URL != "" {
+		webhook.URL = req.URL
+	}
+	if len(req.Events) != 0 {
+		webhook.Events = req.Events
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	updated, err := w.svc.UpdateWebhook(ctx, webhook)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newWebhookResponse(updated)
+	handleSuccess(ctx, res)
+}
+
+// DeleteWebhook ql-kho-lua
+//
+//	@Summary		Delete a webhook
+//	@Description	Delete a webhook and its outbox messages
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int				true	"Webhook id"
+//	@Success		200	{object}	response		"Deleted"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		403	{object}	errorResponse	"Forbidden error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/webhooks/{id}  [delete]
+//	@Security		JWTAuth
+func (w *WebhookHandler) DeleteWebhook(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	err = w.svc.DeleteWebhook(ctx, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+type getListOutboxRequest struct {
+	WebhookID int                 `form:"webhook_id" binding:"omitempty,min=1" example:"1"`
+	Status    domain.OutboxStatus `form:"status" binding:"omitempty,oneof=pending delivered dead" example:"dead"`
+	Skip      int                 `form:"skip" binding:"min=1" example:"1"`
+	Limit     int                 `form:"limit" binding:"min=5" example:"5"`
+}
+
+// GetListOutboxMessages ql-kho-lua
+//
+//	@Summary		Get webhook outbox
+//	@Description	Get the webhook outbox messages, newest first; status=dead lists the messages that failed every attempt
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			webhook_id	query		int												false	"Webhook id"
+//	@Param			status		query		string											false	"Status"	Enums(pending, delivered, dead)
+//	@Param			skip		query		int												false	"Skip"		default(1)	minimum(1)
+//	@Param			limit		query		int												false	"Limit"		default(5)	minimum(5)
+//	@Success		200			{object}	responseWithPagination{data=[]outboxResponse}	"Outbox data"
+//	@Failure		400			{object}	errorResponse									"Validation error"
+//	@Failure		401			{object}	errorResponse									"Unauthorized error"
+//	@Failure		403			{object}	errorResponse									"Forbidden error"
+//	@Failure		404			{object}	errorResponse									"Data not found error"
+//	@Failure		500			{object}	errorResponse									"Internal server error"
+//	@Router			/webhooks/outbox  [get]
+//	@Security		JWTAuth
+func (w *WebhookHandler) GetListOutboxMessages(ctx *gin.Context) {
+	req := getListOutboxRequest{
+		Skip:  1,
+		Limit: 5,
+	}
+	err := ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	filter := domain.OutboxFilter{
+		WebhookID: req.WebhookID,
+		Status:    req.Status,
+	}
+
+	count, err := w.svc.CountOutboxMessages(ctx, filter)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	if isPageOutOfRange(count, req.Limit, req.Skip) {
+		handleError(ctx, domain.ErrDataNotFound)
+		return
+	}
+
+	msgs, err := w.svc.GetListOutboxMessages(ctx, filter, req.Limit, req.Skip)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]outboxResponse, 0, len(msgs))
+	for _, v := range msgs {
+		res = append(res, newOutboxResponse(&v))
+	}
+
+	pagination := newPagination(count, len(msgs), req.Limit, req.Skip)
+	handleSuccessPagination(ctx, pagination, res)
+}
+
+// RetryOutboxMessage ql-kho-lua
+//
+//	@Summary		Retry a dead message
+//	@Description	Send a dead outbox message again on the next delivery run
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int								true	"Outbox message id"
+//	@Success		200	{object}	response{data=outboxResponse}	"Outbox data"
+//	@Failure		400	{object}	errorResponse					"Validation error"
+//	@Failure		401	{object}	errorResponse					"Unauthorized error"
+//	@Failure		403	{object}	errorResponse					"Forbidden error"
+//	@Failure		404	{object}	errorResponse					"Data not found error"
+//	@Failure		409	{object}	errorResponse					"Conflicting data error"
+//	@Failure		500	{object}	errorResponse					"Internal server error"
+//	@Router			/webhooks/outbox/{id}/retry  [post]
+//	@Security		JWTAuth
+func (w *WebhookHandler) RetryOutboxMessage(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, errors.New("id must be a number"))
+		return
+	}
+
+	msg, err := w.svc.RetryOutboxMessage(ctx, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newOutboxResponse(msg)
+	handleSuccess(ctx, res)
+}
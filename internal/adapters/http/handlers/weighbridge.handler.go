@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tommjj/ql-kho-lua/internal/config"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+// maxTicketLength is the longest weighbridge ticket, the invoice reference also holds its prefix
+const maxTicketLength = 80
+
+type WeighbridgeHandler struct {
+	svc  ports.IWeighbridgeService
+	acc  ports.IAccessControlService
+	conf config.Weighbridge
+}
+
+func NewWeighbridgeHandler(svc ports.IWeighbridgeService, acc ports.IAccessControlService, conf config.Weighbridge) *WeighbridgeHandler {
+	return &WeighbridgeHandler{
+		svc:  svc,
+		acc:  acc,
+		conf: conf,
+	}
+}
+
+type weighbridgeImportRequest struct {
+	File        *multipart.FileHeader `form:"file" binding:"required"`
+	WarehouseID int                   `form:"warehouse_id" binding:"required,min=1" example:"1"`
+}
+
+// weighbridgeColumns is the index of the mapped columns in a weighbridge file, -1 when the file does not have one
+type weighbridgeColumns struct {
+	ticket, direction, partner, rice, net, gross, tare, price int
+}
+
+// findColumns find the mapped columns in the header of a weighbridge file, net can be left out when gross and tare are there
+func (w *WeighbridgeHandler) findColumns(header []string) (weighbridgeColumns, error) {
+	index := func(name string) int {
+		for i, v := range header {
+			if strings.EqualFold(strings.TrimSpace(v), name) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	cols := weighbridgeColumns{
+		ticket:    index(w.conf.TicketColumn),
+		direction: index(w.conf.DirectionColumn),
+		partner:   index(w.conf.PartnerColumn),
+		rice:      index(w.conf.RiceColumn),
+		net:       index(w.conf.NetColumn),
+		gross:     index(w.conf.GrossColumn),
+		tare:      index(w.conf.TareColumn),
+		price:     index(w.conf.PriceColumn),
+	}
+
+	for _, v := range []struct {
+		name  string
+		index int
+	}{
+		{w.conf.TicketColumn, cols.ticket},
+		{w.conf.DirectionColumn, cols.direction},
+		{w.conf.PartnerColumn, cols.partner},
+		{w.conf.RiceColumn, cols.rice},
+		{w.conf.PriceColumn, cols.price},
+	} {
+		if v.index < 0 {
+			return cols, fmt.Errorf("file has no %s column", v.name)
+		}
+	}
+	if cols.net < 0 && (cols.gross < 0 || cols.tare < 0) {
+		return cols, fmt.Errorf("file has no %s column, or %s and %s columns", w.conf.NetColumn, w.conf.GrossColumn, w.conf.TareColumn)
+	}
+	return cols, nil
+}
+
+// kindOf return the invoice kind of a direction value
+func (w *WeighbridgeHandler) kindOf(direction string) (domain.InvoiceKind, bool) {
+	for _, v := range w.conf.ImportValues {
+		if strings.EqualFold(strings.TrimSpace(v), direction) {
+			return domain.InvoiceKindImport, true
+		}
+	}
+	for _, v := range w.conf.ExportValues {
+		if strings.EqualFold(strings.TrimSpace(v), direction) {
+			return domain.InvoiceKindExport, true
+		}
+	}
+	return "", false
+}
+
+// parseWeighbridgeNumber read a number cell, the error is written for people reading the report
+func parseWeighbridgeNumber(value string) (float64, error) {
+	if value == "" {
+		return 0, errors.New("is required")
+	}
+
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, errors.New("must be a number")
+	}
+	return v, nil
+}
+
+// readNetWeight read the net weight of a row and the columns it is read from, it is gross minus tare when the net cell is empty
+func (w *WeighbridgeHandler) readNetWeight(cols weighbridgeColumns, cell func(i int) string, fail func(column string, err error)) (float64, string, bool) {
+	if v := cell(cols.net); v != "" || cols.gross < 0 || cols.tare < 0 {
+		net, err := parseWeighbridgeNumber(v)
+		if err != nil {
+			fail(w.conf.NetColumn, err)
+			return 0, "", false
+		}
+		return net, w.conf.NetColumn, true
+	}
+
+	gross, grossErr := parseWeighbridgeNumber(cell(cols.gross))
+	if grossErr != nil {
+		fail(w.conf.GrossColumn, grossErr)
+	}
+	tare, tareErr := parseWeighbridgeNumber(cell(cols.tare))
+	if tareErr != nil {
+		fail(w.conf.TareColumn, tareErr)
+	}
+	if grossErr != nil || tareErr != nil {
+		return 0, "", false
+	}
+	return gross - tare, w.conf.GrossColumn + ", " + w.conf.TareColumn, true
+}
+
+// readWeighing read a row of a weighbridge file, the quantity is the net weight divided by the unit
+func (w *WeighbridgeHandler) readWeighing(cols weighbridgeColumns, line int, cells []string) domain.ImportRow[domain.Weighing] {
+	row := domain.ImportRow[domain.Weighing]{Line: line}
+	cell := func(i int) string {
+		if i < 0 || i >= len(cells) {
+			return ""
+		}
+		return strings.TrimSpace(cells[i])
+	}
+	fail := func(column string, err error) {
+		row.Errors = append(row.Errors, domain.ImportRowError{Line: line, Field: column, Message: err.Error()})
+	}
+
+	weighing := domain.Weighing{
+		Ticket:  cell(cols.ticket),
+		Partner: cell(cols.partner),
+		Rice:    cell(cols.rice),
+	}
+
+	if weighing.Ticket == "" {
+		fail(w.conf.TicketColumn, errors.New("is required"))
+	} else if utf8.RuneCountInString(weighing.Ticket) > maxTicketLength {
+		fail(w.conf.TicketColumn, fmt.Errorf("must be at most %d characters", maxTicketLength))
+	}
+
+	kind, ok := w.kindOf(cell(cols.direction))
+	if !ok {
+		values := append(append([]string{}, w.conf.ImportValues...), w.conf.ExportValues...)
+		fail(w.conf.DirectionColumn, fmt.Errorf("must be one of %s", strings.Join(values, ", ")))
+	}
+	weighing.Kind = kind
+
+	if weighing.Partner == "" {
+		fail(w.conf.PartnerColumn, errors.New("is required"))
+	}
+	if weighing.Rice == "" {
+		fail(w.conf.RiceColumn, errors.New("is required"))
+	}
+
+	if net, column, ok := w.readNetWeight(cols, cell, fail); ok {
+		weighing.Quantity = int(math.Round(net / w.conf.Unit))
+		if weighing.Quantity < 1 {
+			fail(column, errors.New("must be more than 0"))
+		}
+	}
+
+	price, err := parseWeighbridgeNumber(cell(cols.price))
+	if err != nil {
+		fail(w.conf.PriceColumn, err)
+	} else if price < 1 {
+		fail(w.conf.PriceColumn, errors.New("must be at least 1"))
+	}
+	weighing.Price = price
+
+	if len(row.Errors) == 0 {
+		row.Data = weighing
+	}
+	return row
+}
+
+type weighbridgeImportResponse struct {
+	Total   int                     `json:"total" example:"42"`
+	Posted  []domain.PostedWeighing `json:"posted"`
+	Skipped []domain.PostedWeighing `json:"skipped"`
+	Errors  []domain.ImportRowError `json:"errors"`
+}
+
+// ImportWeighings ql-kho-lua
+//
+//	@Summary		Import weighbridge tickets
+//	@Description	Post the truck weighings of a weighbridge CSV or XLSX file as import and export invoices of a warehouse, one invoice per ticket.
+//	@Description	The columns are mapped with the WEIGHBRIDGE_* settings, each row gets the capacity and stock checks of a new invoice and fails on its own.
+//	@Description	A ticket that is already posted is skipped, so a file can be uploaded again
+//	@Tags			weighbridge
+//	@Accept			mpfd
+//	@Produce		json
+//	@Param			file			formData	file										true	"CSV or XLSX file, the first row is the header"
+//	@Param			warehouse_id	formData	int											true	"Warehouse of the weighbridge"
+//	@Success		200				{object}	response{data=weighbridgeImportResponse}	"Import result"
+//	@Failure		400				{object}	errorResponse								"Validation error"
+//	@Failure		401				{object}	errorResponse								"Unauthorized error"
+//	@Failure		403				{object}	errorResponse								"Forbidden error"
+//	@Failure		404				{object}	errorResponse								"Data not found error"
+//	@Failure		500				{object}	errorResponse								"Internal server error"
+//	@Router			/weighbridge/import [post]
+//	@Security		JWTAuth
+func (w *WeighbridgeHandler) ImportWeighings(ctx *gin.Context) {
+	var req weighbridgeImportRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	cells, err := readSheetFile(req.File, w.conf.Comma)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	cols, err := w.findColumns(cells[0])
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	rows := make([]domain.ImportRow[domain.Weighing], 0, len(cells)-1)
+	actions := map[domain.AccessAction]bool{}
+	invalid := map[int]bool{}
+	for i, v := range cells[1:] {
+		if isBlankRow(v) {
+			continue
+		}
+
+		row := w.readWeighing(cols, i+2, v)
+		if len(row.Errors) > 0 {
+			invalid[row.Line] = true
+		} else {
+			if row.Data.Kind == domain.InvoiceKindImport {
+				actions[domain.ActionImport] = true
+			} else {
+				actions[domain.ActionExport] = true
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+	if token.Role != domain.Root {
+		for _, action := range []domain.AccessAction{domain.ActionImport, domain.ActionExport} {
+			if !actions[action] {
+				continue
+			}
+			err := w.acc.HasAccess(ctx, req.WarehouseID, token.ID, action)
+			if err != nil {
+				handleError(ctx, err)
+				return
+			}
+		}
+	}
+
+	result, err := w.svc.ImportWeighings(ctx, req.WarehouseID, token.ID, rows)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	// the service names the partner, rice and quantity of the rows it posts, the report names the columns of the file
+	fields := map[string]string{
+		"partner":  w.conf.PartnerColumn,
+		"rice":     w.conf.RiceColumn,
+		"quantity": w.conf.NetColumn,
+	}
+	if cols.net < 0 {
+		fields["quantity"] = w.conf.GrossColumn + ", " + w.conf.TareColumn
+	}
+	for i, v := range result.Errors {
+		if column, ok := fields[v.Field]; ok && !invalid[v.Line] {
+			result.Errors[i].Field = column
+		}
+	}
+
+	res := weighbridgeImportResponse{
+		Total:   result.Total,
+		Posted:  result.Posted,
+		Skipped: result.Skipped,
+		Errors:  result.Errors,
+	}
+	handleSuccess(ctx, res)
+}
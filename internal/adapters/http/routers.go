@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/tommjj/ql-kho-lua/internal/adapters/http/handlers"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
 	"github.com/tommjj/ql-kho-lua/internal/core/ports"
 )
 
@@ -28,11 +29,19 @@ func RegisterPingRoute() RegisterRouterFunc {
 }
 
 // RegisterAuthRoute is a option function to return register auth router function
-func RegisterAuthRoute(authHandler *handlers.AuthHandler) RegisterRouterFunc {
+func RegisterAuthRoute(token ports.ITokenService, authHandler *handlers.AuthHandler) RegisterRouterFunc {
 	return func(e gin.IRouter) {
 		r := e.Group("/auth")
 		{
 			r.POST("/login", authHandler.Login)
+			r.POST("/refresh", authHandler.Refresh)
+
+			auth := r.Group("", handlers.AuthMiddleware(token))
+			{
+				auth.POST("/logout", authHandler.Logout)
+				auth.GET("/sessions", authHandler.GetSessions)
+				auth.DELETE("/sessions/:id", authHandler.RevokeSession)
+			}
 		}
 	}
 }
@@ -60,11 +69,12 @@ func RegisterUsersRoute(token ports.ITokenService, userHandler *handlers.UserHan
 			auth.GET("/:id", userHandler.GetUserByID)
 			auth.PATCH("/:id", userHandler.UpdateUser)
 
-			root := auth.Group("", handlers.RoleRootMiddleware())
+			manage := auth.Group("", handlers.RequirePermission(domain.PermUserManage))
 			{
-				root.GET("", userHandler.GetListUsers)
-				root.POST("", userHandler.CreateUser)
-				root.DELETE("/:id", userHandler.DeleteUserByID)
+				manage.GET("", userHandler.GetListUsers)
+				manage.POST("", userHandler.CreateUser)
+				manage.PATCH("/:id/role", userHandler.UpdateUserRole)
+				manage.DELETE("/:id", userHandler.DeleteUserByID)
 			}
 		}
 	}
@@ -81,11 +91,11 @@ func RegisterWarehouseRoute(token ports.ITokenService, warehouseHandler *handler
 			auth.GET("/:id/used_capacity", warehouseHandler.GetUsedCapacityByID)
 			auth.GET("/:id/inventory", warehouseHandler.GetInventory)
 
-			root := auth.Group("", handlers.RoleRootMiddleware())
+			write := auth.Group("", handlers.RequirePermission(domain.PermWarehouseWrite))
 			{
-				root.POST("", warehouseHandler.CreateWarehouse)
-				root.PATCH("/:id", warehouseHandler.UpdateWarehouse)
-				root.DELETE("/:id", warehouseHandler.DeleteWarehouse)
+				write.POST("", warehouseHandler.CreateWarehouse)
+				write.PATCH("/:id", warehouseHandler.UpdateWarehouse)
+				write.DELETE("/:id", warehouseHandler.DeleteWarehouse)
 			}
 		}
 	}
@@ -98,11 +108,11 @@ func RegisterRiceRoute(token ports.ITokenService, riceHandler *handlers.RiceHand
 		{
 			auth.GET("", riceHandler.GetListRice)
 			auth.GET("/:id", riceHandler.GetRiceByID)
-			root := auth.Group("", handlers.RoleRootMiddleware())
+			write := auth.Group("", handlers.RequirePermission(domain.PermRiceWrite))
 			{
-				root.POST("", riceHandler.CreateRice)
-				root.PATCH("/:id", riceHandler.UpdateRice)
-				root.DELETE("/:id", riceHandler.DeleteRice)
+				write.POST("", riceHandler.CreateRice)
+				write.PATCH("/:id", riceHandler.UpdateRice)
+				write.DELETE("/:id", riceHandler.DeleteRice)
 			}
 		}
 	}
@@ -115,36 +125,200 @@ func RegisterCustomerRoute(token ports.ITokenService, customerHandler *handlers.
 		{
 			auth.GET("", customerHandler.GetListCustomers)
 			auth.GET("/:id", customerHandler.GetCustomerByID)
-			root := auth.Group("", handlers.RoleRootMiddleware())
+			write := auth.Group("", handlers.RequirePermission(domain.PermCustomerWrite))
 			{
-				root.POST("", customerHandler.CreateCustomer)
-				root.PATCH("/:id", customerHandler.UpdateCustomer)
-				root.DELETE("/:id", customerHandler.DeleteCustomer)
+				write.POST("", customerHandler.CreateCustomer)
+				write.PATCH("/:id", customerHandler.UpdateCustomer)
+				write.DELETE("/:id", customerHandler.DeleteCustomer)
 			}
 		}
+		e.GET("/suppliers", handlers.AuthMiddleware(token), customerHandler.GetListSuppliers)
+	}
+}
+
+// RegisterBulkImportRoute is a option function to return register bulk import router function
+func RegisterBulkImportRoute(token ports.ITokenService, bulkImportHandler *handlers.BulkImportHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		auth := e.Group("", handlers.AuthMiddleware(token))
+		{
+			auth.POST("/rice/import", handlers.RequirePermission(domain.PermRiceWrite), bulkImportHandler.ImportRice)
+			auth.POST("/customers/import", handlers.RequirePermission(domain.PermCustomerWrite), bulkImportHandler.ImportCustomers)
+			auth.POST("/warehouses/import", handlers.RequirePermission(domain.PermWarehouseWrite), bulkImportHandler.ImportWarehouses)
+		}
 	}
 }
 
 // RegisterImportInvoiceRoute is a option function to return register import invoice router function
-func RegisterImportInvoiceRoute(token ports.ITokenService, imInvHandler *handlers.ImportInvoiceHandler) RegisterRouterFunc {
+func RegisterImportInvoiceRoute(token ports.ITokenService, idempotency ports.IIdempotencyService, imInvHandler *handlers.ImportInvoiceHandler) RegisterRouterFunc {
 	return func(e gin.IRouter) {
 		auth := e.Group("/import_invoices", handlers.AuthMiddleware(token))
 		{
-			auth.POST("", imInvHandler.CreateImInvoice)
 			auth.GET("", imInvHandler.GetListImInvoices)
 			auth.GET("/:id", imInvHandler.GetImInvoiceByID)
+			auth.GET("/:id/pdf", imInvHandler.GetImInvoicePDF)
+			auth.POST("", handlers.RequirePermission(domain.PermInvoiceCreate), handlers.IdempotencyMiddleware(idempotency), imInvHandler.CreateImInvoice)
+			auth.POST("/:id/cancel", handlers.RequirePermission(domain.PermInvoiceCancel), imInvHandler.CancelImInvoice)
+			auth.POST("/:id/approve", handlers.RequirePermission(domain.PermInvoiceApprove), imInvHandler.ApproveImInvoice)
+			auth.POST("/:id/reject", handlers.RequirePermission(domain.PermInvoiceApprove), imInvHandler.RejectImInvoice)
 		}
 	}
 }
 
 // RegisterExportInvoiceRoute is a option function to return register export invoice router function
-func RegisterExportInvoiceRoute(token ports.ITokenService, exInvHandler *handlers.ExportInvoiceHandler) RegisterRouterFunc {
+func RegisterExportInvoiceRoute(token ports.ITokenService, idempotency ports.IIdempotencyService, exInvHandler *handlers.ExportInvoiceHandler) RegisterRouterFunc {
 	return func(e gin.IRouter) {
 		auth := e.Group("/export_invoices", handlers.AuthMiddleware(token))
 		{
 			auth.GET("", exInvHandler.GetListExInvoices)
 			auth.GET("/:id", exInvHandler.GetExInvoiceByID)
-			auth.POST("", exInvHandler.CreateExInvoice)
+			auth.GET("/:id/pdf", exInvHandler.GetExInvoicePDF)
+			auth.POST("", handlers.RequirePermission(domain.PermInvoiceCreate), handlers.IdempotencyMiddleware(idempotency), exInvHandler.CreateExInvoice)
+			auth.POST("/:id/cancel", handlers.RequirePermission(domain.PermInvoiceCancel), exInvHandler.CancelExInvoice)
+			auth.POST("/:id/approve", handlers.RequirePermission(domain.PermInvoiceApprove), exInvHandler.ApproveExInvoice)
+			auth.POST("/:id/reject", handlers.RequirePermission(domain.PermInvoiceApprove), exInvHandler.RejectExInvoice)
+		}
+	}
+}
+
+// RegisterWeighbridgeRoute is a option function to return register weighbridge router function
+func RegisterWeighbridgeRoute(token ports.ITokenService, weighbridgeHandler *handlers.WeighbridgeHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		auth := e.Group("/weighbridge", handlers.AuthMiddleware(token))
+		{
+			auth.POST("/import", handlers.RequirePermission(domain.PermInvoiceCreate), weighbridgeHandler.ImportWeighings)
+		}
+	}
+}
+
+// RegisterAccessControlRoute is a option function to return register access control router function
+func RegisterAccessControlRoute(token ports.ITokenService, accessHandler *handlers.AccessControlHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		manage := e.Group("", handlers.AuthMiddleware(token), handlers.RequirePermission(domain.PermAccessManage))
+		{
+			manage.GET("/warehouses/:id/users", accessHandler.GetWarehouseUsers)
+			manage.POST("/warehouses/:id/users/:user_id", accessHandler.GrantAccess)
+			manage.PATCH("/warehouses/:id/users/:user_id", accessHandler.UpdateAccess)
+			manage.DELETE("/warehouses/:id/users/:user_id", accessHandler.RevokeAccess)
+			manage.GET("/users/:id/warehouses", accessHandler.GetUserWarehouses)
+		}
+	}
+}
+
+// RegisterTransferRoute is a option function to return register transfer router function
+func RegisterTransferRoute(token ports.ITokenService, transferHandler *handlers.TransferHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		auth := e.Group("/transfers", handlers.AuthMiddleware(token))
+		{
+			auth.POST("", handlers.RequirePermission(domain.PermTransferCreate), transferHandler.CreateTransfer)
+			auth.GET("/:id", transferHandler.GetTransferByID)
+			auth.POST("/:id/reverse", handlers.RequirePermission(domain.PermInvoiceCancel), transferHandler.ReverseTransfer)
+		}
+	}
+}
+
+// RegisterOrderRoute is a option function to return register order router function
+func RegisterOrderRoute(token ports.ITokenService, orderHandler *handlers.OrderHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		auth := e.Group("/orders", handlers.AuthMiddleware(token))
+		{
+			auth.GET("", orderHandler.GetListOrders)
+			auth.GET("/:id", orderHandler.GetOrderByID)
+			auth.POST("", handlers.RequirePermission(domain.PermInvoiceCreate), orderHandler.CreateOrder)
+			auth.POST("/:id/confirm", handlers.RequirePermission(domain.PermInvoiceCreate), orderHandler.ConfirmOrder)
+			auth.POST("/:id/convert", handlers.RequirePermission(domain.PermInvoiceCreate), orderHandler.ConvertOrder)
+			auth.POST("/:id/cancel", handlers.RequirePermission(domain.PermInvoiceCancel), orderHandler.CancelOrder)
+		}
+	}
+}
+
+// RegisterAuditRoute is a option function to return register audit router function
+func RegisterAuditRoute(token ports.ITokenService, auditHandler *handlers.AuditHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		auth := e.Group("/audit", handlers.AuthMiddleware(token), handlers.RequirePermission(domain.PermAuditRead))
+		{
+			auth.GET("", auditHandler.GetListAuditLogs)
+		}
+	}
+}
+
+// RegisterReportRoute is a option function to return register report router function
+func RegisterReportRoute(token ports.ITokenService, reportHandler *handlers.ReportHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		auth := e.Group("", handlers.AuthMiddleware(token), handlers.RequirePermission(domain.PermReportRead))
+		{
+			auth.GET("/warehouses/:id/reports/movement", reportHandler.GetStockMovement)
+			auth.GET("/warehouses/:id/reports/valuation", reportHandler.GetInventoryValuation)
+			auth.GET("/warehouses/:id/reports/gross_margin", reportHandler.GetGrossMargin)
+			auth.GET("/reports/revenue", reportHandler.GetRevenue)
+			auth.GET("/reports/purchases", reportHandler.GetPurchases)
+		}
+	}
+}
+
+// RegisterPaymentRoute is a option function to return register payment router function
+func RegisterPaymentRoute(token ports.ITokenService, paymentHandler *handlers.PaymentHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		auth := e.Group("", handlers.AuthMiddleware(token))
+		{
+			auth.POST("/payments", handlers.RequirePermission(domain.PermPaymentWrite), paymentHandler.CreatePayment)
+
+			read := auth.Group("", handlers.RequirePermission(domain.PermReportRead))
+			read.GET("/payments", paymentHandler.GetListPayments)
+			read.GET("/payments/:id", paymentHandler.GetPaymentByID)
+			read.GET("/reports/receivables", paymentHandler.GetReceivables)
+			read.GET("/reports/receivables/aging", paymentHandler.GetReceivablesAging)
+			read.GET("/reports/payables", paymentHandler.GetPayables)
+			read.GET("/reports/payables/aging", paymentHandler.GetPayablesAging)
+		}
+	}
+}
+
+// RegisterAlertRoute is a option function to return register alert router function
+func RegisterAlertRoute(token ports.ITokenService, alertHandler *handlers.AlertHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		auth := e.Group("", handlers.AuthMiddleware(token))
+		{
+			read := auth.Group("", handlers.RequirePermission(domain.PermReportRead))
+			{
+				read.GET("/warehouses/:id/thresholds", alertHandler.GetThresholds)
+				read.GET("/alerts", alertHandler.GetListAlerts)
+			}
+
+			manage := auth.Group("", handlers.RequirePermission(domain.PermAlertManage))
+			{
+				manage.PUT("/warehouses/:id/thresholds/:rice_id", alertHandler.SetThreshold)
+				manage.DELETE("/warehouses/:id/thresholds/:rice_id", alertHandler.DeleteThreshold)
+				manage.POST("/alerts/:id/acknowledge", alertHandler.AcknowledgeAlert)
+			}
+		}
+	}
+}
+
+// RegisterNotificationRoute is a option function to return register notification router function
+func RegisterNotificationRoute(token ports.ITokenService, notificationHandler *handlers.NotificationHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		auth := e.Group("/notifications", handlers.AuthMiddleware(token))
+		{
+			auth.GET("", notificationHandler.GetListNotifications)
+			auth.POST("/:id/read", notificationHandler.ReadNotification)
+			auth.GET("/subscriptions", notificationHandler.GetSubscriptions)
+			auth.POST("/subscriptions", notificationHandler.Subscribe)
+			auth.DELETE("/subscriptions/:id", notificationHandler.Unsubscribe)
+		}
+	}
+}
+
+// RegisterWebhookRoute is a option function to return register webhook router function
+func RegisterWebhookRoute(token ports.ITokenService, webhookHandler *handlers.WebhookHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		auth := e.Group("/webhooks", handlers.AuthMiddleware(token), handlers.RequirePermission(domain.PermWebhookManage))
+		{
+			auth.GET("", webhookHandler.GetListWebhooks)
+			auth.POST("", webhookHandler.CreateWebhook)
+			auth.PATCH("/:id", webhookHandler.UpdateWebhook)
+			auth.DELETE("/:id", webhookHandler.DeleteWebhook)
+			auth.GET("/outbox", webhookHandler.GetListOutboxMessages)
+			auth.POST("/outbox/:id/retry", webhookHandler.RetryOutboxMessage)
 		}
 	}
 }
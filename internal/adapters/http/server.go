@@ -31,6 +31,8 @@ func NewAdapter(conf *config.HTTP, options ...RegisterRouterFunc) (*router, erro
 	}
 
 	r := gin.New()
+	// let values of the request context, like the actor set by AuthMiddleware, reach services through *gin.Context
+	r.ContextWithFallback = true
 
 	// set logger middleware
 	// logger, err := logger.New(conf.Logger)
@@ -13,7 +13,7 @@ var UserRoleValidator validator.Func = func(fl validator.FieldLevel) bool {
 	}
 
 	switch userRole {
-	case domain.Root, domain.Member:
+	case domain.Root, domain.Member, domain.WarehouseManager, domain.Accountant, domain.Viewer:
 		return true
 	default:
 		return false
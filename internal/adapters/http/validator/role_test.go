@@ -21,6 +21,9 @@ func TestUserRoleValidator(t *testing.T) {
 	}{
 		{"Valid Role: Root", domain.Root, true},
 		{"Valid Role: Member", domain.Member, true},
+		{"Valid Role: Warehouse manager", domain.WarehouseManager, true},
+		{"Valid Role: Accountant", domain.Accountant, true},
+		{"Valid Role: Viewer", domain.Viewer, true},
 		{"Invalid Role: Admin", "Admin", false},
 		{"Invalid Role: Empty", "", false},
 		{"Invalid Role: invalid type", nil, false},
@@ -0,0 +1,119 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/config"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+// implement ports.INotifier, send events as plain text emails
+type emailNotifier struct {
+	host string
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewEmailNotifier(conf config.SMTP) ports.INotifier {
+	n := &emailNotifier{
+		host: conf.Host,
+		addr: net.JoinHostPort(conf.Host, strconv.Itoa(conf.Port)),
+		from: conf.From,
+	}
+
+	if conf.Username != "" {
+		n.auth = smtp.PlainAuth("", conf.Username, conf.Password, conf.Host)
+	}
+	return n
+}
+
+func (e *emailNotifier) Channel() domain.NotificationChannel {
+	return domain.ChannelEmail
+}
+
+func (e *emailNotifier) Notify(ctx context.Context, sub *domain.Subscription, event *domain.Event) error {
+	if sub.Target == "" {
+		return errors.New("subscriber has no email")
+	}
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", e.addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, e.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		err = c.StartTLS(&tls.Config{ServerName: e.host})
+		if err != nil {
+			return err
+		}
+	}
+
+	if e.auth != nil {
+		err = c.Auth(e.auth)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.Mail(e.from)
+	if err != nil {
+		return err
+	}
+	err = c.Rcpt(sub.Target)
+	if err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(e.message(sub.Target, event))
+	if err != nil {
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+// message build the email of the event
+func (e *emailNotifier) message(to string, event *domain.Event) []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "From: %s\r\n", e.from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", event.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(event.Message)
+	b.WriteString("\r\n")
+
+	return b.Bytes()
+}
@@ -0,0 +1,114 @@
+package notify_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/notify"
+	"github.com/tommjj/ql-kho-lua/internal/config"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+// stubSMTPServer accept one mail on a local port and send its envelope and data to the returned channel
+func stubSMTPServer(t *testing.T) (config.SMTP, <-chan []string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	mails := make(chan []string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+
+		mail := []string{}
+		reply("220 stub ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+
+			switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); cmd {
+			case "EHLO", "HELO":
+				reply("250 stub")
+			case "MAIL", "RCPT":
+				mail = append(mail, line)
+				reply("250 OK")
+			case "DATA":
+				reply("354 end with .")
+				for {
+					data, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if data == ".\r\n" {
+						break
+					}
+					mail = append(mail, strings.TrimRight(data, "\r\n"))
+				}
+				reply("250 OK")
+			case "QUIT":
+				reply("221 bye")
+				mails <- mail
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+
+	addr := l.Addr().(*net.TCPAddr)
+	return config.SMTP{
+		Host: "127.0.0.1",
+		Port: addr.Port,
+		From: "kho@mail.com",
+	}, mails
+}
+
+func TestEmailNotifier_Notify(t *testing.T) {
+	conf, mails := stubSMTPServer(t)
+
+	notifier := notify.NewEmailNotifier(conf)
+	assert.Equal(t, domain.ChannelEmail, notifier.Channel())
+
+	err := notifier.Notify(context.TODO(), &domain.Subscription{
+		ID:      1,
+		UserID:  2,
+		Channel: domain.ChannelEmail,
+		Target:  "manager@mail.com",
+	}, &domain.Event{
+		Type:        domain.EventLargeExport,
+		WarehouseID: 1,
+		Subject:     "large export invoice #1 created",
+		Message:     "export invoice #1 of warehouse 1: 2000 items, total price 10.00",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mail := <-mails
+	assert.Contains(t, mail, "MAIL FROM:<kho@mail.com>")
+	assert.Contains(t, mail, "RCPT TO:<manager@mail.com>")
+	assert.Contains(t, mail, "Subject: large export invoice #1 created")
+	assert.Contains(t, mail, "export invoice #1 of warehouse 1: 2000 items, total price 10.00")
+}
+
+func TestEmailNotifier_NoEmail(t *testing.T) {
+	notifier := notify.NewEmailNotifier(config.SMTP{Host: "127.0.0.1", Port: 25})
+
+	err := notifier.Notify(context.TODO(), &domain.Subscription{ID: 1}, &domain.Event{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,33 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+// implement ports.INotifier, store events as notifications users read in the app
+type inAppNotifier struct {
+	repo ports.INotificationRepository
+}
+
+func NewInAppNotifier(repo ports.INotificationRepository) ports.INotifier {
+	return &inAppNotifier{
+		repo: repo,
+	}
+}
+
+func (i *inAppNotifier) Channel() domain.NotificationChannel {
+	return domain.ChannelInApp
+}
+
+func (i *inAppNotifier) Notify(ctx context.Context, sub *domain.Subscription, event *domain.Event) error {
+	return i.repo.CreateNotification(ctx, &domain.Notification{
+		UserID:      sub.UserID,
+		EventType:   event.Type,
+		WarehouseID: event.WarehouseID,
+		Subject:     event.Subject,
+		Message:     event.Message,
+	})
+}
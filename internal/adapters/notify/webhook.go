@@ -0,0 +1,54 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+// implement ports.INotifier, post events as JSON to the subscription url
+type webhookNotifier struct {
+	client *http.Client
+}
+
+// NewWebhookNotifier create a webhook notifier, timeout limit each request
+func NewWebhookNotifier(timeout time.Duration) ports.INotifier {
+	return &webhookNotifier{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (w *webhookNotifier) Channel() domain.NotificationChannel {
+	return domain.ChannelWebhook
+}
+
+func (w *webhookNotifier) Notify(ctx context.Context, sub *domain.Subscription, event *domain.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Type", string(event.Type))
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %s", res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+// headers of webhook requests, the signature is "sha256=" followed by Sign of the timestamp and body
+const (
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// Sign return the hex HMAC-SHA256 of "timestamp.body" keyed with the secret,
+// receivers compute it the same way to check a payload and reject old timestamps to stop replays
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// implement ports.IWebhookSender
+type webhookSender struct {
+	client *http.Client
+}
+
+// NewWebhookSender create a webhook sender, timeout limit each request
+func NewWebhookSender(timeout time.Duration) ports.IWebhookSender {
+	return &webhookSender{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (w *webhookSender) Send(ctx context.Context, webhook *domain.Webhook, msg *domain.OutboxMessage) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(msg.Payload))
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(msg.Event))
+	req.Header.Set(DeliveryHeader, msg.EventID)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, "sha256="+Sign(webhook.Secret, timestamp, msg.Payload))
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %s", res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package notify_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/notify"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+func TestWebhookSender_Send(t *testing.T) {
+	secret := "secret"
+	payload := []byte(`{"id":"e1","event":"stock.changed","data":{"warehouse_id":1}}`)
+
+	var header http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender := notify.NewWebhookSender(time.Second)
+	err := sender.Send(context.TODO(), &domain.Webhook{ID: 1, URL: server.URL, Secret: secret}, &domain.OutboxMessage{
+		ID:      1,
+		EventID: "e1",
+		Event:   domain.WebhookStockChanged,
+		Payload: payload,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, payload, body)
+	assert.Equal(t, "stock.changed", header.Get(notify.EventHeader))
+	assert.Equal(t, "e1", header.Get(notify.DeliveryHeader))
+
+	timestamp, err := strconv.ParseInt(header.Get(notify.TimestampHeader), 10, 64)
+	assert.Nil(t, err)
+	assert.Equal(t, "sha256="+notify.Sign(secret, timestamp, body), header.Get(notify.SignatureHeader))
+	assert.NotEqual(t, "sha256="+notify.Sign("other", timestamp, body), header.Get(notify.SignatureHeader))
+}
+
+func TestWebhookSender_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sender := notify.NewWebhookSender(time.Second)
+	err := sender.Send(context.TODO(), &domain.Webhook{URL: server.URL}, &domain.OutboxMessage{Payload: []byte("{}")})
+
+	assert.Error(t, err)
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{}' | openssl dgst -sha256 -hmac key
+	assert.Equal(t, "9d713ed406bb7076d4123f0dc2c39d2df5c654ed4b0cd56b52c8b4c940bd63ae", notify.Sign("key", 1700000000, []byte("{}")))
+}
@@ -0,0 +1,54 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/notify"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	var received domain.Event
+	var eventType string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		eventType = r.Header.Get("X-Event-Type")
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := notify.NewWebhookNotifier(time.Second)
+	assert.Equal(t, domain.ChannelWebhook, notifier.Channel())
+
+	event := &domain.Event{
+		Type:        domain.EventNearCapacity,
+		WarehouseID: 3,
+		Subject:     "near_capacity alert in warehouse 3",
+		Message:     "warehouse Kho A is 95% full: 950 of 1000",
+	}
+	err := notifier.Notify(context.TODO(), &domain.Subscription{ID: 1, Target: server.URL}, event)
+
+	assert.Nil(t, err)
+	assert.Equal(t, string(domain.EventNearCapacity), eventType)
+	assert.Equal(t, event.Subject, received.Subject)
+	assert.Equal(t, 3, received.WarehouseID)
+}
+
+func TestWebhookNotifier_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	notifier := notify.NewWebhookNotifier(time.Second)
+	err := notifier.Notify(context.TODO(), &domain.Subscription{ID: 1, Target: server.URL}, &domain.Event{})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,217 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// A4 page size in points
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+)
+
+type fontStyle int
+
+const (
+	regular fontStyle = iota
+	bold
+)
+
+// resource name of the font styles in the page resources
+var fontNames = map[fontStyle]string{
+	regular: "F1",
+	bold:    "F2",
+}
+
+// document is a minimal PDF writer, text is drawn with the standard Helvetica fonts
+// so no font has to be embedded
+type document struct {
+	pages []*bytes.Buffer
+}
+
+// addPage start a new page, the next drawing goes to it
+func (d *document) addPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *document) page() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.addPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// text draw s with its baseline starting at x, y (from the bottom left corner of the page)
+func (d *document) text(x, y float64, style fontStyle, size float64, s string) {
+	fmt.Fprintf(d.page(), "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", fontNames[style], size, x, y, escapeText(encodeText(s)))
+}
+
+// line draw a thin line from x1, y1 to x2, y2
+func (d *document) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.page(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, y1, x2, y2)
+}
+
+// write write the document as a PDF file to w
+func (d *document) write(w io.Writer) error {
+	if len(d.pages) == 0 {
+		d.addPage()
+	}
+
+	buf := &bytes.Buffer{}
+	offsets := []int{}
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// objects 1 to 4 are the catalog, the page tree and the fonts, then every page takes a page and a content object
+	kids := make([]string, 0, len(d.pages))
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+i*2))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 6+i*2))
+
+		content := &bytes.Buffer{}
+		zw := zlib.NewWriter(content)
+		_, err := zw.Write(page.Bytes())
+		if err != nil {
+			return err
+		}
+		err = zw.Close()
+		if err != nil {
+			return err
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", content.Len(), content.Bytes()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// winAnsi is the WinAnsiEncoding code of the characters outside latin-1 it has
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// encodeText encode s in WinAnsiEncoding, letters it does not have lose their accents
+// (Vietnamese "Gạo Tám" is written "Gao Tám") and other characters are written as ?
+func encodeText(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		if c, ok := encodeRune(r); ok {
+			out = append(out, c)
+			continue
+		}
+
+		switch r {
+		case 'đ':
+			out = append(out, 'd')
+			continue
+		case 'Đ':
+			out = append(out, 'D')
+			continue
+		}
+
+		// keep the base letter and as many of the accents as latin-1 has
+		folded := false
+		decomposed := []rune(norm.NFD.String(string(r)))
+		for i := len(decomposed); i > 0 && !folded; i-- {
+			composed := []rune(norm.NFC.String(string(decomposed[:i])))
+			if len(composed) != 1 {
+				continue
+			}
+			if c, ok := encodeRune(composed[0]); ok {
+				out = append(out, c)
+				folded = true
+			}
+		}
+		if !folded {
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+func encodeRune(r rune) (byte, bool) {
+	switch {
+	case r >= 0x20 && r <= 0x7e, r >= 0xa0 && r <= 0xff:
+		return byte(r), true
+	default:
+		c, ok := winAnsi[r]
+		return c, ok
+	}
+}
+
+// escapeText escape the characters of a PDF literal string
+func escapeText(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		switch c {
+		case '(', ')', '\\':
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// helvetica and helveticaBold are the widths of the printable ascii characters in 1/1000 of the font size
+var (
+	helvetica = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBold = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// textWidth return the width of s in points, characters outside ascii are counted as wide as a digit
+func textWidth(s string, style fontStyle, size float64) float64 {
+	widths := &helvetica
+	if style == bold {
+		widths = &helveticaBold
+	}
+
+	total := 0
+	for _, c := range encodeText(s) {
+		if c >= 0x20 && c <= 0x7e {
+			total += widths[c-0x20]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
@@ -0,0 +1,216 @@
+package pdf
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/config"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+//go:embed templates/invoice.tmpl
+var defaultInvoiceTemplate string
+
+// implement ports.IInvoiceRenderer, the template renders the directives of a layout that are drawn to a PDF
+type invoiceRenderer struct {
+	tmpl    *template.Template
+	company company
+}
+
+// NewInvoiceRenderer parse the invoice template of conf, the embedded default is used when no template file is set
+func NewInvoiceRenderer(conf config.Invoice) (ports.IInvoiceRenderer, error) {
+	src := defaultInvoiceTemplate
+	if conf.Template != "" {
+		b, err := os.ReadFile(conf.Template)
+		if err != nil {
+			return nil, err
+		}
+		src = string(b)
+	}
+
+	tmpl, err := template.New("invoice").Funcs(templateFuncs).Parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return &invoiceRenderer{
+		tmpl: tmpl,
+		company: company{
+			Name:    oneLine(conf.CompanyName),
+			Address: oneLine(conf.CompanyAddress),
+			Phone:   oneLine(conf.CompanyPhone),
+			TaxCode: oneLine(conf.CompanyTaxCode),
+		},
+	}, nil
+}
+
+func (r *invoiceRenderer) RenderInvoice(w io.Writer, kind domain.InvoiceKind, invoice *domain.Invoice) error {
+	src := &bytes.Buffer{}
+	err := r.tmpl.Execute(src, newInvoiceData(r.company, kind, invoice))
+	if err != nil {
+		return err
+	}
+
+	doc, err := renderLayout(src.String())
+	if err != nil {
+		return err
+	}
+	return doc.write(w)
+}
+
+type company struct {
+	Name    string
+	Address string
+	Phone   string
+	TaxCode string
+}
+
+type partner struct {
+	Name    string
+	Address string
+	Phone   string
+	TaxCode string
+}
+
+type warehouse struct {
+	Name     string
+	Location string
+}
+
+type invoiceLine struct {
+	No       int
+	Rice     string
+	Quantity int
+	Price    float64
+	Amount   float64
+}
+
+// invoiceData is the data the invoice template is executed with, text fields are kept on one line
+// and free of | so they can not break the layout directives
+type invoiceData struct {
+	Company      company
+	Title        string
+	PartnerLabel string
+	ID           int
+	CreatedAt    time.Time
+	Status       domain.InvoiceStatus
+	Warehouse    warehouse
+	Partner      partner
+	Lines        []invoiceLine
+	Quantity     int
+	Total        float64
+	Paid         float64
+	Balance      float64
+	CreatedBy    string
+	ApprovedBy   string
+	CancelReason string
+}
+
+func newInvoiceData(c company, kind domain.InvoiceKind, invoice *domain.Invoice) *invoiceData {
+	data := &invoiceData{
+		Company:      c,
+		Title:        "EXPORT INVOICE",
+		PartnerLabel: "Customer",
+		ID:           invoice.ID,
+		CreatedAt:    invoice.CreatedAt,
+		Status:       invoice.Status,
+		Quantity:     invoice.Quantity(),
+		Total:        invoice.TotalPrice,
+		Paid:         invoice.PaidAmount,
+		Balance:      invoice.Balance(),
+		CancelReason: oneLine(invoice.CancelReason),
+		Lines:        make([]invoiceLine, 0, len(invoice.Details)),
+	}
+	if kind == domain.InvoiceKindImport {
+		data.Title = "IMPORT INVOICE"
+		data.PartnerLabel = "Supplier"
+	}
+
+	if invoice.Warehouse != nil {
+		data.Warehouse = warehouse{
+			Name:     oneLine(invoice.Warehouse.Name),
+			Location: oneLine(invoice.Warehouse.Location),
+		}
+	}
+	if invoice.Customer != nil {
+		data.Partner = partner{
+			Name:    oneLine(invoice.Customer.Name),
+			Address: oneLine(invoice.Customer.Address),
+			Phone:   oneLine(invoice.Customer.Phone),
+			TaxCode: oneLine(invoice.Customer.TaxCode),
+		}
+	}
+	if invoice.CreatedBy != nil {
+		data.CreatedBy = oneLine(invoice.CreatedBy.Name)
+	}
+	if invoice.Approver != nil {
+		data.ApprovedBy = oneLine(invoice.Approver.Name)
+	}
+
+	for i, v := range invoice.Details {
+		rice := strconv.Itoa(v.RiceID)
+		if v.Rice != nil {
+			rice = oneLine(v.Rice.Name)
+		}
+
+		data.Lines = append(data.Lines, invoiceLine{
+			No:       i + 1,
+			Rice:     rice,
+			Quantity: v.Quantity,
+			Price:    v.Price,
+			Amount:   v.Price * float64(v.Quantity),
+		})
+	}
+	return data
+}
+
+// oneLine replace line breaks and | in s so it stays inside one directive and one cell
+var lineReplacer = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "|", "/")
+
+func oneLine(s string) string {
+	return lineReplacer.Replace(s)
+}
+
+var templateFuncs = template.FuncMap{
+	"money":    formatMoney,
+	"quantity": formatQuantity,
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02 15:04")
+	},
+}
+
+// formatMoney format an amount with thousands separators and two decimals, 1234567.5 is 1,234,567.50
+func formatMoney(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	whole, decimals, _ := strings.Cut(s, ".")
+	return groupThousands(whole) + "." + decimals
+}
+
+// formatQuantity format a quantity with thousands separators
+func formatQuantity(v int) string {
+	return groupThousands(strconv.Itoa(v))
+}
+
+func groupThousands(digits string) string {
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+
+	var sb strings.Builder
+	for i, c := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(c)
+	}
+	return sign + sb.String()
+}
@@ -0,0 +1,152 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tommjj/ql-kho-lua/internal/config"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+func newTestInvoice() *domain.Invoice {
+	return &domain.Invoice{
+		ID:          12,
+		WarehouseID: 1,
+		CustomerID:  1,
+		UserID:      1,
+		CreatedAt:   time.Date(2024, 9, 1, 8, 30, 0, 0, time.UTC),
+		TotalPrice:  1234567.5,
+		Status:      domain.InvoiceCompleted,
+		PaidAmount:  1000,
+		Details: []domain.InvoiceItem{
+			{RiceID: 1, Price: 20000, Quantity: 60, Rice: &domain.Rice{ID: 1, Name: "Gạo ST25"}},
+			{RiceID: 2, Price: 5637.5, Quantity: 6, Rice: &domain.Rice{ID: 2, Name: "Nàng (Hương)"}},
+		},
+		CreatedBy: &domain.User{ID: 1, Name: "vertin"},
+		Customer:  &domain.Customer{ID: 1, Name: "Ascalon | Co", Address: "12 Street\nHCMC", TaxCode: "0301234567"},
+		Warehouse: &domain.Warehouse{ID: 1, Name: "store 01", Location: "Can Tho"},
+	}
+}
+
+// pageText inflate the content streams of a PDF
+func pageText(t *testing.T, pdf []byte) string {
+	re := regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`)
+
+	var text strings.Builder
+	for _, m := range re.FindAllSubmatch(pdf, -1) {
+		r, err := zlib.NewReader(bytes.NewReader(m[1]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		text.Write(b)
+	}
+	return text.String()
+}
+
+func TestRenderInvoice(t *testing.T) {
+	renderer, err := NewInvoiceRenderer(config.Invoice{CompanyName: "Kho Lua", CompanyPhone: "0900000000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	err = renderer.RenderInvoice(buf, domain.InvoiceKindExport, newTestInvoice())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.Bytes()
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+
+	// every xref entry points at the start of its object
+	xref := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(out)
+	offset, _ := strconv.Atoi(string(xref[1]))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(out[offset:], -1)
+	for i, e := range entries {
+		at, _ := strconv.Atoi(string(e[1]))
+		assert.True(t, bytes.HasPrefix(out[at:], []byte(strconv.Itoa(i+1)+" 0 obj")), "object %d", i+1)
+	}
+
+	text := pageText(t, out)
+	assert.Contains(t, text, "(Kho Lua)")
+	assert.Contains(t, text, "(EXPORT INVOICE No. 12)")
+	assert.Contains(t, text, "(Customer: Ascalon / Co)")
+	assert.Contains(t, text, "(12 Street HCMC)")
+	assert.Contains(t, text, "(Gao ST25)")
+	assert.Contains(t, text, "(N\xe0ng \\(Huong\\))")
+	assert.Contains(t, text, "(1,234,567.50)")
+	assert.Contains(t, text, "(1,233,567.50)")
+	assert.Contains(t, text, "(Created by: vertin)")
+}
+
+func TestRenderInvoice_ManyLinesAddPages(t *testing.T) {
+	renderer, err := NewInvoiceRenderer(config.Invoice{CompanyName: "Kho Lua"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invoice := newTestInvoice()
+	for i := 0; i < 40; i++ {
+		invoice.Details = append(invoice.Details, domain.InvoiceItem{RiceID: 3 + i, Price: 1, Quantity: 1})
+	}
+
+	buf := &bytes.Buffer{}
+	err = renderer.RenderInvoice(buf, domain.InvoiceKindImport, invoice)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Contains(t, buf.String(), "/Count 2")
+	assert.Contains(t, pageText(t, buf.Bytes()), "(Supplier: Ascalon / Co)")
+}
+
+func TestNewInvoiceRenderer_MissingTemplate(t *testing.T) {
+	_, err := NewInvoiceRenderer(config.Invoice{Template: "not-found.tmpl"})
+	assert.NotNil(t, err)
+}
+
+func TestRenderLayout_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"unknown directive", "title a\nimage logo.png"},
+		{"too many cells", "columns 50 50\nrow a | b | c"},
+		{"row without columns", "row a"},
+		{"bad column width", "columns 50 abc"},
+		{"bad space", "space -1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := renderLayout(tt.src)
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestEncodeText(t *testing.T) {
+	// latin-1 keeps á and â, the other Vietnamese letters lose their accents
+	assert.Equal(t, []byte("Gao T\xe1m Thom d\xe2c san"), encodeText("Gạo Tám Thơm đấc sản"))
+	assert.Equal(t, []byte("\x80 10 \x96 ?"), encodeText("€ 10 – 中"))
+}
+
+func TestFormatMoney(t *testing.T) {
+	assert.Equal(t, "0.00", formatMoney(0))
+	assert.Equal(t, "999.50", formatMoney(999.5))
+	assert.Equal(t, "1,234,567.50", formatMoney(1234567.5))
+	assert.Equal(t, "-1,000.00", formatMoney(-1000))
+	assert.Equal(t, "12,000", formatQuantity(12000))
+}
@@ -0,0 +1,200 @@
+package pdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// margin around the content of a page in points
+const margin = 50.0
+
+// column is a column of the rows of a layout
+type column struct {
+	width float64
+	right bool
+}
+
+// layout place the lines of a rendered template on pages from top to bottom, one directive per line:
+//
+//	title TEXT              large bold text
+//	heading TEXT            bold text a bit larger than the body
+//	text TEXT               body text
+//	bold TEXT               bold body text
+//	right TEXT              body text aligned to the right margin
+//	columns W W> ...        set the columns of the next rows in percent of the content width, > aligns a column right
+//	header CELL | CELL ...  bold row followed by a rule
+//	row CELL | CELL ...     row of cells in the columns
+//	rule                    horizontal line across the content
+//	space [POINTS]          vertical space, 10 points by default
+//
+// blank lines and lines starting with # are skipped, a new page is started when the content reaches the bottom margin
+type layout struct {
+	doc     *document
+	y       float64
+	columns []column
+}
+
+func newLayout() *layout {
+	l := &layout{doc: &document{}}
+	l.newPage()
+	return l
+}
+
+func (l *layout) newPage() {
+	l.doc.addPage()
+	l.y = pageHeight - margin
+}
+
+// reserve move down height points, starting a new page when they do not fit
+func (l *layout) reserve(height float64) {
+	if l.y-height < margin {
+		l.newPage()
+	}
+	l.y -= height
+}
+
+func (l *layout) line(style fontStyle, size float64, s string, right bool) {
+	l.reserve(size * 1.5)
+
+	x := margin
+	if right {
+		x = pageWidth - margin - textWidth(s, style, size)
+	}
+	l.doc.text(x, l.y+size*0.4, style, size, s)
+}
+
+func (l *layout) row(style fontStyle, cells []string) error {
+	if len(cells) > len(l.columns) {
+		return fmt.Errorf("row has %d cells but %d columns are set", len(cells), len(l.columns))
+	}
+
+	const size = 10.0
+	l.reserve(size * 1.6)
+
+	x := margin
+	for i, cell := range cells {
+		col := l.columns[i]
+		text := fit(strings.TrimSpace(cell), style, size, col.width-6)
+
+		if text != "" {
+			cx := x + 3
+			if col.right {
+				cx = x + col.width - 3 - textWidth(text, style, size)
+			}
+			l.doc.text(cx, l.y+size*0.45, style, size, text)
+		}
+		x += col.width
+	}
+	return nil
+}
+
+func (l *layout) rule() {
+	l.reserve(4)
+	l.doc.line(margin, l.y+2, pageWidth-margin, l.y+2)
+}
+
+func (l *layout) setColumns(args string) error {
+	content := pageWidth - margin*2
+
+	columns := []column{}
+	for _, f := range strings.Fields(args) {
+		col := column{}
+		if strings.HasSuffix(f, ">") {
+			col.right = true
+			f = strings.TrimSuffix(f, ">")
+		}
+
+		percent, err := strconv.ParseFloat(f, 64)
+		if err != nil || percent <= 0 {
+			return fmt.Errorf("column width must be a positive percent: %q", f)
+		}
+		col.width = content * percent / 100
+		columns = append(columns, col)
+	}
+
+	if len(columns) == 0 {
+		return fmt.Errorf("columns needs at least one width")
+	}
+	l.columns = columns
+	return nil
+}
+
+// apply draw one directive line
+func (l *layout) apply(line string) error {
+	directive, args, _ := strings.Cut(line, " ")
+	args = strings.TrimSpace(args)
+
+	switch directive {
+	case "title":
+		l.line(bold, 18, args, false)
+	case "heading":
+		l.line(bold, 12, args, false)
+	case "text":
+		l.line(regular, 10, args, false)
+	case "bold":
+		l.line(bold, 10, args, false)
+	case "right":
+		l.line(regular, 10, args, true)
+	case "columns":
+		return l.setColumns(args)
+	case "header":
+		err := l.row(bold, strings.Split(args, "|"))
+		if err != nil {
+			return err
+		}
+		l.rule()
+	case "row":
+		return l.row(regular, strings.Split(args, "|"))
+	case "rule":
+		l.rule()
+	case "space":
+		height := 10.0
+		if args != "" {
+			v, err := strconv.ParseFloat(args, 64)
+			if err != nil || v < 0 {
+				return fmt.Errorf("space must be a positive number of points: %q", args)
+			}
+			height = v
+		}
+		l.reserve(height)
+	default:
+		return fmt.Errorf("unknown directive %q", directive)
+	}
+	return nil
+}
+
+// renderLayout draw the directives of src and return the document
+func renderLayout(src string) (*document, error) {
+	l := newLayout()
+
+	for i, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		err := l.apply(line)
+		if err != nil {
+			return nil, fmt.Errorf("layout line %d: %w", i+1, err)
+		}
+	}
+	return l.doc, nil
+}
+
+// fit cut s to width with an ellipsis when it is too wide
+func fit(s string, style fontStyle, size, width float64) string {
+	if textWidth(s, style, size) <= width {
+		return s
+	}
+
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		cut := string(runes) + "..."
+		if textWidth(cut, style, size) <= width {
+			return cut
+		}
+	}
+	return ""
+}
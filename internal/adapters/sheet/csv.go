@@ -0,0 +1,53 @@
+package sheet
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+// NewCSVWriter create a csv writer, the file starts with a utf-8 byte order mark so spreadsheet apps read accents right
+func NewCSVWriter(w io.Writer) (Writer, error) {
+	_, err := io.WriteString(w, "\ufeff")
+	if err != nil {
+		return nil, err
+	}
+
+	return &csvWriter{
+		w: csv.NewWriter(w),
+	}, nil
+}
+
+func (c *csvWriter) Write(row []any) error {
+	record := make([]string, len(row))
+	for i, v := range row {
+		text := formatText(v)
+		if _, ok := deref(v).(string); ok {
+			text = escapeFormula(text)
+		}
+		record[i] = text
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// escapeFormula quote a text cell that a spreadsheet app would run as a formula,
+// signed numbers such as +84123456789 are plain values and are kept
+func escapeFormula(s string) string {
+	if s == "" || !strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return s
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return s
+	}
+	return "'" + s
+}
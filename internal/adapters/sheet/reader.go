@@ -0,0 +1,313 @@
+package sheet
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// maxPartSize is the largest uncompressed part of an xlsx file that is read
+const maxPartSize = 64 << 20
+
+var ErrUnknownFormat = errors.New("file must be a .csv or .xlsx file")
+
+// FormatOf return the format of a file by its extension
+func FormatOf(filename string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return CSV, nil
+	case ".xlsx":
+		return XLSX, nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+// ReadAll read every row of a csv file or of the first sheet of an xlsx file as text
+func ReadAll(format Format, r io.ReaderAt, size int64) ([][]string, error) {
+	switch format {
+	case CSV:
+		return ReadCSV(io.NewSectionReader(r, 0, size), ',')
+	case XLSX:
+		return ReadXLSX(r, size)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// ReadCSV read every record of a csv file, a byte order mark is skipped and
+// the quote written by the csv writer in front of formula like text is removed.
+// Blank lines are kept as empty rows so a row is at the index of the line it starts on
+func ReadCSV(r io.Reader, comma rune) ([][]string, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.FieldsPerRecord = -1
+
+	rows := [][]string{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := cr.FieldPos(0)
+		for len(rows) < line-1 {
+			rows = append(rows, []string{})
+		}
+
+		for j, cell := range record {
+			if len(rows) == 0 && j == 0 {
+				cell = strings.TrimPrefix(cell, "\ufeff")
+			}
+			record[j] = unescapeFormula(cell)
+		}
+		rows = append(rows, record)
+	}
+	return rows, nil
+}
+
+// unescapeFormula undo escapeFormula
+func unescapeFormula(s string) string {
+	if len(s) > 1 && s[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(s[1])) {
+		return s[1:]
+	}
+	return s
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Type   string `xml:"Type,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxWorkbookSheets struct {
+	Sheets []struct {
+		RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xlsxText is the text of an inline or shared string, rich text is split in runs
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t *xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+
+	var sb strings.Builder
+	for _, r := range t.Runs {
+		sb.WriteString(r.T)
+	}
+	return sb.String()
+}
+
+type xlsxRow struct {
+	Ref   int `xml:"r,attr"`
+	Cells []struct {
+		Ref    string    `xml:"r,attr"`
+		Type   string    `xml:"t,attr"`
+		Value  string    `xml:"v"`
+		Inline *xlsxText `xml:"is"`
+	} `xml:"c"`
+}
+
+// ReadXLSX read every row of the first sheet of an xlsx file, numbers are read as their shortest text
+// and empty rows and cells between the filled ones are kept so rows have their line in the sheet
+func ReadXLSX(r io.ReaderAt, size int64) ([][]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("read xlsx: %w", err)
+	}
+
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, sharedPath, err := xlsxSheetPaths(files)
+	if err != nil {
+		return nil, err
+	}
+
+	shared := []string{}
+	if f, ok := files[sharedPath]; ok {
+		var sst struct {
+			Items []xlsxText `xml:"si"`
+		}
+		err = decodePart(f, &sst)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range sst.Items {
+			shared = append(shared, item.String())
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("read xlsx: missing %s", sheetPath)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	rows := [][]string{}
+	d := xml.NewDecoder(io.LimitReader(rc, maxPartSize))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read xlsx: %w", err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var row xlsxRow
+		err = d.DecodeElement(&row, &start)
+		if err != nil {
+			return nil, fmt.Errorf("read xlsx: %w", err)
+		}
+
+		for row.Ref > len(rows)+1 {
+			rows = append(rows, []string{})
+		}
+
+		cells := []string{}
+		for _, c := range row.Cells {
+			col := len(cells)
+			if c.Ref != "" {
+				col, err = columnIndex(c.Ref)
+				if err != nil {
+					return nil, err
+				}
+			}
+			for len(cells) < col {
+				cells = append(cells, "")
+			}
+
+			value := c.Value
+			switch c.Type {
+			case "s":
+				i, err := strconv.Atoi(c.Value)
+				if err != nil || i < 0 || i >= len(shared) {
+					return nil, fmt.Errorf("read xlsx: cell %s has a bad shared string", c.Ref)
+				}
+				value = shared[i]
+			case "inlineStr":
+				if c.Inline != nil {
+					value = c.Inline.String()
+				}
+			case "b":
+				value = strconv.FormatBool(c.Value == "1")
+			case "", "n":
+				if v, err := strconv.ParseFloat(c.Value, 64); err == nil {
+					value = strconv.FormatFloat(v, 'f', -1, 64)
+				}
+			}
+			cells = append(cells, value)
+		}
+		rows = append(rows, cells)
+	}
+	return rows, nil
+}
+
+// xlsxSheetPaths return the path of the first worksheet and of the shared strings of a workbook
+func xlsxSheetPaths(files map[string]*zip.File) (string, string, error) {
+	f, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", "", errors.New("read xlsx: missing xl/workbook.xml")
+	}
+	var workbook xlsxWorkbookSheets
+	err := decodePart(f, &workbook)
+	if err != nil {
+		return "", "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", "", errors.New("read xlsx: workbook has no sheet")
+	}
+
+	f, ok = files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "", "", errors.New("read xlsx: missing xl/_rels/workbook.xml.rels")
+	}
+	var rels xlsxRelationships
+	err = decodePart(f, &rels)
+	if err != nil {
+		return "", "", err
+	}
+
+	sheetPath, sharedPath := "", "xl/sharedStrings.xml"
+	for _, rel := range rels.Relationships {
+		target := rel.Target
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = path.Join("xl", target)
+		}
+
+		if rel.ID == workbook.Sheets[0].RelID {
+			sheetPath = target
+		}
+		if strings.HasSuffix(rel.Type, "/sharedStrings") {
+			sharedPath = target
+		}
+	}
+	if sheetPath == "" {
+		return "", "", errors.New("read xlsx: first sheet not found")
+	}
+	return sheetPath, sharedPath, nil
+}
+
+func decodePart(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	err = xml.NewDecoder(io.LimitReader(rc, maxPartSize)).Decode(v)
+	if err != nil {
+		return fmt.Errorf("read xlsx %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// columnIndex return the zero based column of a cell reference, B3 is 1
+func columnIndex(ref string) (int, error) {
+	col := 0
+	for _, c := range ref {
+		if c >= 'A' && c <= 'Z' {
+			col = col*26 + int(c-'A'+1)
+			continue
+		}
+		break
+	}
+	if col == 0 {
+		return 0, fmt.Errorf("read xlsx: bad cell reference %q", ref)
+	}
+	return col - 1, nil
+}
@@ -0,0 +1,94 @@
+package sheet
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Format is the file format of a sheet
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+)
+
+// ContentType return the mime type of the format
+func (f Format) ContentType() string {
+	if f == XLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Writer write the rows of one sheet, the first row is the header.
+// Cells can be strings, numbers, bools, time.Time or pointers to them, nil pointers are empty cells
+type Writer interface {
+	// Write write a row
+	Write(row []any) error
+	// Close flush the rows and end the file, nothing is written after it
+	Close() error
+}
+
+// NewWriter create a writer of the format, name is the sheet name of xlsx files
+func NewWriter(format Format, w io.Writer, name string) (Writer, error) {
+	switch format {
+	case CSV:
+		return NewCSVWriter(w)
+	case XLSX:
+		return NewXLSXWriter(w, name)
+	default:
+		return nil, fmt.Errorf("unknown sheet format %q", format)
+	}
+}
+
+// deref return the value a pointer cell points to, or nil
+func deref(v any) any {
+	switch p := v.(type) {
+	case *string:
+		if p != nil {
+			return *p
+		}
+	case *int:
+		if p != nil {
+			return *p
+		}
+	case *float64:
+		if p != nil {
+			return *p
+		}
+	case *time.Time:
+		if p != nil {
+			return *p
+		}
+	default:
+		return v
+	}
+	return nil
+}
+
+// formatText return the text of a cell in csv files
+func formatText(v any) string {
+	switch c := deref(v).(type) {
+	case nil:
+		return ""
+	case string:
+		return c
+	case int:
+		return strconv.Itoa(c)
+	case int64:
+		return strconv.FormatInt(c, 10)
+	case float64:
+		return strconv.FormatFloat(c, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(c)
+	case time.Time:
+		return c.Format(time.RFC3339)
+	case fmt.Stringer:
+		return c.String()
+	default:
+		return fmt.Sprint(c)
+	}
+}
@@ -0,0 +1,191 @@
+package sheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(CSV, buf, "users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createdAt := time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)
+	var missing *time.Time
+
+	assert.NoError(t, w.Write([]any{"id", "name", "created_at", "amount", "note"}))
+	assert.NoError(t, w.Write([]any{1, "Gạo, Tám", createdAt, 1250.5, missing}))
+	assert.NoError(t, w.Write([]any{2, "=HYPERLINK(\"x\")", &createdAt, -3.0, "+84123456789"}))
+	assert.NoError(t, w.Write([]any{3, "@SUM(A1)", nil, 0, "-"}))
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, "\ufeff"+
+		"id,name,created_at,amount,note\n"+
+		"1,\"Gạo, Tám\",2024-03-01T08:30:00Z,1250.5,\n"+
+		"2,\"'=HYPERLINK(\"\"x\"\")\",2024-03-01T08:30:00Z,-3,+84123456789\n"+
+		"3,'@SUM(A1),,0,'-\n", buf.String())
+}
+
+func TestXLSXWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(XLSX, buf, "import <invoices>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, w.Write([]any{"id", "name", "created_at", "paid"}))
+	assert.NoError(t, w.Write([]any{1, "Gạo & <Tám>", createdAt, true}))
+	assert.NoError(t, w.Write([]any{2.5, nil, "=1+1", false}))
+	assert.NoError(t, w.Close())
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Close()
+
+		// every part must be well formed xml
+		d := xml.NewDecoder(bytes.NewReader(b))
+		for {
+			_, err := d.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(f.Name, err)
+			}
+		}
+		parts[f.Name] = string(b)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml",
+		"xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		assert.Contains(t, parts, name)
+	}
+
+	assert.Contains(t, parts["xl/workbook.xml"], `name="import &lt;invoices&gt;"`)
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<row r="1"><c s="2" t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`)
+	assert.Contains(t, sheet, `<c s="0" t="inlineStr"><is><t xml:space="preserve">Gạo &amp; &lt;Tám&gt;</t></is></c>`)
+	assert.Contains(t, sheet, `<c s="1"><v>45352.5</v></c>`)
+	assert.Contains(t, sheet, `<c s="0" t="b"><v>1</v></c>`)
+	assert.Contains(t, sheet, `<row r="3"><c s="0"><v>2.5</v></c><c s="0"/>`)
+	// text is never a formula in xlsx, it is kept as it is
+	assert.Contains(t, sheet, `<t xml:space="preserve">=1+1</t>`)
+	assert.Equal(t, 3, strings.Count(sheet, "<row "))
+}
+
+func TestNewWriter_UnknownFormat(t *testing.T) {
+	_, err := NewWriter(Format("pdf"), &bytes.Buffer{}, "users")
+	assert.Error(t, err)
+}
+
+func TestReadAll_RoundTrip(t *testing.T) {
+	for _, format := range []Format{CSV, XLSX} {
+		buf := &bytes.Buffer{}
+		w, err := NewWriter(format, buf, "customers")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.NoError(t, w.Write([]any{"name", "phone", "note", "capacity"}))
+		assert.NoError(t, w.Write([]any{"Gạo, Tám", "+84123456789", "=1+1", 1200}))
+		assert.NoError(t, w.Write([]any{"Nàng Hương", nil, "", 50.12}))
+		assert.NoError(t, w.Close())
+
+		rows, err := ReadAll(format, bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatal(format, err)
+		}
+
+		assert.Equal(t, []string{"name", "phone", "note", "capacity"}, rows[0], format)
+		assert.Equal(t, []string{"Gạo, Tám", "+84123456789", "=1+1", "1200"}, rows[1], format)
+		assert.Equal(t, []string{"Nàng Hương", "", "", "50.12"}, rows[2], format)
+	}
+}
+
+func TestReadXLSX_SharedStringsAndGaps(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Data" sheetId="1" r:id="rId7"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId7" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/data.xml"/>` +
+			`<Relationship Id="rId8" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings" Target="sharedStrings.xml"/>` +
+			`</Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<si><t>name</t></si><si><r><t>Gạo </t></r><r><t>Tám</t></r></si></sst>`,
+		"xl/worksheets/data.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c></row>` +
+			`<row r="3"><c r="A3" t="s"><v>1</v></c><c r="C3"><v>50.119999999999997</v></c><c r="D3" t="b"><v>1</v></c></row>` +
+			`</sheetData></worksheet>`,
+	}
+	for name, content := range parts {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = io.WriteString(f, content)
+	}
+	assert.NoError(t, zw.Close())
+
+	rows, err := ReadXLSX(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, [][]string{
+		{"name"},
+		{},
+		{"Gạo Tám", "", "50.12", "true"},
+	}, rows)
+}
+
+func TestFormatOf(t *testing.T) {
+	format, err := FormatOf("Rice.XLSX")
+	assert.NoError(t, err)
+	assert.Equal(t, XLSX, format)
+
+	_, err = FormatOf("rice.xls")
+	assert.Equal(t, ErrUnknownFormat, err)
+}
+
+func TestReadCSV_BlankLines(t *testing.T) {
+	rows, err := ReadCSV(strings.NewReader("\ufeffname,note\n\nGạo,'=1\n\n\nTám,\"a\nb\"\n"), ',')
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, [][]string{
+		{"name", "note"},
+		{},
+		{"Gạo", "=1"},
+		{},
+		{},
+		{"Tám", "a\nb"},
+	}, rows)
+}
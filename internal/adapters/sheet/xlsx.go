@@ -0,0 +1,173 @@
+package sheet
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// style ids of the cellXfs in xlsxStyles
+const (
+	styleDate   = 1
+	styleHeader = 2
+)
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+// xlsxStyles has the default style, a date time style (built-in format 22) and a bold header style
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="22" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+	`</styleSheet>`
+
+// xlsxWriter stream the rows of one worksheet into a zip, the other parts of the package are written on Close
+type xlsxWriter struct {
+	zw   *zip.Writer
+	buf  *bufio.Writer
+	name string
+	rows int
+}
+
+// NewXLSXWriter create an xlsx writer with one sheet, the first row is written bold
+func NewXLSXWriter(w io.Writer, name string) (Writer, error) {
+	zw := zip.NewWriter(w)
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	x := &xlsxWriter{
+		zw:   zw,
+		buf:  bufio.NewWriter(sheet),
+		name: name,
+	}
+	_, err = x.buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+func (x *xlsxWriter) Write(row []any) error {
+	x.rows++
+	fmt.Fprintf(x.buf, `<row r="%d">`, x.rows)
+
+	for _, v := range row {
+		style := 0
+		if x.rows == 1 {
+			style = styleHeader
+		}
+
+		switch c := deref(v).(type) {
+		case nil:
+			fmt.Fprintf(x.buf, `<c s="%d"/>`, style)
+		case int:
+			fmt.Fprintf(x.buf, `<c s="%d"><v>%d</v></c>`, style, c)
+		case int64:
+			fmt.Fprintf(x.buf, `<c s="%d"><v>%d</v></c>`, style, c)
+		case float64:
+			fmt.Fprintf(x.buf, `<c s="%d"><v>%s</v></c>`, style, strconv.FormatFloat(c, 'f', -1, 64))
+		case bool:
+			b := 0
+			if c {
+				b = 1
+			}
+			fmt.Fprintf(x.buf, `<c s="%d" t="b"><v>%d</v></c>`, style, b)
+		case time.Time:
+			fmt.Fprintf(x.buf, `<c s="%d"><v>%s</v></c>`, styleDate, strconv.FormatFloat(excelTime(c), 'f', -1, 64))
+		default:
+			fmt.Fprintf(x.buf, `<c s="%d" t="inlineStr"><is><t xml:space="preserve">`, style)
+			err := xml.EscapeText(x.buf, []byte(formatText(c)))
+			if err != nil {
+				return err
+			}
+			x.buf.WriteString(`</t></is></c>`)
+		}
+	}
+
+	_, err := x.buf.WriteString(`</row>`)
+	return err
+}
+
+func (x *xlsxWriter) Close() error {
+	_, err := x.buf.WriteString(`</sheetData></worksheet>`)
+	if err != nil {
+		return err
+	}
+	err = x.buf.Flush()
+	if err != nil {
+		return err
+	}
+
+	name := &strings.Builder{}
+	err = xml.EscapeText(name, []byte(x.name))
+	if err != nil {
+		return err
+	}
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, name.String())},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, part := range parts {
+		f, err := x.zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(f, part.content)
+		if err != nil {
+			return err
+		}
+	}
+
+	return x.zw.Close()
+}
+
+// excelEpoch is day 0 of the 1900 date system of spreadsheets, it absorbs the 1900 leap year bug for dates after March 1900
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// excelTime return the serial date of t, the wall clock of t is kept
+func excelTime(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return wall.Sub(excelEpoch).Hours() / 24
+}
@@ -21,46 +21,99 @@ func NewAccessControlRepository(db *mysqldb.MysqlDB) ports.IAccessControlReposit
 	}
 }
 
-func (ar *accessControlRepository) HasAccess(ctx context.Context, warehouseID int, userID int) error {
-	result := struct {
-		warehouseID int
-		UserID      int
-	}{UserID: -1}
+func (ar *accessControlRepository) GetAccessLevel(ctx context.Context, warehouseID int, userID int) (domain.AccessLevel, error) {
+	authorized := &schema.Authorized{}
 
 	err := ar.db.WithContext(ctx).
-		Raw("SELECT * FROM authorized WHERE warehouse_id = ? AND user_id = ?", warehouseID, userID).
-		Scan(&result).Error
+		Where("warehouse_id = ? AND user_id = ?", warehouseID, userID).
+		First(authorized).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return domain.ErrDataNotFound
+			return "", domain.ErrForbidden
 		}
-		return err
-	}
-
-	if result.UserID != userID {
-		return domain.ErrForbidden
+		return "", err
 	}
-	return nil
 
+	return authorized.AccessLevel, nil
 }
 
-func (ar *accessControlRepository) SetAccess(ctx context.Context, warehouseID int, userID int) error {
-	err := ar.db.WithContext(ctx).
-		Model(&schema.User{ID: userID}).Association("AuthorizedWarehouses").Append(&schema.Warehouse{ID: warehouseID})
+func (ar *accessControlRepository) SetAccess(ctx context.Context, warehouseID int, userID int, level domain.AccessLevel) error {
+	err := ar.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Select("id").Where("id = ?", userID).First(&schema.User{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Select("id").Where("id = ?", warehouseID).First(&schema.Warehouse{}).Error
+		if err != nil {
+			return err
+		}
 
+		var count int64
+		err = tx.Table("authorized").Where("warehouse_id = ? AND user_id = ?", warehouseID, userID).Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count != 0 {
+			return domain.ErrConflictingData
+		}
+
+		return tx.Create(&schema.Authorized{
+			WarehouseID: warehouseID,
+			UserID:      userID,
+			AccessLevel: level,
+		}).Error
+	})
 	if err != nil {
-		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return domain.ErrDataNotFound
+		case errors.Is(err, gorm.ErrForeignKeyViolated):
+			return domain.ErrDataNotFound
+		case errors.Is(err, gorm.ErrDuplicatedKey):
 			return domain.ErrConflictingData
+		case errors.Is(err, domain.ErrConflictingData):
+			return domain.ErrConflictingData
+		default:
+			return err
 		}
-		return err
+	}
+	return nil
+}
+
+func (ar *accessControlRepository) UpdateAccess(ctx context.Context, warehouseID int, userID int, level domain.AccessLevel) error {
+	result := ar.db.WithContext(ctx).Model(&schema.Authorized{}).
+		Where("warehouse_id = ? AND user_id = ?", warehouseID, userID).
+		Update("access_level", level)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		var count int64
+		err := ar.db.WithContext(ctx).Model(&schema.Authorized{}).
+			Where("warehouse_id = ? AND user_id = ?", warehouseID, userID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return domain.ErrDataNotFound
+		}
+		return domain.ErrNoUpdatedData
 	}
 	return nil
 }
 
 func (ar *accessControlRepository) DelAccess(ctx context.Context, warehouseID int, userID int) error {
-	err := ar.db.WithContext(ctx).Model(&schema.User{ID: userID}).Association("AuthorizedWarehouses").Delete(&schema.Warehouse{ID: warehouseID})
-	if err != nil {
-		return err
+	result := ar.db.WithContext(ctx).
+		Exec("DELETE FROM authorized WHERE warehouse_id = ? AND user_id = ?", warehouseID, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrDataNotFound
 	}
 	return nil
 }
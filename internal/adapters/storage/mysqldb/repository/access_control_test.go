@@ -7,6 +7,7 @@ import (
 
 	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
 	"github.com/tommjj/ql-kho-lua/internal/config"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
 	"github.com/tommjj/ql-kho-lua/internal/core/ports"
 )
 
@@ -24,16 +25,18 @@ func NewDefaultAccessControlRepo() (ports.IAccessControlRepository, error) {
 	return NewAccessControlRepository(db), nil
 }
 
-func TestAccessControl_HasAccess(t *testing.T) {
+func TestAccessControl_GetAccessLevel(t *testing.T) {
 	repo, err := NewDefaultAccessControlRepo()
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	err = repo.HasAccess(context.TODO(), 2, 1)
+	level, err := repo.GetAccessLevel(context.TODO(), 2, 1)
 	if err != nil {
 		t.Fatal(err)
 	}
+
+	t.Log(level)
 }
 
 func TestAccessControl_SetAccess(t *testing.T) {
@@ -42,7 +45,19 @@ func TestAccessControl_SetAccess(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = repo.SetAccess(context.TODO(), 2, 3)
+	err = repo.SetAccess(context.TODO(), 2, 3, domain.AccessFull)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAccessControl_UpdateAccess(t *testing.T) {
+	repo, err := NewDefaultAccessControlRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.UpdateAccess(context.TODO(), 2, 3, domain.AccessReadOnly)
 	if err != nil {
 		t.Fatal(err)
 	}
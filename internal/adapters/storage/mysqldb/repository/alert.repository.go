@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb/schema"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// implement ports.IAlertRepository
+type alertRepository struct {
+	db *mysqldb.MysqlDB
+}
+
+func NewAlertRepository(db *mysqldb.MysqlDB) ports.IAlertRepository {
+	return &alertRepository{
+		db: db,
+	}
+}
+
+func (a *alertRepository) SetThreshold(ctx context.Context, threshold *domain.StockThreshold) (*domain.StockThreshold, error) {
+	data := &schema.StockThreshold{
+		WarehouseID: threshold.WarehouseID,
+		RiceID:      threshold.RiceID,
+		MinQuantity: threshold.MinQuantity,
+	}
+
+	err := a.db.WithContext(ctx).Omit("Warehouse", "Rice").Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"min_quantity", "updated_at"}),
+	}).Create(data).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return convertToStockThreshold(data), nil
+}
+
+func (a *alertRepository) GetThresholds(ctx context.Context, warehouseID int) ([]domain.StockThreshold, error) {
+	data := []schema.StockThreshold{}
+
+	err := a.db.WithContext(ctx).Preload("Rice").
+		Where("warehouse_id = ?", warehouseID).Order("rice_id").Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	thresholds := make([]domain.StockThreshold, 0, len(data))
+	for _, v := range data {
+		thresholds = append(thresholds, *convertToStockThreshold(&v))
+	}
+
+	return thresholds, nil
+}
+
+func (a *alertRepository) DeleteThreshold(ctx context.Context, warehouseID, riceID int) error {
+	result := a.db.WithContext(ctx).
+		Where("warehouse_id = ? AND rice_id = ?", warehouseID, riceID).Delete(&schema.StockThreshold{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrDataNotFound
+	}
+	return nil
+}
+
+func (a *alertRepository) GetLowStock(ctx context.Context) ([]domain.LowStock, error) {
+	result := []domain.LowStock{}
+
+	err := a.db.WithContext(ctx).Table("stock_thresholds").
+		Select(`stock_thresholds.warehouse_id, stock_thresholds.rice_id, rice.name AS rice_name,
+			COALESCE(stock_balances.quantity, 0) AS quantity, stock_thresholds.min_quantity`).
+		Joins("INNER JOIN warehouses ON warehouses.id = stock_thresholds.warehouse_id AND warehouses.deleted_at IS NULL").
+		Joins("INNER JOIN rice ON rice.id = stock_thresholds.rice_id AND rice.deleted_at IS NULL").
+		Joins(`LEFT JOIN stock_balances ON stock_balances.warehouse_id = stock_thresholds.warehouse_id
+			AND stock_balances.rice_id = stock_thresholds.rice_id`).
+		Where("COALESCE(stock_balances.quantity, 0) < stock_thresholds.min_quantity").
+		Order("stock_thresholds.warehouse_id, stock_thresholds.rice_id").
+		Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (a *alertRepository) GetNearCapacity(ctx context.Context, ratio float64) ([]domain.WarehouseUsage, error) {
+	result := []domain.WarehouseUsage{}
+
+	err := a.db.WithContext(ctx).Table("warehouses").
+		Select("warehouses.id AS warehouse_id, warehouses.name, COALESCE(SUM(stock_balances.quantity), 0) AS used, warehouses.capacity").
+		Joins("LEFT JOIN stock_balances ON stock_balances.warehouse_id = warehouses.id").
+		Where("warehouses.deleted_at IS NULL AND warehouses.capacity > 0").
+		Group("warehouses.id, warehouses.name, warehouses.capacity").
+		Having("COALESCE(SUM(stock_balances.quantity), 0) >= warehouses.capacity * ?", ratio).
+		Order("warehouses.id").
+		Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (a *alertRepository) GetExpiringLots(ctx context.Context, before time.Time) ([]domain.Lot, error) {
+	data := []schema.Lot{}
+
+	err := a.db.WithContext(ctx).Preload("Rice", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).
+		Joins("INNER JOIN warehouses ON warehouses.id = lots.warehouse_id AND warehouses.deleted_at IS NULL").
+		Where("lots.remaining > 0 AND lots.expiry_date IS NOT NULL AND lots.expiry_date <= ?", before).
+		Order("lots.expiry_date, lots.id").Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	lots := make([]domain.Lot, 0, len(data))
+	for _, v := range data {
+		lots = append(lots, *convertToLot(&v))
+	}
+
+	return lots, nil
+}
+
+func (a *alertRepository) CreateAlert(ctx context.Context, alert *domain.Alert) (bool, error) {
+	created := false
+	data := &schema.Alert{
+		Type:        alert.Type,
+		AlertKey:    alert.Key(),
+		WarehouseID: alert.WarehouseID,
+		RiceID:      alert.RiceID,
+		LotID:       alert.LotID,
+		Message:     alert.Message,
+		Value:       alert.Value,
+		Threshold:   alert.Threshold,
+	}
+
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var open int64
+		err := tx.Model(&schema.Alert{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("alert_key = ? AND acknowledged_at IS NULL", data.AlertKey).Count(&open).Error
+		if err != nil {
+			return err
+		}
+		if open > 0 {
+			return nil
+		}
+
+		created = true
+		return tx.Omit("Warehouse").Create(data).Error
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if created {
+		alert.ID = data.ID
+		alert.CreatedAt = data.CreatedAt
+	}
+	return created, nil
+}
+
+// filterAlerts add the conditions of the filter to q
+func filterAlerts(q *gorm.DB, filter domain.AlertFilter) *gorm.DB {
+	if filter.WarehouseID != 0 {
+		q = q.Where("warehouse_id = ?", filter.WarehouseID)
+	}
+	if filter.Type != "" {
+		q = q.Where("type = ?", filter.Type)
+	}
+	if filter.Acknowledged != nil {
+		if *filter.Acknowledged {
+			q = q.Where("acknowledged_at IS NOT NULL")
+		} else {
+			q = q.Where("acknowledged_at IS NULL")
+		}
+	}
+	return q
+}
+
+func (a *alertRepository) CountAlerts(ctx context.Context, filter domain.AlertFilter) (int64, error) {
+	var count int64
+
+	err := filterAlerts(a.db.WithContext(ctx).Model(&schema.Alert{}), filter).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (a *alertRepository) GetListAlerts(ctx context.Context, filter domain.AlertFilter, limit, skip int) ([]domain.Alert, error) {
+	data := []schema.Alert{}
+
+	q := a.db.WithContext(ctx).Model(&schema.Alert{}).Limit(limit).Offset((skip - 1) * limit).Order("id DESC")
+	q = filterAlerts(q, filter)
+
+	err := q.Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	alerts := make([]domain.Alert, 0, len(data))
+	for _, v := range data {
+		alerts = append(alerts, *convertToAlert(&v))
+	}
+
+	return alerts, nil
+}
+
+func (a *alertRepository) GetAlertByID(ctx context.Context, id int) (*domain.Alert, error) {
+	data := &schema.Alert{}
+
+	err := a.db.WithContext(ctx).Where("id = ?", id).First(data).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return convertToAlert(data), nil
+}
+
+func (a *alertRepository) AcknowledgeAlert(ctx context.Context, id, userID int) (*domain.Alert, error) {
+	result := a.db.WithContext(ctx).Model(&schema.Alert{}).
+		Where("id = ? AND acknowledged_at IS NULL", id).
+		Updates(map[string]any{
+			"acknowledged_at": time.Now(),
+			"acknowledged_by": userID,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	alert, err := a.GetAlertByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.ErrAlertAcknowledged
+	}
+
+	return alert, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/config"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+func NewDefaultAlertRepo() (ports.IAlertRepository, error) {
+	db, err := mysqldb.NewMysqlDB(config.DB{
+		DSN:             "root:@tcp(127.0.0.1:3306)/ql?charset=utf8mb4&parseTime=True&loc=Local",
+		MaxIdleConns:    10,
+		MaxOpenConns:    100,
+		ConnMaxLifetime: time.Hour,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAlertRepository(db), nil
+}
+
+func TestAlertRepo_GetLowStock(t *testing.T) {
+	repo, err := NewDefaultAlertRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.SetThreshold(context.TODO(), &domain.StockThreshold{
+		WarehouseID: 1,
+		RiceID:      1,
+		MinQuantity: 1000000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lowStock, err := repo.GetLowStock(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(lowStock)
+}
+
+func TestAlertRepo_CreateAlert(t *testing.T) {
+	repo, err := NewDefaultAlertRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	riceID := 1
+	alert := &domain.Alert{
+		Type:        domain.AlertLowStock,
+		WarehouseID: 1,
+		RiceID:      &riceID,
+		Message:     "test",
+	}
+
+	created, err := repo.CreateAlert(context.TODO(), alert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(created, alert.ID)
+
+	// an open alert with the same key is not created again
+	created, err = repo.CreateAlert(context.TODO(), &domain.Alert{
+		Type:        domain.AlertLowStock,
+		WarehouseID: 1,
+		RiceID:      &riceID,
+		Message:     "test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created {
+		t.Fatal("duplicate alert created")
+	}
+}
+
+func TestAlertRepo_GetListAlerts(t *testing.T) {
+	repo, err := NewDefaultAlertRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	open := false
+	alerts, err := repo.GetListAlerts(context.TODO(), domain.AlertFilter{WarehouseID: 1, Acknowledged: &open}, 5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(alerts)
+}
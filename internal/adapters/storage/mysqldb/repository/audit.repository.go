@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb/schema"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+// implement ports.IAuditRepository
+type auditRepository struct {
+	db *mysqldb.MysqlDB
+}
+
+func NewAuditRepository(db *mysqldb.MysqlDB) ports.IAuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+func (a *auditRepository) CreateAuditLog(ctx context.Context, log *domain.AuditLog) error {
+	createdLog := &schema.AuditLog{
+		ActorID:   log.ActorID,
+		ActorName: log.ActorName,
+		Action:    log.Action,
+		Entity:    log.Entity,
+		EntityID:  log.EntityID,
+		Before:    log.Before,
+		After:     log.After,
+		IP:        log.IP,
+	}
+
+	err := a.db.WithContext(ctx).Create(createdLog).Error
+	if err != nil {
+		return err
+	}
+
+	log.ID = createdLog.ID
+	log.CreatedAt = createdLog.CreatedAt
+	return nil
+}
+
+// filterAuditLogs add the conditions of the filter to q
+func filterAuditLogs(q *gorm.DB, filter domain.AuditFilter) *gorm.DB {
+	if filter.ActorID != 0 {
+		q = q.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Entity != "" {
+		q = q.Where("entity = ?", filter.Entity)
+	}
+	if filter.EntityID != 0 {
+		q = q.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Start != nil {
+		q = q.Where("created_at >= ?", filter.Start)
+	}
+	if filter.End != nil {
+		q = q.Where("created_at <= ?", filter.End)
+	}
+	return q
+}
+
+func (a *auditRepository) CountAuditLogs(ctx context.Context, filter domain.AuditFilter) (int64, error) {
+	var count int64
+
+	q := filterAuditLogs(a.db.WithContext(ctx).Model(&schema.AuditLog{}), filter)
+
+	err := q.Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (a *auditRepository) GetListAuditLogs(ctx context.Context, filter domain.AuditFilter, limit, skip int) ([]domain.AuditLog, error) {
+	logs := []schema.AuditLog{}
+
+	q := a.db.WithContext(ctx).Model(&schema.AuditLog{}).Limit(limit).Offset((skip - 1) * limit).Order("id DESC")
+	q = filterAuditLogs(q, filter)
+
+	err := q.Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(logs) == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	result := make([]domain.AuditLog, 0, len(logs))
+	for _, log := range logs {
+		result = append(result, *convertToAuditLog(&log))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb/schema"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+type bulkImportRepository struct {
+	db *mysqldb.MysqlDB
+}
+
+func NewBulkImportRepository(db *mysqldb.MysqlDB) ports.IBulkImportRepository {
+	return &bulkImportRepository{
+		db: db,
+	}
+}
+
+// errRollback end a batch transaction without committing it
+var errRollback = errors.New("rollback batch")
+
+// insertBatch insert the rows one by one in a transaction. A duplicate key only undoes its own insert, so every row is
+// checked against the unique indexes with the database collation, including the rows inserted before it.
+// The transaction is rolled back unless commit is set and every row was inserted
+func insertBatch[S any](ctx context.Context, db *gorm.DB, rows []S, commit bool) ([]error, error) {
+	errs := make([]error, len(rows))
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		failed := false
+		for i := range rows {
+			err := tx.Create(&rows[i]).Error
+			if err != nil {
+				if !errors.Is(err, gorm.ErrDuplicatedKey) {
+					return err
+				}
+				errs[i] = domain.ErrConflictingData
+				failed = true
+			}
+		}
+
+		if failed || !commit {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		return nil, err
+	}
+
+	return errs, nil
+}
+
+func (b *bulkImportRepository) CreateRiceBatch(ctx context.Context, rice []domain.Rice, commit bool) ([]domain.Rice, []error, error) {
+	data := make([]schema.Rice, len(rice))
+	for i, v := range rice {
+		data[i] = schema.Rice{
+			Name: v.Name,
+		}
+	}
+
+	errs, err := insertBatch(ctx, b.db.DB, data, commit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	created := make([]domain.Rice, len(data))
+	for i := range data {
+		created[i] = *convertToRice(&data[i])
+	}
+	return created, errs, nil
+}
+
+func (b *bulkImportRepository) CreateCustomerBatch(ctx context.Context, customers []domain.Customer, commit bool) ([]domain.Customer, []error, error) {
+	data := make([]schema.Customer, len(customers))
+	for i, v := range customers {
+		data[i] = schema.Customer{
+			Name:    v.Name,
+			Email:   v.Email,
+			Phone:   v.Phone,
+			Address: v.Address,
+			Role:    v.Role,
+			TaxCode: v.TaxCode,
+		}
+	}
+
+	errs, err := insertBatch(ctx, b.db.DB, data, commit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	created := make([]domain.Customer, len(data))
+	for i := range data {
+		created[i] = *convertToCustomer(&data[i])
+	}
+	return created, errs, nil
+}
+
+func (b *bulkImportRepository) CreateWarehouseBatch(ctx context.Context, warehouses []domain.Warehouse, commit bool) ([]domain.Warehouse, []error, error) {
+	data := make([]schema.Warehouse, len(warehouses))
+	for i, v := range warehouses {
+		data[i] = schema.Warehouse{
+			Name:     v.Name,
+			Location: v.Location,
+			Capacity: v.Capacity,
+			Image:    v.Image,
+		}
+	}
+
+	errs, err := insertBatch(ctx, b.db.DB, data, commit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	created := make([]domain.Warehouse, len(data))
+	for i := range data {
+		created[i] = *convertToWarehouse(&data[i])
+	}
+	return created, errs, nil
+}
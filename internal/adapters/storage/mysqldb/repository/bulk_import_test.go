@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/config"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+func NewDefaultBulkImportRepo() (ports.IBulkImportRepository, error) {
+	db, err := mysqldb.NewMysqlDB(config.DB{
+		DSN:             "root:@tcp(127.0.0.1:3306)/ql?charset=utf8mb4&parseTime=True&loc=Local",
+		MaxIdleConns:    10,
+		MaxOpenConns:    100,
+		ConnMaxLifetime: time.Hour,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBulkImportRepository(db), nil
+}
+
+func TestBulkImport_CreateRiceBatch_DryRun(t *testing.T) {
+	repo, err := NewDefaultBulkImportRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rice, errs, err := repo.CreateRiceBatch(context.TODO(), []domain.Rice{
+		{Name: "bulk rice 01"},
+		{Name: "bulk rice 01"},
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("%+v %v\n", rice, errs)
+}
@@ -29,6 +29,8 @@ func (cr *customerRepository) CreateCustomer(ctx context.Context, customer *doma
 		Email:   customer.Email,
 		Phone:   customer.Phone,
 		Address: customer.Address,
+		Role:    customer.Role,
+		TaxCode: customer.TaxCode,
 	}
 
 	err := cr.db.WithContext(ctx).Create(createData).Error
@@ -56,7 +58,7 @@ func (cr *customerRepository) GetCustomerByID(ctx context.Context, id int) (*dom
 	return convertToCustomer(customer), nil
 }
 
-func (cr *customerRepository) CountCustomers(ctx context.Context, query string) (int64, error) {
+func (cr *customerRepository) CountCustomers(ctx context.Context, query string, role domain.PartnerRole) (int64, error) {
 	var count int64
 	var err error
 
@@ -66,6 +68,9 @@ func (cr *customerRepository) CountCustomers(ctx context.Context, query string)
 	if trimQuery != "" {
 		q.Where("name LIKE ?", fmt.Sprintf("%%%v%%", trimQuery))
 	}
+	if role != "" {
+		q.Where("role IN ?", role.Roles())
+	}
 
 	err = q.Count(&count).Error
 	if err != nil {
@@ -75,7 +80,7 @@ func (cr *customerRepository) CountCustomers(ctx context.Context, query string)
 	return count, nil
 }
 
-func (cr *customerRepository) GetListCustomers(ctx context.Context, query string, limit, skip int) ([]domain.Customer, error) {
+func (cr *customerRepository) GetListCustomers(ctx context.Context, query string, role domain.PartnerRole, limit, skip int) ([]domain.Customer, error) {
 	customers := []domain.Customer{}
 	var err error
 
@@ -86,6 +91,9 @@ func (cr *customerRepository) GetListCustomers(ctx context.Context, query string
 	if trimQuery != "" {
 		q.Where("name LIKE ?", fmt.Sprintf("%%%v%%", trimQuery))
 	}
+	if role != "" {
+		q.Where("role IN ?", role.Roles())
+	}
 
 	err = q.Scan(&customers).Error
 	if err != nil {
@@ -98,6 +106,32 @@ func (cr *customerRepository) GetListCustomers(ctx context.Context, query string
 	return customers, nil
 }
 
+func (cr *customerRepository) GetListCustomersAfter(ctx context.Context, query string, role domain.PartnerRole, afterID, limit int) ([]domain.Customer, error) {
+	customers := []domain.Customer{}
+
+	q := cr.db.WithContext(ctx).Table("customers").
+		Where("id > ? AND deleted_at is NULL", afterID).
+		Limit(limit).Order("id")
+
+	trimQuery := strings.TrimSpace(query)
+	if trimQuery != "" {
+		q.Where("name LIKE ?", fmt.Sprintf("%%%v%%", trimQuery))
+	}
+	if role != "" {
+		q.Where("role IN ?", role.Roles())
+	}
+
+	err := q.Scan(&customers).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(customers) == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	return customers, nil
+}
+
 func (cr *customerRepository) UpdateCustomer(ctx context.Context, customer *domain.Customer) (*domain.Customer, error) {
 	updatedData := &schema.Customer{}
 
@@ -107,6 +141,8 @@ func (cr *customerRepository) UpdateCustomer(ctx context.Context, customer *doma
 			Email:   customer.Email,
 			Phone:   customer.Phone,
 			Address: customer.Address,
+			Role:    customer.Role,
+			TaxCode: customer.TaxCode,
 		})
 
 	if result.Error != nil {
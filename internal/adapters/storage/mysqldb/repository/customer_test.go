@@ -66,7 +66,7 @@ func TestCustomerRepository_GetListCustomers(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	data, err := repo.GetListCustomers(context.TODO(), "cus", 5, 1)
+	data, err := repo.GetListCustomers(context.TODO(), "cus", domain.PartnerSupplier, 5, 1)
 	if err != nil {
 		t.Fatal(err)
 	}
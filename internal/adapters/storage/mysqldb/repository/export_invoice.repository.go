@@ -23,29 +23,34 @@ func NewExInvoicesRepository(db *mysqldb.MysqlDB) ports.IExportInvoiceRepository
 	}
 }
 
-func (e *exportInvoiceRepository) CreateExInvoice(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error) {
-	createData := &schema.ExportInvoice{
-		WarehouseID: invoice.WarehouseID,
-		CustomerID:  invoice.CustomerID,
-		UserID:      invoice.UserID,
-		Details:     make([]schema.ExportInvoiceDetail, len(invoice.Details)),
-		TotalPrice:  invoice.TotalPrice,
+// insertExInvoice insert an export invoice, take its lots and remove its stock, it return the id of the invoice.
+// An invoice waiting for approval only records the lots its lines name, it takes nothing until it is approved.
+// It must be called inside a transaction
+func insertExInvoice(tx *gorm.DB, invoice *domain.Invoice) (int, error) {
+	createData := convertToExportInvoiceSchema(invoice)
+
+	err := tx.Preload("Details").Create(createData).Error
+	if err != nil {
+		return 0, err
 	}
 
-	for i, detail := range invoice.Details {
-		createData.Details[i] = schema.ExportInvoiceDetail{
-			RiceID:   detail.RiceID,
-			Price:    detail.Price,
-			Quantity: detail.Quantity,
-		}
+	if createData.Status == domain.InvoicePendingApproval {
+		return createData.ID, holdLotAllocations(tx, createData.ID, invoice.Details)
 	}
+	err = consumeLots(tx, createData.WarehouseID, createData.ID, invoice.Details)
+	if err != nil {
+		return 0, err
+	}
+	return createData.ID, addStock(tx, createData.WarehouseID, invoice.Details, -1)
+}
+
+func (e *exportInvoiceRepository) CreateExInvoice(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error) {
+	var id int
 
 	err := e.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		err := tx.Preload("Details").Create(createData).Error
-		if err != nil {
-			return err
-		}
-		return nil
+		var err error
+		id, err = insertExInvoice(tx, invoice)
+		return err
 	})
 	if err != nil {
 		switch {
@@ -53,12 +58,14 @@ func (e *exportInvoiceRepository) CreateExInvoice(ctx context.Context, invoice *
 			return nil, domain.ErrDataNotFound
 		case errors.Is(err, gorm.ErrRecordNotFound):
 			return nil, domain.ErrDataNotFound
+		case errors.Is(err, gorm.ErrDuplicatedKey):
+			return nil, domain.ErrConflictingData
 		default:
 			return nil, err
 		}
 	}
 
-	return e.GetExInvoiceWithAssociationsByID(ctx, createData.ID)
+	return e.GetExInvoiceWithAssociationsByID(ctx, id)
 }
 
 func (i *exportInvoiceRepository) GetExInvoiceByID(ctx context.Context, id int) (*domain.Invoice, error) {
@@ -73,12 +80,20 @@ func (i *exportInvoiceRepository) GetExInvoiceByID(ctx context.Context, id int)
 	}
 
 	invoice := &domain.Invoice{
-		ID:          data.ID,
-		UserID:      data.UserID,
-		CustomerID:  data.CustomerID,
-		WarehouseID: data.WarehouseID,
-		TotalPrice:  data.TotalPrice,
-		Details:     make([]domain.InvoiceItem, len(data.Details)),
+		ID:           data.ID,
+		UserID:       data.UserID,
+		CustomerID:   convertFromCustomerID(data.CustomerID),
+		WarehouseID:  data.WarehouseID,
+		TotalPrice:   data.TotalPrice,
+		Status:       data.Status,
+		CancelReason: data.CancelReason,
+		CancelledAt:  data.CancelledAt,
+		PaidAmount:   data.PaidAmount,
+		ApprovedBy:   data.ApprovedBy,
+		ApprovedAt:   data.ApprovedAt,
+		Reference:    convertFromReference(data.Reference),
+		CreatedAt:    data.CreatedAt,
+		Details:      make([]domain.InvoiceItem, len(data.Details)),
 	}
 
 	for i, detail := range data.Details {
@@ -105,13 +120,20 @@ func (i *exportInvoiceRepository) GetExInvoiceWithAssociationsByID(ctx context.C
 	}
 
 	invoice := &domain.Invoice{
-		ID:          data.ID,
-		UserID:      data.UserID,
-		CustomerID:  data.CustomerID,
-		WarehouseID: data.WarehouseID,
-		TotalPrice:  data.TotalPrice,
-		CreatedAt:   data.CreatedAt,
-		Details:     make([]domain.InvoiceItem, len(data.Details)),
+		ID:           data.ID,
+		UserID:       data.UserID,
+		CustomerID:   convertFromCustomerID(data.CustomerID),
+		WarehouseID:  data.WarehouseID,
+		TotalPrice:   data.TotalPrice,
+		Status:       data.Status,
+		CancelReason: data.CancelReason,
+		CancelledAt:  data.CancelledAt,
+		PaidAmount:   data.PaidAmount,
+		ApprovedBy:   data.ApprovedBy,
+		ApprovedAt:   data.ApprovedAt,
+		Reference:    convertFromReference(data.Reference),
+		CreatedAt:    data.CreatedAt,
+		Details:      make([]domain.InvoiceItem, len(data.Details)),
 	}
 
 	if data.Customer.ID != 0 {
@@ -126,6 +148,10 @@ func (i *exportInvoiceRepository) GetExInvoiceWithAssociationsByID(ctx context.C
 		invoice.CreatedBy = convertToUser(&data.User)
 	}
 
+	if data.Approver.ID != 0 {
+		invoice.Approver = convertToUser(&data.Approver)
+	}
+
 	for i, detail := range data.Details {
 		invoice.Details[i] = domain.InvoiceItem{
 			Price:    detail.Price,
@@ -138,6 +164,24 @@ func (i *exportInvoiceRepository) GetExInvoiceWithAssociationsByID(ctx context.C
 		}
 	}
 
+	allocations := []schema.LotAllocation{}
+	err = i.db.WithContext(ctx).Preload("Lot").Where("export_invoice_id = ?", id).Order("lot_id").Find(&allocations).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range allocations {
+		for j := range invoice.Details {
+			if invoice.Details[j].RiceID == v.RiceID {
+				invoice.Details[j].Lots = append(invoice.Details[j].Lots, domain.LotAllocation{
+					LotID:      v.LotID,
+					LotNumber:  v.Lot.LotNumber,
+					ExpiryDate: v.Lot.ExpiryDate,
+					Quantity:   v.Quantity,
+				})
+			}
+		}
+	}
+
 	return invoice, nil
 }
 
@@ -167,7 +211,7 @@ func (i *exportInvoiceRepository) GetListExInvoices(ctx context.Context, warehou
 	invoices := []domain.Invoice{}
 
 	q := i.db.WithContext(ctx).Select(
-		"id", "warehouse_id", "customer_id", "user_id", "created_at", "total_price",
+		"id", "warehouse_id", "IFNULL(customer_id, 0) AS customer_id", "user_id", "created_at", "total_price", "status", "cancel_reason", "paid_amount",
 	).Model(&schema.ExportInvoice{}).Limit(limit).Offset((skip - 1) * limit).Order("id DESC")
 
 	if start != nil {
@@ -195,6 +239,9 @@ func (i *exportInvoiceRepository) GetListExInvoices(ctx context.Context, warehou
 			&invoice.UserID,
 			&invoice.CreatedAt,
 			&invoice.TotalPrice,
+			&invoice.Status,
+			&invoice.CancelReason,
+			&invoice.PaidAmount,
 		)
 
 		invoices = append(invoices, invoice)
@@ -206,3 +253,239 @@ func (i *exportInvoiceRepository) GetListExInvoices(ctx context.Context, warehou
 
 	return invoices, nil
 }
+
+func (i *exportInvoiceRepository) GetListExInvoicesWithAssociations(ctx context.Context, warehouseID int, start *time.Time, end *time.Time, afterID, limit int) ([]domain.Invoice, error) {
+	data := []schema.ExportInvoice{}
+
+	q := i.db.WithContext(ctx).Preload("Details.Rice").Preload("Customer").Preload("Warehouse").Preload("User").
+		Where("id > ?", afterID).Limit(limit).Order("id")
+
+	if start != nil {
+		q.Where("created_at >= ?", start)
+	}
+	if end != nil {
+		q.Where("created_at <= ?", end)
+	}
+	if warehouseID != 0 {
+		q.Where("warehouse_id = ?", warehouseID)
+	}
+
+	err := q.Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	invoices := make([]domain.Invoice, len(data))
+	for i, v := range data {
+		invoices[i] = domain.Invoice{
+			ID:           v.ID,
+			UserID:       v.UserID,
+			CustomerID:   convertFromCustomerID(v.CustomerID),
+			WarehouseID:  v.WarehouseID,
+			TotalPrice:   v.TotalPrice,
+			Status:       v.Status,
+			CancelReason: v.CancelReason,
+			CancelledAt:  v.CancelledAt,
+			PaidAmount:   v.PaidAmount,
+			ApprovedBy:   v.ApprovedBy,
+			ApprovedAt:   v.ApprovedAt,
+			Reference:    convertFromReference(v.Reference),
+			CreatedAt:    v.CreatedAt,
+			Details:      make([]domain.InvoiceItem, len(v.Details)),
+		}
+
+		if v.Customer.ID != 0 {
+			invoices[i].Customer = convertToCustomer(&v.Customer)
+		}
+		if v.Warehouse.ID != 0 {
+			invoices[i].Warehouse = convertToWarehouse(&v.Warehouse)
+		}
+		if v.User.ID != 0 {
+			invoices[i].CreatedBy = convertToUser(&v.User)
+		}
+
+		for j, detail := range v.Details {
+			invoices[i].Details[j] = domain.InvoiceItem{
+				Price:    detail.Price,
+				Quantity: detail.Quantity,
+				RiceID:   detail.RiceID,
+			}
+
+			if detail.Rice.ID != 0 {
+				invoices[i].Details[j].Rice = convertToRice(&detail.Rice)
+			}
+		}
+	}
+
+	return invoices, nil
+}
+
+func (e *exportInvoiceRepository) CancelExInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	err := e.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		data := &schema.ExportInvoice{}
+		err := tx.Preload("Details").Where("id = ?", id).First(data).Error
+		if err != nil {
+			return err
+		}
+
+		// the legs of a transfer are only cancelled together, by reversing the transfer
+		result := tx.Model(&schema.ExportInvoice{}).
+			Where("id = ? AND status = ? AND paid_amount = 0", id, domain.InvoiceCompleted).
+			Where("NOT EXISTS (SELECT 1 FROM transfers WHERE transfers.export_invoice_id = export_invoices.id)").
+			Updates(map[string]any{
+				"status":        domain.InvoiceCancelled,
+				"cancel_reason": reason,
+				"cancelled_at":  time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			var transfers int64
+			err := tx.Model(&schema.Transfer{}).Where("export_invoice_id = ?", id).Count(&transfers).Error
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case transfers > 0:
+				return domain.ErrTransferInvoice
+			case data.Status == domain.InvoiceCancelled:
+				return domain.ErrInvoiceCancelled
+			case data.Status != domain.InvoiceCompleted:
+				return domain.ErrInvoiceNotCompleted
+			default:
+				return domain.ErrInvoicePaid
+			}
+		}
+
+		err = restoreLots(tx, id)
+		if err != nil {
+			return err
+		}
+
+		items := make([]domain.InvoiceItem, 0, len(data.Details))
+		for _, detail := range data.Details {
+			items = append(items, domain.InvoiceItem{RiceID: detail.RiceID, Quantity: detail.Quantity})
+		}
+		return addStock(tx, data.WarehouseID, items, 1)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, domain.ErrDataNotFound
+		case errors.Is(err, domain.ErrInvoiceCancelled):
+			return nil, domain.ErrInvoiceCancelled
+		case errors.Is(err, domain.ErrInvoiceNotCompleted):
+			return nil, domain.ErrInvoiceNotCompleted
+		case errors.Is(err, domain.ErrInvoicePaid):
+			return nil, domain.ErrInvoicePaid
+		case errors.Is(err, domain.ErrTransferInvoice):
+			return nil, domain.ErrTransferInvoice
+		default:
+			return nil, err
+		}
+	}
+
+	return e.GetExInvoiceWithAssociationsByID(ctx, id)
+}
+
+func (e *exportInvoiceRepository) ApproveExInvoice(ctx context.Context, id int, approverID int) (*domain.Invoice, error) {
+	err := e.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		data := &schema.ExportInvoice{}
+		err := tx.Preload("Details").Where("id = ?", id).First(data).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&schema.ExportInvoice{}).
+			Where("id = ? AND status = ?", id, domain.InvoicePendingApproval).
+			Updates(map[string]any{
+				"status":      domain.InvoiceCompleted,
+				"approved_by": approverID,
+				"approved_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrInvoiceNotPending
+		}
+
+		items := make([]domain.InvoiceItem, 0, len(data.Details))
+		for _, detail := range data.Details {
+			items = append(items, domain.InvoiceItem{RiceID: detail.RiceID, Quantity: detail.Quantity})
+		}
+
+		err = takeLotAllocations(tx, id, items)
+		if err != nil {
+			return err
+		}
+		err = consumeLots(tx, data.WarehouseID, id, items)
+		if err != nil {
+			return err
+		}
+		return addStock(tx, data.WarehouseID, items, -1)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, domain.ErrDataNotFound
+		case errors.Is(err, gorm.ErrForeignKeyViolated):
+			return nil, domain.ErrDataNotFound
+		case errors.Is(err, domain.ErrDataNotFound):
+			return nil, domain.ErrDataNotFound
+		case errors.Is(err, domain.ErrInvoiceNotPending):
+			return nil, domain.ErrInvoiceNotPending
+		case errors.Is(err, domain.ErrInsufficientStock):
+			return nil, domain.ErrInsufficientStock
+		default:
+			return nil, err
+		}
+	}
+
+	return e.GetExInvoiceWithAssociationsByID(ctx, id)
+}
+
+func (e *exportInvoiceRepository) RejectExInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	err := e.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		data := &schema.ExportInvoice{}
+		err := tx.Where("id = ?", id).First(data).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&schema.ExportInvoice{}).
+			Where("id = ? AND status = ?", id, domain.InvoicePendingApproval).
+			Updates(map[string]any{
+				"status":        domain.InvoiceRejected,
+				"cancel_reason": reason,
+				"cancelled_at":  time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrInvoiceNotPending
+		}
+
+		// the lots the invoice named were never taken, drop them
+		return takeLotAllocations(tx, id, nil)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, domain.ErrDataNotFound
+		case errors.Is(err, domain.ErrInvoiceNotPending):
+			return nil, domain.ErrInvoiceNotPending
+		default:
+			return nil, err
+		}
+	}
+
+	return e.GetExInvoiceWithAssociationsByID(ctx, id)
+}
@@ -89,3 +89,101 @@ func TestExInvoices_getListInvoices(t *testing.T) {
 
 	t.Logf("%+v\n", data)
 }
+
+func TestExInvoices_getListInvoicesWithAssociations(t *testing.T) {
+	repo, err := NewDefaultExInvoicesRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := repo.GetListExInvoicesWithAssociations(context.TODO(), 2, nil, nil, 1, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, v := range data {
+		if v.ID <= 1 || (i > 0 && v.ID <= data[i-1].ID) {
+			t.Fatalf("invoice %d is not after the previous id", v.ID)
+		}
+		t.Logf("%+v %+v\n", v.Customer, v.Details)
+	}
+}
+
+func TestExInvoices_approveInvoice(t *testing.T) {
+	repo, err := NewDefaultExInvoicesRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	create := &domain.Invoice{
+		UserID:      1,
+		CustomerID:  1,
+		WarehouseID: 2,
+		Status:      domain.InvoicePendingApproval,
+		Details: []domain.InvoiceItem{
+			{
+				RiceID:   1,
+				Price:    200,
+				Quantity: 20,
+			},
+		},
+	}
+	create.CalcTotalPrice()
+
+	pending, err := repo.CreateExInvoice(context.TODO(), create)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending.Status != domain.InvoicePendingApproval {
+		t.Fatalf("status is %s", pending.Status)
+	}
+
+	approved, err := repo.ApproveExInvoice(context.TODO(), pending.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if approved.Status != domain.InvoiceCompleted || approved.ApprovedBy == nil {
+		t.Fatalf("%+v", approved)
+	}
+
+	_, err = repo.RejectExInvoice(context.TODO(), pending.ID, "too large")
+	if err != domain.ErrInvoiceNotPending {
+		t.Fatal(err)
+	}
+}
+
+func TestExInvoices_createInvoiceNotCovered(t *testing.T) {
+	repo, err := NewDefaultExInvoicesRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	warehouseRepo, err := NewDefaultWarehouseRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inventory, err := warehouseRepo.GetInventory(context.TODO(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stock := 0
+	for _, v := range inventory {
+		if v.RiceID == 1 {
+			stock = v.Quantity
+		}
+	}
+
+	// neither the lots nor the stock imported before lots were tracked cover one more than the stock
+	create := &domain.Invoice{
+		UserID:      1,
+		CustomerID:  1,
+		WarehouseID: 2,
+		Details:     []domain.InvoiceItem{{RiceID: 1, Price: 200, Quantity: stock + 1}},
+	}
+	create.CalcTotalPrice()
+
+	_, err = repo.CreateExInvoice(context.TODO(), create)
+	if err != domain.ErrInsufficientStock {
+		t.Fatalf("err %v, want %v", err, domain.ErrInsufficientStock)
+	}
+}
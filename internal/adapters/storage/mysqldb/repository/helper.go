@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"strings"
+
 	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb/schema"
 	"github.com/tommjj/ql-kho-lua/internal/core/domain"
 )
@@ -44,5 +46,378 @@ func convertToCustomer(c *schema.Customer) *domain.Customer {
 		Email:   c.Email,
 		Phone:   c.Phone,
 		Address: c.Address,
+		Role:    c.Role,
+		TaxCode: c.TaxCode,
+	}
+}
+
+// convertToImportInvoiceSchema is a helper to convert domain invoice to schema import invoice type
+func convertToImportInvoiceSchema(invoice *domain.Invoice) *schema.ImportInvoice {
+	data := &schema.ImportInvoice{
+		WarehouseID: invoice.WarehouseID,
+		CustomerID:  convertToCustomerID(invoice.CustomerID),
+		UserID:      invoice.UserID,
+		Details:     make([]schema.ImportInvoiceDetail, len(invoice.Details)),
+		TotalPrice:  invoice.TotalPrice,
+		Status:      domain.InvoiceCompleted,
+		Reference:   convertToReference(invoice.Reference),
+	}
+	// only an invoice waiting for approval is written with another status
+	if invoice.Status == domain.InvoicePendingApproval {
+		data.Status = invoice.Status
+	}
+
+	for i, detail := range invoice.Details {
+		data.Details[i] = schema.ImportInvoiceDetail{
+			RiceID:   detail.RiceID,
+			Price:    detail.Price,
+			Quantity: detail.Quantity,
+		}
+	}
+
+	return data
+}
+
+// convertToCustomerID is a helper to store the partner of an invoice, the legs of a transfer have none and store null
+func convertToCustomerID(id int) *int {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+// convertFromCustomerID is a helper to read an invoice without partner as customer 0
+func convertFromCustomerID(id *int) int {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+// convertToReference is a helper to store an empty invoice reference as null, invoices without one do not collide
+func convertToReference(reference string) *string {
+	if reference == "" {
+		return nil
+	}
+	return &reference
+}
+
+// convertFromReference is a helper to read a null invoice reference as empty
+func convertFromReference(reference *string) string {
+	if reference == nil {
+		return ""
+	}
+	return *reference
+}
+
+// convertToExportInvoiceSchema is a helper to convert domain invoice to schema export invoice type
+func convertToExportInvoiceSchema(invoice *domain.Invoice) *schema.ExportInvoice {
+	data := &schema.ExportInvoice{
+		WarehouseID: invoice.WarehouseID,
+		CustomerID:  convertToCustomerID(invoice.CustomerID),
+		UserID:      invoice.UserID,
+		Details:     make([]schema.ExportInvoiceDetail, len(invoice.Details)),
+		TotalPrice:  invoice.TotalPrice,
+		Status:      domain.InvoiceCompleted,
+		Reference:   convertToReference(invoice.Reference),
+	}
+	// only an invoice waiting for approval is written with another status
+	if invoice.Status == domain.InvoicePendingApproval {
+		data.Status = invoice.Status
+	}
+
+	for i, detail := range invoice.Details {
+		data.Details[i] = schema.ExportInvoiceDetail{
+			RiceID:   detail.RiceID,
+			Price:    detail.Price,
+			Quantity: detail.Quantity,
+		}
+	}
+
+	return data
+}
+
+func convertToSession(s *schema.Session) *domain.Session {
+	return &domain.Session{
+		ID:         s.ID,
+		UserID:     s.UserID,
+		Key:        s.Key,
+		RefreshKey: s.RefreshKey,
+		UserAgent:  s.UserAgent,
+		IP:         s.IP,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		ExpiresAt:  s.ExpiresAt,
+	}
+}
+
+func convertToIdempotencyRecord(i *schema.IdempotencyKey) *domain.IdempotencyRecord {
+	return &domain.IdempotencyRecord{
+		ID:          i.ID,
+		UserID:      i.UserID,
+		Key:         i.Key,
+		Fingerprint: i.Fingerprint,
+		StatusCode:  i.StatusCode,
+		Response:    i.Response,
+		Completed:   i.Completed,
+		CreatedAt:   i.CreatedAt,
+		ExpiresAt:   i.ExpiresAt,
+	}
+}
+
+func convertToAuditLog(a *schema.AuditLog) *domain.AuditLog {
+	return &domain.AuditLog{
+		ID:        a.ID,
+		ActorID:   a.ActorID,
+		ActorName: a.ActorName,
+		Action:    a.Action,
+		Entity:    a.Entity,
+		EntityID:  a.EntityID,
+		Before:    a.Before,
+		After:     a.After,
+		IP:        a.IP,
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+func convertToLot(l *schema.Lot) *domain.Lot {
+	lot := &domain.Lot{
+		ID:              l.ID,
+		WarehouseID:     l.WarehouseID,
+		RiceID:          l.RiceID,
+		ImportInvoiceID: l.ImportInvoiceID,
+		LotNumber:       l.LotNumber,
+		HarvestDate:     l.HarvestDate,
+		ExpiryDate:      l.ExpiryDate,
+		Grade:           l.Grade,
+		Moisture:        l.Moisture,
+		Quantity:        l.Quantity,
+		Remaining:       l.Remaining,
+		CreatedAt:       l.CreatedAt,
+	}
+
+	if l.Rice.ID != 0 {
+		lot.Rice = convertToRice(&l.Rice)
+	}
+
+	return lot
+}
+
+func convertToStockThreshold(t *schema.StockThreshold) *domain.StockThreshold {
+	threshold := &domain.StockThreshold{
+		WarehouseID: t.WarehouseID,
+		RiceID:      t.RiceID,
+		MinQuantity: t.MinQuantity,
+		UpdatedAt:   t.UpdatedAt,
+	}
+
+	if t.Rice.ID != 0 {
+		threshold.Rice = convertToRice(&t.Rice)
+	}
+
+	return threshold
+}
+
+func convertToAlert(a *schema.Alert) *domain.Alert {
+	return &domain.Alert{
+		ID:             a.ID,
+		Type:           a.Type,
+		WarehouseID:    a.WarehouseID,
+		RiceID:         a.RiceID,
+		LotID:          a.LotID,
+		Message:        a.Message,
+		Value:          a.Value,
+		Threshold:      a.Threshold,
+		CreatedAt:      a.CreatedAt,
+		AcknowledgedAt: a.AcknowledgedAt,
+		AcknowledgedBy: a.AcknowledgedBy,
+	}
+}
+
+// convertToSubscription is a helper to convert schema subscription to domain subscription type
+func convertToSubscription(s *schema.Subscription) *domain.Subscription {
+	sub := &domain.Subscription{
+		ID:        s.ID,
+		UserID:    s.UserID,
+		EventType: s.EventType,
+		Channel:   s.Channel,
+		Target:    s.Target,
+		CreatedAt: s.CreatedAt,
+	}
+
+	if s.User.ID != 0 {
+		sub.User = convertToUser(&s.User)
+	}
+	return sub
+}
+
+// convertToNotification is a helper to convert schema notification to domain notification type
+func convertToNotification(n *schema.Notification) *domain.Notification {
+	return &domain.Notification{
+		ID:          n.ID,
+		UserID:      n.UserID,
+		EventType:   n.EventType,
+		WarehouseID: n.WarehouseID,
+		Subject:     n.Subject,
+		Message:     n.Message,
+		CreatedAt:   n.CreatedAt,
+		ReadAt:      n.ReadAt,
+	}
+}
+
+// convertToWebhook is a helper to convert schema webhook to domain webhook type
+func convertToWebhook(w *schema.Webhook) *domain.Webhook {
+	events := []domain.WebhookEvent{}
+	for _, e := range strings.Split(w.Events, ",") {
+		if e != "" {
+			events = append(events, domain.WebhookEvent(e))
+		}
+	}
+
+	return &domain.Webhook{
+		ID:        w.ID,
+		URL:       w.URL,
+		Secret:    w.Secret,
+		Events:    events,
+		Active:    w.Active,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+// joinWebhookEvents is a helper to store the events of a webhook in one column
+func joinWebhookEvents(events []domain.WebhookEvent) string {
+	s := make([]string, 0, len(events))
+	for _, e := range events {
+		s = append(s, string(e))
+	}
+	return strings.Join(s, ",")
+}
+
+// convertToOutboxMessage is a helper to convert schema webhook outbox to domain outbox message type
+func convertToOutboxMessage(o *schema.WebhookOutbox) *domain.OutboxMessage {
+	msg := &domain.OutboxMessage{
+		ID:            o.ID,
+		WebhookID:     o.WebhookID,
+		EventID:       o.EventID,
+		Event:         o.Event,
+		Payload:       o.Payload,
+		Status:        o.Status,
+		Attempts:      o.Attempts,
+		NextAttemptAt: o.NextAttemptAt,
+		LastError:     o.LastError,
+		CreatedAt:     o.CreatedAt,
+		DeliveredAt:   o.DeliveredAt,
+	}
+
+	if o.Webhook.ID != 0 {
+		msg.Webhook = convertToWebhook(&o.Webhook)
+	}
+	return msg
+}
+
+// convertToOrder is a helper to convert schema order to domain order type
+func convertToOrder(o *schema.Order) *domain.Order {
+	order := &domain.Order{
+		ID:          o.ID,
+		Type:        o.Type,
+		WarehouseID: o.WarehouseID,
+		CustomerID:  o.CustomerID,
+		UserID:      o.UserID,
+		Status:      o.Status,
+		TotalPrice:  o.TotalPrice,
+		InvoiceID:   o.InvoiceID,
+		CreatedAt:   o.CreatedAt,
+		ConfirmedAt: o.ConfirmedAt,
+		ClosedAt:    o.ClosedAt,
+		Details:     make([]domain.InvoiceItem, len(o.Details)),
+	}
+
+	if o.Customer.ID != 0 {
+		order.Customer = convertToCustomer(&o.Customer)
+	}
+
+	if o.Warehouse.ID != 0 {
+		order.Warehouse = convertToWarehouse(&o.Warehouse)
+	}
+
+	if o.User.ID != 0 {
+		order.CreatedBy = convertToUser(&o.User)
+	}
+
+	for i, detail := range o.Details {
+		order.Details[i] = domain.InvoiceItem{
+			Price:    detail.Price,
+			Quantity: detail.Quantity,
+			RiceID:   detail.RiceID,
+		}
+		if detail.Rice.ID != 0 {
+			order.Details[i].Rice = convertToRice(&detail.Rice)
+		}
+	}
+
+	return order
+}
+
+// convertToOrderSchema is a helper to convert domain order to schema order type
+func convertToOrderSchema(order *domain.Order) *schema.Order {
+	data := &schema.Order{
+		Type:        order.Type,
+		WarehouseID: order.WarehouseID,
+		CustomerID:  order.CustomerID,
+		UserID:      order.UserID,
+		Status:      domain.OrderDraft,
+		TotalPrice:  order.TotalPrice,
+		Details:     make([]schema.OrderDetail, len(order.Details)),
+	}
+
+	for i, detail := range order.Details {
+		data.Details[i] = schema.OrderDetail{
+			RiceID:   detail.RiceID,
+			Price:    detail.Price,
+			Quantity: detail.Quantity,
+		}
+	}
+
+	return data
+}
+
+// convertToPayment is a helper to convert schema payment to domain payment type
+func convertToPayment(p *schema.Payment) *domain.Payment {
+	payment := &domain.Payment{
+		ID:          p.ID,
+		InvoiceKind: p.InvoiceKind,
+		InvoiceID:   p.InvoiceID,
+		CustomerID:  p.CustomerID,
+		UserID:      p.UserID,
+		Amount:      p.Amount,
+		Method:      p.Method,
+		Reference:   p.Reference,
+		PaidAt:      p.PaidAt,
+		CreatedAt:   p.CreatedAt,
+	}
+
+	if p.Customer.ID != 0 {
+		payment.Customer = convertToCustomer(&p.Customer)
+	}
+
+	if p.User.ID != 0 {
+		payment.CreatedBy = convertToUser(&p.User)
+	}
+
+	return payment
+}
+
+// convertToPaymentSchema is a helper to convert domain payment to schema payment type
+func convertToPaymentSchema(payment *domain.Payment) *schema.Payment {
+	return &schema.Payment{
+		InvoiceKind: payment.InvoiceKind,
+		InvoiceID:   payment.InvoiceID,
+		CustomerID:  payment.CustomerID,
+		UserID:      payment.UserID,
+		Amount:      payment.Amount,
+		Method:      payment.Method,
+		Reference:   payment.Reference,
+		PaidAt:      payment.PaidAt,
 	}
 }
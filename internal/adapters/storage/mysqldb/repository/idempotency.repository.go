@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb/schema"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+// implement ports.IIdempotencyRepository
+type idempotencyRepository struct {
+	db *mysqldb.MysqlDB
+}
+
+func NewIdempotencyRepository(db *mysqldb.MysqlDB) ports.IIdempotencyRepository {
+	return &idempotencyRepository{
+		db: db,
+	}
+}
+
+func (i *idempotencyRepository) CreateIdempotencyRecord(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	created := &schema.IdempotencyKey{
+		UserID:      record.UserID,
+		Key:         record.Key,
+		Fingerprint: record.Fingerprint,
+		StatusCode:  record.StatusCode,
+		Response:    record.Response,
+		Completed:   record.Completed,
+		ExpiresAt:   record.ExpiresAt,
+	}
+
+	err := i.db.WithContext(ctx).Omit("User").Create(created).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return nil, domain.ErrDataNotFound
+		}
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return convertToIdempotencyRecord(created), nil
+}
+
+func (i *idempotencyRepository) GetIdempotencyRecord(ctx context.Context, userID int, key string) (*domain.IdempotencyRecord, error) {
+	record := &schema.IdempotencyKey{}
+
+	err := i.db.WithContext(ctx).Where("user_id = ? AND `key` = ?", userID, key).First(record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return convertToIdempotencyRecord(record), nil
+}
+
+func (i *idempotencyRepository) CompleteIdempotencyRecord(ctx context.Context, id int, statusCode int, response []byte) error {
+	result := i.db.WithContext(ctx).Model(&schema.IdempotencyKey{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status_code": statusCode,
+			"response":    response,
+			"completed":   true,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (i *idempotencyRepository) DeleteIdempotencyRecord(ctx context.Context, id int) error {
+	result := i.db.WithContext(ctx).Where("id = ?", id).Delete(&schema.IdempotencyKey{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (i *idempotencyRepository) DeleteExpiredIdempotencyRecords(ctx context.Context, before time.Time) (int64, error) {
+	result := i.db.WithContext(ctx).Where("expires_at < ?", before).Delete(&schema.IdempotencyKey{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
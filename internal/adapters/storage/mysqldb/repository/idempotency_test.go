@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/config"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+func NewDefaultIdempotencyRepo() (ports.IIdempotencyRepository, error) {
+	db, err := mysqldb.NewMysqlDB(config.DB{
+		DSN:             "root:@tcp(127.0.0.1:3306)/ql?charset=utf8mb4&parseTime=True&loc=Local",
+		MaxIdleConns:    10,
+		MaxOpenConns:    100,
+		ConnMaxLifetime: time.Hour,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewIdempotencyRepository(db), nil
+}
+
+func TestIdempotency_Create(t *testing.T) {
+	repo, err := NewDefaultIdempotencyRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := "test-" + time.Now().Format(time.RFC3339Nano)
+	record, err := repo.CreateIdempotencyRecord(context.TODO(), &domain.IdempotencyRecord{
+		UserID:      1,
+		Key:         key,
+		Fingerprint: "0000000000000000000000000000000000000000000000000000000000000000",
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.CreateIdempotencyRecord(context.TODO(), &domain.IdempotencyRecord{UserID: 1, Key: key, ExpiresAt: time.Now()})
+	if err != domain.ErrConflictingData {
+		t.Fatalf("expected ErrConflictingData, got %v", err)
+	}
+
+	err = repo.CompleteIdempotencyRecord(context.TODO(), record.ID, 200, []byte(`{"success":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.GetIdempotencyRecord(context.TODO(), 1, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%+v\n", got)
+
+	err = repo.DeleteIdempotencyRecord(context.TODO(), record.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIdempotency_DeleteExpired(t *testing.T) {
+	repo, err := NewDefaultIdempotencyRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := repo.DeleteExpiredIdempotencyRecords(context.TODO(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(deleted)
+}
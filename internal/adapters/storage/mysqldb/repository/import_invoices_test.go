@@ -86,3 +86,22 @@ func TestImInvoices_getListInvoices(t *testing.T) {
 
 	t.Logf("%+v\n", data)
 }
+
+func TestImInvoices_getListInvoicesWithAssociations(t *testing.T) {
+	repo, err := NewDefaultImInvoicesRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := repo.GetListImInvoicesWithAssociations(context.TODO(), 40, nil, nil, 1, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, v := range data {
+		if v.ID <= 1 || (i > 0 && v.ID <= data[i-1].ID) {
+			t.Fatalf("invoice %d is not after the previous id", v.ID)
+		}
+		t.Logf("%+v %+v\n", v.Customer, v.Details)
+	}
+}
@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb/schema"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// defaultLotNumber is the lot number of an import line created without one
+func defaultLotNumber(invoiceID, riceID int) string {
+	return fmt.Sprintf("IM%d-%d", invoiceID, riceID)
+}
+
+// createLots create a lot for every line of an import invoice,
+// it must be called inside the transaction that writes the invoice
+func createLots(tx *gorm.DB, warehouseID, invoiceID int, items []domain.InvoiceItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	lots := make([]schema.Lot, 0, len(items))
+	for _, item := range items {
+		lot := schema.Lot{
+			WarehouseID:     warehouseID,
+			RiceID:          item.RiceID,
+			ImportInvoiceID: invoiceID,
+			LotNumber:       defaultLotNumber(invoiceID, item.RiceID),
+			Quantity:        item.Quantity,
+			Remaining:       item.Quantity,
+		}
+		if item.Lot != nil {
+			if item.Lot.LotNumber != "" {
+				lot.LotNumber = item.Lot.LotNumber
+			}
+			lot.HarvestDate = item.Lot.HarvestDate
+			lot.ExpiryDate = item.Lot.ExpiryDate
+			lot.Grade = item.Lot.Grade
+			lot.Moisture = item.Lot.Moisture
+		}
+		lots = append(lots, lot)
+	}
+
+	return tx.Omit("Warehouse", "Rice", "ImportInvoice").Create(&lots).Error
+}
+
+// consumeLots take the quantity of every line of an export invoice from the lots of the warehouse,
+// from the lots named by the line or first-expiry-first-out, and fill the Lots of the lines with what was taken.
+// Stock imported before lots were tracked has no lot, the part of a line it covers is left unallocated,
+// it return domain.ErrInsufficientStock when neither the lots nor that stock cover a line.
+// It must be called inside the transaction that writes the invoice, before the stock of the invoice is removed
+func consumeLots(tx *gorm.DB, warehouseID, invoiceID int, items []domain.InvoiceItem) error {
+	for i := range items {
+		item := &items[i]
+
+		q := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("warehouse_id = ? AND rice_id = ? AND remaining > 0", warehouseID, item.RiceID)
+
+		var allocations []domain.LotAllocation
+		if len(item.Lots) > 0 {
+			ids := make([]int, 0, len(item.Lots))
+			for _, v := range item.Lots {
+				ids = append(ids, v.LotID)
+			}
+
+			data := []schema.Lot{}
+			err := q.Where("id IN ?", ids).Find(&data).Error
+			if err != nil {
+				return err
+			}
+
+			lots := make(map[int]*schema.Lot, len(data))
+			for j := range data {
+				lots[data[j].ID] = &data[j]
+			}
+
+			for _, v := range item.Lots {
+				lot, ok := lots[v.LotID]
+				if !ok {
+					return domain.ErrDataNotFound
+				}
+				if lot.Remaining < v.Quantity {
+					return domain.ErrInsufficientStock
+				}
+				allocations = append(allocations, domain.LotAllocation{
+					LotID:      lot.ID,
+					LotNumber:  lot.LotNumber,
+					ExpiryDate: lot.ExpiryDate,
+					Quantity:   v.Quantity,
+				})
+			}
+		} else {
+			data := []schema.Lot{}
+			err := q.Find(&data).Error
+			if err != nil {
+				return err
+			}
+
+			lots := make([]domain.Lot, 0, len(data))
+			for _, v := range data {
+				lots = append(lots, *convertToLot(&v))
+			}
+			var shortfall int
+			allocations, shortfall = domain.AllocateFEFO(lots, item.Quantity)
+			// the part the lots do not cover must be stock imported before lots were tracked,
+			// the stock still holds the line here as it is removed after the lots are taken
+			if shortfall > 0 {
+				stock, err := stockOf(tx, warehouseID, item.RiceID)
+				if err != nil {
+					return err
+				}
+				if stock < item.Quantity {
+					return domain.ErrInsufficientStock
+				}
+			}
+		}
+
+		for _, v := range allocations {
+			err := tx.Model(&schema.Lot{}).Where("id = ?", v.LotID).
+				Update("remaining", gorm.Expr("remaining - ?", v.Quantity)).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Omit("ExportInvoice", "Lot").Create(&schema.LotAllocation{
+				ExportInvoiceID: invoiceID,
+				LotID:           v.LotID,
+				RiceID:          item.RiceID,
+				Quantity:        v.Quantity,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		item.Lots = allocations
+	}
+
+	return nil
+}
+
+// restoreLots put the quantity an export invoice took back into its lots,
+// it must be called inside the transaction that cancels the invoice
+func restoreLots(tx *gorm.DB, invoiceID int) error {
+	allocations := []schema.LotAllocation{}
+	err := tx.Where("export_invoice_id = ?", invoiceID).Find(&allocations).Error
+	if err != nil {
+		return err
+	}
+
+	for _, v := range allocations {
+		err := tx.Model(&schema.Lot{}).Where("id = ?", v.LotID).
+			Update("remaining", gorm.Expr("remaining + ?", v.Quantity)).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeLots empty the lots of an import invoice, a lot that has already been partly exported can not be removed,
+// it must be called inside the transaction that cancels the invoice
+func removeLots(tx *gorm.DB, invoiceID int) error {
+	var consumed int64
+	err := tx.Model(&schema.Lot{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("import_invoice_id = ? AND remaining <> quantity", invoiceID).Count(&consumed).Error
+	if err != nil {
+		return err
+	}
+	if consumed > 0 {
+		return domain.ErrLotConsumed
+	}
+
+	return tx.Model(&schema.Lot{}).Where("import_invoice_id = ?", invoiceID).Update("remaining", 0).Error
+}
+
+// moveLots create the lots of the import invoice of a transfer from the lots its export invoice took,
+// so the lots keep their number and dates in the destination warehouse.
+// The part of a line not taken from a lot gets a new lot
+func moveLots(tx *gorm.DB, warehouseID, invoiceID int, items []domain.InvoiceItem) error {
+	for _, item := range items {
+		ids := make([]int, 0, len(item.Lots))
+		for _, v := range item.Lots {
+			ids = append(ids, v.LotID)
+		}
+
+		sources := []schema.Lot{}
+		if len(ids) > 0 {
+			err := tx.Where("id IN ?", ids).Find(&sources).Error
+			if err != nil {
+				return err
+			}
+		}
+		byID := make(map[int]*schema.Lot, len(sources))
+		for j := range sources {
+			byID[sources[j].ID] = &sources[j]
+		}
+
+		lots := []schema.Lot{}
+		untracked := item.Quantity
+		for _, v := range item.Lots {
+			source, ok := byID[v.LotID]
+			if !ok {
+				continue
+			}
+			lots = append(lots, schema.Lot{
+				WarehouseID:     warehouseID,
+				RiceID:          item.RiceID,
+				ImportInvoiceID: invoiceID,
+				LotNumber:       source.LotNumber,
+				HarvestDate:     source.HarvestDate,
+				ExpiryDate:      source.ExpiryDate,
+				Grade:           source.Grade,
+				Moisture:        source.Moisture,
+				Quantity:        v.Quantity,
+				Remaining:       v.Quantity,
+			})
+			untracked -= v.Quantity
+		}
+		if untracked > 0 {
+			lots = append(lots, schema.Lot{
+				WarehouseID:     warehouseID,
+				RiceID:          item.RiceID,
+				ImportInvoiceID: invoiceID,
+				LotNumber:       defaultLotNumber(invoiceID, item.RiceID),
+				Quantity:        untracked,
+				Remaining:       untracked,
+			})
+		}
+
+		if len(lots) == 0 {
+			continue
+		}
+
+		err := tx.Omit("Warehouse", "Rice", "ImportInvoice").Create(&lots).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// holdLots empty the lots of an import invoice waiting for approval, so they can not be exported before it is approved,
+// it must be called inside the transaction that writes the invoice
+func holdLots(tx *gorm.DB, invoiceID int) error {
+	return tx.Model(&schema.Lot{}).Where("import_invoice_id = ?", invoiceID).Update("remaining", 0).Error
+}
+
+// releaseLots fill the lots of an import invoice with their quantity,
+// it must be called inside the transaction that approves the invoice
+func releaseLots(tx *gorm.DB, invoiceID int) error {
+	return tx.Model(&schema.Lot{}).Where("import_invoice_id = ?", invoiceID).Update("remaining", gorm.Expr("quantity")).Error
+}
+
+// holdLotAllocations record the lots named by the lines of an export invoice waiting for approval without taking them,
+// it must be called inside the transaction that writes the invoice
+func holdLotAllocations(tx *gorm.DB, invoiceID int, items []domain.InvoiceItem) error {
+	allocations := []schema.LotAllocation{}
+	for _, item := range items {
+		for _, v := range item.Lots {
+			allocations = append(allocations, schema.LotAllocation{
+				ExportInvoiceID: invoiceID,
+				LotID:           v.LotID,
+				RiceID:          item.RiceID,
+				Quantity:        v.Quantity,
+			})
+		}
+	}
+	if len(allocations) == 0 {
+		return nil
+	}
+
+	return tx.Omit("ExportInvoice", "Lot").Create(&allocations).Error
+}
+
+// takeLotAllocations remove the lots held by an export invoice waiting for approval and put them back on its lines,
+// so the lines can be consumed when the invoice is approved.
+// It must be called inside the transaction that approves or rejects the invoice
+func takeLotAllocations(tx *gorm.DB, invoiceID int, items []domain.InvoiceItem) error {
+	allocations := []schema.LotAllocation{}
+	err := tx.Where("export_invoice_id = ?", invoiceID).Order("lot_id").Find(&allocations).Error
+	if err != nil {
+		return err
+	}
+
+	for _, v := range allocations {
+		for i := range items {
+			if items[i].RiceID == v.RiceID {
+				items[i].Lots = append(items[i].Lots, domain.LotAllocation{LotID: v.LotID, Quantity: v.Quantity})
+			}
+		}
+	}
+
+	return tx.Where("export_invoice_id = ?", invoiceID).Delete(&schema.LotAllocation{}).Error
+}
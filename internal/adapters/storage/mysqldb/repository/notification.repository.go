@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb/schema"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+// implement ports.INotificationRepository
+type notificationRepository struct {
+	db *mysqldb.MysqlDB
+}
+
+func NewNotificationRepository(db *mysqldb.MysqlDB) ports.INotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+func (n *notificationRepository) CreateSubscription(ctx context.Context, sub *domain.Subscription) (*domain.Subscription, error) {
+	data := &schema.Subscription{
+		UserID:    sub.UserID,
+		EventType: sub.EventType,
+		Channel:   sub.Channel,
+		Target:    sub.Target,
+	}
+
+	err := n.db.WithContext(ctx).Omit("User").Create(data).Error
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrDuplicatedKey):
+			return nil, domain.ErrConflictingData
+		case errors.Is(err, gorm.ErrForeignKeyViolated):
+			return nil, domain.ErrDataNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return convertToSubscription(data), nil
+}
+
+func (n *notificationRepository) GetSubscriptionsByUser(ctx context.Context, userID int) ([]domain.Subscription, error) {
+	data := []schema.Subscription{}
+
+	err := n.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	subs := make([]domain.Subscription, 0, len(data))
+	for _, v := range data {
+		subs = append(subs, *convertToSubscription(&v))
+	}
+
+	return subs, nil
+}
+
+func (n *notificationRepository) GetSubscriptionsByEvent(ctx context.Context, eventType domain.EventType) ([]domain.Subscription, error) {
+	data := []schema.Subscription{}
+
+	// deleted users are not preloaded, so their subscriptions come without user
+	err := n.db.WithContext(ctx).Preload("User").Where("event_type = ?", eventType).Order("id").Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	subs := make([]domain.Subscription, 0, len(data))
+	for _, v := range data {
+		subs = append(subs, *convertToSubscription(&v))
+	}
+
+	return subs, nil
+}
+
+func (n *notificationRepository) DeleteSubscription(ctx context.Context, id, userID int) error {
+	result := n.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&schema.Subscription{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (n *notificationRepository) CreateNotification(ctx context.Context, notification *domain.Notification) error {
+	data := &schema.Notification{
+		UserID:      notification.UserID,
+		EventType:   notification.EventType,
+		WarehouseID: notification.WarehouseID,
+		Subject:     notification.Subject,
+		Message:     notification.Message,
+	}
+
+	err := n.db.WithContext(ctx).Omit("User").Create(data).Error
+	if err != nil {
+		return err
+	}
+
+	notification.ID = data.ID
+	notification.CreatedAt = data.CreatedAt
+	return nil
+}
+
+// filterNotifications add the conditions of the user and unread to q
+func filterNotifications(q *gorm.DB, userID int, unread bool) *gorm.DB {
+	q = q.Where("user_id = ?", userID)
+	if unread {
+		q = q.Where("read_at IS NULL")
+	}
+	return q
+}
+
+func (n *notificationRepository) CountNotifications(ctx context.Context, userID int, unread bool) (int64, error) {
+	var count int64
+
+	err := filterNotifications(n.db.WithContext(ctx).Model(&schema.Notification{}), userID, unread).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (n *notificationRepository) GetListNotifications(ctx context.Context, userID int, unread bool, limit, skip int) ([]domain.Notification, error) {
+	data := []schema.Notification{}
+
+	q := n.db.WithContext(ctx).Model(&schema.Notification{}).Limit(limit).Offset((skip - 1) * limit).Order("id DESC")
+	q = filterNotifications(q, userID, unread)
+
+	err := q.Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	notifications := make([]domain.Notification, 0, len(data))
+	for _, v := range data {
+		notifications = append(notifications, *convertToNotification(&v))
+	}
+
+	return notifications, nil
+}
+
+func (n *notificationRepository) ReadNotification(ctx context.Context, id, userID int) (*domain.Notification, error) {
+	err := n.db.WithContext(ctx).Model(&schema.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", id, userID).
+		Update("read_at", time.Now()).Error
+	if err != nil {
+		return nil, err
+	}
+
+	data := &schema.Notification{}
+	err = n.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(data).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return convertToNotification(data), nil
+}
+
+func (n *notificationRepository) CreateDelivery(ctx context.Context, delivery *domain.Delivery) error {
+	data := &schema.NotificationDelivery{
+		SubscriptionID: delivery.SubscriptionID,
+		EventType:      delivery.EventType,
+		Channel:        delivery.Channel,
+		Target:         delivery.Target,
+		Attempts:       delivery.Attempts,
+		Error:          delivery.Error,
+	}
+
+	err := n.db.WithContext(ctx).Create(data).Error
+	if err != nil {
+		return err
+	}
+
+	delivery.ID = data.ID
+	delivery.CreatedAt = data.CreatedAt
+	return nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/config"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+func NewDefaultNotificationRepo() (ports.INotificationRepository, error) {
+	db, err := mysqldb.NewMysqlDB(config.DB{
+		DSN:             "root:@tcp(127.0.0.1:3306)/ql?charset=utf8mb4&parseTime=True&loc=Local",
+		MaxIdleConns:    10,
+		MaxOpenConns:    100,
+		ConnMaxLifetime: time.Hour,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNotificationRepository(db), nil
+}
+
+func TestNotificationRepo_CreateSubscription(t *testing.T) {
+	repo, err := NewDefaultNotificationRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub, err := repo.CreateSubscription(context.TODO(), &domain.Subscription{
+		UserID:    1,
+		EventType: domain.EventLargeExport,
+		Channel:   domain.ChannelInApp,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(sub)
+
+	_, err = repo.CreateSubscription(context.TODO(), &domain.Subscription{
+		UserID:    1,
+		EventType: domain.EventLargeExport,
+		Channel:   domain.ChannelInApp,
+	})
+	if err != domain.ErrConflictingData {
+		t.Fatal("duplicate subscription created")
+	}
+
+	subs, err := repo.GetSubscriptionsByEvent(context.TODO(), domain.EventLargeExport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(subs)
+
+	err = repo.DeleteSubscription(context.TODO(), sub.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNotificationRepo_Notifications(t *testing.T) {
+	repo, err := NewDefaultNotificationRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notification := &domain.Notification{
+		UserID:      1,
+		EventType:   domain.EventLowStock,
+		WarehouseID: 1,
+		Subject:     "test",
+		Message:     "test",
+	}
+	err = repo.CreateNotification(context.TODO(), notification)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := repo.ReadNotification(context.TODO(), notification.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.ReadAt == nil {
+		t.Fatal("notification not read")
+	}
+
+	notifications, err := repo.GetListNotifications(context.TODO(), 1, false, 5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(notifications)
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb/schema"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type orderRepository struct {
+	db     *mysqldb.MysqlDB
+	exRepo *exportInvoiceRepository
+	imRepo *importInvoiceRepository
+}
+
+func NewOrderRepository(db *mysqldb.MysqlDB) ports.IOrderRepository {
+	return &orderRepository{
+		db:     db,
+		exRepo: &exportInvoiceRepository{db: db},
+		imRepo: &importInvoiceRepository{db: db},
+	}
+}
+
+// reservations select the details of the confirmed orders of a type in a warehouse
+func reservations(q *gorm.DB, warehouseID int, orderType domain.OrderType) *gorm.DB {
+	return q.Table("order_details").
+		Joins("INNER JOIN orders ON orders.id = order_details.order_id").
+		Where("orders.warehouse_id = ? AND orders.type = ? AND orders.status = ?",
+			warehouseID, orderType, domain.OrderConfirmed)
+}
+
+func (o *orderRepository) CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error) {
+	createData := convertToOrderSchema(order)
+
+	err := o.db.WithContext(ctx).Omit("Warehouse", "Customer", "User").Create(createData).Error
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrForeignKeyViolated):
+			return nil, domain.ErrDataNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return o.GetOrderByID(ctx, createData.ID)
+}
+
+func (o *orderRepository) GetOrderByID(ctx context.Context, id int) (*domain.Order, error) {
+	data := &schema.Order{}
+
+	err := o.db.WithContext(ctx).Preload("Details.Rice").
+		Preload(clause.Associations).Where("id = ?", id).First(data).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return convertToOrder(data), nil
+}
+
+// filterOrders add the conditions of the filter to q
+func filterOrders(q *gorm.DB, filter domain.OrderFilter) *gorm.DB {
+	if filter.WarehouseID != 0 {
+		q = q.Where("warehouse_id = ?", filter.WarehouseID)
+	}
+	if filter.Type != "" {
+		q = q.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	return q
+}
+
+func (o *orderRepository) CountOrders(ctx context.Context, filter domain.OrderFilter) (int64, error) {
+	var count int64
+
+	err := filterOrders(o.db.WithContext(ctx).Model(&schema.Order{}), filter).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (o *orderRepository) GetListOrders(ctx context.Context, filter domain.OrderFilter, limit, skip int) ([]domain.Order, error) {
+	data := []schema.Order{}
+
+	q := o.db.WithContext(ctx).Model(&schema.Order{}).Preload("Details").
+		Limit(limit).Offset((skip - 1) * limit).Order("id DESC")
+	q = filterOrders(q, filter)
+
+	err := q.Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	orders := make([]domain.Order, 0, len(data))
+	for _, v := range data {
+		orders = append(orders, *convertToOrder(&v))
+	}
+
+	return orders, nil
+}
+
+// orderStatusUpdates return the columns written when an order moves to the status
+func orderStatusUpdates(status domain.OrderStatus) map[string]any {
+	updates := map[string]any{
+		"status": status,
+	}
+
+	switch status {
+	case domain.OrderConfirmed:
+		updates["confirmed_at"] = time.Now()
+	case domain.OrderCancelled, domain.OrderConverted:
+		updates["closed_at"] = time.Now()
+	}
+	return updates
+}
+
+func (o *orderRepository) UpdateOrderStatus(ctx context.Context, id int, status domain.OrderStatus) (*domain.Order, error) {
+	err := o.db.WithContext(ctx).First(&schema.Order{}, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	result := o.db.WithContext(ctx).Model(&schema.Order{}).
+		Where("id = ? AND status IN ?", id, status.From()).
+		Updates(orderStatusUpdates(status))
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.ErrInvalidOrderStatus
+	}
+
+	return o.GetOrderByID(ctx, id)
+}
+
+func (o *orderRepository) ConvertOrder(ctx context.Context, order *domain.Order, invoice *domain.Invoice) (*domain.Invoice, error) {
+	var invoiceID int
+
+	err := o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// mark the order first so the reservation is released by the same transaction that moves the goods
+		result := tx.Model(&schema.Order{}).
+			Where("id = ? AND status IN ?", order.ID, domain.OrderConverted.From()).
+			Updates(orderStatusUpdates(domain.OrderConverted))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrInvalidOrderStatus
+		}
+
+		var err error
+		if order.Type == domain.PurchaseOrder {
+			invoiceID, err = insertImInvoice(tx, invoice)
+		} else {
+			invoiceID, err = insertExInvoice(tx, invoice)
+		}
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&schema.Order{}).Where("id = ?", order.ID).
+			Update("invoice_id", invoiceID).Error
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrForeignKeyViolated):
+			return nil, domain.ErrDataNotFound
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, domain.ErrDataNotFound
+		case errors.Is(err, domain.ErrInvalidOrderStatus):
+			return nil, domain.ErrInvalidOrderStatus
+		default:
+			return nil, err
+		}
+	}
+
+	if order.Type == domain.PurchaseOrder {
+		return o.imRepo.GetImInvoiceWithAssociationsByID(ctx, invoiceID)
+	}
+	return o.exRepo.GetExInvoiceWithAssociationsByID(ctx, invoiceID)
+}
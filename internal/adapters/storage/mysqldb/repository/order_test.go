@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/config"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+func NewDefaultOrderRepo() (ports.IOrderRepository, ports.IWarehouseRepository, error) {
+	db, err := mysqldb.NewMysqlDB(config.DB{
+		DSN:             "root:@tcp(127.0.0.1:3306)/ql?charset=utf8mb4&parseTime=True&loc=Local",
+		MaxIdleConns:    10,
+		MaxOpenConns:    100,
+		ConnMaxLifetime: time.Hour,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return NewOrderRepository(db), NewWarehouseRepository(db), nil
+}
+
+func TestOrderRepo_PurchaseOrderReservesCapacity(t *testing.T) {
+	repo, warehouseRepo, err := NewDefaultOrderRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := warehouseRepo.GetUsedCapacityByID(context.TODO(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := repo.CreateOrder(context.TODO(), &domain.Order{
+		Type:        domain.PurchaseOrder,
+		WarehouseID: 1,
+		CustomerID:  1,
+		UserID:      1,
+		Details:     []domain.InvoiceItem{{RiceID: 1, Quantity: 10, Price: 10}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.UpdateOrderStatus(context.TODO(), order.ID, domain.OrderConfirmed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := warehouseRepo.GetUsedCapacityByID(context.TODO(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after != before+10 {
+		t.Fatalf("used capacity %d, want %d", after, before+10)
+	}
+
+	invoice, err := repo.ConvertOrder(context.TODO(), order, order.Invoice(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	converted, err := warehouseRepo.GetUsedCapacityByID(context.TODO(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if converted != after {
+		t.Fatalf("used capacity %d after convert, want %d", converted, after)
+	}
+
+	t.Log(invoice)
+}
+
+func TestOrderRepo_UpdateOrderStatus(t *testing.T) {
+	repo, _, err := NewDefaultOrderRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := repo.CreateOrder(context.TODO(), &domain.Order{
+		Type:        domain.SalesOrder,
+		WarehouseID: 1,
+		CustomerID:  1,
+		UserID:      1,
+		Details:     []domain.InvoiceItem{{RiceID: 1, Quantity: 1, Price: 10}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.UpdateOrderStatus(context.TODO(), order.ID, domain.OrderCancelled)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.UpdateOrderStatus(context.TODO(), order.ID, domain.OrderConfirmed)
+	if err != domain.ErrInvalidOrderStatus {
+		t.Fatalf("got %v, want %v", err, domain.ErrInvalidOrderStatus)
+	}
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb/schema"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type paymentRepository struct {
+	db *mysqldb.MysqlDB
+}
+
+func NewPaymentRepository(db *mysqldb.MysqlDB) ports.IPaymentRepository {
+	return &paymentRepository{
+		db: db,
+	}
+}
+
+// invoiceTable return the invoice table of a kind and the column of the transfers table referencing it
+func invoiceTable(kind domain.InvoiceKind) (table, transferColumn string) {
+	if kind == domain.InvoiceKindImport {
+		return "import_invoices", "import_invoice_id"
+	}
+	return "export_invoices", "export_invoice_id"
+}
+
+func (p *paymentRepository) CreatePayment(ctx context.Context, payment *domain.Payment) (*domain.Payment, error) {
+	table, transferColumn := invoiceTable(payment.InvoiceKind)
+	createData := convertToPaymentSchema(payment)
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		invoice := struct {
+			CustomerID int
+			Status     domain.InvoiceStatus
+			Transfer   bool
+		}{}
+
+		err := tx.Table(table).
+			Select(fmt.Sprintf("IFNULL(customer_id, 0) AS customer_id, status, EXISTS (SELECT 1 FROM transfers WHERE transfers.%s = %s.id) AS transfer", transferColumn, table)).
+			Where("id = ?", payment.InvoiceID).Take(&invoice).Error
+		if err != nil {
+			return err
+		}
+
+		// transfers move rice between our own warehouses, nothing is owed on them
+		if invoice.Status != domain.InvoiceCompleted || invoice.Transfer {
+			return domain.ErrInvoiceNotPayable
+		}
+
+		// the condition and the increment run in one statement so concurrent payments can not overpay the invoice
+		result := tx.Table(table).
+			Where("id = ? AND status = ? AND ROUND(paid_amount + ?, 2) <= ROUND(total_price, 2)",
+				payment.InvoiceID, domain.InvoiceCompleted, payment.Amount).
+			Update("paid_amount", gorm.Expr("paid_amount + ?", payment.Amount))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrOverpayment
+		}
+
+		createData.CustomerID = invoice.CustomerID
+		return tx.Omit("Customer", "User").Create(createData).Error
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, domain.ErrDataNotFound
+		case errors.Is(err, gorm.ErrForeignKeyViolated):
+			return nil, domain.ErrDataNotFound
+		case errors.Is(err, domain.ErrInvoiceNotPayable):
+			return nil, domain.ErrInvoiceNotPayable
+		case errors.Is(err, domain.ErrOverpayment):
+			return nil, domain.ErrOverpayment
+		default:
+			return nil, err
+		}
+	}
+
+	return p.GetPaymentByID(ctx, createData.ID)
+}
+
+func (p *paymentRepository) GetPaymentByID(ctx context.Context, id int) (*domain.Payment, error) {
+	data := &schema.Payment{}
+
+	err := p.db.WithContext(ctx).Preload(clause.Associations).Where("id = ?", id).First(data).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	payment := convertToPayment(data)
+	payment.WarehouseID, err = p.GetInvoiceWarehouseID(ctx, payment.InvoiceKind, payment.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+func (p *paymentRepository) GetInvoiceWarehouseID(ctx context.Context, kind domain.InvoiceKind, invoiceID int) (int, error) {
+	table, _ := invoiceTable(kind)
+
+	var warehouseID int
+	err := p.db.WithContext(ctx).Table(table).Select("warehouse_id").Where("id = ?", invoiceID).Take(&warehouseID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, domain.ErrDataNotFound
+		}
+		return 0, err
+	}
+
+	return warehouseID, nil
+}
+
+// filterPayments add the conditions of the filter to q
+func filterPayments(q *gorm.DB, filter domain.PaymentFilter) *gorm.DB {
+	if filter.InvoiceKind != "" {
+		q = q.Where("invoice_kind = ?", filter.InvoiceKind)
+	}
+	if filter.InvoiceID != 0 {
+		q = q.Where("invoice_id = ?", filter.InvoiceID)
+	}
+	if filter.CustomerID != 0 {
+		q = q.Where("customer_id = ?", filter.CustomerID)
+	}
+	if filter.WarehouseID != 0 {
+		// payments keep no warehouse, it is the warehouse of their invoice
+		q = q.Where(`(invoice_kind = ? AND invoice_id IN (SELECT id FROM import_invoices WHERE warehouse_id = ?))
+			OR (invoice_kind = ? AND invoice_id IN (SELECT id FROM export_invoices WHERE warehouse_id = ?))`,
+			domain.InvoiceKindImport, filter.WarehouseID, domain.InvoiceKindExport, filter.WarehouseID)
+	}
+	if filter.Start != nil {
+		q = q.Where("paid_at >= ?", filter.Start)
+	}
+	if filter.End != nil {
+		q = q.Where("paid_at <= ?", filter.End)
+	}
+	return q
+}
+
+func (p *paymentRepository) CountPayments(ctx context.Context, filter domain.PaymentFilter) (int64, error) {
+	var count int64
+
+	err := filterPayments(p.db.WithContext(ctx).Model(&schema.Payment{}), filter).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (p *paymentRepository) GetListPayments(ctx context.Context, filter domain.PaymentFilter, limit, skip int) ([]domain.Payment, error) {
+	data := []schema.Payment{}
+
+	q := p.db.WithContext(ctx).Model(&schema.Payment{}).Preload("Customer").
+		Limit(limit).Offset((skip - 1) * limit).Order("paid_at DESC, id DESC")
+	q = filterPayments(q, filter)
+
+	err := q.Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	payments := make([]domain.Payment, 0, len(data))
+	for _, v := range data {
+		payments = append(payments, *convertToPayment(&v))
+	}
+
+	return payments, nil
+}
+
+func (p *paymentRepository) GetPartnerBalances(ctx context.Context, kind domain.InvoiceKind, warehouseID int) ([]domain.PartnerBalance, error) {
+	table, transferColumn := invoiceTable(kind)
+
+	q := p.db.WithContext(ctx).Table(table)
+	if warehouseID != 0 {
+		q = q.Where(table+".warehouse_id = ?", warehouseID)
+	}
+
+	result := []domain.PartnerBalance{}
+	err := q.
+		Select(fmt.Sprintf(`%s.customer_id, customers.name AS customer_name,
+			ROUND(SUM(%s.total_price), 2) AS invoiced,
+			ROUND(SUM(%s.paid_amount), 2) AS paid,
+			ROUND(SUM(%s.total_price - %s.paid_amount), 2) AS outstanding`, table, table, table, table, table)).
+		Joins(fmt.Sprintf("INNER JOIN customers ON customers.id = %s.customer_id", table)).
+		Where(table+".status = ?", domain.InvoiceCompleted).
+		Where(fmt.Sprintf("NOT EXISTS (SELECT 1 FROM transfers WHERE transfers.%s = %s.id)", transferColumn, table)).
+		Group(table + ".customer_id, customers.name").
+		Order("customers.name, " + table + ".customer_id").
+		Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (p *paymentRepository) GetOpenInvoices(ctx context.Context, kind domain.InvoiceKind, warehouseID int, at time.Time) ([]domain.OpenInvoice, error) {
+	table, transferColumn := invoiceTable(kind)
+
+	// only the payments made up to at count, so the report can be run for a past date,
+	// invoices are aged from the time they were completed, as the other reports do
+	completedAt := completedAtExpr("t")
+	result := []domain.OpenInvoice{}
+	err := p.db.WithContext(ctx).Raw(fmt.Sprintf(`SELECT t.id AS invoice_id, t.customer_id, customers.name AS customer_name, %s AS completed_at,
+				ROUND(t.total_price - COALESCE((SELECT SUM(payments.amount) FROM payments
+					WHERE payments.invoice_kind = @kind AND payments.invoice_id = t.id AND payments.paid_at <= @at), 0), 2) AS outstanding
+			FROM %s t
+			INNER JOIN customers ON customers.id = t.customer_id
+			WHERE t.status = @status AND %s <= @at AND (@warehouse = 0 OR t.warehouse_id = @warehouse)
+				AND NOT EXISTS (SELECT 1 FROM transfers WHERE transfers.%s = t.id)
+			HAVING outstanding > 0
+			ORDER BY completed_at, t.id`, completedAt, table, completedAt, transferColumn),
+		sql.Named("kind", kind),
+		sql.Named("status", domain.InvoiceCompleted),
+		sql.Named("warehouse", warehouseID),
+		sql.Named("at", at),
+	).Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
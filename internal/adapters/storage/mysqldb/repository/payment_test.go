@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/config"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+func NewDefaultPaymentRepo() (ports.IPaymentRepository, ports.IImportInvoicesRepository, error) {
+	db, err := mysqldb.NewMysqlDB(config.DB{
+		DSN:             "root:@tcp(127.0.0.1:3306)/ql?charset=utf8mb4&parseTime=True&loc=Local",
+		MaxIdleConns:    10,
+		MaxOpenConns:    100,
+		ConnMaxLifetime: time.Hour,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return NewPaymentRepository(db), NewImInvoicesRepository(db), nil
+}
+
+func TestPaymentRepo_CreatePayment(t *testing.T) {
+	repo, imRepo, err := NewDefaultPaymentRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invoice, err := imRepo.CreateImInvoice(context.TODO(), &domain.Invoice{
+		UserID:      1,
+		CustomerID:  1,
+		WarehouseID: 1,
+		TotalPrice:  1000,
+		Status:      domain.InvoiceCompleted,
+		Details:     []domain.InvoiceItem{{RiceID: 1, Price: 100, Quantity: 10}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payment, err := repo.CreatePayment(context.TODO(), &domain.Payment{
+		InvoiceKind: domain.InvoiceKindImport,
+		InvoiceID:   invoice.ID,
+		UserID:      1,
+		Amount:      400,
+		Method:      domain.PaymentBankTransfer,
+		Reference:   "FT24001",
+		PaidAt:      time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payment.CustomerID != invoice.CustomerID {
+		t.Fatalf("payment customer %d, want %d", payment.CustomerID, invoice.CustomerID)
+	}
+
+	_, err = repo.CreatePayment(context.TODO(), &domain.Payment{
+		InvoiceKind: domain.InvoiceKindImport,
+		InvoiceID:   invoice.ID,
+		UserID:      1,
+		Amount:      600.01,
+		Method:      domain.PaymentCash,
+		PaidAt:      time.Now(),
+	})
+	if err != domain.ErrOverpayment {
+		t.Fatalf("err %v, want %v", err, domain.ErrOverpayment)
+	}
+
+	paid, err := imRepo.GetImInvoiceByID(context.TODO(), invoice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paid.PaidAmount != 400 {
+		t.Fatalf("paid amount %v, want 400", paid.PaidAmount)
+	}
+
+	_, err = imRepo.CancelImInvoice(context.TODO(), invoice.ID, "wrong quantity")
+	if err != domain.ErrInvoicePaid {
+		t.Fatalf("err %v, want %v", err, domain.ErrInvoicePaid)
+	}
+}
+
+func TestPaymentRepo_GetOpenInvoices(t *testing.T) {
+	repo, _, err := NewDefaultPaymentRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invoices, err := repo.GetOpenInvoices(context.TODO(), domain.InvoiceKindExport, 0, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("%+v\n", domain.AgeInvoices(invoices, time.Now()))
+}
+
+func TestPaymentRepo_GetPartnerBalances(t *testing.T) {
+	repo, _, err := NewDefaultPaymentRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	balances, err := repo.GetPartnerBalances(context.TODO(), domain.InvoiceKindImport, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("%+v\n", balances)
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb/schema"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+type reportRepository struct {
+	db *mysqldb.MysqlDB
+}
+
+func NewReportRepository(db *mysqldb.MysqlDB) ports.IReportRepository {
+	return &reportRepository{
+		db: db,
+	}
+}
+
+func (r *reportRepository) GetStockMovement(ctx context.Context, warehouseID int, start, end time.Time) ([]domain.StockMovement, error) {
+	err := r.db.WithContext(ctx).First(&schema.Warehouse{ID: warehouseID}).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	// every movement completed before start goes to the opening balance, cancelled invoices are ignored,
+	// invoices are dated when completed so one created before start and approved after it moves in the period
+	result := []domain.StockMovement{}
+	err = r.db.WithContext(ctx).Raw(`SELECT t.rice_id, rice.name AS rice_name,
+				SUM(CASE WHEN t.created_at < @start THEN t.quantity ELSE 0 END) AS opening,
+				SUM(CASE WHEN t.created_at >= @start AND t.quantity > 0 THEN t.quantity ELSE 0 END) AS imported,
+				SUM(CASE WHEN t.created_at >= @start AND t.quantity < 0 THEN -t.quantity ELSE 0 END) AS exported
+			FROM
+				(SELECT import_invoice_details.rice_id, import_invoice_details.quantity,
+					COALESCE(import_invoices.approved_at, import_invoices.created_at) AS created_at
+				FROM import_invoices INNER JOIN import_invoice_details ON import_invoice_details.invoice_id = import_invoices.id
+				WHERE import_invoices.warehouse_id = @warehouse AND import_invoices.status = @status AND COALESCE(import_invoices.approved_at, import_invoices.created_at) <= @end
+				UNION ALL
+				SELECT export_invoice_details.rice_id, -export_invoice_details.quantity,
+					COALESCE(export_invoices.approved_at, export_invoices.created_at)
+				FROM export_invoices INNER JOIN export_invoice_details ON export_invoice_details.invoice_id = export_invoices.id
+				WHERE export_invoices.warehouse_id = @warehouse AND export_invoices.status = @status AND COALESCE(export_invoices.approved_at, export_invoices.created_at) <= @end) t
+			INNER JOIN rice ON rice.id = t.rice_id
+			GROUP BY t.rice_id, rice.name
+			ORDER BY t.rice_id`,
+		sql.Named("warehouse", warehouseID),
+		sql.Named("status", domain.InvoiceCompleted),
+		sql.Named("start", start),
+		sql.Named("end", end),
+	).Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].Closing = result[i].Opening + result[i].Imported - result[i].Exported
+	}
+
+	return result, nil
+}
+
+// completedAtExpr return the sql expression of the time the invoices of table were completed,
+// invoices waiting for approval are completed when approved, the others when created
+func completedAtExpr(table string) string {
+	return fmt.Sprintf("COALESCE(%s.approved_at, %s.created_at)", table, table)
+}
+
+// periodExpr return the sql expression of the first day of the period column belong to
+func periodExpr(period domain.ReportPeriod, column string) string {
+	switch period {
+	case domain.PeriodWeek:
+		return fmt.Sprintf("DATE_SUB(DATE(%s), INTERVAL WEEKDAY(%s) DAY)", column, column)
+	case domain.PeriodMonth:
+		return fmt.Sprintf("CAST(DATE_FORMAT(%s, '%%Y-%%m-01') AS DATE)", column)
+	default:
+		return fmt.Sprintf("DATE(%s)", column)
+	}
+}
+
+// getInvoiceTotals sum the lines of the completed invoices of table grouped by period,
+// invoices created by a transfer (referenced by transferColumn) are excluded
+func (r *reportRepository) getInvoiceTotals(ctx context.Context, table, detailTable, transferColumn string, filter domain.InvoiceAnalyticsFilter) ([]domain.InvoiceTotal, error) {
+	completedAt := completedAtExpr(table)
+	period := periodExpr(filter.GroupBy, completedAt)
+
+	q := r.db.WithContext(ctx).Table(table).
+		Select(fmt.Sprintf(`%s AS period,
+			SUM(%s.price * %s.quantity) AS amount,
+			SUM(%s.quantity) AS quantity,
+			COUNT(DISTINCT %s.id) AS invoices`, period, detailTable, detailTable, detailTable, table)).
+		Joins(fmt.Sprintf("INNER JOIN %s ON %s.invoice_id = %s.id", detailTable, detailTable, table)).
+		Where(table+".status = ?", domain.InvoiceCompleted).
+		Where(fmt.Sprintf("NOT EXISTS (SELECT 1 FROM transfers WHERE transfers.%s = %s.id)", transferColumn, table))
+
+	if filter.WarehouseID != 0 {
+		q = q.Where(table+".warehouse_id = ?", filter.WarehouseID)
+	}
+	if filter.CustomerID != 0 {
+		q = q.Where(table+".customer_id = ?", filter.CustomerID)
+	}
+	if filter.RiceID != 0 {
+		q = q.Where(detailTable+".rice_id = ?", filter.RiceID)
+	}
+	if filter.Start != nil {
+		q = q.Where(completedAt+" >= ?", filter.Start)
+	}
+	if filter.End != nil {
+		q = q.Where(completedAt+" <= ?", filter.End)
+	}
+
+	result := []domain.InvoiceTotal{}
+	err := q.Group("period").Order("period").Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *reportRepository) GetRevenue(ctx context.Context, filter domain.InvoiceAnalyticsFilter) ([]domain.InvoiceTotal, error) {
+	return r.getInvoiceTotals(ctx, "export_invoices", "export_invoice_details", "export_invoice_id", filter)
+}
+
+func (r *reportRepository) GetPurchases(ctx context.Context, filter domain.InvoiceAnalyticsFilter) ([]domain.InvoiceTotal, error) {
+	return r.getInvoiceTotals(ctx, "import_invoices", "import_invoice_details", "import_invoice_id", filter)
+}
+
+func (r *reportRepository) GetStockLines(ctx context.Context, warehouseID int, end time.Time) ([]domain.StockLine, error) {
+	err := r.db.WithContext(ctx).First(&schema.Warehouse{ID: warehouseID}).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	// imports are ordered before exports of the same time so a sale never runs ahead of the stock it sells
+	result := []domain.StockLine{}
+	err = r.db.WithContext(ctx).Raw(`SELECT t.invoice_id, t.rice_id, rice.name AS rice_name, t.quantity, t.price, t.transfer, t.created_at
+			FROM
+				(SELECT import_invoices.id AS invoice_id, import_invoice_details.rice_id, import_invoice_details.quantity,
+					import_invoice_details.price, transfers.id IS NOT NULL AS transfer,
+					COALESCE(import_invoices.approved_at, import_invoices.created_at) AS created_at, 0 AS kind
+				FROM import_invoices
+				INNER JOIN import_invoice_details ON import_invoice_details.invoice_id = import_invoices.id
+				LEFT JOIN transfers ON transfers.import_invoice_id = import_invoices.id
+				WHERE import_invoices.warehouse_id = @warehouse AND import_invoices.status = @status AND COALESCE(import_invoices.approved_at, import_invoices.created_at) <= @end
+				UNION ALL
+				SELECT export_invoices.id, export_invoice_details.rice_id, -export_invoice_details.quantity,
+					export_invoice_details.price, transfers.id IS NOT NULL,
+					COALESCE(export_invoices.approved_at, export_invoices.created_at), 1
+				FROM export_invoices
+				INNER JOIN export_invoice_details ON export_invoice_details.invoice_id = export_invoices.id
+				LEFT JOIN transfers ON transfers.export_invoice_id = export_invoices.id
+				WHERE export_invoices.warehouse_id = @warehouse AND export_invoices.status = @status AND COALESCE(export_invoices.approved_at, export_invoices.created_at) <= @end) t
+			INNER JOIN rice ON rice.id = t.rice_id
+			ORDER BY t.created_at, t.kind, t.invoice_id`,
+		sql.Named("warehouse", warehouseID),
+		sql.Named("status", domain.InvoiceCompleted),
+		sql.Named("end", end),
+	).Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
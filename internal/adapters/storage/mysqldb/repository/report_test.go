@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/config"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+func NewDefaultReportRepo() (ports.IReportRepository, error) {
+	db, err := mysqldb.NewMysqlDB(config.DB{
+		DSN:             "root:@tcp(127.0.0.1:3306)/ql?charset=utf8mb4&parseTime=True&loc=Local",
+		MaxIdleConns:    10,
+		MaxOpenConns:    100,
+		ConnMaxLifetime: time.Hour,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReportRepository(db), nil
+}
+
+func TestReport_GetStockMovement(t *testing.T) {
+	repo, err := NewDefaultReportRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	end := time.Now()
+	start := end.AddDate(0, -1, 0)
+
+	movements, err := repo.GetStockMovement(context.TODO(), 1, start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range movements {
+		if v.Closing != v.Opening+v.Imported-v.Exported {
+			t.Fatalf("closing balance does not match movement: %+v", v)
+		}
+	}
+}
+
+func TestReport_GetRevenue(t *testing.T) {
+	repo, err := NewDefaultReportRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, period := range []domain.ReportPeriod{domain.PeriodDay, domain.PeriodWeek, domain.PeriodMonth} {
+		_, err := repo.GetRevenue(context.TODO(), domain.InvoiceAnalyticsFilter{GroupBy: period})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = repo.GetPurchases(context.TODO(), domain.InvoiceAnalyticsFilter{GroupBy: period, RiceID: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReport_GetStockLines(t *testing.T) {
+	repo, err := NewDefaultReportRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines, err := repo.GetStockLines(context.TODO(), 1, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i < len(lines); i++ {
+		if lines[i].CreatedAt.Before(lines[i-1].CreatedAt) {
+			t.Fatalf("stock lines are not ordered by time: %+v", lines)
+		}
+	}
+}
@@ -96,6 +96,29 @@ func (rr *riceRepository) GetListRice(ctx context.Context, query string, limit,
 	return rice, nil
 }
 
+func (rr *riceRepository) GetListRiceAfter(ctx context.Context, query string, afterID, limit int) ([]domain.Rice, error) {
+	rice := []domain.Rice{}
+
+	q := rr.db.WithContext(ctx).Table("rice").
+		Where("id > ? AND deleted_at is NULL", afterID).
+		Limit(limit).Order("id")
+
+	trimQuery := strings.TrimSpace(query)
+	if trimQuery != "" {
+		q.Where("name LIKE ?", fmt.Sprintf("%%%v%%", trimQuery))
+	}
+
+	err := q.Scan(&rice).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rice) == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	return rice, nil
+}
+
 func (rr *riceRepository) UpdateRice(ctx context.Context, rice *domain.Rice) (*domain.Rice, error) {
 	updateData := &schema.Rice{
 		ID:   rice.ID,
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb/schema"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+// implement ports.ISessionRepository
+type sessionRepository struct {
+	db *mysqldb.MysqlDB
+}
+
+func NewSessionRepository(db *mysqldb.MysqlDB) ports.ISessionRepository {
+	return &sessionRepository{
+		db: db,
+	}
+}
+
+func (s *sessionRepository) CreateSession(ctx context.Context, session *domain.Session) (*domain.Session, error) {
+	createdSession := &schema.Session{
+		UserID:     session.UserID,
+		Key:        session.Key,
+		RefreshKey: session.RefreshKey,
+		UserAgent:  session.UserAgent,
+		IP:         session.IP,
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+	}
+
+	err := s.db.WithContext(ctx).Omit("User").Create(createdSession).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return nil, domain.ErrDataNotFound
+		}
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return convertToSession(createdSession), nil
+}
+
+// activeSessions return a query of unexpired sessions whose user has not been deleted
+func (s *sessionRepository) activeSessions(ctx context.Context) *gorm.DB {
+	return s.db.WithContext(ctx).Model(&schema.Session{}).
+		Joins("JOIN users ON users.id = sessions.user_id AND users.deleted_at IS NULL").
+		Where("sessions.expires_at > ?", time.Now())
+}
+
+func (s *sessionRepository) GetSessionByKey(ctx context.Context, key string) (*domain.Session, error) {
+	session := &schema.Session{}
+
+	err := s.activeSessions(ctx).Where("sessions.`key` = ?", key).First(session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return convertToSession(session), nil
+}
+
+func (s *sessionRepository) GetSessionsByUserID(ctx context.Context, userID int) ([]domain.Session, error) {
+	sessions := []schema.Session{}
+
+	err := s.activeSessions(ctx).
+		Where("sessions.user_id = ?", userID).
+		Order("sessions.last_seen_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Session, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, *convertToSession(&session))
+	}
+
+	return result, nil
+}
+
+func (s *sessionRepository) UpdateLastSeen(ctx context.Context, id int, lastSeenAt time.Time) error {
+	result := s.db.WithContext(ctx).Model(&schema.Session{}).
+		Where("id = ?", id).
+		Update("last_seen_at", lastSeenAt)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrNoUpdatedData
+	}
+
+	return nil
+}
+
+func (s *sessionRepository) SwapRefreshKey(ctx context.Context, id int, oldRefreshKey, newRefreshKey string, expiresAt time.Time) error {
+	result := s.db.WithContext(ctx).Model(&schema.Session{}).
+		Where("id = ? AND refresh_key = ?", id, oldRefreshKey).
+		Updates(map[string]any{
+			"refresh_key":  newRefreshKey,
+			"last_seen_at": time.Now(),
+			"expires_at":   expiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrNoUpdatedData
+	}
+
+	return nil
+}
+
+func (s *sessionRepository) DeleteSession(ctx context.Context, userID, id int) error {
+	result := s.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&schema.Session{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (s *sessionRepository) DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).Where("expires_at < ?", before).Delete(&schema.Session{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
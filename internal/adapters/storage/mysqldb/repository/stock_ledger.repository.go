@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb/schema"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// addStock is a helper to add (or subtract with a negative sign) invoice items to the stock balances of a warehouse,
+// it must be called inside the transaction that writes the invoice
+func addStock(tx *gorm.DB, warehouseID int, items []domain.InvoiceItem, sign int) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	balances := make([]schema.StockBalance, 0, len(items))
+	for _, item := range items {
+		balances = append(balances, schema.StockBalance{
+			WarehouseID: warehouseID,
+			RiceID:      item.RiceID,
+			Quantity:    sign * item.Quantity,
+		})
+	}
+
+	return tx.Omit("Warehouse", "Rice").Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"quantity":   gorm.Expr("quantity + VALUES(quantity)"),
+			"updated_at": gorm.Expr("VALUES(updated_at)"),
+		}),
+	}).Create(&balances).Error
+}
+
+// stockOf return the stock of a rice in a warehouse and lock it until the transaction ends,
+// it must be called inside a transaction
+func stockOf(tx *gorm.DB, warehouseID, riceID int) (int, error) {
+	var quantity int
+	err := tx.Model(&schema.StockBalance{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("quantity").Where("warehouse_id = ? AND rice_id = ?", warehouseID, riceID).
+		Scan(&quantity).Error
+	if err != nil {
+		return 0, err
+	}
+	return quantity, nil
+}
+
+type stockLedgerRepository struct {
+	db *mysqldb.MysqlDB
+}
+
+func NewStockLedgerRepository(db *mysqldb.MysqlDB) ports.IStockLedgerRepository {
+	return &stockLedgerRepository{
+		db: db,
+	}
+}
+
+type stockKey struct {
+	warehouseID int
+	riceID      int
+}
+
+func (s *stockLedgerRepository) BackfillStockBalances(ctx context.Context) ([]domain.StockDrift, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&schema.StockBalance{}).Count(&count).Error
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return []domain.StockDrift{}, nil
+	}
+
+	return s.ReconcileStockBalances(ctx, true)
+}
+
+func (s *stockLedgerRepository) ReconcileStockBalances(ctx context.Context, apply bool) ([]domain.StockDrift, error) {
+	drifts := []domain.StockDrift{}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ledger := []schema.StockBalance{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Find(&ledger).Error
+		if err != nil {
+			return err
+		}
+
+		expected := []schema.StockBalance{}
+		err = tx.Raw(`SELECT t.warehouse_id, t.rice_id, SUM(t.quantity) AS quantity
+			FROM
+				(SELECT import_invoices.warehouse_id, import_invoice_details.rice_id, import_invoice_details.quantity
+				FROM import_invoices INNER JOIN import_invoice_details ON import_invoice_details.invoice_id = import_invoices.id
+				WHERE import_invoices.status = @status
+				UNION ALL
+				SELECT export_invoices.warehouse_id, export_invoice_details.rice_id, -export_invoice_details.quantity
+				FROM export_invoices INNER JOIN export_invoice_details ON export_invoice_details.invoice_id = export_invoices.id
+				WHERE export_invoices.status = @status) t
+			GROUP BY t.warehouse_id, t.rice_id`, sql.Named("status", domain.InvoiceCompleted)).Scan(&expected).Error
+		if err != nil {
+			return err
+		}
+
+		balances := make(map[stockKey]*domain.StockDrift)
+		for _, v := range ledger {
+			balances[stockKey{v.WarehouseID, v.RiceID}] = &domain.StockDrift{
+				WarehouseID:    v.WarehouseID,
+				RiceID:         v.RiceID,
+				LedgerQuantity: v.Quantity,
+			}
+		}
+		for _, v := range expected {
+			key := stockKey{v.WarehouseID, v.RiceID}
+			if _, ok := balances[key]; !ok {
+				balances[key] = &domain.StockDrift{WarehouseID: v.WarehouseID, RiceID: v.RiceID}
+			}
+			balances[key].ExpectedQuantity = v.Quantity
+		}
+
+		for _, v := range balances {
+			if v.LedgerQuantity != v.ExpectedQuantity {
+				drifts = append(drifts, *v)
+			}
+		}
+
+		if !apply || len(drifts) == 0 {
+			return nil
+		}
+
+		for _, v := range drifts {
+			err := tx.Omit("Warehouse", "Rice").Clauses(clause.OnConflict{
+				DoUpdates: clause.AssignmentColumns([]string{"quantity", "updated_at"}),
+			}).Create(&schema.StockBalance{
+				WarehouseID: v.WarehouseID,
+				RiceID:      v.RiceID,
+				Quantity:    v.ExpectedQuantity,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].WarehouseID != drifts[j].WarehouseID {
+			return drifts[i].WarehouseID < drifts[j].WarehouseID
+		}
+		return drifts[i].RiceID < drifts[j].RiceID
+	})
+
+	return drifts, nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/config"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+func NewDefaultStockLedgerRepo() (ports.IStockLedgerRepository, error) {
+	db, err := mysqldb.NewMysqlDB(config.DB{
+		DSN:             "root:@tcp(127.0.0.1:3306)/ql?charset=utf8mb4&parseTime=True&loc=Local",
+		MaxIdleConns:    10,
+		MaxOpenConns:    100,
+		ConnMaxLifetime: time.Hour,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStockLedgerRepository(db), nil
+}
+
+func TestStockLedger_Reconcile(t *testing.T) {
+	repo, err := NewDefaultStockLedgerRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.ReconcileStockBalances(context.TODO(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	drifts, err := repo.ReconcileStockBalances(context.TODO(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(drifts) != 0 {
+		t.Fatalf("expected no drift after rebuild, got %+v", drifts)
+	}
+}
+
+func TestStockLedger_Backfill(t *testing.T) {
+	repo, err := NewDefaultStockLedgerRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := repo.BackfillStockBalances(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("%+v\n", written)
+}
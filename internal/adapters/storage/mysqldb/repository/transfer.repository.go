@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb/schema"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+type transferRepository struct {
+	db     *mysqldb.MysqlDB
+	exRepo *exportInvoiceRepository
+	imRepo *importInvoiceRepository
+}
+
+func NewTransferRepository(db *mysqldb.MysqlDB) ports.ITransferRepository {
+	return &transferRepository{
+		db:     db,
+		exRepo: &exportInvoiceRepository{db: db},
+		imRepo: &importInvoiceRepository{db: db},
+	}
+}
+
+func (t *transferRepository) CreateTransfer(ctx context.Context, transfer *domain.Transfer) (*domain.Transfer, error) {
+	exData := convertToExportInvoiceSchema(transfer.ExportInvoice)
+	imData := convertToImportInvoiceSchema(transfer.ImportInvoice)
+
+	createData := &schema.Transfer{
+		FromWarehouseID: transfer.FromWarehouseID,
+		ToWarehouseID:   transfer.ToWarehouseID,
+		UserID:          transfer.UserID,
+	}
+
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(exData).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Create(imData).Error
+		if err != nil {
+			return err
+		}
+
+		err = consumeLots(tx, transfer.FromWarehouseID, exData.ID, transfer.ExportInvoice.Details)
+		if err != nil {
+			return err
+		}
+
+		err = moveLots(tx, transfer.ToWarehouseID, imData.ID, transfer.ExportInvoice.Details)
+		if err != nil {
+			return err
+		}
+
+		err = addStock(tx, transfer.FromWarehouseID, transfer.ExportInvoice.Details, -1)
+		if err != nil {
+			return err
+		}
+
+		err = addStock(tx, transfer.ToWarehouseID, transfer.ImportInvoice.Details, 1)
+		if err != nil {
+			return err
+		}
+
+		createData.ExportInvoiceID = exData.ID
+		createData.ImportInvoiceID = imData.ID
+
+		return tx.Omit("FromWarehouse", "ToWarehouse", "User", "ExportInvoice", "ImportInvoice").
+			Create(createData).Error
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrForeignKeyViolated):
+			return nil, domain.ErrDataNotFound
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, domain.ErrDataNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return t.GetTransferByID(ctx, createData.ID)
+}
+
+func (t *transferRepository) GetTransferByID(ctx context.Context, id int) (*domain.Transfer, error) {
+	data := &schema.Transfer{}
+
+	err := t.db.WithContext(ctx).Where("id = ?", id).First(data).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	exInvoice, err := t.exRepo.GetExInvoiceWithAssociationsByID(ctx, data.ExportInvoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	imInvoice, err := t.imRepo.GetImInvoiceWithAssociationsByID(ctx, data.ImportInvoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Transfer{
+		ID:              data.ID,
+		FromWarehouseID: data.FromWarehouseID,
+		ToWarehouseID:   data.ToWarehouseID,
+		UserID:          data.UserID,
+		ExportInvoiceID: data.ExportInvoiceID,
+		ImportInvoiceID: data.ImportInvoiceID,
+		CreatedAt:       data.CreatedAt,
+		Details:         exInvoice.Details,
+		ExportInvoice:   exInvoice,
+		ImportInvoice:   imInvoice,
+	}, nil
+}
+
+// cancelTransferLeg mark a leg of a transfer as cancelled, domain.ErrTransferReversed when it is not completed.
+// It must be called inside the transaction that reverses the transfer
+func cancelTransferLeg(tx *gorm.DB, model any, id int, reason string) error {
+	result := tx.Model(model).
+		Where("id = ? AND status = ?", id, domain.InvoiceCompleted).
+		Updates(map[string]any{
+			"status":        domain.InvoiceCancelled,
+			"cancel_reason": reason,
+			"cancelled_at":  time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrTransferReversed
+	}
+	return nil
+}
+
+func (t *transferRepository) ReverseTransfer(ctx context.Context, id int, reason string) (*domain.Transfer, error) {
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		data := &schema.Transfer{}
+		err := tx.Preload("ExportInvoice.Details").Where("id = ?", id).First(data).Error
+		if err != nil {
+			return err
+		}
+
+		// both legs move the same lines
+		items := make([]domain.InvoiceItem, 0, len(data.ExportInvoice.Details))
+		for _, detail := range data.ExportInvoice.Details {
+			items = append(items, domain.InvoiceItem{RiceID: detail.RiceID, Quantity: detail.Quantity})
+		}
+
+		// the rice leaves the destination first, it fails when the moved lots have been exported from there
+		err = cancelTransferLeg(tx, &schema.ImportInvoice{}, data.ImportInvoiceID, reason)
+		if err != nil {
+			return err
+		}
+
+		err = removeLots(tx, data.ImportInvoiceID)
+		if err != nil {
+			return err
+		}
+
+		err = addStock(tx, data.ToWarehouseID, items, -1)
+		if err != nil {
+			return err
+		}
+
+		err = cancelTransferLeg(tx, &schema.ExportInvoice{}, data.ExportInvoiceID, reason)
+		if err != nil {
+			return err
+		}
+
+		err = restoreLots(tx, data.ExportInvoiceID)
+		if err != nil {
+			return err
+		}
+
+		return addStock(tx, data.FromWarehouseID, items, 1)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, domain.ErrDataNotFound
+		case errors.Is(err, domain.ErrTransferReversed):
+			return nil, domain.ErrTransferReversed
+		case errors.Is(err, domain.ErrLotConsumed):
+			return nil, domain.ErrLotConsumed
+		default:
+			return nil, err
+		}
+	}
+
+	return t.GetTransferByID(ctx, id)
+}
@@ -118,6 +118,75 @@ func (ur *userRepository) GetListUsers(ctx context.Context, query string, limit,
 	return users, nil
 }
 
+func (ur *userRepository) GetListUsersAfter(ctx context.Context, query string, afterID, limit int) ([]domain.User, error) {
+	users := []domain.User{}
+
+	q := ur.db.WithContext(ctx).Table("users").
+		Select("id", "name", "email", "phone", "role").
+		Where("id > ? AND deleted_at is NULL", afterID).
+		Limit(limit).Order("id")
+
+	trimQuery := strings.TrimSpace(query)
+	if trimQuery != "" {
+		q.Where("name LIKE ?", fmt.Sprintf("%%%v%%", trimQuery))
+	}
+
+	err := q.Scan(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	return users, nil
+}
+
+func (ur *userRepository) CountAuthorizedUsers(ctx context.Context, warehouseID int, query string) (int64, error) {
+	var count int64
+
+	q := ur.db.WithContext(ctx).Table("users").
+		Joins("INNER JOIN authorized on authorized.user_id = users.id").
+		Where("authorized.warehouse_id = ? AND deleted_at is NULL", warehouseID)
+
+	trimQuery := strings.TrimSpace(query)
+	if trimQuery != "" {
+		q.Where("name LIKE ?", fmt.Sprintf("%%%v%%", trimQuery))
+	}
+
+	err := q.Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (ur *userRepository) GetAuthorizedUsers(ctx context.Context, warehouseID int, query string, limit, skip int) ([]domain.User, error) {
+	users := []domain.User{}
+
+	q := ur.db.WithContext(ctx).Table("users").
+		Select("users.id", "users.name", "users.email", "users.phone", "users.role").
+		Joins("INNER JOIN authorized on authorized.user_id = users.id").
+		Where("authorized.warehouse_id = ? AND deleted_at is NULL", warehouseID).
+		Limit(limit).Offset((skip - 1) * limit).Order("users.id desc")
+
+	trimQuery := strings.TrimSpace(query)
+	if trimQuery != "" {
+		q.Where("name LIKE ?", fmt.Sprintf("%%%v%%", trimQuery))
+	}
+
+	err := q.Scan(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	return users, nil
+}
+
 func (ur *userRepository) UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
 	updateData := &schema.User{
 		ID:       user.ID,
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -135,3 +136,49 @@ func TestUserRepo_Delete(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestUserRepo_Roles(t *testing.T) {
+	repo, err := NewDefaultUserRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roles := []domain.Role{domain.Root, domain.Member, domain.WarehouseManager, domain.Accountant, domain.Viewer}
+	suffix := time.Now().UnixNano()
+
+	for i, role := range roles {
+		user, err := repo.CreateUser(context.TODO(), &domain.User{
+			Name:     "role test",
+			Phone:    "+84123456789",
+			Email:    fmt.Sprintf("%s-%d@mail.com", role, suffix),
+			Password: "12345678",
+			Role:     role,
+		})
+		if err != nil {
+			t.Fatal(role, err)
+		}
+
+		saved, err := repo.GetUserByID(context.TODO(), user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if saved.Role != role {
+			t.Fatalf("role %s is read back as %s", role, saved.Role)
+		}
+
+		// every role is also saved by an update
+		next := roles[(i+1)%len(roles)]
+		updated, err := repo.UpdateUser(context.TODO(), &domain.User{ID: user.ID, Role: next})
+		if err != nil {
+			t.Fatal(next, err)
+		}
+		if updated.Role != next {
+			t.Fatalf("role %s is read back as %s", next, updated.Role)
+		}
+
+		err = repo.DeleteUser(context.TODO(), user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
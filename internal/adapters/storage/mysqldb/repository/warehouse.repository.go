@@ -118,6 +118,34 @@ func (w *warehouseRepository) GetListWarehouses(ctx context.Context, query strin
 	return stores, nil
 }
 
+func (w *warehouseRepository) GetListWarehousesAfter(ctx context.Context, query string, afterID, limit int) ([]domain.Warehouse, error) {
+	list := []schema.Warehouse{}
+
+	q := w.db.WithContext(ctx).Where("id > ? AND deleted_at is NULL", afterID).
+		Limit(limit).Order("id")
+
+	trimQuery := strings.TrimSpace(query)
+	if trimQuery != "" {
+		q.Where("name LIKE ?", fmt.Sprintf("%%%v%%", trimQuery))
+	}
+
+	err := q.Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	warehouses := make([]domain.Warehouse, 0, len(list))
+	for _, v := range list {
+		warehouses = append(warehouses, *convertToWarehouse(&v))
+	}
+
+	return warehouses, nil
+}
+
 func (w *warehouseRepository) CountAuthorizedWarehouses(ctx context.Context, userID int, query string) (int64, error) {
 	var count int64
 	var err error
@@ -169,6 +197,35 @@ func (w *warehouseRepository) GetAuthorizedWarehouses(ctx context.Context, userI
 	return warehouse, nil
 }
 
+func (w *warehouseRepository) GetAuthorizedWarehousesAfter(ctx context.Context, userID int, query string, afterID, limit int) ([]domain.Warehouse, error) {
+	list := []schema.Warehouse{}
+
+	q := w.db.WithContext(ctx).Joins("LEFT JOIN authorized on authorized.warehouse_id = warehouses.id").
+		Where("authorized.user_id = ? AND warehouses.id > ? AND deleted_at is NULL", userID, afterID).
+		Limit(limit).Order("warehouses.id")
+
+	trimQuery := strings.TrimSpace(query)
+	if trimQuery != "" {
+		q.Where("name LIKE ?", fmt.Sprintf("%%%v%%", trimQuery))
+	}
+
+	err := q.Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	warehouses := make([]domain.Warehouse, 0, len(list))
+	for _, v := range list {
+		warehouses = append(warehouses, *convertToWarehouse(&v))
+	}
+
+	return warehouses, nil
+}
+
 func (w *warehouseRepository) GetUsedCapacityByID(ctx context.Context, id int) (int64, error) {
 	err := w.db.WithContext(ctx).First(&schema.Warehouse{ID: id}).Error
 	if err != nil {
@@ -182,21 +239,25 @@ func (w *warehouseRepository) GetUsedCapacityByID(ctx context.Context, id int) (
 		Total int64
 	}{}
 
-	err = w.db.WithContext(ctx).
-		Raw(`SELECT (SUM(IF(t.type = "i", t.total, 0)) - SUM(IF(t.type = "e", t.total, 0))) as "total"
-			FROM 
-				(SELECT SUM( export_invoice_details.quantity) as total, "e" as type
-    			FROM export_invoices INNER JOIN export_invoice_details on export_invoices.id = export_invoice_details.invoice_id 
-    			WHERE export_invoices.warehouse_id = @id 
-    			UNION ALL
-    			SELECT SUM( import_invoice_details.quantity) as total, "i" as type 
-    			FROM import_invoices INNER JOIN import_invoice_details on import_invoices.id = import_invoice_details.invoice_id 
-    			WHERE import_invoices.warehouse_id = @id) as t`, sql.Named("id", id)).Scan(&total).Error
+	err = w.db.WithContext(ctx).Model(&schema.StockBalance{}).
+		Select("COALESCE(SUM(quantity), 0) as total").
+		Where("warehouse_id = ?", id).Scan(&total).Error
 	if err != nil {
 		return 0, err
 	}
 
-	return total.Total, nil
+	reserved := struct {
+		Total int64
+	}{}
+
+	// confirmed purchase orders hold the capacity of the goods they are still to bring in
+	err = reservations(w.db.WithContext(ctx), id, domain.PurchaseOrder).
+		Select("COALESCE(SUM(order_details.quantity), 0) as total").Scan(&reserved).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return total.Total + reserved.Total, nil
 }
 
 func (w *warehouseRepository) GetInventory(ctx context.Context, id int) ([]domain.WarehouseItem, error) {
@@ -208,25 +269,16 @@ func (w *warehouseRepository) GetInventory(ctx context.Context, id int) ([]domai
 		return nil, err
 	}
 
-	rows, err := w.db.Raw(`SELECT rice.id, rice.name, (t.total_import - t.total_export) as total
-		FROM 
-			(SELECT 
-    			COALESCE(im.rice_id, ex.rice_id) AS rice_id,
-    			COALESCE(im.total_im, 0) AS total_import,
-    			COALESCE(ex.total_ex, 0) AS total_export
-			FROM 
-    			(SELECT import_invoice_details.rice_id AS rice_id, SUM(import_invoice_details.quantity) as total_im
-				FROM import_invoices LEFT JOIN import_invoice_details on import_invoice_details.invoice_id = import_invoices.id 
-				WHERE import_invoices.warehouse_id = @id
-				GROUP BY import_invoice_details.rice_id) im
-			LEFT JOIN 
-    			(SELECT export_invoice_details.rice_id AS rice_id, SUM(export_invoice_details.quantity) as total_ex
-				FROM export_invoices LEFT JOIN export_invoice_details on export_invoice_details.invoice_id = export_invoices.id 
-				WHERE export_invoices.warehouse_id = @id
-				GROUP BY export_invoice_details.rice_id) ex 
-				ON im.rice_id = ex.rice_id) t JOIN rice on t.rice_id = rice.id
-  		WHERE (t.total_import - t.total_export) > 0
-		ORDER BY rice.id DESC`, sql.Named("id", id)).Rows()
+	reserved := reservations(w.db.WithContext(ctx), id, domain.SalesOrder).
+		Select("order_details.rice_id, SUM(order_details.quantity) as quantity").
+		Group("order_details.rice_id")
+
+	rows, err := w.db.WithContext(ctx).Table("stock_balances").
+		Select("rice.id, rice.name, stock_balances.quantity, COALESCE(reserved.quantity, 0)").
+		Joins("INNER JOIN rice on rice.id = stock_balances.rice_id").
+		Joins("LEFT JOIN (?) AS reserved ON reserved.rice_id = stock_balances.rice_id", reserved).
+		Where("stock_balances.warehouse_id = ? AND stock_balances.quantity > 0", id).
+		Order("rice.id DESC").Rows()
 	if err != nil {
 		return nil, err
 	}
@@ -240,6 +292,7 @@ func (w *warehouseRepository) GetInventory(ctx context.Context, id int) ([]domai
 			&item.RiceID,
 			&item.Rice.Name,
 			&item.Quantity,
+			&item.Reserved,
 		)
 		if err != nil {
 			return nil, err
@@ -253,6 +306,32 @@ func (w *warehouseRepository) GetInventory(ctx context.Context, id int) ([]domai
 	return result, nil
 }
 
+func (w *warehouseRepository) GetInventoryByLot(ctx context.Context, id int) ([]domain.Lot, error) {
+	err := w.db.WithContext(ctx).First(&schema.Warehouse{ID: id}).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	data := []schema.Lot{}
+	err = w.db.WithContext(ctx).Preload("Rice", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Where("warehouse_id = ? AND remaining > 0", id).Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	lots := make([]domain.Lot, 0, len(data))
+	for _, v := range data {
+		lots = append(lots, *convertToLot(&v))
+	}
+	domain.SortFEFO(lots)
+
+	return lots, nil
+}
+
 func (w *warehouseRepository) UpdateWarehouse(ctx context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error) {
 	result := w.db.WithContext(ctx).
 		Model(&schema.Warehouse{}).Where("id = ?", warehouse.ID).
@@ -141,3 +141,17 @@ func TestWarehouseRepo_GetInventory(t *testing.T) {
 
 	t.Logf("%+v\n", data)
 }
+
+func TestWarehouseRepo_GetInventoryByLot(t *testing.T) {
+	repo, err := NewDefaultWarehouseRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := repo.GetInventoryByLot(context.TODO(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("%+v\n", data)
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb/schema"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+// implement ports.IWebhookRepository
+type webhookRepository struct {
+	db *mysqldb.MysqlDB
+}
+
+func NewWebhookRepository(db *mysqldb.MysqlDB) ports.IWebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (w *webhookRepository) CreateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	data := &schema.Webhook{
+		URL:    webhook.URL,
+		Secret: webhook.Secret,
+		Events: joinWebhookEvents(webhook.Events),
+		Active: webhook.Active,
+	}
+
+	err := w.db.WithContext(ctx).Create(data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToWebhook(data), nil
+}
+
+func (w *webhookRepository) GetListWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	data := []schema.Webhook{}
+
+	err := w.db.WithContext(ctx).Order("id").Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := make([]domain.Webhook, 0, len(data))
+	for _, v := range data {
+		webhooks = append(webhooks, *convertToWebhook(&v))
+	}
+
+	return webhooks, nil
+}
+
+func (w *webhookRepository) GetWebhookByID(ctx context.Context, id int) (*domain.Webhook, error) {
+	data := &schema.Webhook{}
+
+	err := w.db.WithContext(ctx).Where("id = ?", id).First(data).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return convertToWebhook(data), nil
+}
+
+func (w *webhookRepository) UpdateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	result := w.db.WithContext(ctx).Model(&schema.Webhook{}).Where("id = ?", webhook.ID).
+		Select("url", "events", "active", "updated_at").
+		Updates(&schema.Webhook{
+			URL:    webhook.URL,
+			Events: joinWebhookEvents(webhook.Events),
+			Active: webhook.Active,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return w.GetWebhookByID(ctx, webhook.ID)
+}
+
+func (w *webhookRepository) DeleteWebhook(ctx context.Context, id int) error {
+	result := w.db.WithContext(ctx).Where("id = ?", id).Delete(&schema.Webhook{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (w *webhookRepository) CreateOutboxMessages(ctx context.Context, msgs []domain.OutboxMessage) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	data := make([]schema.WebhookOutbox, 0, len(msgs))
+	for _, v := range msgs {
+		data = append(data, schema.WebhookOutbox{
+			WebhookID:     v.WebhookID,
+			EventID:       v.EventID,
+			Event:         v.Event,
+			Payload:       v.Payload,
+			Status:        v.Status,
+			NextAttemptAt: v.NextAttemptAt,
+		})
+	}
+
+	return w.db.WithContext(ctx).Omit("Webhook").Create(&data).Error
+}
+
+func (w *webhookRepository) GetDueMessages(ctx context.Context, now time.Time, limit int) ([]domain.OutboxMessage, error) {
+	data := []schema.WebhookOutbox{}
+
+	// messages of inactive webhooks wait until the webhook is active again
+	err := w.db.WithContext(ctx).Preload("Webhook").
+		Joins("JOIN webhooks ON webhooks.id = webhook_outbox.webhook_id AND webhooks.active = ?", true).
+		Where("webhook_outbox.status = ? AND webhook_outbox.next_attempt_at <= ?", domain.OutboxPending, now).
+		Order("webhook_outbox.next_attempt_at, webhook_outbox.id").Limit(limit).Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]domain.OutboxMessage, 0, len(data))
+	for _, v := range data {
+		msgs = append(msgs, *convertToOutboxMessage(&v))
+	}
+
+	return msgs, nil
+}
+
+func (w *webhookRepository) UpdateOutboxMessage(ctx context.Context, msg *domain.OutboxMessage) error {
+	return w.db.WithContext(ctx).Model(&schema.WebhookOutbox{}).Where("id = ?", msg.ID).
+		Updates(map[string]any{
+			"status":          msg.Status,
+			"attempts":        msg.Attempts,
+			"next_attempt_at": msg.NextAttemptAt,
+			"last_error":      msg.LastError,
+			"delivered_at":    msg.DeliveredAt,
+		}).Error
+}
+
+// filterOutbox add the conditions of the filter to q
+func filterOutbox(q *gorm.DB, filter domain.OutboxFilter) *gorm.DB {
+	if filter.WebhookID != 0 {
+		q = q.Where("webhook_id = ?", filter.WebhookID)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	return q
+}
+
+func (w *webhookRepository) CountOutboxMessages(ctx context.Context, filter domain.OutboxFilter) (int64, error) {
+	var count int64
+
+	err := filterOutbox(w.db.WithContext(ctx).Model(&schema.WebhookOutbox{}), filter).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (w *webhookRepository) GetListOutboxMessages(ctx context.Context, filter domain.OutboxFilter, limit, skip int) ([]domain.OutboxMessage, error) {
+	data := []schema.WebhookOutbox{}
+
+	q := w.db.WithContext(ctx).Model(&schema.WebhookOutbox{}).Limit(limit).Offset((skip - 1) * limit).Order("id DESC")
+	q = filterOutbox(q, filter)
+
+	err := q.Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	msgs := make([]domain.OutboxMessage, 0, len(data))
+	for _, v := range data {
+		msgs = append(msgs, *convertToOutboxMessage(&v))
+	}
+
+	return msgs, nil
+}
+
+func (w *webhookRepository) GetOutboxMessageByID(ctx context.Context, id int) (*domain.OutboxMessage, error) {
+	data := &schema.WebhookOutbox{}
+
+	err := w.db.WithContext(ctx).Where("id = ?", id).First(data).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return convertToOutboxMessage(data), nil
+}
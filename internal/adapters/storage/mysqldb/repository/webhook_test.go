@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/config"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+func NewDefaultWebhookRepo() (ports.IWebhookRepository, error) {
+	db, err := mysqldb.NewMysqlDB(config.DB{
+		DSN:             "root:@tcp(127.0.0.1:3306)/ql?charset=utf8mb4&parseTime=True&loc=Local",
+		MaxIdleConns:    10,
+		MaxOpenConns:    100,
+		ConnMaxLifetime: time.Hour,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWebhookRepository(db), nil
+}
+
+func TestWebhookRepo_Outbox(t *testing.T) {
+	repo, err := NewDefaultWebhookRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hook, err := repo.CreateWebhook(context.TODO(), &domain.Webhook{
+		URL:    "http://127.0.0.1:9000/hooks",
+		Secret: "secret",
+		Events: []domain.WebhookEvent{domain.WebhookStockChanged, domain.WebhookRiceCreated},
+		Active: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.DeleteWebhook(context.TODO(), hook.ID)
+
+	err = repo.CreateOutboxMessages(context.TODO(), []domain.OutboxMessage{{
+		WebhookID:     hook.ID,
+		EventID:       "00000000-0000-0000-0000-000000000001",
+		Event:         domain.WebhookStockChanged,
+		Payload:       []byte(`{}`),
+		Status:        domain.OutboxPending,
+		NextAttemptAt: time.Now(),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, err := repo.GetDueMessages(context.TODO(), time.Now(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) == 0 || msgs[0].Webhook == nil {
+		t.Fatal("due message not found")
+	}
+
+	msg := msgs[0]
+	msg.Status = domain.OutboxDead
+	msg.Attempts = 8
+	err = repo.UpdateOutboxMessage(context.TODO(), &msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dead, err := repo.GetListOutboxMessages(context.TODO(), domain.OutboxFilter{WebhookID: hook.ID, Status: domain.OutboxDead}, 5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(dead)
+}
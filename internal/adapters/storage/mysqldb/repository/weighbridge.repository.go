@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb/schema"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+type weighbridgeRepository struct {
+	db *mysqldb.MysqlDB
+}
+
+func NewWeighbridgeRepository(db *mysqldb.MysqlDB) ports.IWeighbridgeRepository {
+	return &weighbridgeRepository{
+		db: db,
+	}
+}
+
+func (w *weighbridgeRepository) GetPostedWeighings(ctx context.Context, warehouseID int, tickets []string) ([]domain.PostedWeighing, error) {
+	posted := []domain.PostedWeighing{}
+	if len(tickets) == 0 {
+		return posted, nil
+	}
+
+	references := make([]string, len(tickets))
+	for i, ticket := range tickets {
+		references[i] = (&domain.Weighing{Ticket: ticket}).Reference(warehouseID)
+	}
+
+	for _, v := range []struct {
+		kind  domain.InvoiceKind
+		model any
+	}{
+		{domain.InvoiceKindImport, &schema.ImportInvoice{}},
+		{domain.InvoiceKindExport, &schema.ExportInvoice{}},
+	} {
+		var rows []struct {
+			ID        int
+			Reference string
+			Status    domain.InvoiceStatus
+		}
+
+		err := w.db.WithContext(ctx).Model(v.model).Select("id, reference, status").
+			Where("warehouse_id = ? AND reference IN ?", warehouseID, references).Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			ticket, _ := domain.TicketOf(row.Reference)
+			posted = append(posted, domain.PostedWeighing{
+				Ticket:    ticket,
+				Kind:      v.kind,
+				InvoiceID: row.ID,
+				Status:    row.Status,
+			})
+		}
+	}
+
+	return posted, nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/adapters/storage/mysqldb"
+	"github.com/tommjj/ql-kho-lua/internal/config"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+func NewDefaultWeighbridgeRepo() (ports.IWeighbridgeRepository, error) {
+	db, err := mysqldb.NewMysqlDB(config.DB{
+		DSN:             "root:@tcp(127.0.0.1:3306)/ql?charset=utf8mb4&parseTime=True&loc=Local",
+		MaxIdleConns:    10,
+		MaxOpenConns:    100,
+		ConnMaxLifetime: time.Hour,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWeighbridgeRepository(db), nil
+}
+
+func TestWeighbridge_GetPostedWeighings(t *testing.T) {
+	repo, err := NewDefaultWeighbridgeRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	posted, err := repo.GetPostedWeighings(context.TODO(), 1, []string{"T-0001", "T-0002"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("%+v\n", posted)
+}
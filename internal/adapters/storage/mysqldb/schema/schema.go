@@ -1,7 +1,6 @@
 package schema
 
 import (
-	"database/sql"
 	"time"
 
 	"github.com/tommjj/ql-kho-lua/internal/core/domain"
@@ -13,15 +12,25 @@ type User struct {
 	Name                 string          `gorm:"type:VARCHAR(32);not null"`
 	Email                string          `gorm:"type:VARCHAR(320);uniqueIndex;not null"`
 	Phone                string          `gorm:"type:VARCHAR(16);not null"`
-	Role                 domain.Role     `gorm:"type:VARCHAR(10);not null;default:'member'"`
+	Role                 domain.Role     `gorm:"type:VARCHAR(20);not null;default:'member'"`
 	Password             string          `gorm:"type:VARCHAR(320);not null"`
-	Key                  sql.NullString  `gorm:"type:VARCHAR(320)"`
 	DeletedAt            gorm.DeletedAt  `gorm:"index"`
 	AuthorizedWarehouses []*Warehouse    `gorm:"many2many:authorized"`
 	ExportInvoices       []ExportInvoice `gorm:"foreignKey:UserID"`
 	ImportInvoices       []ImportInvoice `gorm:"foreignKey:UserID"`
 }
 
+// Authorized is the join table of users and the warehouses they are granted access to
+type Authorized struct {
+	UserID      int                `gorm:"primaryKey;autoIncrement:false"`
+	WarehouseID int                `gorm:"primaryKey;autoIncrement:false"`
+	AccessLevel domain.AccessLevel `gorm:"type:VARCHAR(20);not null;default:'full'"`
+}
+
+func (Authorized) TableName() string {
+	return "authorized"
+}
+
 type Warehouse struct {
 	ID              int             `gorm:"primaryKey;autoIncrement"`
 	Name            string          `gorm:"type:VARCHAR(255);uniqueIndex;not null"`
@@ -43,27 +52,37 @@ type Rice struct {
 }
 
 type Customer struct {
-	ID             int             `gorm:"primaryKey;autoIncrement"`
-	Name           string          `gorm:"type:VARCHAR(255);not null"`
-	Email          string          `gorm:"type:VARCHAR(320);not null"`
-	Phone          string          `gorm:"type:VARCHAR(16);not null"`
-	Address        string          `gorm:"type:VARCHAR(255);not null"`
-	DeletedAt      gorm.DeletedAt  `gorm:"index"`
-	ExportInvoices []ExportInvoice `gorm:"foreignKey:CustomerID"`
-	ImportInvoices []ImportInvoice `gorm:"foreignKey:CustomerID"`
+	ID             int                `gorm:"primaryKey;autoIncrement"`
+	Name           string             `gorm:"type:VARCHAR(255);not null"`
+	Email          string             `gorm:"type:VARCHAR(320);not null"`
+	Phone          string             `gorm:"type:VARCHAR(16);not null"`
+	Address        string             `gorm:"type:VARCHAR(255);not null"`
+	Role           domain.PartnerRole `gorm:"type:VARCHAR(10);not null;default:'both';index"`
+	TaxCode        string             `gorm:"type:VARCHAR(20);not null;default:''"`
+	DeletedAt      gorm.DeletedAt     `gorm:"index"`
+	ExportInvoices []ExportInvoice    `gorm:"foreignKey:CustomerID"`
+	ImportInvoices []ImportInvoice    `gorm:"foreignKey:CustomerID"`
 }
 
 type ExportInvoice struct {
-	ID          int                   `gorm:"primaryKey;autoIncrement"`
-	WarehouseID int                   `gorm:"not null;index"`
-	CustomerID  int                   `gorm:"not null"`
-	UserID      int                   `gorm:"not null"`
-	TotalPrice  float64               `gorm:"not null"`
-	CreatedAt   time.Time             ``
-	Warehouse   Warehouse             `gorm:"foreignKey:WarehouseID"`
-	Customer    Customer              `gorm:"foreignKey:CustomerID"`
-	User        User                  `gorm:"foreignKey:UserID"`
-	Details     []ExportInvoiceDetail `gorm:"foreignKey:InvoiceID"`
+	ID           int                   `gorm:"primaryKey;autoIncrement"`
+	WarehouseID  int                   `gorm:"not null;index"`
+	CustomerID   *int                  ``
+	UserID       int                   `gorm:"not null"`
+	TotalPrice   float64               `gorm:"not null"`
+	Status       domain.InvoiceStatus  `gorm:"type:VARCHAR(20);not null;default:'completed';index"`
+	CancelReason string                `gorm:"type:VARCHAR(255);not null;default:''"`
+	CancelledAt  *time.Time            ``
+	PaidAmount   float64               `gorm:"not null;default:0"`
+	ApprovedBy   *int                  ``
+	ApprovedAt   *time.Time            ``
+	Reference    *string               `gorm:"type:VARCHAR(100);uniqueIndex"`
+	CreatedAt    time.Time             ``
+	Warehouse    Warehouse             `gorm:"foreignKey:WarehouseID"`
+	Customer     Customer              `gorm:"foreignKey:CustomerID"`
+	User         User                  `gorm:"foreignKey:UserID"`
+	Approver     User                  `gorm:"foreignKey:ApprovedBy"`
+	Details      []ExportInvoiceDetail `gorm:"foreignKey:InvoiceID"`
 }
 
 type ExportInvoiceDetail struct {
@@ -75,16 +94,24 @@ type ExportInvoiceDetail struct {
 }
 
 type ImportInvoice struct {
-	ID          int                   `gorm:"primaryKey;autoIncrement"`
-	WarehouseID int                   `gorm:"not null;index"`
-	CustomerID  int                   `gorm:"not null"`
-	UserID      int                   `gorm:"not null"`
-	TotalPrice  float64               `gorm:"not null"`
-	CreatedAt   time.Time             ``
-	Warehouse   Warehouse             `gorm:"foreignKey:WarehouseID"`
-	Customer    Customer              `gorm:"foreignKey:CustomerID"`
-	User        User                  `gorm:"foreignKey:UserID"`
-	Details     []ImportInvoiceDetail `gorm:"foreignKey:InvoiceID"`
+	ID           int                   `gorm:"primaryKey;autoIncrement"`
+	WarehouseID  int                   `gorm:"not null;index"`
+	CustomerID   *int                  ``
+	UserID       int                   `gorm:"not null"`
+	TotalPrice   float64               `gorm:"not null"`
+	Status       domain.InvoiceStatus  `gorm:"type:VARCHAR(20);not null;default:'completed';index"`
+	CancelReason string                `gorm:"type:VARCHAR(255);not null;default:''"`
+	CancelledAt  *time.Time            ``
+	PaidAmount   float64               `gorm:"not null;default:0"`
+	ApprovedBy   *int                  ``
+	ApprovedAt   *time.Time            ``
+	Reference    *string               `gorm:"type:VARCHAR(100);uniqueIndex"`
+	CreatedAt    time.Time             ``
+	Warehouse    Warehouse             `gorm:"foreignKey:WarehouseID"`
+	Customer     Customer              `gorm:"foreignKey:CustomerID"`
+	User         User                  `gorm:"foreignKey:UserID"`
+	Approver     User                  `gorm:"foreignKey:ApprovedBy"`
+	Details      []ImportInvoiceDetail `gorm:"foreignKey:InvoiceID"`
 }
 
 type ImportInvoiceDetail struct {
@@ -94,3 +121,222 @@ type ImportInvoiceDetail struct {
 	Quantity  int     `gorm:"not null"`
 	Rice      Rice    `gorm:"foreignKey:RiceID"`
 }
+
+type Transfer struct {
+	ID              int           `gorm:"primaryKey;autoIncrement"`
+	FromWarehouseID int           `gorm:"not null;index"`
+	ToWarehouseID   int           `gorm:"not null;index"`
+	UserID          int           `gorm:"not null"`
+	ExportInvoiceID int           `gorm:"not null;uniqueIndex"`
+	ImportInvoiceID int           `gorm:"not null;uniqueIndex"`
+	CreatedAt       time.Time     ``
+	FromWarehouse   Warehouse     `gorm:"foreignKey:FromWarehouseID"`
+	ToWarehouse     Warehouse     `gorm:"foreignKey:ToWarehouseID"`
+	User            User          `gorm:"foreignKey:UserID"`
+	ExportInvoice   ExportInvoice `gorm:"foreignKey:ExportInvoiceID"`
+	ImportInvoice   ImportInvoice `gorm:"foreignKey:ImportInvoiceID"`
+}
+
+type Order struct {
+	ID          int                `gorm:"primaryKey;autoIncrement"`
+	Type        domain.OrderType   `gorm:"type:VARCHAR(10);not null;index:idx_order_reservation,priority:2"`
+	WarehouseID int                `gorm:"not null;index:idx_order_reservation,priority:1"`
+	CustomerID  int                `gorm:"not null"`
+	UserID      int                `gorm:"not null"`
+	Status      domain.OrderStatus `gorm:"type:VARCHAR(20);not null;default:'draft';index:idx_order_reservation,priority:3"`
+	TotalPrice  float64            `gorm:"not null"`
+	InvoiceID   *int               ``
+	CreatedAt   time.Time          ``
+	ConfirmedAt *time.Time         ``
+	ClosedAt    *time.Time         ``
+	Warehouse   Warehouse          `gorm:"foreignKey:WarehouseID"`
+	Customer    Customer           `gorm:"foreignKey:CustomerID"`
+	User        User               `gorm:"foreignKey:UserID"`
+	Details     []OrderDetail      `gorm:"foreignKey:OrderID"`
+}
+
+type OrderDetail struct {
+	OrderID  int     `gorm:"primaryKey"`
+	RiceID   int     `gorm:"primaryKey"`
+	Price    float64 `gorm:"not null"`
+	Quantity int     `gorm:"not null"`
+	Rice     Rice    `gorm:"foreignKey:RiceID"`
+}
+
+// Payment is a payment against an import or export invoice, the invoice is found by its kind and id
+type Payment struct {
+	ID          int                  `gorm:"primaryKey;autoIncrement"`
+	InvoiceKind domain.InvoiceKind   `gorm:"type:VARCHAR(10);not null;index:idx_payment_invoice,priority:1"`
+	InvoiceID   int                  `gorm:"not null;index:idx_payment_invoice,priority:2"`
+	CustomerID  int                  `gorm:"not null;index"`
+	UserID      int                  `gorm:"not null"`
+	Amount      float64              `gorm:"not null"`
+	Method      domain.PaymentMethod `gorm:"type:VARCHAR(20);not null"`
+	Reference   string               `gorm:"type:VARCHAR(100);not null;default:''"`
+	PaidAt      time.Time            `gorm:"not null;index"`
+	CreatedAt   time.Time            ``
+	Customer    Customer             `gorm:"foreignKey:CustomerID"`
+	User        User                 `gorm:"foreignKey:UserID"`
+}
+
+type StockBalance struct {
+	WarehouseID int       `gorm:"primaryKey;autoIncrement:false"`
+	RiceID      int       `gorm:"primaryKey;autoIncrement:false"`
+	Quantity    int       `gorm:"not null;default:0"`
+	UpdatedAt   time.Time ``
+	Warehouse   Warehouse `gorm:"foreignKey:WarehouseID"`
+	Rice        Rice      `gorm:"foreignKey:RiceID"`
+}
+
+type Lot struct {
+	ID              int           `gorm:"primaryKey;autoIncrement"`
+	WarehouseID     int           `gorm:"not null;index:idx_lot_stock,priority:1"`
+	RiceID          int           `gorm:"not null;index:idx_lot_stock,priority:2"`
+	ImportInvoiceID int           `gorm:"not null;index"`
+	LotNumber       string        `gorm:"type:VARCHAR(50);not null;index"`
+	HarvestDate     *time.Time    `gorm:"type:DATE"`
+	ExpiryDate      *time.Time    `gorm:"type:DATE"`
+	Grade           string        `gorm:"type:VARCHAR(20);not null;default:''"`
+	Moisture        float64       `gorm:"not null;default:0"`
+	Quantity        int           `gorm:"not null"`
+	Remaining       int           `gorm:"not null"`
+	CreatedAt       time.Time     ``
+	Warehouse       Warehouse     `gorm:"foreignKey:WarehouseID"`
+	Rice            Rice          `gorm:"foreignKey:RiceID"`
+	ImportInvoice   ImportInvoice `gorm:"foreignKey:ImportInvoiceID"`
+}
+
+type LotAllocation struct {
+	ExportInvoiceID int           `gorm:"primaryKey;autoIncrement:false"`
+	LotID           int           `gorm:"primaryKey;autoIncrement:false"`
+	RiceID          int           `gorm:"not null"`
+	Quantity        int           `gorm:"not null"`
+	ExportInvoice   ExportInvoice `gorm:"foreignKey:ExportInvoiceID"`
+	Lot             Lot           `gorm:"foreignKey:LotID"`
+}
+
+type Session struct {
+	ID         int       `gorm:"primaryKey;autoIncrement"`
+	UserID     int       `gorm:"not null;index"`
+	Key        string    `gorm:"type:VARCHAR(64);uniqueIndex;not null"`
+	RefreshKey string    `gorm:"type:VARCHAR(64);not null"`
+	UserAgent  string    `gorm:"type:VARCHAR(255);not null"`
+	IP         string    `gorm:"type:VARCHAR(45);not null"`
+	CreatedAt  time.Time ``
+	LastSeenAt time.Time `gorm:"not null"`
+	ExpiresAt  time.Time `gorm:"not null;index"`
+	User       User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+type AuditLog struct {
+	ID        int                `gorm:"primaryKey;autoIncrement"`
+	ActorID   *int               `gorm:"index"`
+	ActorName string             `gorm:"type:VARCHAR(32);not null"`
+	Action    domain.AuditAction `gorm:"type:VARCHAR(20);not null"`
+	Entity    domain.AuditEntity `gorm:"type:VARCHAR(30);not null;index:idx_audit_entity"`
+	EntityID  int                `gorm:"not null;index:idx_audit_entity"`
+	Before    []byte             `gorm:"type:JSON"`
+	After     []byte             `gorm:"type:JSON"`
+	IP        string             `gorm:"type:VARCHAR(45);not null"`
+	CreatedAt time.Time          `gorm:"index"`
+}
+
+type StockThreshold struct {
+	WarehouseID int       `gorm:"primaryKey;autoIncrement:false"`
+	RiceID      int       `gorm:"primaryKey;autoIncrement:false"`
+	MinQuantity int       `gorm:"not null"`
+	UpdatedAt   time.Time ``
+	Warehouse   Warehouse `gorm:"foreignKey:WarehouseID"`
+	Rice        Rice      `gorm:"foreignKey:RiceID"`
+}
+
+type Alert struct {
+	ID             int              `gorm:"primaryKey;autoIncrement"`
+	Type           domain.AlertType `gorm:"type:VARCHAR(20);not null;index"`
+	AlertKey       string           `gorm:"type:VARCHAR(100);not null;index"`
+	WarehouseID    int              `gorm:"not null;index"`
+	RiceID         *int             ``
+	LotID          *int             ``
+	Message        string           `gorm:"type:VARCHAR(255);not null"`
+	Value          int              `gorm:"not null"`
+	Threshold      int              `gorm:"not null"`
+	CreatedAt      time.Time        `gorm:"index"`
+	AcknowledgedAt *time.Time       `gorm:"index"`
+	AcknowledgedBy *int             ``
+	Warehouse      Warehouse        `gorm:"foreignKey:WarehouseID"`
+}
+
+type Subscription struct {
+	ID        int                        `gorm:"primaryKey;autoIncrement"`
+	UserID    int                        `gorm:"not null;uniqueIndex:idx_subscription"`
+	EventType domain.EventType           `gorm:"type:VARCHAR(50);not null;uniqueIndex:idx_subscription;index"`
+	Channel   domain.NotificationChannel `gorm:"type:VARCHAR(20);not null;uniqueIndex:idx_subscription"`
+	Target    string                     `gorm:"type:VARCHAR(255);not null;uniqueIndex:idx_subscription"`
+	CreatedAt time.Time                  ``
+	User      User                       `gorm:"foreignKey:UserID"`
+}
+
+type Notification struct {
+	ID          int              `gorm:"primaryKey;autoIncrement"`
+	UserID      int              `gorm:"not null;index"`
+	EventType   domain.EventType `gorm:"type:VARCHAR(50);not null"`
+	WarehouseID int              `gorm:"not null"`
+	Subject     string           `gorm:"type:VARCHAR(255);not null"`
+	Message     string           `gorm:"type:TEXT;not null"`
+	CreatedAt   time.Time        ``
+	ReadAt      *time.Time       ``
+	User        User             `gorm:"foreignKey:UserID"`
+}
+
+type NotificationDelivery struct {
+	ID             int                        `gorm:"primaryKey;autoIncrement"`
+	SubscriptionID int                        `gorm:"not null;index"`
+	EventType      domain.EventType           `gorm:"type:VARCHAR(50);not null"`
+	Channel        domain.NotificationChannel `gorm:"type:VARCHAR(20);not null"`
+	Target         string                     `gorm:"type:VARCHAR(320);not null"`
+	Attempts       int                        `gorm:"not null"`
+	Error          string                     `gorm:"type:TEXT"`
+	CreatedAt      time.Time                  `gorm:"index"`
+}
+
+type Webhook struct {
+	ID        int       `gorm:"primaryKey;autoIncrement"`
+	URL       string    `gorm:"type:VARCHAR(255);not null"`
+	Secret    string    `gorm:"type:VARCHAR(64);not null"`
+	Events    string    `gorm:"type:VARCHAR(1000);not null"`
+	Active    bool      `gorm:"not null;default:true"`
+	CreatedAt time.Time ``
+	UpdatedAt time.Time ``
+}
+
+type WebhookOutbox struct {
+	ID            int                 `gorm:"primaryKey;autoIncrement"`
+	WebhookID     int                 `gorm:"not null;index"`
+	EventID       string              `gorm:"type:CHAR(36);not null"`
+	Event         domain.WebhookEvent `gorm:"type:VARCHAR(50);not null"`
+	Payload       []byte              `gorm:"type:JSON;not null"`
+	Status        domain.OutboxStatus `gorm:"type:VARCHAR(10);not null;index:idx_outbox_due"`
+	Attempts      int                 `gorm:"not null;default:0"`
+	NextAttemptAt time.Time           `gorm:"not null;index:idx_outbox_due"`
+	LastError     string              `gorm:"type:TEXT"`
+	CreatedAt     time.Time           ``
+	DeliveredAt   *time.Time          ``
+	Webhook       Webhook             `gorm:"foreignKey:WebhookID;constraint:OnDelete:CASCADE"`
+}
+
+func (WebhookOutbox) TableName() string {
+	return "webhook_outbox"
+}
+
+type IdempotencyKey struct {
+	ID          int       `gorm:"primaryKey;autoIncrement"`
+	UserID      int       `gorm:"not null;uniqueIndex:idx_idempotency_user_key"`
+	Key         string    `gorm:"type:VARCHAR(255);not null;uniqueIndex:idx_idempotency_user_key"`
+	Fingerprint string    `gorm:"type:CHAR(64);not null"`
+	StatusCode  int       `gorm:"not null;default:0"`
+	Response    []byte    `gorm:"type:MEDIUMBLOB"`
+	Completed   bool      `gorm:"not null;default:false"`
+	CreatedAt   time.Time ``
+	ExpiresAt   time.Time `gorm:"not null;index"`
+	User        User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
@@ -34,15 +34,38 @@ func NewMysqlDB(conf config.DB) (*MysqlDB, error) {
 	mysql.SetMaxOpenConns(conf.MaxOpenConns)
 	mysql.SetConnMaxLifetime(conf.ConnMaxLifetime)
 
+	err = setupJoinTables(db)
+	if err != nil {
+		return nil, err
+	}
+
 	err = db.AutoMigrate(
 		&schema.User{},
 		&schema.Warehouse{},
+		&schema.Authorized{},
 		&schema.Customer{},
 		&schema.Rice{},
 		&schema.ExportInvoice{},
 		&schema.ExportInvoiceDetail{},
 		&schema.ImportInvoice{},
 		&schema.ImportInvoiceDetail{},
+		&schema.Transfer{},
+		&schema.StockBalance{},
+		&schema.Lot{},
+		&schema.LotAllocation{},
+		&schema.Session{},
+		&schema.AuditLog{},
+		&schema.StockThreshold{},
+		&schema.Alert{},
+		&schema.Subscription{},
+		&schema.Notification{},
+		&schema.NotificationDelivery{},
+		&schema.Webhook{},
+		&schema.WebhookOutbox{},
+		&schema.Order{},
+		&schema.OrderDetail{},
+		&schema.Payment{},
+		&schema.IdempotencyKey{},
 	)
 	if err != nil {
 		return nil, err
@@ -52,3 +75,13 @@ func NewMysqlDB(conf config.DB) (*MysqlDB, error) {
 		db,
 	}, nil
 }
+
+// setupJoinTables use custom models for many2many join tables that carry extra columns
+func setupJoinTables(db *gorm.DB) error {
+	err := db.SetupJoinTable(&schema.User{}, "AuthorizedWarehouses", &schema.Authorized{})
+	if err != nil {
+		return err
+	}
+
+	return db.SetupJoinTable(&schema.Warehouse{}, "AuthorizedUsers", &schema.Authorized{})
+}
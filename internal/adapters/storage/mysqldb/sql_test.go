@@ -69,6 +69,22 @@ func TestDown(t *testing.T) {
 
 	m := db.Migrator()
 	m.DropTable(
+		&schema.Payment{},
+		&schema.OrderDetail{},
+		&schema.Order{},
+		&schema.WebhookOutbox{},
+		&schema.Webhook{},
+		&schema.NotificationDelivery{},
+		&schema.Notification{},
+		&schema.Subscription{},
+		&schema.Alert{},
+		&schema.StockThreshold{},
+		&schema.LotAllocation{},
+		&schema.Lot{},
+		&schema.StockBalance{},
+		&schema.Session{},
+		&schema.AuditLog{},
+		&schema.Transfer{},
 		&schema.ExportInvoiceDetail{},
 		&schema.ExportInvoice{},
 		&schema.ImportInvoiceDetail{},
@@ -97,11 +113,28 @@ func TestUp(t *testing.T) {
 	m.AutoMigrate(
 		&schema.User{},
 		&schema.Warehouse{},
+		&schema.Authorized{},
 		&schema.Customer{},
 		&schema.Rice{},
 		&schema.ExportInvoice{},
 		&schema.ExportInvoiceDetail{},
 		&schema.ImportInvoice{},
 		&schema.ImportInvoiceDetail{},
+		&schema.Transfer{},
+		&schema.StockBalance{},
+		&schema.Lot{},
+		&schema.LotAllocation{},
+		&schema.Session{},
+		&schema.AuditLog{},
+		&schema.StockThreshold{},
+		&schema.Alert{},
+		&schema.Subscription{},
+		&schema.Notification{},
+		&schema.NotificationDelivery{},
+		&schema.Webhook{},
+		&schema.WebhookOutbox{},
+		&schema.Order{},
+		&schema.OrderDetail{},
+		&schema.Payment{},
 	)
 }
@@ -18,6 +18,13 @@ type (
 		Http            *HTTP
 		DB              *DB
 		DefaultRootUser *DefaultRootUser
+		Alert           *Alert
+		Notify          *Notify
+		Webhook         *Webhook
+		Approval        *Approval
+		Invoice         *Invoice
+		Weighbridge     *Weighbridge
+		Idempotency     *Idempotency
 	}
 
 	App struct {
@@ -45,9 +52,12 @@ type (
 		MaxAge     int
 	}
 
+	// Auth is the signing key and lifetimes of the tokens, expired sessions are deleted on SessionSchedule
 	Auth struct {
-		SecretKey string
-		Duration  time.Duration
+		SecretKey       string
+		Duration        time.Duration
+		RefreshDuration time.Duration
+		SessionSchedule string
 	}
 
 	HTTP struct {
@@ -64,6 +74,80 @@ type (
 		Password string
 		Phone    string
 	}
+
+	Alert struct {
+		Schedule      string
+		CapacityRatio float64
+		ExpiryWindow  time.Duration
+	}
+
+	// SMTP is the mail server of email notifications, no authentication is used when Username is empty
+	SMTP struct {
+		Host     string
+		Port     int
+		Username string
+		Password string
+		From     string
+	}
+
+	Notify struct {
+		// SMTP is nil when SMTP_HOST is not set, the email channel is then unavailable
+		SMTP                *SMTP
+		WebhookTimeout      time.Duration
+		RetryTimes          int
+		RetryDelay          time.Duration
+		LargeExportQuantity int
+	}
+
+	Webhook struct {
+		Schedule    string
+		Timeout     time.Duration
+		MaxAttempts int
+		Backoff     time.Duration
+		MaxBackoff  time.Duration
+	}
+
+	// Approval is the limits above which an invoice waits for a root approver, a zero limit is not checked
+	Approval struct {
+		ImportQuantity   int
+		ImportTotalPrice float64
+		ExportQuantity   int
+		ExportTotalPrice float64
+	}
+
+	// Invoice is the company header of printed invoices and the template of their layout,
+	// the embedded default layout is used when Template is empty
+	Invoice struct {
+		CompanyName    string
+		CompanyAddress string
+		CompanyPhone   string
+		CompanyTaxCode string
+		Template       string
+	}
+
+	// Weighbridge is the column mapping of weighbridge files, headers and direction values are matched without case.
+	// Net is read from Gross minus Tare when a file has no Net column, weights are divided by Unit to get the quantity
+	Weighbridge struct {
+		Comma           rune
+		TicketColumn    string
+		DirectionColumn string
+		PartnerColumn   string
+		RiceColumn      string
+		NetColumn       string
+		GrossColumn     string
+		TareColumn      string
+		PriceColumn     string
+		ImportValues    []string
+		ExportValues    []string
+		Unit            float64
+	}
+
+	// Idempotency is how long the response of a request sent with an Idempotency-Key is replayed,
+	// expired keys are deleted on Schedule
+	Idempotency struct {
+		Window   time.Duration
+		Schedule string
+	}
 )
 
 func New() (*Config, error) {
@@ -101,6 +185,38 @@ func New() (*Config, error) {
 		return nil, err
 	}
 
+	alert, err := GetAlertConf()
+	if err != nil {
+		return nil, err
+	}
+
+	notify, err := GetNotifyConf()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := GetWebhookConf()
+	if err != nil {
+		return nil, err
+	}
+
+	approval, err := GetApprovalConf()
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := GetInvoiceConf()
+
+	weighbridge, err := GetWeighbridgeConf()
+	if err != nil {
+		return nil, err
+	}
+
+	idempotency, err := GetIdempotencyConf()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		App:             app,
 		Logger:          logger,
@@ -108,6 +224,13 @@ func New() (*Config, error) {
 		Http:            http,
 		DB:              db,
 		DefaultRootUser: defaultRootUser,
+		Alert:           alert,
+		Notify:          notify,
+		Webhook:         webhook,
+		Approval:        approval,
+		Invoice:         invoice,
+		Weighbridge:     weighbridge,
+		Idempotency:     idempotency,
 	}, nil
 }
 
@@ -154,15 +277,36 @@ func GetLoggerConf() (*Logger, error) {
 	}, nil
 }
 
+// auth defaults are used when the AUTH_* variables are not set
+const (
+	defaultRefreshTokenDuration = 7 * 24 * time.Hour
+	defaultSessionSchedule      = "@hourly"
+)
+
 func GetAuthConf() (*Auth, error) {
 	duration, err := time.ParseDuration(os.Getenv("AUTH_TOKEN_DURATION"))
 	if err != nil {
 		return nil, err
 	}
 
+	refreshDuration := defaultRefreshTokenDuration
+	if v := os.Getenv("AUTH_REFRESH_TOKEN_DURATION"); v != "" {
+		refreshDuration, err = time.ParseDuration(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sessionSchedule := defaultSessionSchedule
+	if v := os.Getenv("AUTH_SESSION_SCHEDULE"); v != "" {
+		sessionSchedule = v
+	}
+
 	return &Auth{
-		SecretKey: os.Getenv("AUTH_SECRET"),
-		Duration:  duration,
+		SecretKey:       os.Getenv("AUTH_SECRET"),
+		Duration:        duration,
+		RefreshDuration: refreshDuration,
+		SessionSchedule: sessionSchedule,
 	}, nil
 }
 
@@ -242,3 +386,300 @@ func GetDefaultRootUserConf() (*DefaultRootUser, error) {
 		Phone:    os.Getenv("ROOT_USER_PHONE"),
 	}, nil
 }
+
+// alert defaults are used when the ALERT_* variables are not set
+const (
+	defaultAlertSchedule      = "@hourly"
+	defaultAlertCapacityRatio = 0.9
+	defaultAlertExpiryWindow  = 30 * 24 * time.Hour
+)
+
+func GetAlertConf() (*Alert, error) {
+	conf := &Alert{
+		Schedule:      defaultAlertSchedule,
+		CapacityRatio: defaultAlertCapacityRatio,
+		ExpiryWindow:  defaultAlertExpiryWindow,
+	}
+
+	if v := os.Getenv("ALERT_SCHEDULE"); v != "" {
+		conf.Schedule = v
+	}
+
+	if v := os.Getenv("ALERT_CAPACITY_RATIO"); v != "" {
+		ratio, err := strconv.ParseFloat(v, 64)
+		if err != nil || ratio <= 0 || ratio > 1 {
+			return nil, fmt.Errorf("ALERT_CAPACITY_RATIO must to be a number in (0, 1]: %v", v)
+		}
+		conf.CapacityRatio = ratio
+	}
+
+	if v := os.Getenv("ALERT_EXPIRY_WINDOW"); v != "" {
+		window, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, err
+		}
+		conf.ExpiryWindow = window
+	}
+
+	return conf, nil
+}
+
+// notify defaults are used when the NOTIFY_* variables are not set
+const (
+	defaultSMTPPort                  = 587
+	defaultNotifyWebhookTimeout      = 10 * time.Second
+	defaultNotifyRetryTimes          = 3
+	defaultNotifyRetryDelay          = 5 * time.Second
+	defaultNotifyLargeExportQuantity = 1000
+)
+
+func GetNotifyConf() (*Notify, error) {
+	conf := &Notify{
+		WebhookTimeout:      defaultNotifyWebhookTimeout,
+		RetryTimes:          defaultNotifyRetryTimes,
+		RetryDelay:          defaultNotifyRetryDelay,
+		LargeExportQuantity: defaultNotifyLargeExportQuantity,
+	}
+
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		port := defaultSMTPPort
+		if v := os.Getenv("SMTP_PORT"); v != "" {
+			p, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("SMTP_PORT must to be a number: %v", err)
+			}
+			port = p
+		}
+
+		conf.SMTP = &SMTP{
+			Host:     host,
+			Port:     port,
+			Username: os.Getenv("SMTP_USER"),
+			Password: os.Getenv("SMTP_PASS"),
+			From:     os.Getenv("SMTP_FROM"),
+		}
+	}
+
+	if v := os.Getenv("NOTIFY_WEBHOOK_TIMEOUT"); v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, err
+		}
+		conf.WebhookTimeout = timeout
+	}
+
+	if v := os.Getenv("NOTIFY_RETRY_TIMES"); v != "" {
+		times, err := strconv.Atoi(v)
+		if err != nil || times < 0 {
+			return nil, fmt.Errorf("NOTIFY_RETRY_TIMES must to be a positive number: %v", v)
+		}
+		conf.RetryTimes = times
+	}
+
+	if v := os.Getenv("NOTIFY_RETRY_DELAY"); v != "" {
+		delay, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, err
+		}
+		conf.RetryDelay = delay
+	}
+
+	if v := os.Getenv("NOTIFY_LARGE_EXPORT_QUANTITY"); v != "" {
+		quantity, err := strconv.Atoi(v)
+		if err != nil || quantity < 1 {
+			return nil, fmt.Errorf("NOTIFY_LARGE_EXPORT_QUANTITY must to be a positive number: %v", v)
+		}
+		conf.LargeExportQuantity = quantity
+	}
+
+	return conf, nil
+}
+
+// webhook defaults are used when the WEBHOOK_* variables are not set
+const (
+	defaultWebhookSchedule    = "@every 10s"
+	defaultWebhookTimeout     = 10 * time.Second
+	defaultWebhookMaxAttempts = 8
+	defaultWebhookBackoff     = 30 * time.Second
+	defaultWebhookMaxBackoff  = 6 * time.Hour
+)
+
+func GetWebhookConf() (*Webhook, error) {
+	conf := &Webhook{
+		Schedule:    defaultWebhookSchedule,
+		Timeout:     defaultWebhookTimeout,
+		MaxAttempts: defaultWebhookMaxAttempts,
+		Backoff:     defaultWebhookBackoff,
+		MaxBackoff:  defaultWebhookMaxBackoff,
+	}
+
+	if v := os.Getenv("WEBHOOK_SCHEDULE"); v != "" {
+		conf.Schedule = v
+	}
+
+	if v := os.Getenv("WEBHOOK_MAX_ATTEMPTS"); v != "" {
+		attempts, err := strconv.Atoi(v)
+		if err != nil || attempts < 1 {
+			return nil, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS must to be a positive number: %v", v)
+		}
+		conf.MaxAttempts = attempts
+	}
+
+	for env, d := range map[string]*time.Duration{
+		"WEBHOOK_TIMEOUT":     &conf.Timeout,
+		"WEBHOOK_BACKOFF":     &conf.Backoff,
+		"WEBHOOK_MAX_BACKOFF": &conf.MaxBackoff,
+	} {
+		if v := os.Getenv(env); v != "" {
+			duration, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s must to be a duration: %v", env, err)
+			}
+			*d = duration
+		}
+	}
+
+	return conf, nil
+}
+
+func GetApprovalConf() (*Approval, error) {
+	conf := &Approval{}
+
+	for env, n := range map[string]*int{
+		"APPROVAL_IMPORT_QUANTITY": &conf.ImportQuantity,
+		"APPROVAL_EXPORT_QUANTITY": &conf.ExportQuantity,
+	} {
+		if v := os.Getenv(env); v != "" {
+			quantity, err := strconv.Atoi(v)
+			if err != nil || quantity < 0 {
+				return nil, fmt.Errorf("%s must to be a positive number: %v", env, v)
+			}
+			*n = quantity
+		}
+	}
+
+	for env, n := range map[string]*float64{
+		"APPROVAL_IMPORT_TOTAL_PRICE": &conf.ImportTotalPrice,
+		"APPROVAL_EXPORT_TOTAL_PRICE": &conf.ExportTotalPrice,
+	} {
+		if v := os.Getenv(env); v != "" {
+			price, err := strconv.ParseFloat(v, 64)
+			if err != nil || price < 0 {
+				return nil, fmt.Errorf("%s must to be a positive number: %v", env, v)
+			}
+			*n = price
+		}
+	}
+
+	return conf, nil
+}
+
+// GetInvoiceConf read the INVOICE_* variables, the company name is the app name when not set
+func GetInvoiceConf() *Invoice {
+	conf := &Invoice{
+		CompanyName:    os.Getenv("INVOICE_COMPANY_NAME"),
+		CompanyAddress: os.Getenv("INVOICE_COMPANY_ADDRESS"),
+		CompanyPhone:   os.Getenv("INVOICE_COMPANY_PHONE"),
+		CompanyTaxCode: os.Getenv("INVOICE_COMPANY_TAX_CODE"),
+		Template:       os.Getenv("INVOICE_TEMPLATE"),
+	}
+
+	if conf.CompanyName == "" {
+		conf.CompanyName = os.Getenv("APP_NAME")
+	}
+	return conf
+}
+
+// weighbridge defaults are used when the WEIGHBRIDGE_* variables are not set
+const (
+	defaultWeighbridgeComma        = ','
+	defaultWeighbridgeImportValues = "in"
+	defaultWeighbridgeExportValues = "out"
+	defaultWeighbridgeUnit         = 1
+)
+
+func GetWeighbridgeConf() (*Weighbridge, error) {
+	conf := &Weighbridge{
+		Comma:           defaultWeighbridgeComma,
+		TicketColumn:    "ticket",
+		DirectionColumn: "direction",
+		PartnerColumn:   "partner",
+		RiceColumn:      "rice",
+		NetColumn:       "net",
+		GrossColumn:     "gross",
+		TareColumn:      "tare",
+		PriceColumn:     "price",
+		ImportValues:    strings.Split(defaultWeighbridgeImportValues, ","),
+		ExportValues:    strings.Split(defaultWeighbridgeExportValues, ","),
+		Unit:            defaultWeighbridgeUnit,
+	}
+
+	if v := os.Getenv("WEIGHBRIDGE_COMMA"); v != "" {
+		runes := []rune(v)
+		if len(runes) != 1 {
+			return nil, fmt.Errorf("WEIGHBRIDGE_COMMA must to be one character: %v", v)
+		}
+		conf.Comma = runes[0]
+	}
+
+	for env, column := range map[string]*string{
+		"WEIGHBRIDGE_TICKET_COLUMN":    &conf.TicketColumn,
+		"WEIGHBRIDGE_DIRECTION_COLUMN": &conf.DirectionColumn,
+		"WEIGHBRIDGE_PARTNER_COLUMN":   &conf.PartnerColumn,
+		"WEIGHBRIDGE_RICE_COLUMN":      &conf.RiceColumn,
+		"WEIGHBRIDGE_NET_COLUMN":       &conf.NetColumn,
+		"WEIGHBRIDGE_GROSS_COLUMN":     &conf.GrossColumn,
+		"WEIGHBRIDGE_TARE_COLUMN":      &conf.TareColumn,
+		"WEIGHBRIDGE_PRICE_COLUMN":     &conf.PriceColumn,
+	} {
+		if v := strings.TrimSpace(os.Getenv(env)); v != "" {
+			*column = v
+		}
+	}
+
+	for env, values := range map[string]*[]string{
+		"WEIGHBRIDGE_IMPORT_VALUES": &conf.ImportValues,
+		"WEIGHBRIDGE_EXPORT_VALUES": &conf.ExportValues,
+	} {
+		if v := os.Getenv(env); v != "" {
+			*values = strings.Split(v, ",")
+		}
+	}
+
+	if v := os.Getenv("WEIGHBRIDGE_UNIT"); v != "" {
+		unit, err := strconv.ParseFloat(v, 64)
+		if err != nil || unit <= 0 {
+			return nil, fmt.Errorf("WEIGHBRIDGE_UNIT must to be a positive number: %v", v)
+		}
+		conf.Unit = unit
+	}
+
+	return conf, nil
+}
+
+// idempotency defaults are used when the IDEMPOTENCY_* variables are not set
+const (
+	defaultIdempotencyWindow   = 24 * time.Hour
+	defaultIdempotencySchedule = "@hourly"
+)
+
+func GetIdempotencyConf() (*Idempotency, error) {
+	conf := &Idempotency{
+		Window:   defaultIdempotencyWindow,
+		Schedule: defaultIdempotencySchedule,
+	}
+
+	if v := os.Getenv("IDEMPOTENCY_WINDOW"); v != "" {
+		window, err := time.ParseDuration(v)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("IDEMPOTENCY_WINDOW must to be a positive duration: %v", v)
+		}
+		conf.Window = window
+	}
+
+	if v := os.Getenv("IDEMPOTENCY_SCHEDULE"); v != "" {
+		conf.Schedule = v
+	}
+
+	return conf, nil
+}
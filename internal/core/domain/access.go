@@ -0,0 +1,47 @@
+package domain
+
+// AccessLevel is what a user granted access to a warehouse is allowed to do there
+type AccessLevel string
+
+const (
+	AccessReadOnly   AccessLevel = "read_only"
+	AccessImportOnly AccessLevel = "import_only"
+	AccessExportOnly AccessLevel = "export_only"
+	AccessFull       AccessLevel = "full"
+)
+
+// AccessAction is an action a user takes on a warehouse
+type AccessAction string
+
+const (
+	// ActionRead is viewing the warehouse, its inventory and invoices
+	ActionRead AccessAction = "read"
+	// ActionImport is posting import invoices or receiving transfers
+	ActionImport AccessAction = "import"
+	// ActionExport is posting export invoices or sending transfers
+	ActionExport AccessAction = "export"
+)
+
+// IsValid report whether the access level is a defined level
+func (l AccessLevel) IsValid() bool {
+	switch l {
+	case AccessReadOnly, AccessImportOnly, AccessExportOnly, AccessFull:
+		return true
+	default:
+		return false
+	}
+}
+
+// Allows report whether the access level allows the action, every level allows reading
+func (l AccessLevel) Allows(action AccessAction) bool {
+	switch action {
+	case ActionRead:
+		return l.IsValid()
+	case ActionImport:
+		return l == AccessImportOnly || l == AccessFull
+	case ActionExport:
+		return l == AccessExportOnly || l == AccessFull
+	default:
+		return false
+	}
+}
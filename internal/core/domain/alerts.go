@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// StockThreshold is the minimum quantity of a rice a warehouse should keep in stock
+type StockThreshold struct {
+	WarehouseID int       `json:"warehouse_id"`
+	RiceID      int       `json:"rice_id"`
+	MinQuantity int       `json:"min_quantity"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Rice        *Rice     `json:"rice,omitempty"`
+}
+
+// AlertType is the condition an alert was raised for
+type AlertType string
+
+const (
+	// AlertLowStock is raised when the stock of a rice falls below its threshold
+	AlertLowStock AlertType = "low_stock"
+	// AlertNearCapacity is raised when the used capacity of a warehouse reach the near-capacity ratio
+	AlertNearCapacity AlertType = "near_capacity"
+	// AlertLotExpiry is raised when a lot still in stock is about to expire
+	AlertLotExpiry AlertType = "lot_expiry"
+)
+
+// Alert is a condition found by the alert job, an alert stays open until it is acknowledged
+// and the same condition is not raised again while it is open
+type Alert struct {
+	ID             int        `json:"id"`
+	Type           AlertType  `json:"type"`
+	WarehouseID    int        `json:"warehouse_id"`
+	RiceID         *int       `json:"rice_id,omitempty"`
+	LotID          *int       `json:"lot_id,omitempty"`
+	Message        string     `json:"message"`
+	Value          int        `json:"value"`
+	Threshold      int        `json:"threshold"`
+	CreatedAt      time.Time  `json:"created_at"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy *int       `json:"acknowledged_by,omitempty"`
+}
+
+// Key identify the condition of the alert, two alerts with the same key are the same condition
+func (a *Alert) Key() string {
+	key := fmt.Sprintf("%s:%d", a.Type, a.WarehouseID)
+	if a.RiceID != nil {
+		key += fmt.Sprintf(":r%d", *a.RiceID)
+	}
+	if a.LotID != nil {
+		key += fmt.Sprintf(":l%d", *a.LotID)
+	}
+	return key
+}
+
+// AlertFilter is the filter of the alert list, zero fields are ignored
+type AlertFilter struct {
+	WarehouseID  int
+	Type         AlertType
+	Acknowledged *bool
+}
+
+// LowStock is a rice whose stock in a warehouse is below its threshold
+type LowStock struct {
+	WarehouseID int
+	RiceID      int
+	RiceName    string
+	Quantity    int
+	MinQuantity int
+}
+
+// WarehouseUsage is the used capacity of a warehouse
+type WarehouseUsage struct {
+	WarehouseID int
+	Name        string
+	Used        int
+	Capacity    int
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+	AuditCancel AuditAction = "cancel"
+	AuditGrant  AuditAction = "grant"
+	AuditRevoke AuditAction = "revoke"
+	// AuditConfirm is confirming an order, it reserves capacity or stock
+	AuditConfirm AuditAction = "confirm"
+	// AuditConvert is converting an order into an invoice
+	AuditConvert AuditAction = "convert"
+	// AuditApprove is approving an invoice waiting for approval
+	AuditApprove AuditAction = "approve"
+	// AuditReject is rejecting an invoice waiting for approval
+	AuditReject AuditAction = "reject"
+)
+
+type AuditEntity string
+
+const (
+	AuditEntityUser          AuditEntity = "user"
+	AuditEntityWarehouse     AuditEntity = "warehouse"
+	AuditEntityRice          AuditEntity = "rice"
+	AuditEntityCustomer      AuditEntity = "customer"
+	AuditEntityImportInvoice AuditEntity = "import_invoice"
+	AuditEntityExportInvoice AuditEntity = "export_invoice"
+	AuditEntityTransfer      AuditEntity = "transfer"
+	AuditEntityOrder         AuditEntity = "order"
+	AuditEntityPayment       AuditEntity = "payment"
+	// AuditEntityAccess is a warehouse access grant, the entity id is the warehouse id
+	AuditEntityAccess AuditEntity = "access"
+)
+
+// AuditLog is a record of a mutating operation, Before and After are JSON snapshots of the entity
+type AuditLog struct {
+	ID        int             `json:"id"`
+	ActorID   *int            `json:"actor_id"`
+	ActorName string          `json:"actor_name"`
+	Action    AuditAction     `json:"action"`
+	Entity    AuditEntity     `json:"entity"`
+	EntityID  int             `json:"entity_id"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	IP        string          `json:"ip"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// AuditFilter filter audit logs, zero fields are ignored
+type AuditFilter struct {
+	ActorID  int
+	Entity   AuditEntity
+	EntityID int
+	Start    *time.Time
+	End      *time.Time
+}
+
+// Actor is the user who makes a request
+type Actor struct {
+	ID   int
+	Name string
+	IP   string
+}
+
+type actorContextKey struct{}
+
+// ContextWithActor return a copy of ctx carrying the actor
+func ContextWithActor(ctx context.Context, actor *Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext return the actor of ctx or nil if ctx carries no actor
+func ActorFromContext(ctx context.Context) *Actor {
+	actor, _ := ctx.Value(actorContextKey{}).(*Actor)
+	return actor
+}
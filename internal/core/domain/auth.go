@@ -1,9 +1,33 @@
 package domain
 
+import "time"
+
 type TokenPayload struct {
 	ID    int    `json:"id"`
 	Name  string `json:"name"`
 	Email string `json:"email"`
 	Role  Role   `json:"role"`
 	Key   string `json:"key"`
+	// SessionID is the id of the session the token belongs to
+	SessionID int `json:"session_id"`
+	// TokenID is the id of a refresh token, empty for access tokens
+	TokenID string `json:"token_id,omitempty"`
+}
+
+type AuthToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Session is a logged in device of a user
+type Session struct {
+	ID         int
+	UserID     int
+	Key        string
+	RefreshKey string
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
 }
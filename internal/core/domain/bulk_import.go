@@ -0,0 +1,35 @@
+package domain
+
+import "sort"
+
+// ImportRow is a row of a bulk import file, Line is its line in the file (the header is line 1).
+// A row with errors failed validation and is never created
+type ImportRow[T any] struct {
+	Line   int
+	Data   T
+	Errors []ImportRowError
+}
+
+// ImportRowError is why a row of a bulk import can not be created, Field is the column when it is known
+type ImportRowError struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportResult is the outcome of a bulk import. The rows are only created, all in one transaction,
+// when every row is valid and the import is not a dry run
+type ImportResult[T any] struct {
+	Total     int              `json:"total"`
+	Valid     int              `json:"valid"`
+	Committed bool             `json:"committed"`
+	Errors    []ImportRowError `json:"errors"`
+	Created   []T              `json:"created"`
+}
+
+// SortErrors order the errors by line, the errors of one line keep their order
+func (r *ImportResult[T]) SortErrors() {
+	sort.SliceStable(r.Errors, func(i, j int) bool {
+		return r.Errors[i].Line < r.Errors[j].Line
+	})
+}
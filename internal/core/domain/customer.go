@@ -1,9 +1,34 @@
 package domain
 
+// PartnerRole is what a partner does with us, suppliers sell rice to us and customers buy it
+type PartnerRole string
+
+const (
+	PartnerSupplier PartnerRole = "supplier"
+	PartnerCustomer PartnerRole = "customer"
+	PartnerBoth     PartnerRole = "both"
+)
+
+// Roles return the roles of the partners that can act as r
+func (r PartnerRole) Roles() []PartnerRole {
+	if r == PartnerBoth {
+		return []PartnerRole{PartnerBoth}
+	}
+	return []PartnerRole{r, PartnerBoth}
+}
+
 type Customer struct {
-	ID      int    `json:"id"`
-	Name    string `json:"name"`
-	Email   string `json:"email"`
-	Phone   string `json:"phone"`
-	Address string `json:"address"`
+	ID      int         `json:"id"`
+	Name    string      `json:"name"`
+	Email   string      `json:"email"`
+	Phone   string      `json:"phone"`
+	Address string      `json:"address"`
+	Role    PartnerRole `json:"role"`
+	TaxCode string      `json:"tax_code"`
+}
+
+// HasRole report whether the partner can act as role,
+// import invoices and purchase orders need a supplier, export invoices and sales orders a customer
+func (c *Customer) HasRole(role PartnerRole) bool {
+	return c.Role == role || c.Role == PartnerBoth
 }
@@ -19,6 +19,50 @@ var (
 	ErrWarehouseFull = errors.New("warehouse is full")
 	// ErrInsufficientStock is an error for when product stock is not enough
 	ErrInsufficientStock = errors.New("rice stock is not enough")
+	// ErrInvoiceCancelled is an error for when the invoice has already been cancelled
+	ErrInvoiceCancelled = errors.New("invoice has already been cancelled")
+	// ErrLotConsumed is an error for when a lot has already been partly exported
+	ErrLotConsumed = errors.New("lot has already been partly exported")
+	// ErrInvoiceNotPending is an error for when an invoice that is not waiting for approval is approved or rejected
+	ErrInvoiceNotPending = errors.New("invoice is not waiting for approval")
+	// ErrInvoiceNotCompleted is an error for when an invoice that never moved stock is cancelled
+	ErrInvoiceNotCompleted = errors.New("only completed invoices can be cancelled")
+	// ErrInvalidLotAllocation is an error for when the lots of an export line do not add up to its quantity
+	ErrInvalidLotAllocation = errors.New("lot quantities must add up to the line quantity")
+	// ErrSameWarehouseTransfer is an error for when the source and destination warehouse of a transfer are the same
+	ErrSameWarehouseTransfer = errors.New("source and destination warehouse must be different")
+	// ErrTransferInvoice is an error for when an invoice of a transfer is cancelled on its own
+	ErrTransferInvoice = errors.New("invoice belongs to a transfer, reverse the transfer instead")
+	// ErrTransferReversed is an error for when a transfer has already been reversed
+	ErrTransferReversed = errors.New("transfer has already been reversed")
+	// ErrInvalidDateRange is an error for when the start of a period is after its end
+	ErrInvalidDateRange = errors.New("start must be before end")
+	// ErrInvalidReportPeriod is an error for when a report is grouped by an unknown period
+	ErrInvalidReportPeriod = errors.New("report period must be day, week or month")
+	// ErrInvalidValuationMethod is an error for when an inventory valuation method is unknown
+	ErrInvalidValuationMethod = errors.New("valuation method must be fifo or weighted_average")
+	// ErrAlertAcknowledged is an error for when the alert has already been acknowledged
+	ErrAlertAcknowledged = errors.New("alert has already been acknowledged")
+	// ErrChannelUnavailable is an error for when a notification channel is not configured
+	ErrChannelUnavailable = errors.New("notification channel is not available")
+	// ErrWebhookTargetRequired is an error for when a webhook subscription has no target url
+	ErrWebhookTargetRequired = errors.New("webhook subscription needs a target url")
+	// ErrOutboxNotDead is an error for when an outbox message that is not dead is retried
+	ErrOutboxNotDead = errors.New("only dead messages can be retried")
+	// ErrInvalidOrderStatus is an error for when an order can not move from its current status
+	ErrInvalidOrderStatus = errors.New("order can not change from its current status")
+	// ErrInvalidPartnerRole is an error for when the partner of an invoice or order does not have the role it needs
+	ErrInvalidPartnerRole = errors.New("partner does not have the role needed, imports need a supplier and exports a customer")
+	// ErrInvoiceNotPayable is an error for when a payment is made against an invoice that is not completed or belongs to a transfer
+	ErrInvoiceNotPayable = errors.New("only completed invoices outside transfers can be paid")
+	// ErrOverpayment is an error for when a payment is more than the balance of its invoice
+	ErrOverpayment = errors.New("payment is more than the invoice balance")
+	// ErrInvoicePaid is an error for when an invoice with payments is cancelled
+	ErrInvoicePaid = errors.New("invoice has payments and can not be cancelled")
+	// ErrIdempotencyKeyReused is an error for when an idempotency key is sent again with a different request
+	ErrIdempotencyKeyReused = errors.New("idempotency key has already been used with a different request")
+	// ErrIdempotencyInProgress is an error for when the first request sent with an idempotency key has not finished yet
+	ErrIdempotencyInProgress = errors.New("a request with this idempotency key is still being processed")
 	// ErrTokenDuration is an error for when the token duration format is invalid
 	ErrTokenDuration = errors.New("invalid token duration format")
 	// ErrTokenCreation is an error for when the token creation fails
@@ -27,6 +71,8 @@ var (
 	ErrExpiredToken = errors.New("access token has expired")
 	// ErrInvalidToken is an error for when the access token is invalid
 	ErrInvalidToken = errors.New("access token is invalid")
+	// ErrRefreshTokenReused is an error for when a rotated refresh token is used again
+	ErrRefreshTokenReused = errors.New("refresh token has already been used")
 	// ErrInvalidCredentials is an error for when the credentials are invalid
 	ErrInvalidCredentials = errors.New("invalid email or password")
 	// ErrEmptyAuthorizationHeader is an error for when the authorization header is empty
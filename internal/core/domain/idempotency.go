@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord is a request sent with an Idempotency-Key header. While it is not completed the request is
+// still running, once completed its response is replayed to a request with the same key until it expires
+type IdempotencyRecord struct {
+	ID     int
+	UserID int
+	Key    string
+	// Fingerprint is a hash of the method, path and body, a key can only be sent again with the same request
+	Fingerprint string
+	StatusCode  int
+	Response    []byte
+	Completed   bool
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
@@ -2,24 +2,49 @@ package domain
 
 import "time"
 
+type InvoiceStatus string
+
+const (
+	InvoiceCompleted InvoiceStatus = "completed"
+	InvoiceCancelled InvoiceStatus = "cancelled"
+	// InvoicePendingApproval is an invoice covered by an approval rule, it does not move stock until it is approved
+	InvoicePendingApproval InvoiceStatus = "pending_approval"
+	// InvoiceRejected is an invoice turned down by the approver, it never moved stock
+	InvoiceRejected InvoiceStatus = "rejected"
+)
+
 type InvoiceItem struct {
 	Price    float64 `json:"price"`
 	Quantity int     `json:"quantity"`
 	RiceID   int     `json:"rice_id"`
 	Rice     *Rice   `json:"rice,omitempty"`
+	// Lot is the lot created by an import line
+	Lot *Lot `json:"lot,omitempty"`
+	// Lots are the lots taken by an export line, they are chosen first-expiry-first-out when empty
+	Lots []LotAllocation `json:"lots,omitempty"`
 }
 
 type Invoice struct {
-	ID          int           `json:"id"`
-	WarehouseID int           `json:"warehouse_id"`
-	CustomerID  int           `json:"customer_id"`
-	UserID      int           `json:"user_id"`
-	CreatedAt   time.Time     `json:"created_at"`
-	TotalPrice  float64       `json:"total_price"`
-	Details     []InvoiceItem `json:"details"`
-	CreatedBy   *User         `json:"created_by"`
-	Customer    *Customer     `json:"customer"`
-	Warehouse   *Warehouse    `json:"warehouse"`
+	ID           int           `json:"id"`
+	WarehouseID  int           `json:"warehouse_id"`
+	CustomerID   int           `json:"customer_id"`
+	UserID       int           `json:"user_id"`
+	CreatedAt    time.Time     `json:"created_at"`
+	TotalPrice   float64       `json:"total_price"`
+	Status       InvoiceStatus `json:"status"`
+	CancelReason string        `json:"cancel_reason,omitempty"`
+	CancelledAt  *time.Time    `json:"cancelled_at,omitempty"`
+	PaidAmount   float64       `json:"paid_amount"`
+	ApprovedBy   *int          `json:"approved_by,omitempty"`
+	ApprovedAt   *time.Time    `json:"approved_at,omitempty"`
+	// Reference is the outside document the invoice was posted from, such as a weighbridge ticket,
+	// an invoice kind has one invoice per reference
+	Reference string        `json:"reference,omitempty"`
+	Details   []InvoiceItem `json:"details"`
+	CreatedBy *User         `json:"created_by"`
+	Approver  *User         `json:"approver,omitempty"`
+	Customer  *Customer     `json:"customer"`
+	Warehouse *Warehouse    `json:"warehouse"`
 }
 
 // CalcTotalPrice calculate total price of invoice
@@ -29,3 +54,26 @@ func (i *Invoice) CalcTotalPrice() float64 {
 	}
 	return i.TotalPrice
 }
+
+// Quantity return the total quantity of the invoice details
+func (i *Invoice) Quantity() int {
+	quantity := 0
+	for _, v := range i.Details {
+		quantity += v.Quantity
+	}
+	return quantity
+}
+
+// ApprovalRule is the limits above which an invoice waits for a root approver, a zero limit is not checked
+type ApprovalRule struct {
+	Quantity   int
+	TotalPrice float64
+}
+
+// Requires report whether the invoice is above a limit of the rule
+func (r ApprovalRule) Requires(invoice *Invoice) bool {
+	if r.Quantity > 0 && invoice.Quantity() > r.Quantity {
+		return true
+	}
+	return r.TotalPrice > 0 && invoice.TotalPrice > r.TotalPrice
+}
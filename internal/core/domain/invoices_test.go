@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApprovalRuleRequires(t *testing.T) {
+	invoice := &Invoice{
+		TotalPrice: 1000,
+		Details:    []InvoiceItem{{RiceID: 1, Quantity: 60}, {RiceID: 2, Quantity: 40}},
+	}
+
+	tests := []struct {
+		name string
+		rule ApprovalRule
+		want bool
+	}{
+		{"no limits", ApprovalRule{}, false},
+		{"at the quantity limit", ApprovalRule{Quantity: 100}, false},
+		{"above the quantity limit", ApprovalRule{Quantity: 99}, true},
+		{"at the total price limit", ApprovalRule{TotalPrice: 1000}, false},
+		{"above the total price limit", ApprovalRule{TotalPrice: 999.5}, true},
+		{"above one of both limits", ApprovalRule{Quantity: 500, TotalPrice: 999}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.rule.Requires(invoice))
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// Lot is a batch of rice brought into a warehouse by an import invoice line,
+// Remaining is the quantity of the lot still in the warehouse
+type Lot struct {
+	ID              int        `json:"id"`
+	WarehouseID     int        `json:"warehouse_id"`
+	RiceID          int        `json:"rice_id"`
+	ImportInvoiceID int        `json:"import_invoice_id"`
+	LotNumber       string     `json:"lot_number"`
+	HarvestDate     *time.Time `json:"harvest_date,omitempty"`
+	ExpiryDate      *time.Time `json:"expiry_date,omitempty"`
+	Grade           string     `json:"grade,omitempty"`
+	Moisture        float64    `json:"moisture,omitempty"`
+	Quantity        int        `json:"quantity"`
+	Remaining       int        `json:"remaining"`
+	CreatedAt       time.Time  `json:"created_at"`
+	Rice            *Rice      `json:"rice,omitempty"`
+}
+
+// LotAllocation is a quantity of a lot taken by an export invoice line
+type LotAllocation struct {
+	LotID      int        `json:"lot_id"`
+	LotNumber  string     `json:"lot_number,omitempty"`
+	ExpiryDate *time.Time `json:"expiry_date,omitempty"`
+	Quantity   int        `json:"quantity"`
+}
+
+// ExpiresBefore report whether l has to leave the warehouse before o,
+// lots without expiry date go last and lots expiring together go oldest first
+func (l *Lot) ExpiresBefore(o *Lot) bool {
+	switch {
+	case l.ExpiryDate == nil && o.ExpiryDate == nil:
+		return l.ID < o.ID
+	case l.ExpiryDate == nil:
+		return false
+	case o.ExpiryDate == nil:
+		return true
+	case !l.ExpiryDate.Equal(*o.ExpiryDate):
+		return l.ExpiryDate.Before(*o.ExpiryDate)
+	default:
+		return l.ID < o.ID
+	}
+}
+
+// SortFEFO sort lots first-expiry-first-out
+func SortFEFO(lots []Lot) {
+	sort.SliceStable(lots, func(i, j int) bool {
+		return lots[i].ExpiresBefore(&lots[j])
+	})
+}
+
+// AllocateFEFO take quantity from the remaining of lots first-expiry-first-out,
+// it return the allocations and the quantity the lots could not cover
+func AllocateFEFO(lots []Lot, quantity int) ([]LotAllocation, int) {
+	sorted := make([]Lot, len(lots))
+	copy(sorted, lots)
+	SortFEFO(sorted)
+
+	allocations := []LotAllocation{}
+	for _, lot := range sorted {
+		if quantity == 0 {
+			break
+		}
+		if lot.Remaining <= 0 {
+			continue
+		}
+
+		taken := min(quantity, lot.Remaining)
+		allocations = append(allocations, LotAllocation{
+			LotID:      lot.ID,
+			LotNumber:  lot.LotNumber,
+			ExpiryDate: lot.ExpiryDate,
+			Quantity:   taken,
+		})
+		quantity -= taken
+	}
+
+	return allocations, quantity
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocateFEFO(t *testing.T) {
+	early := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	lots := []Lot{
+		{ID: 1, LotNumber: "no-expiry", Remaining: 10},
+		{ID: 2, LotNumber: "late", ExpiryDate: &late, Remaining: 10},
+		{ID: 3, LotNumber: "early", ExpiryDate: &early, Remaining: 5},
+		{ID: 4, LotNumber: "late-newer", ExpiryDate: &late, Remaining: 10},
+	}
+
+	allocations, uncovered := AllocateFEFO(lots, 18)
+	assert.Equal(t, 0, uncovered)
+	assert.Equal(t, []LotAllocation{
+		{LotID: 3, LotNumber: "early", ExpiryDate: &early, Quantity: 5},
+		{LotID: 2, LotNumber: "late", ExpiryDate: &late, Quantity: 10},
+		{LotID: 4, LotNumber: "late-newer", ExpiryDate: &late, Quantity: 3},
+	}, allocations)
+
+	// the lots are not changed
+	assert.Equal(t, 5, lots[2].Remaining)
+
+	allocations, uncovered = AllocateFEFO(lots, 40)
+	assert.Len(t, allocations, 4)
+	assert.Equal(t, 5, uncovered)
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// EventType is the kind of an event users can subscribe to
+type EventType string
+
+const (
+	EventImportInvoiceCreated EventType = "import_invoice.created"
+	EventExportInvoiceCreated EventType = "export_invoice.created"
+	// EventLargeExport is published with EventExportInvoiceCreated when the invoice quantity reach the large export quantity
+	EventLargeExport  EventType = "export_invoice.large"
+	EventLowStock     EventType = "alert.low_stock"
+	EventNearCapacity EventType = "alert.near_capacity"
+	EventLotExpiry    EventType = "alert.lot_expiry"
+)
+
+// NotificationChannel is the way a notification is delivered
+type NotificationChannel string
+
+const (
+	ChannelEmail   NotificationChannel = "email"
+	ChannelWebhook NotificationChannel = "webhook"
+	ChannelInApp   NotificationChannel = "in_app"
+)
+
+// Event is something that happened in a warehouse, it is delivered to the users subscribed to its type
+type Event struct {
+	Type        EventType `json:"type"`
+	WarehouseID int       `json:"warehouse_id"`
+	Subject     string    `json:"subject"`
+	Message     string    `json:"message"`
+	Data        any       `json:"data,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewInvoiceEvent create an event about an invoice
+func NewInvoiceEvent(eventType EventType, invoice *Invoice) *Event {
+	kind := "import"
+	if eventType != EventImportInvoiceCreated {
+		kind = "export"
+	}
+
+	subject := fmt.Sprintf("%s invoice #%d created", kind, invoice.ID)
+	if eventType == EventLargeExport {
+		subject = fmt.Sprintf("large export invoice #%d created", invoice.ID)
+	}
+
+	return &Event{
+		Type:        eventType,
+		WarehouseID: invoice.WarehouseID,
+		Subject:     subject,
+		Message: fmt.Sprintf("%s invoice #%d of warehouse %d: %d items, total price %.2f",
+			kind, invoice.ID, invoice.WarehouseID, invoice.Quantity(), invoice.TotalPrice),
+		Data:      invoice,
+		CreatedAt: time.Now(),
+	}
+}
+
+// alertEventTypes is the event type published for each alert type
+var alertEventTypes = map[AlertType]EventType{
+	AlertLowStock:     EventLowStock,
+	AlertNearCapacity: EventNearCapacity,
+	AlertLotExpiry:    EventLotExpiry,
+}
+
+// NewAlertEvent create an event about a raised alert
+func NewAlertEvent(alert *Alert) *Event {
+	return &Event{
+		Type:        alertEventTypes[alert.Type],
+		WarehouseID: alert.WarehouseID,
+		Subject:     fmt.Sprintf("%s alert in warehouse %d", alert.Type, alert.WarehouseID),
+		Message:     alert.Message,
+		Data:        alert,
+		CreatedAt:   alert.CreatedAt,
+	}
+}
+
+// Subscription subscribe a user to an event type on a channel,
+// Target is the url of a webhook subscription, emails are sent to the user email
+type Subscription struct {
+	ID        int                 `json:"id"`
+	UserID    int                 `json:"user_id"`
+	EventType EventType           `json:"event_type"`
+	Channel   NotificationChannel `json:"channel"`
+	Target    string              `json:"target,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+	User      *User               `json:"user,omitempty"`
+}
+
+// Notification is an in-app notification of a user
+type Notification struct {
+	ID          int        `json:"id"`
+	UserID      int        `json:"user_id"`
+	EventType   EventType  `json:"event_type"`
+	WarehouseID int        `json:"warehouse_id"`
+	Subject     string     `json:"subject"`
+	Message     string     `json:"message"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+}
+
+// Delivery is the log of delivering an event to a subscription, Error is empty when it succeeded
+type Delivery struct {
+	ID             int                 `json:"id"`
+	SubscriptionID int                 `json:"subscription_id"`
+	EventType      EventType           `json:"event_type"`
+	Channel        NotificationChannel `json:"channel"`
+	Target         string              `json:"target"`
+	Attempts       int                 `json:"attempts"`
+	Error          string              `json:"error,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+}
@@ -0,0 +1,122 @@
+package domain
+
+import "time"
+
+// OrderType is the direction of an order, a purchase order is received into the warehouse and a sales order is shipped from it
+type OrderType string
+
+const (
+	PurchaseOrder OrderType = "purchase"
+	SalesOrder    OrderType = "sales"
+)
+
+// PartnerRole is the role the partner of an order needs, purchases are made from suppliers and sales to customers
+func (t OrderType) PartnerRole() PartnerRole {
+	if t == PurchaseOrder {
+		return PartnerSupplier
+	}
+	return PartnerCustomer
+}
+
+// OrderStatus is the state of an order
+type OrderStatus string
+
+const (
+	OrderDraft OrderStatus = "draft"
+	// OrderConfirmed is an order that holds its reservation until it is converted or cancelled
+	OrderConfirmed OrderStatus = "confirmed"
+	OrderCancelled OrderStatus = "cancelled"
+	// OrderConverted is an order that has been turned into an invoice when the goods moved
+	OrderConverted OrderStatus = "converted"
+)
+
+// Order is a purchase or sales order placed ahead of invoicing.
+// A confirmed purchase order reserves warehouse capacity and a confirmed sales order reserves stock,
+// nothing moves until the order is converted into an invoice
+type Order struct {
+	ID          int           `json:"id"`
+	Type        OrderType     `json:"type"`
+	WarehouseID int           `json:"warehouse_id"`
+	CustomerID  int           `json:"customer_id"`
+	UserID      int           `json:"user_id"`
+	Status      OrderStatus   `json:"status"`
+	TotalPrice  float64       `json:"total_price"`
+	InvoiceID   *int          `json:"invoice_id,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	ConfirmedAt *time.Time    `json:"confirmed_at,omitempty"`
+	ClosedAt    *time.Time    `json:"closed_at,omitempty"`
+	Details     []InvoiceItem `json:"details"`
+	CreatedBy   *User         `json:"created_by"`
+	Customer    *Customer     `json:"customer"`
+	Warehouse   *Warehouse    `json:"warehouse"`
+}
+
+// OrderFilter filter orders, zero fields are ignored
+type OrderFilter struct {
+	WarehouseID int
+	Type        OrderType
+	Status      OrderStatus
+}
+
+// CalcTotalPrice calculate total price of order
+func (o *Order) CalcTotalPrice() float64 {
+	o.TotalPrice = 0
+	for _, v := range o.Details {
+		o.TotalPrice += v.Price * float64(v.Quantity)
+	}
+	return o.TotalPrice
+}
+
+// Quantity return the total quantity of the order details
+func (o *Order) Quantity() int {
+	quantity := 0
+	for _, v := range o.Details {
+		quantity += v.Quantity
+	}
+	return quantity
+}
+
+// orderTransitions is the statuses an order can move to each status from
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderConfirmed: {OrderDraft},
+	OrderCancelled: {OrderDraft, OrderConfirmed},
+	OrderConverted: {OrderConfirmed},
+}
+
+// From return the statuses an order can move to the status from
+func (s OrderStatus) From() []OrderStatus {
+	return orderTransitions[s]
+}
+
+// CanMoveTo report whether the order can change from its current status to the status
+func (o *Order) CanMoveTo(status OrderStatus) bool {
+	for _, v := range status.From() {
+		if v == o.Status {
+			return true
+		}
+	}
+	return false
+}
+
+// Invoice build the invoice the order is converted into, it is an import invoice for a purchase order
+// and an export invoice for a sales order
+func (o *Order) Invoice(userID int) *Invoice {
+	details := make([]InvoiceItem, 0, len(o.Details))
+	for _, v := range o.Details {
+		details = append(details, InvoiceItem{
+			Price:    v.Price,
+			Quantity: v.Quantity,
+			RiceID:   v.RiceID,
+		})
+	}
+
+	invoice := &Invoice{
+		WarehouseID: o.WarehouseID,
+		CustomerID:  o.CustomerID,
+		UserID:      userID,
+		Details:     details,
+	}
+	invoice.CalcTotalPrice()
+
+	return invoice
+}
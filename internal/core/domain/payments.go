@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// InvoiceKind is the kind of invoice a payment is made against,
+// customers pay export invoices (receivable) and suppliers are paid for import invoices (payable)
+type InvoiceKind string
+
+const (
+	InvoiceKindImport InvoiceKind = "import"
+	InvoiceKindExport InvoiceKind = "export"
+)
+
+// PaymentMethod is how a payment was made
+type PaymentMethod string
+
+const (
+	PaymentCash         PaymentMethod = "cash"
+	PaymentBankTransfer PaymentMethod = "bank_transfer"
+	PaymentCard         PaymentMethod = "card"
+	PaymentOther        PaymentMethod = "other"
+)
+
+// PaymentStatus is how much of an invoice has been paid
+type PaymentStatus string
+
+const (
+	PaymentUnpaid        PaymentStatus = "unpaid"
+	PaymentPartiallyPaid PaymentStatus = "partially_paid"
+	PaymentPaid          PaymentStatus = "paid"
+)
+
+// Payment is a full or partial payment of an invoice, CustomerID and WarehouseID are the partner and warehouse of the invoice
+type Payment struct {
+	ID          int           `json:"id"`
+	InvoiceKind InvoiceKind   `json:"invoice_kind"`
+	InvoiceID   int           `json:"invoice_id"`
+	CustomerID  int           `json:"customer_id"`
+	WarehouseID int           `json:"warehouse_id"`
+	UserID      int           `json:"user_id"`
+	Amount      float64       `json:"amount"`
+	Method      PaymentMethod `json:"method"`
+	Reference   string        `json:"reference"`
+	PaidAt      time.Time     `json:"paid_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+	Customer    *Customer     `json:"customer,omitempty"`
+	CreatedBy   *User         `json:"created_by,omitempty"`
+}
+
+// PaymentFilter is the filter of the payment list, zero fields are ignored
+type PaymentFilter struct {
+	InvoiceKind InvoiceKind
+	InvoiceID   int
+	CustomerID  int
+	WarehouseID int
+	Start       *time.Time
+	End         *time.Time
+}
+
+// roundMoney round an amount to cents so sums of payments compare equal to the invoice total
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// Balance is the amount of the invoice still to be paid
+func (i *Invoice) Balance() float64 {
+	return roundMoney(i.TotalPrice - i.PaidAmount)
+}
+
+// PaymentStatus report how much of the invoice has been paid
+func (i *Invoice) PaymentStatus() PaymentStatus {
+	switch {
+	case i.Balance() <= 0:
+		return PaymentPaid
+	case i.PaidAmount <= 0:
+		return PaymentUnpaid
+	default:
+		return PaymentPartiallyPaid
+	}
+}
+
+// PartnerBalance is what a partner was invoiced and paid on one side of the ledger
+type PartnerBalance struct {
+	CustomerID   int     `json:"customer_id"`
+	CustomerName string  `json:"customer_name"`
+	Invoiced     float64 `json:"invoiced"`
+	Paid         float64 `json:"paid"`
+	Outstanding  float64 `json:"outstanding"`
+}
+
+// OpenInvoice is a completed invoice with an outstanding balance,
+// CompletedAt is the approval time of an approved invoice and the creation time of the others
+type OpenInvoice struct {
+	InvoiceID    int       `json:"invoice_id"`
+	CustomerID   int       `json:"customer_id"`
+	CustomerName string    `json:"customer_name"`
+	CompletedAt  time.Time `json:"completed_at"`
+	Outstanding  float64   `json:"outstanding"`
+}
+
+// AgingRow is the outstanding balance of a partner split by the age of its invoices,
+// CustomerID is 0 on the total row
+type AgingRow struct {
+	CustomerID   int     `json:"customer_id"`
+	CustomerName string  `json:"customer_name"`
+	Current      float64 `json:"current"`
+	Days31To60   float64 `json:"days_31_60"`
+	Days61To90   float64 `json:"days_61_90"`
+	Over90       float64 `json:"over_90"`
+	Total        float64 `json:"total"`
+}
+
+// add put an outstanding amount in the bucket of an invoice age in days,
+// 0-30 days is current
+func (r *AgingRow) add(days int, amount float64) {
+	switch {
+	case days <= 30:
+		r.Current = roundMoney(r.Current + amount)
+	case days <= 60:
+		r.Days31To60 = roundMoney(r.Days31To60 + amount)
+	case days <= 90:
+		r.Days61To90 = roundMoney(r.Days61To90 + amount)
+	default:
+		r.Over90 = roundMoney(r.Over90 + amount)
+	}
+	r.Total = roundMoney(r.Total + amount)
+}
+
+// AgingReport is the aged outstanding balances of every partner at a time
+type AgingReport struct {
+	At    time.Time  `json:"at"`
+	Rows  []AgingRow `json:"rows"`
+	Total AgingRow   `json:"total"`
+}
+
+// AgeInvoices group the open invoices by partner and by the days between their completion and at,
+// rows are ordered by partner name
+func AgeInvoices(invoices []OpenInvoice, at time.Time) *AgingReport {
+	report := &AgingReport{At: at, Rows: []AgingRow{}}
+
+	rows := map[int]*AgingRow{}
+	for _, v := range invoices {
+		row, ok := rows[v.CustomerID]
+		if !ok {
+			row = &AgingRow{CustomerID: v.CustomerID, CustomerName: v.CustomerName}
+			rows[v.CustomerID] = row
+		}
+
+		days := int(at.Sub(v.CompletedAt).Hours() / 24)
+		row.add(days, v.Outstanding)
+		report.Total.add(days, v.Outstanding)
+	}
+
+	for _, row := range rows {
+		report.Rows = append(report.Rows, *row)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		if report.Rows[i].CustomerName != report.Rows[j].CustomerName {
+			return report.Rows[i].CustomerName < report.Rows[j].CustomerName
+		}
+		return report.Rows[i].CustomerID < report.Rows[j].CustomerID
+	})
+	return report
+}
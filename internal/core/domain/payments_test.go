@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvoicePaymentStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		paid float64
+		want PaymentStatus
+	}{
+		{"nothing paid", 0, PaymentUnpaid},
+		{"part paid", 400.5, PaymentPartiallyPaid},
+		{"fully paid", 1000, PaymentPaid},
+		{"paid up to rounding", 999.999, PaymentPaid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoice := &Invoice{TotalPrice: 1000, PaidAmount: tt.paid}
+			assert.Equal(t, tt.want, invoice.PaymentStatus())
+		})
+	}
+}
+
+func TestAgeInvoices(t *testing.T) {
+	at := time.Date(2024, 9, 30, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time {
+		return at.AddDate(0, 0, -days)
+	}
+
+	invoices := []OpenInvoice{
+		{InvoiceID: 1, CustomerID: 2, CustomerName: "Bonn", CompletedAt: daysAgo(30), Outstanding: 100},
+		{InvoiceID: 2, CustomerID: 2, CustomerName: "Bonn", CompletedAt: daysAgo(31), Outstanding: 50},
+		{InvoiceID: 3, CustomerID: 1, CustomerName: "Ascalon", CompletedAt: daysAgo(61), Outstanding: 25.5},
+		{InvoiceID: 4, CustomerID: 1, CustomerName: "Ascalon", CompletedAt: daysAgo(91), Outstanding: 10},
+	}
+
+	report := AgeInvoices(invoices, at)
+
+	assert.Equal(t, at, report.At)
+	assert.Equal(t, []AgingRow{
+		{CustomerID: 1, CustomerName: "Ascalon", Days61To90: 25.5, Over90: 10, Total: 35.5},
+		{CustomerID: 2, CustomerName: "Bonn", Current: 100, Days31To60: 50, Total: 150},
+	}, report.Rows)
+	assert.Equal(t, AgingRow{Current: 100, Days31To60: 50, Days61To90: 25.5, Over90: 10, Total: 185.5}, report.Total)
+}
+
+func TestAgeInvoices_Empty(t *testing.T) {
+	report := AgeInvoices(nil, time.Now())
+
+	assert.Empty(t, report.Rows)
+	assert.NotNil(t, report.Rows)
+}
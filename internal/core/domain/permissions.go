@@ -0,0 +1,61 @@
+package domain
+
+// Permission is a named action a role can be allowed to do
+type Permission string
+
+const (
+	PermUserManage     Permission = "user:manage"
+	PermAccessManage   Permission = "access:manage"
+	PermWarehouseWrite Permission = "warehouse:write"
+	PermRiceWrite      Permission = "rice:write"
+	PermCustomerWrite  Permission = "customer:write"
+	PermInvoiceCreate  Permission = "invoice:create"
+	PermInvoiceCancel  Permission = "invoice:cancel"
+	PermTransferCreate Permission = "transfer:create"
+	PermReportRead     Permission = "report:read"
+	PermAuditRead      Permission = "audit:read"
+	PermAlertManage    Permission = "alert:manage"
+	PermWebhookManage  Permission = "webhook:manage"
+	PermPaymentWrite   Permission = "payment:write"
+	// PermInvoiceApprove is granted to no role, only root approves invoices covered by an approval rule
+	PermInvoiceApprove Permission = "invoice:approve"
+)
+
+// rolePermissions is the permissions granted to each role, root is granted every permission
+var rolePermissions = map[Role][]Permission{
+	Member: {
+		PermInvoiceCreate,
+		PermTransferCreate,
+	},
+	WarehouseManager: {
+		PermRiceWrite,
+		PermCustomerWrite,
+		PermInvoiceCreate,
+		PermInvoiceCancel,
+		PermTransferCreate,
+		PermReportRead,
+		PermAlertManage,
+	},
+	Accountant: {
+		PermCustomerWrite,
+		PermReportRead,
+		PermPaymentWrite,
+	},
+	Viewer: {
+		PermReportRead,
+	},
+}
+
+// HasPermission report whether the role is granted the permission
+func (r Role) HasPermission(perm Permission) bool {
+	if r == Root {
+		return true
+	}
+
+	for _, p := range rolePermissions[r] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+// StockMovement is the movement of a rice type in a warehouse over a period,
+// Closing is always Opening + Imported - Exported
+type StockMovement struct {
+	RiceID   int    `json:"rice_id"`
+	RiceName string `json:"rice_name"`
+	Opening  int    `json:"opening"`
+	Imported int    `json:"imported"`
+	Exported int    `json:"exported"`
+	Closing  int    `json:"closing"`
+}
+
+// ReportPeriod is the size of the buckets an analytics report is grouped by
+type ReportPeriod string
+
+const (
+	PeriodDay   ReportPeriod = "day"
+	PeriodWeek  ReportPeriod = "week"
+	PeriodMonth ReportPeriod = "month"
+)
+
+// IsValid report whether the period is a defined period
+func (p ReportPeriod) IsValid() bool {
+	switch p {
+	case PeriodDay, PeriodWeek, PeriodMonth:
+		return true
+	default:
+		return false
+	}
+}
+
+// InvoiceAnalyticsFilter is the filter of revenue and purchase reports, zero fields are ignored
+type InvoiceAnalyticsFilter struct {
+	GroupBy     ReportPeriod
+	WarehouseID int
+	CustomerID  int
+	RiceID      int
+	Start       *time.Time
+	End         *time.Time
+}
+
+// InvoiceTotal is the total of the completed invoices in a period,
+// Period is the first day of the bucket, weeks start on Monday
+type InvoiceTotal struct {
+	Period   time.Time `json:"period"`
+	Amount   float64   `json:"amount"`
+	Quantity int       `json:"quantity"`
+	Invoices int       `json:"invoices"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+type Transfer struct {
+	ID              int           `json:"id"`
+	FromWarehouseID int           `json:"from_warehouse_id"`
+	ToWarehouseID   int           `json:"to_warehouse_id"`
+	UserID          int           `json:"user_id"`
+	ExportInvoiceID int           `json:"export_invoice_id"`
+	ImportInvoiceID int           `json:"import_invoice_id"`
+	CreatedAt       time.Time     `json:"created_at"`
+	Details         []InvoiceItem `json:"details"`
+	ExportInvoice   *Invoice      `json:"export_invoice,omitempty"`
+	ImportInvoice   *Invoice      `json:"import_invoice,omitempty"`
+}
+
+// Invoices build the export invoice of the source warehouse and the import invoice of the destination warehouse,
+// a transfer is an internal move so the invoices have no partner and the lines are priced at the cost of the stock
+func (t *Transfer) Invoices() (*Invoice, *Invoice) {
+	exInvoice := &Invoice{
+		WarehouseID: t.FromWarehouseID,
+		UserID:      t.UserID,
+		Details:     t.Details,
+	}
+	exInvoice.CalcTotalPrice()
+
+	imInvoice := &Invoice{
+		WarehouseID: t.ToWarehouseID,
+		UserID:      t.UserID,
+		Details:     t.Details,
+	}
+	imInvoice.CalcTotalPrice()
+
+	return exInvoice, imInvoice
+}
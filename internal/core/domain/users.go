@@ -3,8 +3,11 @@ package domain
 type Role string
 
 const (
-	Root   Role = "root"
-	Member Role = "member"
+	Root             Role = "root"
+	Member           Role = "member"
+	WarehouseManager Role = "warehouse_manager"
+	Accountant       Role = "accountant"
+	Viewer           Role = "viewer"
 )
 
 type User struct {
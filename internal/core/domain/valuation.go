@@ -0,0 +1,165 @@
+package domain
+
+import "time"
+
+// ValuationMethod is how the cost of stock leaving a warehouse is measured
+type ValuationMethod string
+
+const (
+	// ValuationFIFO cost exports with the oldest imported stock first
+	ValuationFIFO ValuationMethod = "fifo"
+	// ValuationWeightedAverage cost exports with the average cost of the stock on hand
+	ValuationWeightedAverage ValuationMethod = "weighted_average"
+)
+
+// IsValid report whether the valuation method is a defined method
+func (m ValuationMethod) IsValid() bool {
+	switch m {
+	case ValuationFIFO, ValuationWeightedAverage:
+		return true
+	default:
+		return false
+	}
+}
+
+// StockLine is a line of a completed invoice of a warehouse, Quantity is negative for exports
+type StockLine struct {
+	InvoiceID int
+	RiceID    int
+	RiceName  string
+	Quantity  int
+	Price     float64
+	Transfer  bool
+	// CreatedAt is when the invoice was completed, the approval time for invoices that waited for approval
+	CreatedAt time.Time
+}
+
+// InventoryValue is the quantity and cost basis of a rice in a warehouse
+type InventoryValue struct {
+	RiceID   int     `json:"rice_id"`
+	RiceName string  `json:"rice_name"`
+	Quantity int     `json:"quantity"`
+	UnitCost float64 `json:"unit_cost"`
+	Value    float64 `json:"value"`
+}
+
+// ExportCost is the revenue and cost of goods sold of an export invoice
+type ExportCost struct {
+	InvoiceID int       `json:"invoice_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Revenue   float64   `json:"revenue"`
+	COGS      float64   `json:"cogs"`
+}
+
+// RiceMargin is the gross margin of a rice over a period
+type RiceMargin struct {
+	RiceID   int     `json:"rice_id"`
+	RiceName string  `json:"rice_name"`
+	Quantity int     `json:"quantity"`
+	Revenue  float64 `json:"revenue"`
+	COGS     float64 `json:"cogs"`
+	Margin   float64 `json:"margin"`
+}
+
+// GrossMarginReport is the gross margin of the sales of a warehouse over a period, transfers are not sales
+type GrossMarginReport struct {
+	Method  ValuationMethod `json:"method"`
+	Revenue float64         `json:"revenue"`
+	COGS    float64         `json:"cogs"`
+	Margin  float64         `json:"margin"`
+	Rice    []RiceMargin    `json:"rice"`
+	Exports []ExportCost    `json:"exports"`
+}
+
+// costLayer is a quantity of stock bought at the same unit cost
+type costLayer struct {
+	quantity int
+	unitCost float64
+}
+
+// CostBook keep the cost layers of the stock of a warehouse while its invoice lines are replayed in order,
+// a weighted average book always hold a single layer per rice
+type CostBook struct {
+	method ValuationMethod
+	layers map[int][]costLayer
+}
+
+func NewCostBook(method ValuationMethod) *CostBook {
+	return &CostBook{
+		method: method,
+		layers: make(map[int][]costLayer),
+	}
+}
+
+// Import add quantity of rice bought at unitCost to the book
+func (b *CostBook) Import(riceID, quantity int, unitCost float64) {
+	if quantity <= 0 {
+		return
+	}
+
+	layers := b.layers[riceID]
+	if b.method == ValuationWeightedAverage && len(layers) > 0 {
+		l := layers[0]
+		total := l.quantity + quantity
+		layers[0] = costLayer{
+			quantity: total,
+			unitCost: (float64(l.quantity)*l.unitCost + float64(quantity)*unitCost) / float64(total),
+		}
+		return
+	}
+
+	b.layers[riceID] = append(layers, costLayer{quantity: quantity, unitCost: unitCost})
+}
+
+// Export remove quantity of rice from the book and return its cost,
+// quantity over the stock on hand is costed at the last known unit cost
+func (b *CostBook) Export(riceID, quantity int) float64 {
+	layers := b.layers[riceID]
+	cost := 0.0
+	lastCost := 0.0
+
+	for quantity > 0 && len(layers) > 0 {
+		l := &layers[0]
+		lastCost = l.unitCost
+
+		taken := min(quantity, l.quantity)
+		cost += float64(taken) * l.unitCost
+		quantity -= taken
+		l.quantity -= taken
+
+		if l.quantity == 0 {
+			layers = layers[1:]
+		}
+	}
+	cost += float64(quantity) * lastCost
+
+	b.layers[riceID] = layers
+	return cost
+}
+
+// Quantity return the stock on hand of rice
+func (b *CostBook) Quantity(riceID int) int {
+	quantity := 0
+	for _, l := range b.layers[riceID] {
+		quantity += l.quantity
+	}
+	return quantity
+}
+
+// UnitCost return the average unit cost of the stock on hand of rice, 0 when there is none
+func (b *CostBook) UnitCost(riceID int) float64 {
+	quantity := b.Quantity(riceID)
+	if quantity == 0 {
+		return 0
+	}
+	return b.Value(riceID) / float64(quantity)
+}
+
+// Value return the cost basis of the stock on hand of rice
+func (b *CostBook) Value(riceID int) float64 {
+	value := 0.0
+	for _, l := range b.layers[riceID] {
+		value += float64(l.quantity) * l.unitCost
+	}
+	return value
+}
@@ -9,6 +9,21 @@ type WarehouseItem struct {
 	RiceID   int   `json:"rice_id"`
 	Rice     *Rice `json:"rice,omitempty"`
 	Quantity int   `json:"quantity"`
+	// Reserved is the quantity held by confirmed sales orders
+	Reserved int `json:"reserved"`
+}
+
+// Available return the available-to-promise quantity, the stock not held by sales orders
+func (w *WarehouseItem) Available() int {
+	return w.Quantity - w.Reserved
+}
+
+// StockDrift is a difference between the stock balance ledger and the invoice history
+type StockDrift struct {
+	WarehouseID      int `json:"warehouse_id"`
+	RiceID           int `json:"rice_id"`
+	LedgerQuantity   int `json:"ledger_quantity"`
+	ExpectedQuantity int `json:"expected_quantity"`
 }
 
 type Warehouse struct {
@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookEvent is the kind of a change posted to webhooks
+type WebhookEvent string
+
+const (
+	WebhookImportInvoiceCreated   WebhookEvent = "import_invoice.created"
+	WebhookImportInvoiceCancelled WebhookEvent = "import_invoice.cancelled"
+	WebhookImportInvoiceApproved  WebhookEvent = "import_invoice.approved"
+	WebhookImportInvoiceRejected  WebhookEvent = "import_invoice.rejected"
+	WebhookExportInvoiceCreated   WebhookEvent = "export_invoice.created"
+	WebhookExportInvoiceCancelled WebhookEvent = "export_invoice.cancelled"
+	WebhookExportInvoiceApproved  WebhookEvent = "export_invoice.approved"
+	WebhookExportInvoiceRejected  WebhookEvent = "export_invoice.rejected"
+	// WebhookStockChanged is emitted with every invoice and transfer that moves stock
+	WebhookStockChanged     WebhookEvent = "stock.changed"
+	WebhookCustomerCreated  WebhookEvent = "customer.created"
+	WebhookCustomerUpdated  WebhookEvent = "customer.updated"
+	WebhookCustomerDeleted  WebhookEvent = "customer.deleted"
+	WebhookRiceCreated      WebhookEvent = "rice.created"
+	WebhookRiceUpdated      WebhookEvent = "rice.updated"
+	WebhookRiceDeleted      WebhookEvent = "rice.deleted"
+	WebhookWarehouseCreated WebhookEvent = "warehouse.created"
+	WebhookWarehouseUpdated WebhookEvent = "warehouse.updated"
+	WebhookWarehouseDeleted WebhookEvent = "warehouse.deleted"
+)
+
+// Webhook is an url that changes are posted to, the payloads are signed with Secret
+type Webhook struct {
+	ID        int            `json:"id"`
+	URL       string         `json:"url"`
+	Secret    string         `json:"-"`
+	Events    []WebhookEvent `json:"events"`
+	Active    bool           `json:"active"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// Subscribed report whether the webhook is active and subscribed to the event
+func (w *Webhook) Subscribed(event WebhookEvent) bool {
+	if !w.Active {
+		return false
+	}
+
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// OutboxStatus is the delivery state of an outbox message
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "pending"
+	OutboxDelivered OutboxStatus = "delivered"
+	// OutboxDead is a message that failed every attempt, it is only sent again when retried by hand
+	OutboxDead OutboxStatus = "dead"
+)
+
+// OutboxMessage is a payload waiting to be delivered to a webhook
+type OutboxMessage struct {
+	ID            int             `json:"id"`
+	WebhookID     int             `json:"webhook_id"`
+	EventID       string          `json:"event_id"`
+	Event         WebhookEvent    `json:"event"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        OutboxStatus    `json:"status"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	LastError     string          `json:"last_error,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty"`
+	Webhook       *Webhook        `json:"webhook,omitempty"`
+}
+
+// OutboxFilter is the filter of the outbox list, zero fields are ignored
+type OutboxFilter struct {
+	WebhookID int
+	Status    OutboxStatus
+}
+
+// WebhookPayload is the body posted to webhooks, ID is the same for every webhook an event is posted to
+type WebhookPayload struct {
+	ID        string       `json:"id"`
+	Event     WebhookEvent `json:"event"`
+	CreatedAt time.Time    `json:"created_at"`
+	Data      any          `json:"data"`
+}
+
+// StockChange is the change of the stock of a rice, negative when the stock goes down
+type StockChange struct {
+	RiceID   int `json:"rice_id"`
+	Quantity int `json:"quantity"`
+}
+
+// StockChanged is the data of a stock.changed event
+type StockChanged struct {
+	WarehouseID int           `json:"warehouse_id"`
+	Source      string        `json:"source"`
+	SourceID    int           `json:"source_id"`
+	Changes     []StockChange `json:"changes"`
+}
+
+// NewStockChanged create the stock change of an invoice, sign is 1 for stock coming in and -1 for stock going out
+func NewStockChanged(source string, invoice *Invoice, sign int) *StockChanged {
+	changes := []StockChange{}
+	index := map[int]int{}
+	for _, v := range invoice.Details {
+		i, ok := index[v.RiceID]
+		if !ok {
+			i = len(changes)
+			index[v.RiceID] = i
+			changes = append(changes, StockChange{RiceID: v.RiceID})
+		}
+		changes[i].Quantity += sign * v.Quantity
+	}
+
+	return &StockChanged{
+		WarehouseID: invoice.WarehouseID,
+		Source:      source,
+		SourceID:    invoice.ID,
+		Changes:     changes,
+	}
+}
+
+// deleted is the data of a delete event
+type deleted struct {
+	ID int `json:"id"`
+}
+
+// NewDeleted create the data of a delete event
+func NewDeleted(id int) any {
+	return deleted{ID: id}
+}
+
+// WebhookBackoff return how long to wait after the attempts-th failed attempt,
+// the wait doubles after each attempt from base up to max
+func WebhookBackoff(attempts int, base, max time.Duration) time.Duration {
+	wait := base
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= max {
+			return max
+		}
+	}
+	if wait > max {
+		return max
+	}
+	return wait
+}
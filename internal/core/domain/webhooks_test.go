@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookBackoff(t *testing.T) {
+	base, max := 30*time.Second, 10*time.Minute
+
+	assert.Equal(t, 30*time.Second, WebhookBackoff(1, base, max))
+	assert.Equal(t, time.Minute, WebhookBackoff(2, base, max))
+	assert.Equal(t, 4*time.Minute, WebhookBackoff(4, base, max))
+	assert.Equal(t, max, WebhookBackoff(6, base, max))
+	assert.Equal(t, max, WebhookBackoff(100, base, max))
+}
+
+func TestWebhookSubscribed(t *testing.T) {
+	hook := &Webhook{Active: true, Events: []WebhookEvent{WebhookStockChanged, WebhookRiceCreated}}
+
+	assert.True(t, hook.Subscribed(WebhookStockChanged))
+	assert.False(t, hook.Subscribed(WebhookCustomerCreated))
+
+	hook.Active = false
+	assert.False(t, hook.Subscribed(WebhookStockChanged))
+}
+
+func TestNewStockChanged(t *testing.T) {
+	invoice := &Invoice{
+		ID:          7,
+		WarehouseID: 2,
+		Details: []InvoiceItem{
+			{RiceID: 1, Quantity: 10},
+			{RiceID: 3, Quantity: 5},
+			{RiceID: 1, Quantity: 4},
+		},
+	}
+
+	changed := NewStockChanged("export_invoice", invoice, -1)
+
+	assert.Equal(t, 2, changed.WarehouseID)
+	assert.Equal(t, 7, changed.SourceID)
+	assert.Equal(t, []StockChange{{RiceID: 1, Quantity: -14}, {RiceID: 3, Quantity: -5}}, changed.Changes)
+}
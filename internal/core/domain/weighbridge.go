@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WeighbridgeReferencePrefix is put in front of the warehouse id and weighbridge ticket to get the reference of its invoice,
+// tickets are numbered by each weighbridge so the same ticket can be posted once in every warehouse
+const WeighbridgeReferencePrefix = "weighbridge:"
+
+// Weighing is a truck weighing of a weighbridge file, it is posted as an invoice with one line.
+// Partner and Rice are an id or a name
+type Weighing struct {
+	Ticket   string
+	Kind     InvoiceKind
+	Partner  string
+	Rice     string
+	Quantity int
+	Price    float64
+}
+
+// Reference return the reference of the invoice posted from the weighing in the warehouse, weighbridge:<warehouse_id>:<ticket>
+func (w *Weighing) Reference(warehouseID int) string {
+	return WeighbridgeReferencePrefix + strconv.Itoa(warehouseID) + ":" + w.Ticket
+}
+
+// TicketOf return the weighbridge ticket of an invoice reference, ok is false for other references
+func TicketOf(reference string) (ticket string, ok bool) {
+	rest, ok := strings.CutPrefix(reference, WeighbridgeReferencePrefix)
+	if !ok {
+		return "", false
+	}
+
+	warehouse, ticket, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", false
+	}
+	if _, err := strconv.Atoi(warehouse); err != nil {
+		return "", false
+	}
+	return ticket, true
+}
+
+// PostedWeighing is a weighing posted as an invoice, by this file or an earlier upload
+type PostedWeighing struct {
+	Line      int           `json:"line,omitempty"`
+	Ticket    string        `json:"ticket"`
+	Kind      InvoiceKind   `json:"kind"`
+	InvoiceID int           `json:"invoice_id"`
+	Status    InvoiceStatus `json:"status"`
+}
+
+// WeighbridgeResult is the outcome of a weighbridge file. Every valid row is posted on its own,
+// a row whose ticket is already posted is skipped so the same file can be uploaded again
+type WeighbridgeResult struct {
+	Total   int              `json:"total"`
+	Posted  []PostedWeighing `json:"posted"`
+	Skipped []PostedWeighing `json:"skipped"`
+	Errors  []ImportRowError `json:"errors"`
+}
+
+// SortErrors order the errors by line, the errors of one line keep their order
+func (r *WeighbridgeResult) SortErrors() {
+	sort.SliceStable(r.Errors, func(i, j int) bool {
+		return r.Errors[i].Line < r.Errors[j].Line
+	})
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeighingReference(t *testing.T) {
+	w := Weighing{Ticket: "T-2409:12"}
+
+	reference := w.Reference(3)
+	assert.Equal(t, "weighbridge:3:T-2409:12", reference)
+	assert.NotEqual(t, reference, w.Reference(4))
+
+	ticket, ok := TicketOf(reference)
+	assert.True(t, ok)
+	assert.Equal(t, "T-2409:12", ticket)
+
+	for _, v := range []string{"", "PO-12", "weighbridge:T1", "weighbridge:x:T1"} {
+		_, ok := TicketOf(v)
+		assert.False(t, ok, v)
+	}
+}
@@ -1,21 +1,32 @@
 package ports
 
-import "context"
+import (
+	"context"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
 
 type IAccessControlRepository interface {
-	// HasAccess check if user has access
-	HasAccess(ctx context.Context, warehouseID int, userID int) error
-	// SetAccess set access for user
-	SetAccess(ctx context.Context, warehouseID int, userID int) error
-	// DelAccess remove user access
+	// GetAccessLevel get the access level of the user on the warehouse, return ErrForbidden if the user has no access
+	GetAccessLevel(ctx context.Context, warehouseID int, userID int) (domain.AccessLevel, error)
+	// SetAccess set access for user, return ErrDataNotFound if the user or warehouse does not exist
+	// and ErrConflictingData if the user already has access
+	SetAccess(ctx context.Context, warehouseID int, userID int, level domain.AccessLevel) error
+	// UpdateAccess change the access level of user, return ErrDataNotFound if the user has no access
+	UpdateAccess(ctx context.Context, warehouseID int, userID int, level domain.AccessLevel) error
+	// DelAccess remove user access, return ErrDataNotFound if the user has no access
 	DelAccess(ctx context.Context, warehouseID int, userID int) error
 }
 
 type IAccessControlService interface {
-	// HasAccess check if user has access
-	HasAccess(ctx context.Context, warehouseID int, userID int) error
+	// GetAccessLevel get the access level of the user on the warehouse
+	GetAccessLevel(ctx context.Context, warehouseID int, userID int) (domain.AccessLevel, error)
+	// HasAccess check if user has access to do the action on the warehouse
+	HasAccess(ctx context.Context, warehouseID int, userID int, action domain.AccessAction) error
 	// SetAccess set access for user
-	SetAccess(ctx context.Context, warehouseID int, userID int) error
+	SetAccess(ctx context.Context, warehouseID int, userID int, level domain.AccessLevel) error
+	// UpdateAccess change the access level of user
+	UpdateAccess(ctx context.Context, warehouseID int, userID int, level domain.AccessLevel) error
 	// DelAccess remove user access
 	DelAccess(ctx context.Context, warehouseID int, userID int) error
 }
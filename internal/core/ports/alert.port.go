@@ -0,0 +1,52 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type IAlertRepository interface {
+	// SetThreshold insert or update the stock threshold of a rice in a warehouse
+	SetThreshold(ctx context.Context, threshold *domain.StockThreshold) (*domain.StockThreshold, error)
+	// GetThresholds select the stock thresholds of a warehouse
+	GetThresholds(ctx context.Context, warehouseID int) ([]domain.StockThreshold, error)
+	// DeleteThreshold delete the stock threshold of a rice in a warehouse
+	DeleteThreshold(ctx context.Context, warehouseID, riceID int) error
+	// GetLowStock select every rice whose stock is below its threshold
+	GetLowStock(ctx context.Context) ([]domain.LowStock, error)
+	// GetNearCapacity select every warehouse whose used capacity is at least ratio of its capacity
+	GetNearCapacity(ctx context.Context, ratio float64) ([]domain.WarehouseUsage, error)
+	// GetExpiringLots select the lots still in stock that expire before the time
+	GetExpiringLots(ctx context.Context, before time.Time) ([]domain.Lot, error)
+	// CreateAlert insert an alert unless an open alert with the same key exists, it report whether the alert was created
+	CreateAlert(ctx context.Context, alert *domain.Alert) (bool, error)
+	// CountAlerts count alerts
+	CountAlerts(ctx context.Context, filter domain.AlertFilter) (int64, error)
+	// GetListAlerts select a list of alerts, newest first
+	GetListAlerts(ctx context.Context, filter domain.AlertFilter, limit, skip int) ([]domain.Alert, error)
+	// GetAlertByID select an alert by id
+	GetAlertByID(ctx context.Context, id int) (*domain.Alert, error)
+	// AcknowledgeAlert mark an open alert as acknowledged by a user
+	AcknowledgeAlert(ctx context.Context, id, userID int) (*domain.Alert, error)
+}
+
+type IAlertService interface {
+	// SetThreshold set the minimum stock of a rice in a warehouse
+	SetThreshold(ctx context.Context, threshold *domain.StockThreshold) (*domain.StockThreshold, error)
+	// GetThresholds get the stock thresholds of a warehouse
+	GetThresholds(ctx context.Context, warehouseID int) ([]domain.StockThreshold, error)
+	// DeleteThreshold remove the stock threshold of a rice in a warehouse
+	DeleteThreshold(ctx context.Context, warehouseID, riceID int) error
+	// CheckAlerts raise an alert for every low stock, near-capacity warehouse and expiring lot, it return the new alerts
+	CheckAlerts(ctx context.Context) ([]domain.Alert, error)
+	// CountAlerts count alerts
+	CountAlerts(ctx context.Context, filter domain.AlertFilter) (int64, error)
+	// GetListAlerts get a list of alerts
+	GetListAlerts(ctx context.Context, filter domain.AlertFilter, limit, skip int) ([]domain.Alert, error)
+	// GetAlertByID get an alert by id
+	GetAlertByID(ctx context.Context, id int) (*domain.Alert, error)
+	// AcknowledgeAlert acknowledge an open alert
+	AcknowledgeAlert(ctx context.Context, id, userID int) (*domain.Alert, error)
+}
@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type IAuditRepository interface {
+	// CreateAuditLog insert a new audit log
+	CreateAuditLog(ctx context.Context, log *domain.AuditLog) error
+	// CountAuditLogs count audit logs matching the filter
+	CountAuditLogs(ctx context.Context, filter domain.AuditFilter) (int64, error)
+	// GetListAuditLogs select audit logs matching the filter, newest first
+	GetListAuditLogs(ctx context.Context, filter domain.AuditFilter, limit, skip int) ([]domain.AuditLog, error)
+}
+
+type IAuditService interface {
+	// Record write an audit log for the actor of ctx, before and after are snapshots of the entity.
+	// Failing to write the log does not fail the operation, the error is logged
+	Record(ctx context.Context, action domain.AuditAction, entity domain.AuditEntity, entityID int, before, after any)
+	// CountAuditLogs count audit logs matching the filter
+	CountAuditLogs(ctx context.Context, filter domain.AuditFilter) (int64, error)
+	// GetListAuditLogs get audit logs matching the filter, newest first
+	GetListAuditLogs(ctx context.Context, filter domain.AuditFilter, limit, skip int) ([]domain.AuditLog, error)
+}
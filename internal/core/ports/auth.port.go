@@ -2,26 +2,53 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/tommjj/ql-kho-lua/internal/core/domain"
 )
 
-type IKeyRepository interface {
-	// SetKey set the key for the user
-	SetKey(ctx context.Context, id int, key string) error
-	// GetKey get key by user id
-	GetKey(ctx context.Context, id int) (string, error)
-	// DelKey delete key
-	DelKey(ctx context.Context, id int) error
+type ISessionRepository interface {
+	// CreateSession create an new session
+	CreateSession(ctx context.Context, session *domain.Session) (*domain.Session, error)
+	// GetSessionByKey get an unexpired session by key
+	GetSessionByKey(ctx context.Context, key string) (*domain.Session, error)
+	// GetSessionsByUserID get unexpired sessions of the user
+	GetSessionsByUserID(ctx context.Context, userID int) ([]domain.Session, error)
+	// UpdateLastSeen set the last seen time of the session
+	UpdateLastSeen(ctx context.Context, id int, lastSeenAt time.Time) error
+	// SwapRefreshKey replace the refresh key of the session only if it still equals oldRefreshKey
+	SwapRefreshKey(ctx context.Context, id int, oldRefreshKey, newRefreshKey string, expiresAt time.Time) error
+	// DeleteSession delete a session of the user
+	DeleteSession(ctx context.Context, userID, id int) error
+	// DeleteExpiredSessions delete the sessions that expire before a time and return how many were deleted
+	DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error)
 }
 
 type IAuthService interface {
-	Login(ctx context.Context, email, password string) (string, error)
+	// Login check credentials, start a new session and return a new access token and refresh token
+	Login(ctx context.Context, email, password, userAgent, ip string) (*domain.AuthToken, error)
+	// Refresh rotate the refresh token and return a new token pair
+	Refresh(ctx context.Context, refreshToken string) (*domain.AuthToken, error)
+	// Logout revoke the session of the token payload
+	Logout(ctx context.Context, payload *domain.TokenPayload) error
+	// GetSessions get active sessions of the user
+	GetSessions(ctx context.Context, userID int) ([]domain.Session, error)
+	// RevokeSession revoke a session of the user
+	RevokeSession(ctx context.Context, userID, sessionID int) error
+	// DeleteExpiredSessions delete the expired sessions and return how many were deleted
+	DeleteExpiredSessions(ctx context.Context) (int64, error)
 }
 
 type ITokenService interface {
-	// CreateToken create an new token
-	CreateToken(user *domain.User) (string, error)
+	// CreateToken start a new session and create an access token with a refresh token
+	CreateToken(user *domain.User, userAgent, ip string) (*domain.AuthToken, error)
 	// VerifyToken verify string token
 	VerifyToken(token string) (*domain.TokenPayload, error)
+	// VerifyRefreshToken verify string refresh token
+	VerifyRefreshToken(refreshToken string) (*domain.TokenPayload, error)
+	// RotateToken replace the refresh token of the payload with a new token pair,
+	// the session is revoked if the refresh token has already been used
+	RotateToken(user *domain.User, payload *domain.TokenPayload) (*domain.AuthToken, error)
+	// RevokeToken revoke the session of the token payload
+	RevokeToken(payload *domain.TokenPayload) error
 }
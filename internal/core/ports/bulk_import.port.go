@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type IBulkImportRepository interface {
+	// CreateRiceBatch insert the rice in one transaction, each insert is checked against the unique indexes and the rows
+	// before it. The error of a row is at its index, nil when it was inserted, and the transaction is rolled back
+	// unless commit is set and every row was inserted
+	CreateRiceBatch(ctx context.Context, rice []domain.Rice, commit bool) ([]domain.Rice, []error, error)
+	// CreateCustomerBatch insert the customers in one transaction like CreateRiceBatch
+	CreateCustomerBatch(ctx context.Context, customers []domain.Customer, commit bool) ([]domain.Customer, []error, error)
+	// CreateWarehouseBatch insert the warehouses in one transaction like CreateRiceBatch
+	CreateWarehouseBatch(ctx context.Context, warehouses []domain.Warehouse, commit bool) ([]domain.Warehouse, []error, error)
+}
+
+type IBulkImportService interface {
+	// ImportRice check the valid rows against the existing rice and each other and create them in one transaction,
+	// nothing is created on a dry run or when a row has an error
+	ImportRice(ctx context.Context, rows []domain.ImportRow[domain.Rice], dryRun bool) (*domain.ImportResult[domain.Rice], error)
+	// ImportCustomers check and create customers like ImportRice, a customer without role can supply and buy
+	ImportCustomers(ctx context.Context, rows []domain.ImportRow[domain.Customer], dryRun bool) (*domain.ImportResult[domain.Customer], error)
+	// ImportWarehouses check and create warehouses like ImportRice, the uploaded images are saved only when the rows are created
+	ImportWarehouses(ctx context.Context, rows []domain.ImportRow[domain.Warehouse], dryRun bool) (*domain.ImportResult[domain.Warehouse], error)
+}
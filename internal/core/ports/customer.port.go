@@ -11,10 +11,12 @@ type ICustomerRepository interface {
 	CreateCustomer(ctx context.Context, customer *domain.Customer) (*domain.Customer, error)
 	// GetCustomerByID select a customer by id
 	GetCustomerByID(ctx context.Context, id int) (*domain.Customer, error)
-	// CountCustomers count customer
-	CountCustomers(ctx context.Context, query string) (int64, error)
-	// GetListCustomers select a customer
-	GetListCustomers(ctx context.Context, query string, limit, skip int) ([]domain.Customer, error)
+	// CountCustomers count customer, an empty role counts every partner
+	CountCustomers(ctx context.Context, query string, role domain.PartnerRole) (int64, error)
+	// GetListCustomers select a customer, a role selects the partners that can act as it
+	GetListCustomers(ctx context.Context, query string, role domain.PartnerRole, limit, skip int) ([]domain.Customer, error)
+	// GetListCustomersAfter select up to limit customers with an id greater than afterID in id order
+	GetListCustomersAfter(ctx context.Context, query string, role domain.PartnerRole, afterID, limit int) ([]domain.Customer, error)
 	// UpdateCustomer update a customer, only update non-zero fields by default
 	UpdateCustomer(ctx context.Context, customer *domain.Customer) (*domain.Customer, error)
 	// DeleteCustomer delete a customer
@@ -22,14 +24,16 @@ type ICustomerRepository interface {
 }
 
 type ICustomerService interface {
-	// CreateCustomer create a new customer
+	// CreateCustomer create a new customer, a partner without role is both supplier and customer
 	CreateCustomer(ctx context.Context, customer *domain.Customer) (*domain.Customer, error)
 	// GetCustomerByID get a customer by id
 	GetCustomerByID(ctx context.Context, id int) (*domain.Customer, error)
-	// CountCustomers count customer
-	CountCustomers(ctx context.Context, query string) (int64, error)
-	// GetListCustomers get a list customers
-	GetListCustomers(ctx context.Context, query string, limit, skip int) ([]domain.Customer, error)
+	// CountCustomers count customer, an empty role counts every partner
+	CountCustomers(ctx context.Context, query string, role domain.PartnerRole) (int64, error)
+	// GetListCustomers get a list customers, a role selects the partners that can act as it
+	GetListCustomers(ctx context.Context, query string, role domain.PartnerRole, limit, skip int) ([]domain.Customer, error)
+	// GetListCustomersAfter select up to limit customers with an id greater than afterID in id order
+	GetListCustomersAfter(ctx context.Context, query string, role domain.PartnerRole, afterID, limit int) ([]domain.Customer, error)
 	// UpdateCustomer update a customer, only update non-zero fields by default
 	UpdateCustomer(ctx context.Context, customer *domain.Customer) (*domain.Customer, error)
 	// DeleteCustomer delete a customer
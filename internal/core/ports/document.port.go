@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"io"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+// IInvoiceRenderer render printable invoices
+type IInvoiceRenderer interface {
+	// RenderInvoice write the PDF of an invoice with its associations to w
+	RenderInvoice(w io.Writer, kind domain.InvoiceKind, invoice *domain.Invoice) error
+}
@@ -10,6 +10,12 @@ import (
 type IExportInvoiceRepository interface {
 	// CreateExInvoice create a new export invoice
 	CreateExInvoice(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error)
+	// CancelExInvoice mark a completed invoice as cancelled with a reason
+	CancelExInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error)
+	// ApproveExInvoice mark an invoice waiting for approval as completed by the approver and apply its stock movement
+	ApproveExInvoice(ctx context.Context, id int, approverID int) (*domain.Invoice, error)
+	// RejectExInvoice mark an invoice waiting for approval as rejected with a reason
+	RejectExInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error)
 	// GetExInvoiceByID select a invoice by id
 	GetExInvoiceByID(ctx context.Context, id int) (*domain.Invoice, error)
 	// GetExInvoiceWithAssociationsByID select a invoice with user, warehouse, customer, rice by id
@@ -18,15 +24,27 @@ type IExportInvoiceRepository interface {
 	CountExInvoices(ctx context.Context, warehouseID int, start *time.Time, end *time.Time) (int64, error)
 	// GetListExInvoices select invoices
 	GetListExInvoices(ctx context.Context, warehouseID int, start *time.Time, end *time.Time, skip, limit int) ([]domain.Invoice, error)
+	// GetListExInvoicesWithAssociations select up to limit invoices with an id greater than afterID in id order,
+	// with user, warehouse, customer and the rice of their details
+	GetListExInvoicesWithAssociations(ctx context.Context, warehouseID int, start *time.Time, end *time.Time, afterID, limit int) ([]domain.Invoice, error)
 }
 
 type IExportInvoiceService interface {
 	// CreateExInvoice create a new export invoice
 	CreateExInvoice(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error)
+	// CancelExInvoice cancel an invoice and roll back its stock movement
+	CancelExInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error)
+	// ApproveExInvoice approve an invoice waiting for approval, its stock movement is checked again and applied
+	ApproveExInvoice(ctx context.Context, id int, approverID int) (*domain.Invoice, error)
+	// RejectExInvoice reject an invoice waiting for approval, it never moves stock
+	RejectExInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error)
 	// GetExInvoiceByID select a invoice by id
 	GetExInvoiceByID(ctx context.Context, id int) (*domain.Invoice, error)
 	// CountExInvoices
 	CountExInvoices(ctx context.Context, warehouseID int, start *time.Time, end *time.Time) (int64, error)
 	// GetListExInvoices select invoices
 	GetListExInvoices(ctx context.Context, warehouseID int, start *time.Time, end *time.Time, skip, limit int) ([]domain.Invoice, error)
+	// GetListExInvoicesWithAssociations select up to limit invoices with an id greater than afterID in id order,
+	// with user, warehouse, customer and the rice of their details
+	GetListExInvoicesWithAssociations(ctx context.Context, warehouseID int, start *time.Time, end *time.Time, afterID, limit int) ([]domain.Invoice, error)
 }
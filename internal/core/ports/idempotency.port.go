@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type IIdempotencyRepository interface {
+	// CreateIdempotencyRecord insert a new record, domain.ErrConflictingData when the user already has the key
+	CreateIdempotencyRecord(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error)
+	// GetIdempotencyRecord select the record of the key sent by the user
+	GetIdempotencyRecord(ctx context.Context, userID int, key string) (*domain.IdempotencyRecord, error)
+	// CompleteIdempotencyRecord store the response of the request of a record
+	CompleteIdempotencyRecord(ctx context.Context, id int, statusCode int, response []byte) error
+	// DeleteIdempotencyRecord delete a record by id
+	DeleteIdempotencyRecord(ctx context.Context, id int) error
+	// DeleteExpiredIdempotencyRecords delete the records that expire before a time and return how many were deleted
+	DeleteExpiredIdempotencyRecords(ctx context.Context, before time.Time) (int64, error)
+}
+
+type IIdempotencyService interface {
+	// Begin claim the key for a request of the user. The record is returned completed when the same request
+	// was already answered, then its response is to be replayed. domain.ErrIdempotencyKeyReused when the key
+	// was used with a different request and domain.ErrIdempotencyInProgress when that request is still running
+	Begin(ctx context.Context, userID int, key string, fingerprint string) (*domain.IdempotencyRecord, error)
+	// Complete store the response of a claimed key so it is replayed until the key expires
+	Complete(ctx context.Context, record *domain.IdempotencyRecord, statusCode int, response []byte) error
+	// Release free a claimed key without a response, the request can be sent again with it
+	Release(ctx context.Context, record *domain.IdempotencyRecord) error
+	// DeleteExpired delete the expired keys and return how many were deleted
+	DeleteExpired(ctx context.Context) (int64, error)
+}
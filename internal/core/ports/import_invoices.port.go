@@ -10,6 +10,12 @@ import (
 type IImportInvoicesRepository interface {
 	// CreateImInvoice create a new import invoice
 	CreateImInvoice(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error)
+	// CancelImInvoice mark a completed invoice as cancelled with a reason
+	CancelImInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error)
+	// ApproveImInvoice mark an invoice waiting for approval as completed by the approver and apply its stock movement
+	ApproveImInvoice(ctx context.Context, id int, approverID int) (*domain.Invoice, error)
+	// RejectImInvoice mark an invoice waiting for approval as rejected with a reason
+	RejectImInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error)
 	// GetImInvoiceByID select a invoice by id
 	GetImInvoiceByID(ctx context.Context, id int) (*domain.Invoice, error)
 	// GetImInvoiceWithAssociationsByID select a invoice with user, warehouse, customer, rice by id
@@ -18,15 +24,27 @@ type IImportInvoicesRepository interface {
 	CountImInvoices(ctx context.Context, warehouseID int, start *time.Time, end *time.Time) (int64, error)
 	// GetListImInvoices select invoices
 	GetListImInvoices(ctx context.Context, warehouseID int, start *time.Time, end *time.Time, skip, limit int) ([]domain.Invoice, error)
+	// GetListImInvoicesWithAssociations select up to limit invoices with an id greater than afterID in id order,
+	// with user, warehouse, customer and the rice of their details
+	GetListImInvoicesWithAssociations(ctx context.Context, warehouseID int, start *time.Time, end *time.Time, afterID, limit int) ([]domain.Invoice, error)
 }
 
 type IImportInvoicesService interface {
 	// CreateImInvoice create a new import invoice
 	CreateImInvoice(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error)
+	// CancelImInvoice cancel an invoice and roll back its stock movement
+	CancelImInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error)
+	// ApproveImInvoice approve an invoice waiting for approval, its stock movement is checked again and applied
+	ApproveImInvoice(ctx context.Context, id int, approverID int) (*domain.Invoice, error)
+	// RejectImInvoice reject an invoice waiting for approval, it never moves stock
+	RejectImInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error)
 	// GetImInvoiceByID select a invoice by id
 	GetImInvoiceByID(ctx context.Context, id int) (*domain.Invoice, error)
 	// CountImInvoices
 	CountImInvoices(ctx context.Context, warehouseID int, start *time.Time, end *time.Time) (int64, error)
 	// GetListImInvoices select invoices
 	GetListImInvoices(ctx context.Context, warehouseID int, start *time.Time, end *time.Time, skip, limit int) ([]domain.Invoice, error)
+	// GetListImInvoicesWithAssociations select up to limit invoices with an id greater than afterID in id order,
+	// with user, warehouse, customer and the rice of their details
+	GetListImInvoicesWithAssociations(ctx context.Context, warehouseID int, start *time.Time, end *time.Time, afterID, limit int) ([]domain.Invoice, error)
 }
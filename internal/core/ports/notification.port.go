@@ -0,0 +1,53 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+// INotifier deliver events through one notification channel
+type INotifier interface {
+	// Channel return the channel of the notifier
+	Channel() domain.NotificationChannel
+	// Notify deliver the event to the subscription
+	Notify(ctx context.Context, sub *domain.Subscription, event *domain.Event) error
+}
+
+type INotificationRepository interface {
+	// CreateSubscription create a subscription, return ErrConflictingData if the user already has the same subscription
+	CreateSubscription(ctx context.Context, sub *domain.Subscription) (*domain.Subscription, error)
+	// GetSubscriptionsByUser get the subscriptions of a user
+	GetSubscriptionsByUser(ctx context.Context, userID int) ([]domain.Subscription, error)
+	// GetSubscriptionsByEvent get the subscriptions to an event type with their user
+	GetSubscriptionsByEvent(ctx context.Context, eventType domain.EventType) ([]domain.Subscription, error)
+	// DeleteSubscription delete a subscription of a user, return ErrDataNotFound if the user has no such subscription
+	DeleteSubscription(ctx context.Context, id, userID int) error
+	// CreateNotification create an in-app notification
+	CreateNotification(ctx context.Context, notification *domain.Notification) error
+	// CountNotifications count the in-app notifications of a user
+	CountNotifications(ctx context.Context, userID int, unread bool) (int64, error)
+	// GetListNotifications get the in-app notifications of a user, newest first
+	GetListNotifications(ctx context.Context, userID int, unread bool, limit, skip int) ([]domain.Notification, error)
+	// ReadNotification mark a notification of a user as read, return ErrDataNotFound if the user has no such notification
+	ReadNotification(ctx context.Context, id, userID int) (*domain.Notification, error)
+	// CreateDelivery log a delivery
+	CreateDelivery(ctx context.Context, delivery *domain.Delivery) error
+}
+
+type INotificationService interface {
+	// Subscribe subscribe a user to an event type
+	Subscribe(ctx context.Context, sub *domain.Subscription) (*domain.Subscription, error)
+	// GetSubscriptions get the subscriptions of a user
+	GetSubscriptions(ctx context.Context, userID int) ([]domain.Subscription, error)
+	// Unsubscribe delete a subscription of a user
+	Unsubscribe(ctx context.Context, id, userID int) error
+	// Publish deliver the event to its subscribers and return the deliveries
+	Publish(ctx context.Context, event *domain.Event) []domain.Delivery
+	// CountNotifications count the in-app notifications of a user
+	CountNotifications(ctx context.Context, userID int, unread bool) (int64, error)
+	// GetListNotifications get the in-app notifications of a user
+	GetListNotifications(ctx context.Context, userID int, unread bool, limit, skip int) ([]domain.Notification, error)
+	// ReadNotification mark a notification of a user as read
+	ReadNotification(ctx context.Context, id, userID int) (*domain.Notification, error)
+}
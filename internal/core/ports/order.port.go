@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type IOrderRepository interface {
+	// CreateOrder insert a new order
+	CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error)
+	// GetOrderByID select an order with its associations by id
+	GetOrderByID(ctx context.Context, id int) (*domain.Order, error)
+	// CountOrders count orders
+	CountOrders(ctx context.Context, filter domain.OrderFilter) (int64, error)
+	// GetListOrders select a list of orders, newest first
+	GetListOrders(ctx context.Context, filter domain.OrderFilter, limit, skip int) ([]domain.Order, error)
+	// UpdateOrderStatus update the status of an order
+	UpdateOrderStatus(ctx context.Context, id int, status domain.OrderStatus) (*domain.Order, error)
+	// ConvertOrder insert the invoice of an order and mark the order converted in one transaction,
+	// a purchase order becomes an import invoice and a sales order an export invoice
+	ConvertOrder(ctx context.Context, order *domain.Order, invoice *domain.Invoice) (*domain.Invoice, error)
+}
+
+type IOrderService interface {
+	// CreateOrder create a draft order
+	CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error)
+	// GetOrderByID get an order by id
+	GetOrderByID(ctx context.Context, id int) (*domain.Order, error)
+	// CountOrders count orders
+	CountOrders(ctx context.Context, filter domain.OrderFilter) (int64, error)
+	// GetListOrders get a list of orders
+	GetListOrders(ctx context.Context, filter domain.OrderFilter, limit, skip int) ([]domain.Order, error)
+	// ConfirmOrder confirm a draft order, a purchase order reserves capacity and a sales order reserves stock
+	ConfirmOrder(ctx context.Context, id int) (*domain.Order, error)
+	// CancelOrder cancel a draft or confirmed order and release its reservation
+	CancelOrder(ctx context.Context, id int) (*domain.Order, error)
+	// ConvertOrder turn a confirmed order into an invoice when the goods move, it return the created invoice
+	ConvertOrder(ctx context.Context, id int, userID int) (*domain.Invoice, error)
+}
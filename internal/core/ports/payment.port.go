@@ -0,0 +1,47 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type IPaymentRepository interface {
+	// CreatePayment insert a payment and add its amount to the paid amount of the invoice in one transaction,
+	// the customer of the payment is taken from the invoice
+	CreatePayment(ctx context.Context, payment *domain.Payment) (*domain.Payment, error)
+	// GetPaymentByID select a payment with its associations and the warehouse of its invoice by id
+	GetPaymentByID(ctx context.Context, id int) (*domain.Payment, error)
+	// GetInvoiceWarehouseID select the warehouse of an invoice of a kind
+	GetInvoiceWarehouseID(ctx context.Context, kind domain.InvoiceKind, invoiceID int) (int, error)
+	// CountPayments count payments
+	CountPayments(ctx context.Context, filter domain.PaymentFilter) (int64, error)
+	// GetListPayments select a list of payments, latest first
+	GetListPayments(ctx context.Context, filter domain.PaymentFilter, limit, skip int) ([]domain.Payment, error)
+	// GetPartnerBalances sum the completed invoices of a kind and their payments by partner,
+	// invoices created by a transfer are excluded, warehouseID 0 sums every warehouse
+	GetPartnerBalances(ctx context.Context, kind domain.InvoiceKind, warehouseID int) ([]domain.PartnerBalance, error)
+	// GetOpenInvoices select the completed invoices of a kind completed up to at
+	// with a balance left after the payments made up to at, warehouseID 0 selects every warehouse
+	GetOpenInvoices(ctx context.Context, kind domain.InvoiceKind, warehouseID int, at time.Time) ([]domain.OpenInvoice, error)
+}
+
+type IPaymentService interface {
+	// CreatePayment record a full or partial payment of an invoice
+	CreatePayment(ctx context.Context, payment *domain.Payment) (*domain.Payment, error)
+	// GetPaymentByID get a payment by id
+	GetPaymentByID(ctx context.Context, id int) (*domain.Payment, error)
+	// GetInvoiceWarehouseID get the warehouse of an invoice of a kind
+	GetInvoiceWarehouseID(ctx context.Context, kind domain.InvoiceKind, invoiceID int) (int, error)
+	// CountPayments count payments
+	CountPayments(ctx context.Context, filter domain.PaymentFilter) (int64, error)
+	// GetListPayments get a list of payments
+	GetListPayments(ctx context.Context, filter domain.PaymentFilter, limit, skip int) ([]domain.Payment, error)
+	// GetBalances get the invoiced, paid and outstanding amounts of every partner,
+	// export invoices are receivable from customers and import invoices payable to suppliers, warehouseID 0 is every warehouse
+	GetBalances(ctx context.Context, kind domain.InvoiceKind, warehouseID int) ([]domain.PartnerBalance, error)
+	// GetAging get the outstanding balances of every partner at a time
+	// split in 0-30, 31-60, 61-90 and over 90 days buckets, warehouseID 0 is every warehouse
+	GetAging(ctx context.Context, kind domain.InvoiceKind, warehouseID int, at time.Time) (*domain.AgingReport, error)
+}
@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type IReportRepository interface {
+	// GetStockMovement compute opening, imported, exported and closing quantity of every rice in a warehouse
+	// from the completed invoices between start and end
+	GetStockMovement(ctx context.Context, warehouseID int, start, end time.Time) ([]domain.StockMovement, error)
+	// GetRevenue sum the completed export invoices grouped by period, transfers are not sales and are excluded
+	GetRevenue(ctx context.Context, filter domain.InvoiceAnalyticsFilter) ([]domain.InvoiceTotal, error)
+	// GetPurchases sum the completed import invoices grouped by period, transfers are not purchases and are excluded
+	GetPurchases(ctx context.Context, filter domain.InvoiceAnalyticsFilter) ([]domain.InvoiceTotal, error)
+	// GetStockLines select the lines of the completed invoices of a warehouse created until end,
+	// in the order they moved the stock
+	GetStockLines(ctx context.Context, warehouseID int, end time.Time) ([]domain.StockLine, error)
+}
+
+type IReportService interface {
+	// GetStockMovement get the stock movement of every rice in a warehouse between start and end
+	GetStockMovement(ctx context.Context, warehouseID int, start, end time.Time) ([]domain.StockMovement, error)
+	// GetRevenue get the revenue of export invoices grouped by period
+	GetRevenue(ctx context.Context, filter domain.InvoiceAnalyticsFilter) ([]domain.InvoiceTotal, error)
+	// GetPurchases get the purchase cost of import invoices grouped by period
+	GetPurchases(ctx context.Context, filter domain.InvoiceAnalyticsFilter) ([]domain.InvoiceTotal, error)
+	// GetInventoryValuation get the quantity and cost basis of every rice in a warehouse at a time
+	GetInventoryValuation(ctx context.Context, warehouseID int, method domain.ValuationMethod, at time.Time) ([]domain.InventoryValue, error)
+	// GetGrossMargin get the revenue, cost of goods sold and gross margin of the sales of a warehouse between start and end
+	GetGrossMargin(ctx context.Context, warehouseID int, method domain.ValuationMethod, start, end time.Time) (*domain.GrossMarginReport, error)
+}
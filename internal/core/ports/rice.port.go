@@ -15,6 +15,8 @@ type IRiceRepository interface {
 	CountRice(ctx context.Context, query string) (int64, error)
 	// GetListRice select a rice
 	GetListRice(ctx context.Context, query string, limit, skip int) ([]domain.Rice, error)
+	// GetListRiceAfter select up to limit rice with an id greater than afterID in id order
+	GetListRiceAfter(ctx context.Context, query string, afterID, limit int) ([]domain.Rice, error)
 	// UpdateRice update a rice, only update non-zero fields by default
 	UpdateRice(ctx context.Context, rice *domain.Rice) (*domain.Rice, error)
 	// DeleteRice delete a rice
@@ -30,6 +32,8 @@ type IRiceService interface {
 	CountRice(ctx context.Context, query string) (int64, error)
 	// GetListRice get a list rice
 	GetListRice(ctx context.Context, query string, limit, skip int) ([]domain.Rice, error)
+	// GetListRiceAfter select up to limit rice with an id greater than afterID in id order
+	GetListRiceAfter(ctx context.Context, query string, afterID, limit int) ([]domain.Rice, error)
 	// UpdateRice update a rice, only update non-zero fields by default
 	UpdateRice(ctx context.Context, rice *domain.Rice) (*domain.Rice, error)
 	// DeleteRice delete a rice by rice id
@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type IStockLedgerRepository interface {
+	// ReconcileStockBalances compare the stock balances with the invoice history and return every drift,
+	// the balances are rebuilt from the invoice history if apply is true
+	ReconcileStockBalances(ctx context.Context, apply bool) ([]domain.StockDrift, error)
+	// BackfillStockBalances build the balances from the invoice history when the ledger is empty and return
+	// the balances written, nothing is done when the ledger has any balance
+	BackfillStockBalances(ctx context.Context) ([]domain.StockDrift, error)
+}
+
+type IStockLedgerService interface {
+	// ReconcileStockBalances report the drift between stock balances and invoice history,
+	// rebuild the balances if apply is true
+	ReconcileStockBalances(ctx context.Context, apply bool) ([]domain.StockDrift, error)
+	// BackfillStockBalances build the balances of a database upgraded from before the ledger existed,
+	// it must run before stock is read or moved
+	BackfillStockBalances(ctx context.Context) ([]domain.StockDrift, error)
+}
@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type ITransferRepository interface {
+	// CreateTransfer insert the export invoice, the import invoice and the transfer record in one transaction
+	CreateTransfer(ctx context.Context, transfer *domain.Transfer) (*domain.Transfer, error)
+	// GetTransferByID select a transfer with its invoices by id
+	GetTransferByID(ctx context.Context, id int) (*domain.Transfer, error)
+	// ReverseTransfer cancel both invoices of a transfer and move its stock and lots back in one transaction
+	ReverseTransfer(ctx context.Context, id int, reason string) (*domain.Transfer, error)
+}
+
+type ITransferService interface {
+	// CreateTransfer move rice from a warehouse to another warehouse
+	CreateTransfer(ctx context.Context, transfer *domain.Transfer) (*domain.Transfer, error)
+	// GetTransferByID get a transfer by id
+	GetTransferByID(ctx context.Context, id int) (*domain.Transfer, error)
+	// ReverseTransfer move the rice of a transfer back to the source warehouse, the invoices of a transfer
+	// can only be cancelled this way
+	ReverseTransfer(ctx context.Context, id int, reason string) (*domain.Transfer, error)
+}
@@ -17,6 +17,12 @@ type IUserRepository interface {
 	CountUsers(ctx context.Context, query string) (int64, error)
 	// GetListUsers select a list users
 	GetListUsers(ctx context.Context, query string, limit, skip int) ([]domain.User, error)
+	// GetListUsersAfter select up to limit users with an id greater than afterID in id order
+	GetListUsersAfter(ctx context.Context, query string, afterID, limit int) ([]domain.User, error)
+	// CountAuthorizedUsers count users authorized to access the warehouse
+	CountAuthorizedUsers(ctx context.Context, warehouseID int, query string) (int64, error)
+	// GetAuthorizedUsers select a list users authorized to access the warehouse
+	GetAuthorizedUsers(ctx context.Context, warehouseID int, query string, limit, skip int) ([]domain.User, error)
 	// UpdateUser update a user, only update non-zero fields by default
 	UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error)
 	// DeleteUser delete a user
@@ -34,6 +40,12 @@ type IUserService interface {
 	CountUsers(ctx context.Context, query string) (int64, error)
 	// GetListUsers get a list users
 	GetListUsers(ctx context.Context, query string, limit, skip int) ([]domain.User, error)
+	// GetListUsersAfter select up to limit users with an id greater than afterID in id order
+	GetListUsersAfter(ctx context.Context, query string, afterID, limit int) ([]domain.User, error)
+	// CountAuthorizedUsers count users authorized to access the warehouse
+	CountAuthorizedUsers(ctx context.Context, warehouseID int, query string) (int64, error)
+	// GetAuthorizedUsers get a list users authorized to access the warehouse
+	GetAuthorizedUsers(ctx context.Context, warehouseID int, query string, limit, skip int) ([]domain.User, error)
 	// UpdateUser update a user, only update non-zero fields by default
 	UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error)
 	// DeleteUser delete a user
@@ -15,14 +15,20 @@ type IWarehouseRepository interface {
 	CountWarehouses(ctx context.Context, query string) (int64, error)
 	// GetListWarehouses select a list warehouse
 	GetListWarehouses(ctx context.Context, query string, limit, skip int) ([]domain.Warehouse, error)
+	// GetListWarehousesAfter select up to limit warehouses with an id greater than afterID in id order
+	GetListWarehousesAfter(ctx context.Context, query string, afterID, limit int) ([]domain.Warehouse, error)
 	// CountAuthorizedWarehouses count authorized warehouse
 	CountAuthorizedWarehouses(ctx context.Context, userID int, query string) (int64, error)
 	// GetAuthorizedWarehouses
 	GetAuthorizedWarehouses(ctx context.Context, userID int, query string, limit, skip int) ([]domain.Warehouse, error)
-	// GetUsedCapacityByID get used capacity of warehouse
+	// GetAuthorizedWarehousesAfter select up to limit warehouses of the user with an id greater than afterID in id order
+	GetAuthorizedWarehousesAfter(ctx context.Context, userID int, query string, afterID, limit int) ([]domain.Warehouse, error)
+	// GetUsedCapacityByID get used capacity of warehouse, the stock plus the capacity reserved by confirmed purchase orders
 	GetUsedCapacityByID(ctx context.Context, id int) (int64, error)
-	// GetInventory get warehouse inventory by warehouse id
+	// GetInventory get warehouse inventory by warehouse id with the stock reserved by confirmed sales orders
 	GetInventory(ctx context.Context, id int) ([]domain.WarehouseItem, error)
+	// GetInventoryByLot get the lots still in a warehouse, first-expiry-first-out
+	GetInventoryByLot(ctx context.Context, id int) ([]domain.Lot, error)
 	// UpdateWarehouse update a warehouse, only update non-zero fields by default
 	UpdateWarehouse(ctx context.Context, warehouses *domain.Warehouse) (*domain.Warehouse, error)
 	// DeleteWarehouse delete a warehouse
@@ -38,14 +44,20 @@ type IWarehouseService interface {
 	CountWarehouses(ctx context.Context, query string) (int64, error)
 	// GetListWarehouses select a list warehouses
 	GetListWarehouses(ctx context.Context, query string, limit, skip int) ([]domain.Warehouse, error)
+	// GetListWarehousesAfter select up to limit warehouses with an id greater than afterID in id order
+	GetListWarehousesAfter(ctx context.Context, query string, afterID, limit int) ([]domain.Warehouse, error)
 	// CountAuthorizedWarehouses count authorized warehouse
 	CountAuthorizedWarehouses(ctx context.Context, userID int, query string) (int64, error)
 	// GetAuthorizedWarehouses
 	GetAuthorizedWarehouses(ctx context.Context, userID int, query string, limit, skip int) ([]domain.Warehouse, error)
-	// GetUsedCapacityByID get used capacity of warehouse
+	// GetAuthorizedWarehousesAfter select up to limit warehouses of the user with an id greater than afterID in id order
+	GetAuthorizedWarehousesAfter(ctx context.Context, userID int, query string, afterID, limit int) ([]domain.Warehouse, error)
+	// GetUsedCapacityByID get used capacity of warehouse, the stock plus the capacity reserved by confirmed purchase orders
 	GetUsedCapacityByID(ctx context.Context, id int) (int64, error)
-	// GetInventory get warehouse inventory by warehouse id
+	// GetInventory get warehouse inventory by warehouse id with the stock reserved by confirmed sales orders
 	GetInventory(ctx context.Context, id int) ([]domain.WarehouseItem, error)
+	// GetInventoryByLot get the lots still in a warehouse, first-expiry-first-out
+	GetInventoryByLot(ctx context.Context, id int) ([]domain.Lot, error)
 	// UpdateWarehouse update a warehouse, only update non-zero fields by default
 	UpdateWarehouse(ctx context.Context, warehouses *domain.Warehouse) (*domain.Warehouse, error)
 	// DeleteWarehouse delete a warehouse
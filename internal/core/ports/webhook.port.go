@@ -0,0 +1,62 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+// IWebhookSender post outbox messages to webhooks
+type IWebhookSender interface {
+	// Send post the message payload to the webhook url, signed with the webhook secret
+	Send(ctx context.Context, webhook *domain.Webhook, msg *domain.OutboxMessage) error
+}
+
+type IWebhookRepository interface {
+	// CreateWebhook create a webhook
+	CreateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error)
+	// GetListWebhooks get every webhook
+	GetListWebhooks(ctx context.Context) ([]domain.Webhook, error)
+	// GetWebhookByID get a webhook by id, return ErrDataNotFound if it does not exist
+	GetWebhookByID(ctx context.Context, id int) (*domain.Webhook, error)
+	// UpdateWebhook update the url, events and active of a webhook, return ErrDataNotFound if it does not exist
+	UpdateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error)
+	// DeleteWebhook delete a webhook and its outbox messages, return ErrDataNotFound if it does not exist
+	DeleteWebhook(ctx context.Context, id int) error
+	// CreateOutboxMessages insert messages into the outbox
+	CreateOutboxMessages(ctx context.Context, msgs []domain.OutboxMessage) error
+	// GetDueMessages get pending messages whose next attempt is before now, with their webhook
+	GetDueMessages(ctx context.Context, now time.Time, limit int) ([]domain.OutboxMessage, error)
+	// UpdateOutboxMessage save the delivery state of a message
+	UpdateOutboxMessage(ctx context.Context, msg *domain.OutboxMessage) error
+	// CountOutboxMessages count the outbox messages
+	CountOutboxMessages(ctx context.Context, filter domain.OutboxFilter) (int64, error)
+	// GetListOutboxMessages get the outbox messages, newest first
+	GetListOutboxMessages(ctx context.Context, filter domain.OutboxFilter, limit, skip int) ([]domain.OutboxMessage, error)
+	// GetOutboxMessageByID get an outbox message by id, return ErrDataNotFound if it does not exist
+	GetOutboxMessageByID(ctx context.Context, id int) (*domain.OutboxMessage, error)
+}
+
+type IWebhookService interface {
+	// CreateWebhook create a webhook with a new secret
+	CreateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error)
+	// GetListWebhooks get every webhook
+	GetListWebhooks(ctx context.Context) ([]domain.Webhook, error)
+	// GetWebhookByID get a webhook by id
+	GetWebhookByID(ctx context.Context, id int) (*domain.Webhook, error)
+	// UpdateWebhook update a webhook
+	UpdateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error)
+	// DeleteWebhook delete a webhook
+	DeleteWebhook(ctx context.Context, id int) error
+	// Emit queue the event in the outbox of every webhook subscribed to it
+	Emit(ctx context.Context, event domain.WebhookEvent, data any)
+	// DeliverDue send the due outbox messages and return how many were delivered
+	DeliverDue(ctx context.Context) (int, error)
+	// CountOutboxMessages count the outbox messages
+	CountOutboxMessages(ctx context.Context, filter domain.OutboxFilter) (int64, error)
+	// GetListOutboxMessages get the outbox messages
+	GetListOutboxMessages(ctx context.Context, filter domain.OutboxFilter, limit, skip int) ([]domain.OutboxMessage, error)
+	// RetryOutboxMessage send a dead message again on the next delivery run
+	RetryOutboxMessage(ctx context.Context, id int) (*domain.OutboxMessage, error)
+}
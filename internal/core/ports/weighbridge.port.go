@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type IWeighbridgeRepository interface {
+	// GetPostedWeighings select the import and export invoices of the warehouse posted from the tickets, a ticket that is not posted is left out
+	GetPostedWeighings(ctx context.Context, warehouseID int, tickets []string) ([]domain.PostedWeighing, error)
+}
+
+type IWeighbridgeService interface {
+	// ImportWeighings post the valid weighings in file order as invoices of the warehouse created by the user, each one
+	// through the capacity and stock checks of a new invoice. A ticket that is already posted is skipped, not posted again
+	ImportWeighings(ctx context.Context, warehouseID, userID int, rows []domain.ImportRow[domain.Weighing]) (*domain.WeighbridgeResult, error)
+}
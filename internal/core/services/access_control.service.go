@@ -17,11 +17,43 @@ func NewAccessControlService(repo ports.IAccessControlRepository) ports.IAccessC
 	}
 }
 
-func (acs *accessControlService) HasAccess(ctx context.Context, warehouseID int, userID int) error {
-	err := acs.repo.HasAccess(ctx, warehouseID, userID)
+func (acs *accessControlService) GetAccessLevel(ctx context.Context, warehouseID int, userID int) (domain.AccessLevel, error) {
+	level, err := acs.repo.GetAccessLevel(ctx, warehouseID, userID)
 	if err != nil {
 		switch err {
 		case domain.ErrForbidden:
+			return "", err
+		default:
+			return "", domain.ErrInternal
+		}
+	}
+
+	return level, nil
+}
+
+func (acs *accessControlService) HasAccess(ctx context.Context, warehouseID int, userID int, action domain.AccessAction) error {
+	level, err := acs.repo.GetAccessLevel(ctx, warehouseID, userID)
+	if err != nil {
+		switch err {
+		case domain.ErrForbidden:
+			return err
+		default:
+			return domain.ErrInternal
+		}
+	}
+
+	if !level.Allows(action) {
+		return domain.ErrForbidden
+	}
+
+	return nil
+}
+
+func (acs *accessControlService) SetAccess(ctx context.Context, warehouseID int, userID int, level domain.AccessLevel) error {
+	err := acs.repo.SetAccess(ctx, warehouseID, userID, level)
+	if err != nil {
+		switch err {
+		case domain.ErrConflictingData:
 			return err
 		case domain.ErrDataNotFound:
 			return err
@@ -33,11 +65,13 @@ func (acs *accessControlService) HasAccess(ctx context.Context, warehouseID int,
 	return nil
 }
 
-func (acs *accessControlService) SetAccess(ctx context.Context, warehouseID int, userID int) error {
-	err := acs.repo.SetAccess(ctx, warehouseID, userID)
+func (acs *accessControlService) UpdateAccess(ctx context.Context, warehouseID int, userID int, level domain.AccessLevel) error {
+	err := acs.repo.UpdateAccess(ctx, warehouseID, userID, level)
 	if err != nil {
 		switch err {
-		case domain.ErrConflictingData:
+		case domain.ErrDataNotFound:
+			return err
+		case domain.ErrNoUpdatedData:
 			return err
 		default:
 			return domain.ErrInternal
@@ -50,7 +84,12 @@ func (acs *accessControlService) SetAccess(ctx context.Context, warehouseID int,
 func (acs *accessControlService) DelAccess(ctx context.Context, warehouseID int, userID int) error {
 	err := acs.repo.DelAccess(ctx, warehouseID, userID)
 	if err != nil {
-		return domain.ErrInternal
+		switch err {
+		case domain.ErrDataNotFound:
+			return err
+		default:
+			return domain.ErrInternal
+		}
 	}
 
 	return nil
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	mockRepo "github.com/tommjj/ql-kho-lua/internal/core/services/mock"
+)
+
+func TestAccessControlServiceImplements(t *testing.T) {
+	assert.Implements(t, (*ports.IAccessControlService)(nil), new(accessControlService))
+}
+
+func TestHasAccess(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    domain.AccessLevel
+		repoErr  error
+		action   domain.AccessAction
+		expected error
+	}{
+		{"SuccessFullImport", domain.AccessFull, nil, domain.ActionImport, nil},
+		{"SuccessFullExport", domain.AccessFull, nil, domain.ActionExport, nil},
+		{"SuccessReadOnlyRead", domain.AccessReadOnly, nil, domain.ActionRead, nil},
+		{"SuccessImportOnlyRead", domain.AccessImportOnly, nil, domain.ActionRead, nil},
+		{"SuccessImportOnlyImport", domain.AccessImportOnly, nil, domain.ActionImport, nil},
+		{"SuccessExportOnlyExport", domain.AccessExportOnly, nil, domain.ActionExport, nil},
+		{"FailReadOnlyImport", domain.AccessReadOnly, nil, domain.ActionImport, domain.ErrForbidden},
+		{"FailReadOnlyExport", domain.AccessReadOnly, nil, domain.ActionExport, domain.ErrForbidden},
+		{"FailImportOnlyExport", domain.AccessImportOnly, nil, domain.ActionExport, domain.ErrForbidden},
+		{"FailExportOnlyImport", domain.AccessExportOnly, nil, domain.ActionImport, domain.ErrForbidden},
+		{"FailNoAccess", "", domain.ErrForbidden, domain.ActionRead, domain.ErrForbidden},
+		{"FailUnknownErr", "", errors.New("unknown error"), domain.ActionRead, domain.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockRepo.MockAccessControlRepository)
+			repo.On("GetAccessLevel", mock.Anything, 1, 2).Return(tt.level, tt.repoErr)
+
+			service := NewAccessControlService(repo)
+			err := service.HasAccess(context.TODO(), 1, 2, tt.action)
+			assert.Equal(t, tt.expected, err)
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetAccess(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoErr  error
+		expected error
+	}{
+		{"Success", nil, nil},
+		{"FailNotFound", domain.ErrDataNotFound, domain.ErrDataNotFound},
+		{"FailConflicting", domain.ErrConflictingData, domain.ErrConflictingData},
+		{"FailUnknownErr", errors.New("unknown error"), domain.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockRepo.MockAccessControlRepository)
+			repo.On("SetAccess", mock.Anything, 1, 2, domain.AccessFull).Return(tt.repoErr)
+
+			service := NewAccessControlService(repo)
+			err := service.SetAccess(context.TODO(), 1, 2, domain.AccessFull)
+			assert.Equal(t, tt.expected, err)
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateAccess(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoErr  error
+		expected error
+	}{
+		{"Success", nil, nil},
+		{"FailNotFound", domain.ErrDataNotFound, domain.ErrDataNotFound},
+		{"FailNoUpdated", domain.ErrNoUpdatedData, domain.ErrNoUpdatedData},
+		{"FailUnknownErr", errors.New("unknown error"), domain.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockRepo.MockAccessControlRepository)
+			repo.On("UpdateAccess", mock.Anything, 1, 2, domain.AccessReadOnly).Return(tt.repoErr)
+
+			service := NewAccessControlService(repo)
+			err := service.UpdateAccess(context.TODO(), 1, 2, domain.AccessReadOnly)
+			assert.Equal(t, tt.expected, err)
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDelAccess(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoErr  error
+		expected error
+	}{
+		{"Success", nil, nil},
+		{"FailNotFound", domain.ErrDataNotFound, domain.ErrDataNotFound},
+		{"FailUnknownErr", errors.New("unknown error"), domain.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockRepo.MockAccessControlRepository)
+			repo.On("DelAccess", mock.Anything, 1, 2).Return(tt.repoErr)
+
+			service := NewAccessControlService(repo)
+			err := service.DelAccess(context.TODO(), 1, 2)
+			assert.Equal(t, tt.expected, err)
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
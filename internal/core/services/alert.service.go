@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+type alertService struct {
+	repo          ports.IAlertRepository
+	capacityRatio float64
+	expiryWindow  time.Duration
+}
+
+// NewAlertService create the alert service, a warehouse is near capacity when its used capacity reach capacityRatio
+// of its capacity and a lot is near expiry when it expires within expiryWindow
+func NewAlertService(repo ports.IAlertRepository, capacityRatio float64, expiryWindow time.Duration) ports.IAlertService {
+	return &alertService{
+		repo:          repo,
+		capacityRatio: capacityRatio,
+		expiryWindow:  expiryWindow,
+	}
+}
+
+func (a *alertService) SetThreshold(ctx context.Context, threshold *domain.StockThreshold) (*domain.StockThreshold, error) {
+	created, err := a.repo.SetThreshold(ctx, threshold)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return created, nil
+}
+
+func (a *alertService) GetThresholds(ctx context.Context, warehouseID int) ([]domain.StockThreshold, error) {
+	thresholds, err := a.repo.GetThresholds(ctx, warehouseID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return thresholds, nil
+}
+
+func (a *alertService) DeleteThreshold(ctx context.Context, warehouseID, riceID int) error {
+	err := a.repo.DeleteThreshold(ctx, warehouseID, riceID)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return err
+		default:
+			return domain.ErrInternal
+		}
+	}
+
+	return nil
+}
+
+func (a *alertService) CheckAlerts(ctx context.Context) ([]domain.Alert, error) {
+	candidates := []domain.Alert{}
+
+	lowStock, err := a.repo.GetLowStock(ctx)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	for _, v := range lowStock {
+		riceID := v.RiceID
+		candidates = append(candidates, domain.Alert{
+			Type:        domain.AlertLowStock,
+			WarehouseID: v.WarehouseID,
+			RiceID:      &riceID,
+			Message:     fmt.Sprintf("%s is low in stock: %d left, minimum %d", v.RiceName, v.Quantity, v.MinQuantity),
+			Value:       v.Quantity,
+			Threshold:   v.MinQuantity,
+		})
+	}
+
+	usages, err := a.repo.GetNearCapacity(ctx, a.capacityRatio)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	for _, v := range usages {
+		candidates = append(candidates, domain.Alert{
+			Type:        domain.AlertNearCapacity,
+			WarehouseID: v.WarehouseID,
+			Message:     fmt.Sprintf("warehouse %s is %d%% full: %d of %d", v.Name, v.Used*100/v.Capacity, v.Used, v.Capacity),
+			Value:       v.Used,
+			Threshold:   v.Capacity,
+		})
+	}
+
+	lots, err := a.repo.GetExpiringLots(ctx, time.Now().Add(a.expiryWindow))
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	for _, v := range lots {
+		riceID, lotID := v.RiceID, v.ID
+		riceName := ""
+		if v.Rice != nil {
+			riceName = v.Rice.Name
+		}
+		candidates = append(candidates, domain.Alert{
+			Type:        domain.AlertLotExpiry,
+			WarehouseID: v.WarehouseID,
+			RiceID:      &riceID,
+			LotID:       &lotID,
+			Message: fmt.Sprintf("lot %s of %s expires on %s with %d left",
+				v.LotNumber, riceName, v.ExpiryDate.Format(time.DateOnly), v.Remaining),
+			Value: v.Remaining,
+		})
+	}
+
+	raised := []domain.Alert{}
+	for _, alert := range candidates {
+		created, err := a.repo.CreateAlert(ctx, &alert)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+		if created {
+			raised = append(raised, alert)
+		}
+	}
+
+	return raised, nil
+}
+
+func (a *alertService) CountAlerts(ctx context.Context, filter domain.AlertFilter) (int64, error) {
+	count, err := a.repo.CountAlerts(ctx, filter)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	return count, nil
+}
+
+func (a *alertService) GetListAlerts(ctx context.Context, filter domain.AlertFilter, limit, skip int) ([]domain.Alert, error) {
+	alerts, err := a.repo.GetListAlerts(ctx, filter, limit, skip)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return alerts, nil
+}
+
+func (a *alertService) GetAlertByID(ctx context.Context, id int) (*domain.Alert, error) {
+	alert, err := a.repo.GetAlertByID(ctx, id)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return alert, nil
+}
+
+func (a *alertService) AcknowledgeAlert(ctx context.Context, id, userID int) (*domain.Alert, error) {
+	alert, err := a.repo.AcknowledgeAlert(ctx, id, userID)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound, domain.ErrAlertAcknowledged:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return alert, nil
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	mockRepo "github.com/tommjj/ql-kho-lua/internal/core/services/mock"
+)
+
+func TestAlertServiceImplements(t *testing.T) {
+	assert.Implements(t, (*ports.IAlertService)(nil), new(alertService))
+}
+
+func TestCheckAlerts(t *testing.T) {
+	expiry := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	repo := new(mockRepo.MockAlertRepository)
+	repo.On("GetLowStock", context.TODO()).Return([]domain.LowStock{
+		{WarehouseID: 1, RiceID: 2, RiceName: "ST25", Quantity: 20, MinQuantity: 100},
+	}, nil)
+	repo.On("GetNearCapacity", context.TODO(), 0.9).Return([]domain.WarehouseUsage{
+		{WarehouseID: 1, Name: "Kho A", Used: 950, Capacity: 1000},
+	}, nil)
+	repo.On("GetExpiringLots", context.TODO(), mock.Anything).Return([]domain.Lot{
+		{ID: 3, WarehouseID: 1, RiceID: 2, LotNumber: "L1", ExpiryDate: &expiry, Remaining: 40, Rice: &domain.Rice{Name: "ST25"}},
+	}, nil)
+	// the near capacity alert is still open so it is not raised again
+	repo.On("CreateAlert", context.TODO(), mock.MatchedBy(func(a *domain.Alert) bool {
+		return a.Type == domain.AlertNearCapacity
+	})).Return(false, nil)
+	repo.On("CreateAlert", context.TODO(), mock.Anything).Return(true, nil)
+
+	service := NewAlertService(repo, 0.9, 30*24*time.Hour)
+	alerts, err := service.CheckAlerts(context.TODO())
+
+	repo.AssertExpectations(t)
+	assert.Nil(t, err)
+	if assert.Len(t, alerts, 2) {
+		assert.Equal(t, domain.AlertLowStock, alerts[0].Type)
+		assert.Equal(t, 2, *alerts[0].RiceID)
+		assert.Equal(t, 20, alerts[0].Value)
+		assert.Equal(t, 100, alerts[0].Threshold)
+
+		assert.Equal(t, domain.AlertLotExpiry, alerts[1].Type)
+		assert.Equal(t, 3, *alerts[1].LotID)
+		assert.Equal(t, 40, alerts[1].Value)
+	}
+}
+
+func TestCheckAlerts_RepoErr(t *testing.T) {
+	repo := new(mockRepo.MockAlertRepository)
+	repo.On("GetLowStock", context.TODO()).Return(nil, errors.New("db down"))
+
+	service := NewAlertService(repo, 0.9, 30*24*time.Hour)
+	_, err := service.CheckAlerts(context.TODO())
+
+	repo.AssertNotCalled(t, "CreateAlert")
+	assert.Equal(t, domain.ErrInternal, err)
+}
+
+func TestAcknowledgeAlert(t *testing.T) {
+	now := time.Now()
+	userID := 7
+	alert := &domain.Alert{ID: 1, Type: domain.AlertLowStock, WarehouseID: 1, AcknowledgedAt: &now, AcknowledgedBy: &userID}
+
+	repo := new(mockRepo.MockAlertRepository)
+	repo.On("AcknowledgeAlert", context.TODO(), 1, 7).Return(alert, nil)
+	repo.On("AcknowledgeAlert", context.TODO(), 2, 7).Return(nil, domain.ErrAlertAcknowledged)
+	repo.On("AcknowledgeAlert", context.TODO(), 3, 7).Return(nil, domain.ErrDataNotFound)
+	repo.On("AcknowledgeAlert", context.TODO(), 4, 7).Return(nil, errors.New("db down"))
+
+	service := NewAlertService(repo, 0.9, 30*24*time.Hour)
+
+	res, err := service.AcknowledgeAlert(context.TODO(), 1, 7)
+	assert.Nil(t, err)
+	assert.Equal(t, alert, res)
+
+	_, err = service.AcknowledgeAlert(context.TODO(), 2, 7)
+	assert.Equal(t, domain.ErrAlertAcknowledged, err)
+
+	_, err = service.AcknowledgeAlert(context.TODO(), 3, 7)
+	assert.Equal(t, domain.ErrDataNotFound, err)
+
+	_, err = service.AcknowledgeAlert(context.TODO(), 4, 7)
+	assert.Equal(t, domain.ErrInternal, err)
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+// systemActorName is the actor name of operations made without a logged in user, e.g. creating the root user on start
+const systemActorName = "system"
+
+type auditService struct {
+	repo ports.IAuditRepository
+}
+
+func NewAuditService(repo ports.IAuditRepository) ports.IAuditService {
+	return &auditService{
+		repo: repo,
+	}
+}
+
+func (as *auditService) Record(ctx context.Context, action domain.AuditAction, entity domain.AuditEntity, entityID int, before, after any) {
+	log := &domain.AuditLog{
+		ActorName: systemActorName,
+		Action:    action,
+		Entity:    entity,
+		EntityID:  entityID,
+		Before:    snapshot(before),
+		After:     snapshot(after),
+	}
+
+	if actor := domain.ActorFromContext(ctx); actor != nil {
+		log.ActorID = &actor.ID
+		log.ActorName = actor.Name
+		log.IP = actor.IP
+	}
+
+	// the operation is already done, so the log is written even if the request has been cancelled
+	err := as.repo.CreateAuditLog(context.WithoutCancel(ctx), log)
+	if err != nil {
+		zap.L().Error("write audit log",
+			zap.Error(err),
+			zap.String("action", string(action)),
+			zap.String("entity", string(entity)),
+			zap.Int("entity_id", entityID),
+		)
+	}
+}
+
+// snapshot marshal v to JSON, nil if v is nil or can not be marshalled
+func snapshot(v any) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil || string(b) == "null" {
+		return nil
+	}
+	return b
+}
+
+func (as *auditService) CountAuditLogs(ctx context.Context, filter domain.AuditFilter) (int64, error) {
+	count, err := as.repo.CountAuditLogs(ctx, filter)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	return count, nil
+}
+
+func (as *auditService) GetListAuditLogs(ctx context.Context, filter domain.AuditFilter, limit, skip int) ([]domain.AuditLog, error) {
+	logs, err := as.repo.GetListAuditLogs(ctx, filter, limit, skip)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return logs, nil
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	mockRepo "github.com/tommjj/ql-kho-lua/internal/core/services/mock"
+)
+
+func TestAuditServiceImplements(t *testing.T) {
+	assert.Implements(t, (*ports.IAuditService)(nil), new(auditService))
+}
+
+func TestRecord_WithActor(t *testing.T) {
+	repo := new(mockRepo.MockAuditRepository)
+	var log *domain.AuditLog
+	repo.On("CreateAuditLog", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		log = args.Get(1).(*domain.AuditLog)
+	})
+
+	ctx := domain.ContextWithActor(context.TODO(), &domain.Actor{ID: 2, Name: "vertin", IP: "127.0.0.1"})
+
+	service := NewAuditService(repo)
+	service.Record(ctx, domain.AuditUpdate, domain.AuditEntityRice, 1, &domain.Rice{ID: 1, Name: "old"}, &domain.Rice{ID: 1, Name: "new"})
+
+	repo.AssertExpectations(t)
+	assert.Equal(t, 2, *log.ActorID)
+	assert.Equal(t, "vertin", log.ActorName)
+	assert.Equal(t, "127.0.0.1", log.IP)
+	assert.Equal(t, domain.AuditUpdate, log.Action)
+	assert.Equal(t, domain.AuditEntityRice, log.Entity)
+	assert.Equal(t, 1, log.EntityID)
+	assert.JSONEq(t, `{"id":1,"name":"old"}`, string(log.Before))
+	assert.JSONEq(t, `{"id":1,"name":"new"}`, string(log.After))
+}
+
+func TestRecord_WithoutActor(t *testing.T) {
+	repo := new(mockRepo.MockAuditRepository)
+	var log *domain.AuditLog
+	repo.On("CreateAuditLog", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		log = args.Get(1).(*domain.AuditLog)
+	})
+
+	service := NewAuditService(repo)
+	service.Record(context.TODO(), domain.AuditCreate, domain.AuditEntityUser, 1, (*domain.User)(nil), &domain.User{ID: 1})
+
+	repo.AssertExpectations(t)
+	assert.Nil(t, log.ActorID)
+	assert.Equal(t, systemActorName, log.ActorName)
+	assert.Nil(t, log.Before)
+	assert.NotNil(t, log.After)
+}
+
+func TestRecord_RepoErrDoesNotPanic(t *testing.T) {
+	repo := new(mockRepo.MockAuditRepository)
+	repo.On("CreateAuditLog", mock.Anything, mock.Anything).Return(errors.New("unknown error"))
+
+	service := NewAuditService(repo)
+	assert.NotPanics(t, func() {
+		service.Record(context.TODO(), domain.AuditDelete, domain.AuditEntityRice, 1, nil, nil)
+	})
+
+	repo.AssertExpectations(t)
+}
+
+func TestAuditedRiceService_Update(t *testing.T) {
+	riceRepo := new(mockRepo.MockRiceRepository)
+	riceRepo.On("GetRiceByID", mock.Anything, 1).Return(&domain.Rice{ID: 1, Name: "old"}, nil)
+	riceRepo.On("UpdateRice", mock.Anything, mock.Anything).Return(&domain.Rice{ID: 1, Name: "new"}, nil)
+
+	auditRepo := new(mockRepo.MockAuditRepository)
+	var log *domain.AuditLog
+	auditRepo.On("CreateAuditLog", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		log = args.Get(1).(*domain.AuditLog)
+	})
+
+	service := NewAuditedRiceService(NewRiceService(riceRepo), NewAuditService(auditRepo))
+	_, err := service.UpdateRice(context.TODO(), &domain.Rice{ID: 1, Name: "new"})
+	assert.NoError(t, err)
+
+	auditRepo.AssertExpectations(t)
+	assert.JSONEq(t, `{"id":1,"name":"old"}`, string(log.Before))
+	assert.JSONEq(t, `{"id":1,"name":"new"}`, string(log.After))
+}
+
+func TestAuditedRiceService_FailNotRecorded(t *testing.T) {
+	riceRepo := new(mockRepo.MockRiceRepository)
+	riceRepo.On("CreateRice", mock.Anything, mock.Anything).Return(nil, domain.ErrConflictingData)
+
+	auditRepo := new(mockRepo.MockAuditRepository)
+
+	service := NewAuditedRiceService(NewRiceService(riceRepo), NewAuditService(auditRepo))
+	_, err := service.CreateRice(context.TODO(), &domain.Rice{Name: "rice"})
+	assert.Equal(t, domain.ErrConflictingData, err)
+
+	auditRepo.AssertNotCalled(t, "CreateAuditLog", mock.Anything, mock.Anything)
+}
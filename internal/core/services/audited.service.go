@@ -0,0 +1,538 @@
+package services
+
+import (
+	"context"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+// The audited services wrap a service and write an audit log after each successful mutating call.
+// Read methods are passed through to the wrapped service.
+
+type auditedUserService struct {
+	ports.IUserService
+	audit ports.IAuditService
+}
+
+func NewAuditedUserService(svc ports.IUserService, audit ports.IAuditService) ports.IUserService {
+	return &auditedUserService{
+		IUserService: svc,
+		audit:        audit,
+	}
+}
+
+func (s *auditedUserService) CreateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
+	created, err := s.IUserService.CreateUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditCreate, domain.AuditEntityUser, created.ID, nil, created)
+	return created, nil
+}
+
+func (s *auditedUserService) UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
+	before, _ := s.IUserService.GetUserByID(ctx, user.ID)
+
+	updated, err := s.IUserService.UpdateUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditUpdate, domain.AuditEntityUser, user.ID, before, updated)
+	return updated, nil
+}
+
+func (s *auditedUserService) DeleteUser(ctx context.Context, id int) error {
+	before, _ := s.IUserService.GetUserByID(ctx, id)
+
+	err := s.IUserService.DeleteUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, domain.AuditDelete, domain.AuditEntityUser, id, before, nil)
+	return nil
+}
+
+type auditedWarehouseService struct {
+	ports.IWarehouseService
+	audit ports.IAuditService
+}
+
+func NewAuditedWarehouseService(svc ports.IWarehouseService, audit ports.IAuditService) ports.IWarehouseService {
+	return &auditedWarehouseService{
+		IWarehouseService: svc,
+		audit:             audit,
+	}
+}
+
+func (s *auditedWarehouseService) CreateWarehouse(ctx context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error) {
+	created, err := s.IWarehouseService.CreateWarehouse(ctx, warehouse)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditCreate, domain.AuditEntityWarehouse, created.ID, nil, created)
+	return created, nil
+}
+
+func (s *auditedWarehouseService) UpdateWarehouse(ctx context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error) {
+	before, _ := s.IWarehouseService.GetWarehouseByID(ctx, warehouse.ID)
+
+	updated, err := s.IWarehouseService.UpdateWarehouse(ctx, warehouse)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditUpdate, domain.AuditEntityWarehouse, warehouse.ID, before, updated)
+	return updated, nil
+}
+
+func (s *auditedWarehouseService) DeleteWarehouse(ctx context.Context, id int) error {
+	before, _ := s.IWarehouseService.GetWarehouseByID(ctx, id)
+
+	err := s.IWarehouseService.DeleteWarehouse(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, domain.AuditDelete, domain.AuditEntityWarehouse, id, before, nil)
+	return nil
+}
+
+type auditedRiceService struct {
+	ports.IRiceService
+	audit ports.IAuditService
+}
+
+func NewAuditedRiceService(svc ports.IRiceService, audit ports.IAuditService) ports.IRiceService {
+	return &auditedRiceService{
+		IRiceService: svc,
+		audit:        audit,
+	}
+}
+
+func (s *auditedRiceService) CreateRice(ctx context.Context, rice *domain.Rice) (*domain.Rice, error) {
+	created, err := s.IRiceService.CreateRice(ctx, rice)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditCreate, domain.AuditEntityRice, created.ID, nil, created)
+	return created, nil
+}
+
+func (s *auditedRiceService) UpdateRice(ctx context.Context, rice *domain.Rice) (*domain.Rice, error) {
+	before, _ := s.IRiceService.GetRiceByID(ctx, rice.ID)
+
+	updated, err := s.IRiceService.UpdateRice(ctx, rice)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditUpdate, domain.AuditEntityRice, rice.ID, before, updated)
+	return updated, nil
+}
+
+func (s *auditedRiceService) DeleteRice(ctx context.Context, id int) error {
+	before, _ := s.IRiceService.GetRiceByID(ctx, id)
+
+	err := s.IRiceService.DeleteRice(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, domain.AuditDelete, domain.AuditEntityRice, id, before, nil)
+	return nil
+}
+
+type auditedCustomerService struct {
+	ports.ICustomerService
+	audit ports.IAuditService
+}
+
+func NewAuditedCustomerService(svc ports.ICustomerService, audit ports.IAuditService) ports.ICustomerService {
+	return &auditedCustomerService{
+		ICustomerService: svc,
+		audit:            audit,
+	}
+}
+
+func (s *auditedCustomerService) CreateCustomer(ctx context.Context, customer *domain.Customer) (*domain.Customer, error) {
+	created, err := s.ICustomerService.CreateCustomer(ctx, customer)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditCreate, domain.AuditEntityCustomer, created.ID, nil, created)
+	return created, nil
+}
+
+func (s *auditedCustomerService) UpdateCustomer(ctx context.Context, customer *domain.Customer) (*domain.Customer, error) {
+	before, _ := s.ICustomerService.GetCustomerByID(ctx, customer.ID)
+
+	updated, err := s.ICustomerService.UpdateCustomer(ctx, customer)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditUpdate, domain.AuditEntityCustomer, customer.ID, before, updated)
+	return updated, nil
+}
+
+func (s *auditedCustomerService) DeleteCustomer(ctx context.Context, id int) error {
+	before, _ := s.ICustomerService.GetCustomerByID(ctx, id)
+
+	err := s.ICustomerService.DeleteCustomer(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, domain.AuditDelete, domain.AuditEntityCustomer, id, before, nil)
+	return nil
+}
+
+type auditedImInvoiceService struct {
+	ports.IImportInvoicesService
+	audit ports.IAuditService
+}
+
+func NewAuditedImInvoiceService(svc ports.IImportInvoicesService, audit ports.IAuditService) ports.IImportInvoicesService {
+	return &auditedImInvoiceService{
+		IImportInvoicesService: svc,
+		audit:                  audit,
+	}
+}
+
+func (s *auditedImInvoiceService) CreateImInvoice(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error) {
+	created, err := s.IImportInvoicesService.CreateImInvoice(ctx, invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditCreate, domain.AuditEntityImportInvoice, created.ID, nil, created)
+	return created, nil
+}
+
+func (s *auditedImInvoiceService) CancelImInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	before, _ := s.IImportInvoicesService.GetImInvoiceByID(ctx, id)
+
+	cancelled, err := s.IImportInvoicesService.CancelImInvoice(ctx, id, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditCancel, domain.AuditEntityImportInvoice, id, before, cancelled)
+	return cancelled, nil
+}
+
+func (s *auditedImInvoiceService) ApproveImInvoice(ctx context.Context, id int, approverID int) (*domain.Invoice, error) {
+	before, _ := s.IImportInvoicesService.GetImInvoiceByID(ctx, id)
+
+	approved, err := s.IImportInvoicesService.ApproveImInvoice(ctx, id, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditApprove, domain.AuditEntityImportInvoice, id, before, approved)
+	return approved, nil
+}
+
+func (s *auditedImInvoiceService) RejectImInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	before, _ := s.IImportInvoicesService.GetImInvoiceByID(ctx, id)
+
+	rejected, err := s.IImportInvoicesService.RejectImInvoice(ctx, id, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditReject, domain.AuditEntityImportInvoice, id, before, rejected)
+	return rejected, nil
+}
+
+type auditedExInvoiceService struct {
+	ports.IExportInvoiceService
+	audit ports.IAuditService
+}
+
+func NewAuditedExInvoiceService(svc ports.IExportInvoiceService, audit ports.IAuditService) ports.IExportInvoiceService {
+	return &auditedExInvoiceService{
+		IExportInvoiceService: svc,
+		audit:                 audit,
+	}
+}
+
+func (s *auditedExInvoiceService) CreateExInvoice(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error) {
+	created, err := s.IExportInvoiceService.CreateExInvoice(ctx, invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditCreate, domain.AuditEntityExportInvoice, created.ID, nil, created)
+	return created, nil
+}
+
+func (s *auditedExInvoiceService) CancelExInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	before, _ := s.IExportInvoiceService.GetExInvoiceByID(ctx, id)
+
+	cancelled, err := s.IExportInvoiceService.CancelExInvoice(ctx, id, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditCancel, domain.AuditEntityExportInvoice, id, before, cancelled)
+	return cancelled, nil
+}
+
+func (s *auditedExInvoiceService) ApproveExInvoice(ctx context.Context, id int, approverID int) (*domain.Invoice, error) {
+	before, _ := s.IExportInvoiceService.GetExInvoiceByID(ctx, id)
+
+	approved, err := s.IExportInvoiceService.ApproveExInvoice(ctx, id, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditApprove, domain.AuditEntityExportInvoice, id, before, approved)
+	return approved, nil
+}
+
+func (s *auditedExInvoiceService) RejectExInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	before, _ := s.IExportInvoiceService.GetExInvoiceByID(ctx, id)
+
+	rejected, err := s.IExportInvoiceService.RejectExInvoice(ctx, id, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditReject, domain.AuditEntityExportInvoice, id, before, rejected)
+	return rejected, nil
+}
+
+type auditedTransferService struct {
+	ports.ITransferService
+	audit ports.IAuditService
+}
+
+func NewAuditedTransferService(svc ports.ITransferService, audit ports.IAuditService) ports.ITransferService {
+	return &auditedTransferService{
+		ITransferService: svc,
+		audit:            audit,
+	}
+}
+
+func (s *auditedTransferService) CreateTransfer(ctx context.Context, transfer *domain.Transfer) (*domain.Transfer, error) {
+	created, err := s.ITransferService.CreateTransfer(ctx, transfer)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditCreate, domain.AuditEntityTransfer, created.ID, nil, created)
+	return created, nil
+}
+
+func (s *auditedTransferService) ReverseTransfer(ctx context.Context, id int, reason string) (*domain.Transfer, error) {
+	before, _ := s.ITransferService.GetTransferByID(ctx, id)
+
+	reversed, err := s.ITransferService.ReverseTransfer(ctx, id, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditCancel, domain.AuditEntityTransfer, id, before, reversed)
+	return reversed, nil
+}
+
+type auditedOrderService struct {
+	ports.IOrderService
+	audit ports.IAuditService
+}
+
+func NewAuditedOrderService(svc ports.IOrderService, audit ports.IAuditService) ports.IOrderService {
+	return &auditedOrderService{
+		IOrderService: svc,
+		audit:         audit,
+	}
+}
+
+func (s *auditedOrderService) CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error) {
+	created, err := s.IOrderService.CreateOrder(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditCreate, domain.AuditEntityOrder, created.ID, nil, created)
+	return created, nil
+}
+
+func (s *auditedOrderService) ConfirmOrder(ctx context.Context, id int) (*domain.Order, error) {
+	before, _ := s.IOrderService.GetOrderByID(ctx, id)
+
+	confirmed, err := s.IOrderService.ConfirmOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditConfirm, domain.AuditEntityOrder, id, before, confirmed)
+	return confirmed, nil
+}
+
+func (s *auditedOrderService) CancelOrder(ctx context.Context, id int) (*domain.Order, error) {
+	before, _ := s.IOrderService.GetOrderByID(ctx, id)
+
+	cancelled, err := s.IOrderService.CancelOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditCancel, domain.AuditEntityOrder, id, before, cancelled)
+	return cancelled, nil
+}
+
+// ConvertOrder record the conversion of the order and the creation of its invoice
+func (s *auditedOrderService) ConvertOrder(ctx context.Context, id int, userID int) (*domain.Invoice, error) {
+	before, _ := s.IOrderService.GetOrderByID(ctx, id)
+
+	invoice, err := s.IOrderService.ConvertOrder(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	after, _ := s.IOrderService.GetOrderByID(ctx, id)
+	s.audit.Record(ctx, domain.AuditConvert, domain.AuditEntityOrder, id, before, after)
+
+	entity := domain.AuditEntityExportInvoice
+	if before != nil && before.Type == domain.PurchaseOrder {
+		entity = domain.AuditEntityImportInvoice
+	}
+	s.audit.Record(ctx, domain.AuditCreate, entity, invoice.ID, nil, invoice)
+	return invoice, nil
+}
+
+type auditedAccessControlService struct {
+	ports.IAccessControlService
+	audit ports.IAuditService
+}
+
+func NewAuditedAccessControlService(svc ports.IAccessControlService, audit ports.IAuditService) ports.IAccessControlService {
+	return &auditedAccessControlService{
+		IAccessControlService: svc,
+		audit:                 audit,
+	}
+}
+
+// accessSnapshot is the audit snapshot of a warehouse access grant
+type accessSnapshot struct {
+	UserID      int                `json:"user_id"`
+	AccessLevel domain.AccessLevel `json:"access_level"`
+}
+
+func (s *auditedAccessControlService) SetAccess(ctx context.Context, warehouseID int, userID int, level domain.AccessLevel) error {
+	err := s.IAccessControlService.SetAccess(ctx, warehouseID, userID, level)
+	if err != nil {
+		return err
+	}
+
+	after := &accessSnapshot{UserID: userID, AccessLevel: level}
+	s.audit.Record(ctx, domain.AuditGrant, domain.AuditEntityAccess, warehouseID, nil, after)
+	return nil
+}
+
+func (s *auditedAccessControlService) UpdateAccess(ctx context.Context, warehouseID int, userID int, level domain.AccessLevel) error {
+	beforeLevel, _ := s.IAccessControlService.GetAccessLevel(ctx, warehouseID, userID)
+
+	err := s.IAccessControlService.UpdateAccess(ctx, warehouseID, userID, level)
+	if err != nil {
+		return err
+	}
+
+	before := &accessSnapshot{UserID: userID, AccessLevel: beforeLevel}
+	after := &accessSnapshot{UserID: userID, AccessLevel: level}
+	s.audit.Record(ctx, domain.AuditUpdate, domain.AuditEntityAccess, warehouseID, before, after)
+	return nil
+}
+
+func (s *auditedAccessControlService) DelAccess(ctx context.Context, warehouseID int, userID int) error {
+	beforeLevel, _ := s.IAccessControlService.GetAccessLevel(ctx, warehouseID, userID)
+
+	err := s.IAccessControlService.DelAccess(ctx, warehouseID, userID)
+	if err != nil {
+		return err
+	}
+
+	before := &accessSnapshot{UserID: userID, AccessLevel: beforeLevel}
+	s.audit.Record(ctx, domain.AuditRevoke, domain.AuditEntityAccess, warehouseID, before, nil)
+	return nil
+}
+
+type auditedPaymentService struct {
+	ports.IPaymentService
+	audit ports.IAuditService
+}
+
+func NewAuditedPaymentService(svc ports.IPaymentService, audit ports.IAuditService) ports.IPaymentService {
+	return &auditedPaymentService{
+		IPaymentService: svc,
+		audit:           audit,
+	}
+}
+
+func (s *auditedPaymentService) CreatePayment(ctx context.Context, payment *domain.Payment) (*domain.Payment, error) {
+	created, err := s.IPaymentService.CreatePayment(ctx, payment)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditCreate, domain.AuditEntityPayment, created.ID, nil, created)
+	return created, nil
+}
+
+// auditedBulkImportService record every row a committed import created, as if it was created alone
+type auditedBulkImportService struct {
+	ports.IBulkImportService
+	audit ports.IAuditService
+}
+
+func NewAuditedBulkImportService(svc ports.IBulkImportService, audit ports.IAuditService) ports.IBulkImportService {
+	return &auditedBulkImportService{
+		IBulkImportService: svc,
+		audit:              audit,
+	}
+}
+
+func (s *auditedBulkImportService) ImportRice(ctx context.Context, rows []domain.ImportRow[domain.Rice], dryRun bool) (*domain.ImportResult[domain.Rice], error) {
+	result, err := s.IBulkImportService.ImportRice(ctx, rows, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, created := range result.Created {
+		s.audit.Record(ctx, domain.AuditCreate, domain.AuditEntityRice, created.ID, nil, created)
+	}
+	return result, nil
+}
+
+func (s *auditedBulkImportService) ImportCustomers(ctx context.Context, rows []domain.ImportRow[domain.Customer], dryRun bool) (*domain.ImportResult[domain.Customer], error) {
+	result, err := s.IBulkImportService.ImportCustomers(ctx, rows, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, created := range result.Created {
+		s.audit.Record(ctx, domain.AuditCreate, domain.AuditEntityCustomer, created.ID, nil, created)
+	}
+	return result, nil
+}
+
+func (s *auditedBulkImportService) ImportWarehouses(ctx context.Context, rows []domain.ImportRow[domain.Warehouse], dryRun bool) (*domain.ImportResult[domain.Warehouse], error) {
+	result, err := s.IBulkImportService.ImportWarehouses(ctx, rows, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, created := range result.Created {
+		s.audit.Record(ctx, domain.AuditCreate, domain.AuditEntityWarehouse, created.ID, nil, created)
+	}
+	return result, nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/tommjj/ql-kho-lua/internal/core/domain"
 	"github.com/tommjj/ql-kho-lua/internal/core/ports"
@@ -10,31 +11,104 @@ import (
 
 type authService struct {
 	userRepo     ports.IUserRepository
+	sessionRepo  ports.ISessionRepository
 	tokenService ports.ITokenService
 }
 
-func NewAuthService(userRepo ports.IUserRepository, token ports.ITokenService) ports.IAuthService {
+func NewAuthService(userRepo ports.IUserRepository, sessionRepo ports.ISessionRepository, token ports.ITokenService) ports.IAuthService {
 	return &authService{
 		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
 		tokenService: token,
 	}
 }
 
-func (as *authService) Login(ctx context.Context, email, password string) (string, error) {
+func (as *authService) Login(ctx context.Context, email, password, userAgent, ip string) (*domain.AuthToken, error) {
 	user, err := as.userRepo.GetUserByEmail(ctx, email)
 	if err != nil {
-		return "", domain.ErrInvalidCredentials
+		return nil, domain.ErrInvalidCredentials
 	}
 
 	err = utils.ComparePassword(password, user.Password)
 	if err != nil {
-		return "", domain.ErrInvalidCredentials
+		return nil, domain.ErrInvalidCredentials
 	}
 
-	token, err := as.tokenService.CreateToken(user)
+	token, err := as.tokenService.CreateToken(user, userAgent, ip)
 	if err != nil {
-		return "", domain.ErrInternal
+		return nil, domain.ErrInternal
 	}
 
 	return token, nil
 }
+
+func (as *authService) Refresh(ctx context.Context, refreshToken string) (*domain.AuthToken, error) {
+	payload, err := as.tokenService.VerifyRefreshToken(refreshToken)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidToken, domain.ErrExpiredToken:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	user, err := as.userRepo.GetUserByID(ctx, payload.ID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, domain.ErrInternal
+	}
+
+	token, err := as.tokenService.RotateToken(user, payload)
+	if err != nil {
+		if err == domain.ErrRefreshTokenReused {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return token, nil
+}
+
+func (as *authService) Logout(ctx context.Context, payload *domain.TokenPayload) error {
+	err := as.tokenService.RevokeToken(payload)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (as *authService) GetSessions(ctx context.Context, userID int) ([]domain.Session, error) {
+	sessions, err := as.sessionRepo.GetSessionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return sessions, nil
+}
+
+func (as *authService) RevokeSession(ctx context.Context, userID, sessionID int) error {
+	err := as.sessionRepo.DeleteSession(ctx, userID, sessionID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (as *authService) DeleteExpiredSessions(ctx context.Context) (int64, error) {
+	deleted, err := as.sessionRepo.DeleteExpiredSessions(ctx, time.Now())
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+	return deleted, nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	mockRepo "github.com/tommjj/ql-kho-lua/internal/core/services/mock"
+)
+
+func TestAuthServiceImplements(t *testing.T) {
+	assert.Implements(t, (*ports.IAuthService)(nil), new(authService))
+}
+
+func TestGetSessions(t *testing.T) {
+	sessions := []domain.Session{
+		{ID: 1, UserID: 1, UserAgent: "desktop"},
+		{ID: 2, UserID: 1, UserAgent: "phone"},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		repo := new(mockRepo.MockSessionRepository)
+		repo.On("GetSessionsByUserID", mock.Anything, 1).Return(sessions, nil)
+
+		service := NewAuthService(nil, repo, nil)
+		result, err := service.GetSessions(context.TODO(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, sessions, result)
+
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("FailUnknownErr", func(t *testing.T) {
+		repo := new(mockRepo.MockSessionRepository)
+		repo.On("GetSessionsByUserID", mock.Anything, 1).Return(nil, errors.New("unknown error"))
+
+		service := NewAuthService(nil, repo, nil)
+		_, err := service.GetSessions(context.TODO(), 1)
+		assert.Equal(t, domain.ErrInternal, err)
+
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestRevokeSession(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoErr  error
+		expected error
+	}{
+		{"Success", nil, nil},
+		{"FailNotFound", domain.ErrDataNotFound, domain.ErrDataNotFound},
+		{"FailUnknownErr", errors.New("unknown error"), domain.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockRepo.MockSessionRepository)
+			repo.On("DeleteSession", mock.Anything, 1, 2).Return(tt.repoErr)
+
+			service := NewAuthService(nil, repo, nil)
+			err := service.RevokeSession(context.TODO(), 1, 2)
+			assert.Equal(t, tt.expected, err)
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteExpiredSessions(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := new(mockRepo.MockSessionRepository)
+		repo.On("DeleteExpiredSessions", mock.Anything, mock.AnythingOfType("time.Time")).Return(int64(3), nil)
+
+		service := NewAuthService(nil, repo, nil)
+		deleted, err := service.DeleteExpiredSessions(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), deleted)
+
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("FailUnknownErr", func(t *testing.T) {
+		repo := new(mockRepo.MockSessionRepository)
+		repo.On("DeleteExpiredSessions", mock.Anything, mock.AnythingOfType("time.Time")).Return(int64(0), errors.New("unknown error"))
+
+		service := NewAuthService(nil, repo, nil)
+		_, err := service.DeleteExpiredSessions(context.TODO())
+		assert.Equal(t, domain.ErrInternal, err)
+
+		repo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+type bulkImportService struct {
+	repo ports.IBulkImportRepository
+	file ports.IFileStorage
+}
+
+func NewBulkImportService(repo ports.IBulkImportRepository, file ports.IFileStorage) ports.IBulkImportService {
+	return &bulkImportService{
+		repo: repo,
+		file: file,
+	}
+}
+
+// batchCreate insert the data of the valid rows, see IBulkImportRepository
+type batchCreate[T any] func(data []T, commit bool) ([]T, []error, error)
+
+// importRows run the valid rows through create, they are committed only when every row is valid and it is not a dry run
+func importRows[T any](rows []domain.ImportRow[T], dryRun bool, create batchCreate[T]) (*domain.ImportResult[T], error) {
+	result := &domain.ImportResult[T]{
+		Total:   len(rows),
+		Errors:  []domain.ImportRowError{},
+		Created: []T{},
+	}
+
+	valid := make([]domain.ImportRow[T], 0, len(rows))
+	for _, row := range rows {
+		if len(row.Errors) > 0 {
+			result.Errors = append(result.Errors, row.Errors...)
+			continue
+		}
+		valid = append(valid, row)
+	}
+
+	if len(valid) == 0 {
+		result.SortErrors()
+		return result, nil
+	}
+
+	data := make([]T, len(valid))
+	for i, row := range valid {
+		data[i] = row.Data
+	}
+
+	commit := !dryRun && len(valid) == len(rows)
+	created, errs, err := create(data, commit)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	result.Valid = len(valid)
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		result.Valid--
+		result.Errors = append(result.Errors, newImportRowError(valid[i].Line, err))
+	}
+
+	if commit && result.Valid == len(rows) {
+		result.Committed = true
+		result.Created = created
+	}
+
+	result.SortErrors()
+	return result, nil
+}
+
+// newImportRowError describe the error of a row found while it is created, master data is unique by name
+func newImportRowError(line int, err error) domain.ImportRowError {
+	switch err {
+	case domain.ErrConflictingData:
+		return domain.ImportRowError{Line: line, Field: "name", Message: "name is already used"}
+	case domain.ErrFileIsNotExist:
+		return domain.ImportRowError{Line: line, Field: "image", Message: "image is not uploaded or has expired"}
+	default:
+		return domain.ImportRowError{Line: line, Message: err.Error()}
+	}
+}
+
+func (b *bulkImportService) ImportRice(ctx context.Context, rows []domain.ImportRow[domain.Rice], dryRun bool) (*domain.ImportResult[domain.Rice], error) {
+	return importRows(rows, dryRun, func(data []domain.Rice, commit bool) ([]domain.Rice, []error, error) {
+		return b.repo.CreateRiceBatch(ctx, data, commit)
+	})
+}
+
+func (b *bulkImportService) ImportCustomers(ctx context.Context, rows []domain.ImportRow[domain.Customer], dryRun bool) (*domain.ImportResult[domain.Customer], error) {
+	for i := range rows {
+		if rows[i].Data.Role == "" {
+			rows[i].Data.Role = domain.PartnerBoth
+		}
+	}
+
+	return importRows(rows, dryRun, func(data []domain.Customer, commit bool) ([]domain.Customer, []error, error) {
+		return b.repo.CreateCustomerBatch(ctx, data, commit)
+	})
+}
+
+func (b *bulkImportService) ImportWarehouses(ctx context.Context, rows []domain.ImportRow[domain.Warehouse], dryRun bool) (*domain.ImportResult[domain.Warehouse], error) {
+	return importRows(rows, dryRun, func(data []domain.Warehouse, commit bool) ([]domain.Warehouse, []error, error) {
+		if !commit {
+			return b.repo.CreateWarehouseBatch(ctx, data, false)
+		}
+		return b.createWarehouses(ctx, data)
+	})
+}
+
+// createWarehouses save the images of the warehouses before they are created in one transaction,
+// the saved images are deleted again when a row fails
+func (b *bulkImportService) createWarehouses(ctx context.Context, data []domain.Warehouse) ([]domain.Warehouse, []error, error) {
+	errs := make([]error, len(data))
+	saved := make([]string, 0, len(data))
+	deleteSaved := func() {
+		for _, image := range saved {
+			_ = b.file.DeleteFile(image)
+		}
+	}
+
+	failed := false
+	for i, v := range data {
+		err := b.file.SavePermanentFile(v.Image)
+		if err != nil {
+			if err != domain.ErrFileIsNotExist {
+				deleteSaved()
+				return nil, nil, err
+			}
+			errs[i] = err
+			failed = true
+			continue
+		}
+		saved = append(saved, v.Image)
+	}
+
+	created, dbErrs, err := b.repo.CreateWarehouseBatch(ctx, data, !failed)
+	if err != nil {
+		deleteSaved()
+		return nil, nil, err
+	}
+
+	for i, err := range dbErrs {
+		if err != nil && errs[i] == nil {
+			errs[i] = err
+			failed = true
+		}
+	}
+
+	if failed {
+		deleteSaved()
+		return nil, errs, nil
+	}
+
+	for _, image := range saved {
+		_ = b.file.DeleteTempFile(image)
+	}
+	return created, errs, nil
+}
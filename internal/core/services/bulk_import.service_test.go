@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	mockRepo "github.com/tommjj/ql-kho-lua/internal/core/services/mock"
+)
+
+func TestBulkImportServiceImplements(t *testing.T) {
+	assert.Implements(t, (*ports.IBulkImportService)(nil), new(bulkImportService))
+}
+
+func TestImportRice_Commit(t *testing.T) {
+	rows := []domain.ImportRow[domain.Rice]{
+		{Line: 2, Data: domain.Rice{Name: "Gao Tam"}},
+		{Line: 3, Data: domain.Rice{Name: "ST25"}},
+	}
+	created := []domain.Rice{{ID: 1, Name: "Gao Tam"}, {ID: 2, Name: "ST25"}}
+
+	repo := new(mockRepo.MockBulkImportRepository)
+	repo.On("CreateRiceBatch", mock.Anything, []domain.Rice{{Name: "Gao Tam"}, {Name: "ST25"}}, true).
+		Return(created, []error{nil, nil}, nil)
+
+	service := NewBulkImportService(repo, new(mockRepo.MockFileStorage))
+	res, err := service.ImportRice(context.TODO(), rows, false)
+
+	repo.AssertExpectations(t)
+	assert.Nil(t, err)
+	assert.True(t, res.Committed)
+	assert.Equal(t, 2, res.Total)
+	assert.Equal(t, 2, res.Valid)
+	assert.Empty(t, res.Errors)
+	assert.Equal(t, created, res.Created)
+}
+
+func TestImportRice_DryRun(t *testing.T) {
+	rows := []domain.ImportRow[domain.Rice]{
+		{Line: 2, Data: domain.Rice{Name: "Gao Tam"}},
+	}
+
+	repo := new(mockRepo.MockBulkImportRepository)
+	repo.On("CreateRiceBatch", mock.Anything, []domain.Rice{{Name: "Gao Tam"}}, false).
+		Return([]domain.Rice{{ID: 9, Name: "Gao Tam"}}, []error{nil}, nil)
+
+	service := NewBulkImportService(repo, new(mockRepo.MockFileStorage))
+	res, err := service.ImportRice(context.TODO(), rows, true)
+
+	repo.AssertExpectations(t)
+	assert.Nil(t, err)
+	assert.False(t, res.Committed)
+	assert.Equal(t, 1, res.Valid)
+	assert.Empty(t, res.Created)
+}
+
+func TestImportRice_InvalidAndConflictingRows(t *testing.T) {
+	rows := []domain.ImportRow[domain.Rice]{
+		{Line: 2, Data: domain.Rice{Name: "Gao Tam"}},
+		{Line: 3, Errors: []domain.ImportRowError{{Line: 3, Field: "name", Message: "name is required"}}},
+		{Line: 4, Data: domain.Rice{Name: "gao tam"}},
+	}
+
+	repo := new(mockRepo.MockBulkImportRepository)
+	// an invalid row keeps the rest from being committed, they are still checked for conflicts
+	repo.On("CreateRiceBatch", mock.Anything, []domain.Rice{{Name: "Gao Tam"}, {Name: "gao tam"}}, false).
+		Return([]domain.Rice{{ID: 1, Name: "Gao Tam"}, {Name: "gao tam"}}, []error{nil, domain.ErrConflictingData}, nil)
+
+	service := NewBulkImportService(repo, new(mockRepo.MockFileStorage))
+	res, err := service.ImportRice(context.TODO(), rows, false)
+
+	repo.AssertExpectations(t)
+	assert.Nil(t, err)
+	assert.False(t, res.Committed)
+	assert.Equal(t, 3, res.Total)
+	assert.Equal(t, 1, res.Valid)
+	assert.Empty(t, res.Created)
+	assert.Equal(t, []domain.ImportRowError{
+		{Line: 3, Field: "name", Message: "name is required"},
+		{Line: 4, Field: "name", Message: "name is already used"},
+	}, res.Errors)
+}
+
+func TestImportRice_RepoErr(t *testing.T) {
+	repo := new(mockRepo.MockBulkImportRepository)
+	repo.On("CreateRiceBatch", mock.Anything, mock.Anything, true).Return(nil, nil, errors.New("db down"))
+
+	service := NewBulkImportService(repo, new(mockRepo.MockFileStorage))
+	_, err := service.ImportRice(context.TODO(), []domain.ImportRow[domain.Rice]{{Line: 2, Data: domain.Rice{Name: "ST25"}}}, false)
+
+	assert.Equal(t, domain.ErrInternal, err)
+}
+
+func TestImportCustomers_DefaultRole(t *testing.T) {
+	rows := []domain.ImportRow[domain.Customer]{
+		{Line: 2, Data: domain.Customer{Name: "Ascalon"}},
+		{Line: 3, Data: domain.Customer{Name: "Sentenced", Role: domain.PartnerSupplier}},
+	}
+
+	repo := new(mockRepo.MockBulkImportRepository)
+	repo.On("CreateCustomerBatch", mock.Anything, []domain.Customer{
+		{Name: "Ascalon", Role: domain.PartnerBoth},
+		{Name: "Sentenced", Role: domain.PartnerSupplier},
+	}, true).Return([]domain.Customer{{ID: 1}, {ID: 2}}, []error{nil, nil}, nil)
+
+	service := NewBulkImportService(repo, new(mockRepo.MockFileStorage))
+	res, err := service.ImportCustomers(context.TODO(), rows, false)
+
+	repo.AssertExpectations(t)
+	assert.Nil(t, err)
+	assert.True(t, res.Committed)
+}
+
+func TestImportWarehouses_MissingImage(t *testing.T) {
+	rows := []domain.ImportRow[domain.Warehouse]{
+		{Line: 2, Data: domain.Warehouse{Name: "store 01", Image: "a.png"}},
+		{Line: 3, Data: domain.Warehouse{Name: "store 02", Image: "b.png"}},
+	}
+
+	file := new(mockRepo.MockFileStorage)
+	file.On("SavePermanentFile", "a.png").Return(nil)
+	file.On("SavePermanentFile", "b.png").Return(domain.ErrFileIsNotExist)
+	file.On("DeleteFile", "a.png").Return(nil)
+
+	repo := new(mockRepo.MockBulkImportRepository)
+	repo.On("CreateWarehouseBatch", mock.Anything, mock.Anything, false).
+		Return([]domain.Warehouse{{}, {}}, []error{nil, nil}, nil)
+
+	service := NewBulkImportService(repo, file)
+	res, err := service.ImportWarehouses(context.TODO(), rows, false)
+
+	file.AssertExpectations(t)
+	repo.AssertExpectations(t)
+	assert.Nil(t, err)
+	assert.False(t, res.Committed)
+	assert.Equal(t, []domain.ImportRowError{{Line: 3, Field: "image", Message: "image is not uploaded or has expired"}}, res.Errors)
+}
+
+func TestImportWarehouses_Commit(t *testing.T) {
+	rows := []domain.ImportRow[domain.Warehouse]{
+		{Line: 2, Data: domain.Warehouse{Name: "store 01", Image: "a.png"}},
+	}
+
+	file := new(mockRepo.MockFileStorage)
+	file.On("SavePermanentFile", "a.png").Return(nil)
+	file.On("DeleteTempFile", "a.png").Return(nil)
+
+	repo := new(mockRepo.MockBulkImportRepository)
+	repo.On("CreateWarehouseBatch", mock.Anything, []domain.Warehouse{{Name: "store 01", Image: "a.png"}}, true).
+		Return([]domain.Warehouse{{ID: 1, Name: "store 01", Image: "a.png"}}, []error{nil}, nil)
+
+	service := NewBulkImportService(repo, file)
+	res, err := service.ImportWarehouses(context.TODO(), rows, false)
+
+	file.AssertExpectations(t)
+	file.AssertNotCalled(t, "DeleteFile", mock.Anything)
+	assert.Nil(t, err)
+	assert.True(t, res.Committed)
+	assert.Len(t, res.Created, 1)
+}
@@ -18,6 +18,10 @@ func NewCustomerService(repo ports.ICustomerRepository) ports.ICustomerService {
 }
 
 func (c *customerService) CreateCustomer(ctx context.Context, customer *domain.Customer) (*domain.Customer, error) {
+	if customer.Role == "" {
+		customer.Role = domain.PartnerBoth
+	}
+
 	created, err := c.repo.CreateCustomer(ctx, customer)
 	if err != nil {
 		switch err {
@@ -43,8 +47,8 @@ func (c *customerService) GetCustomerByID(ctx context.Context, id int) (*domain.
 	return customer, nil
 }
 
-func (c *customerService) CountCustomers(ctx context.Context, query string) (int64, error) {
-	count, err := c.repo.CountCustomers(ctx, query)
+func (c *customerService) CountCustomers(ctx context.Context, query string, role domain.PartnerRole) (int64, error) {
+	count, err := c.repo.CountCustomers(ctx, query, role)
 	if err != nil {
 		return 0, domain.ErrInternal
 	}
@@ -52,8 +56,20 @@ func (c *customerService) CountCustomers(ctx context.Context, query string) (int
 	return count, nil
 }
 
-func (c *customerService) GetListCustomers(ctx context.Context, query string, limit, skip int) ([]domain.Customer, error) {
-	customers, err := c.repo.GetListCustomers(ctx, query, limit, skip)
+func (c *customerService) GetListCustomers(ctx context.Context, query string, role domain.PartnerRole, limit, skip int) ([]domain.Customer, error) {
+	customers, err := c.repo.GetListCustomers(ctx, query, role, limit, skip)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return customers, nil
+}
+
+func (c *customerService) GetListCustomersAfter(ctx context.Context, query string, role domain.PartnerRole, afterID, limit int) ([]domain.Customer, error) {
+	customers, err := c.repo.GetListCustomersAfter(ctx, query, role, afterID, limit)
 	if err != nil {
 		if err == domain.ErrDataNotFound {
 			return nil, err
@@ -12,25 +12,69 @@ import (
 type exInvoiceService struct {
 	imInvoiceRepo ports.IExportInvoiceRepository
 	warehouseRepo ports.IWarehouseRepository
+	customerRepo  ports.ICustomerRepository
 	l             *mapmutex.Mapmutex
+	rule          domain.ApprovalRule
 }
 
 func NewExInvoicesService(
 	exInvoiceRepo ports.IExportInvoiceRepository,
 	warehouseRepo ports.IWarehouseRepository,
-	l *mapmutex.Mapmutex) ports.IExportInvoiceService {
+	customerRepo ports.ICustomerRepository,
+	l *mapmutex.Mapmutex,
+	rule domain.ApprovalRule) ports.IExportInvoiceService {
 	return &exInvoiceService{
 		imInvoiceRepo: exInvoiceRepo,
 		warehouseRepo: warehouseRepo,
+		customerRepo:  customerRepo,
 		l:             l,
+		rule:          rule,
 	}
 }
 
 func (e *exInvoiceService) CreateExInvoice(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error) {
+	err := validateLotAllocations(invoice.Details)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkPartner(ctx, e.customerRepo, invoice.CustomerID, domain.PartnerCustomer)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.CalcTotalPrice()
+	invoice.Status = domain.InvoiceCompleted
+	// an invoice above the approval rule takes no stock until it is approved, its stock is checked then
+	if e.rule.Requires(invoice) {
+		invoice.Status = domain.InvoicePendingApproval
+	}
+
 	e.l.Lock(invoice.WarehouseID)
 	defer e.l.UnLock(invoice.WarehouseID)
 
-	inventory, err := e.warehouseRepo.GetInventory(ctx, invoice.WarehouseID)
+	if invoice.Status == domain.InvoiceCompleted {
+		err = e.checkStock(ctx, invoice)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := e.imInvoiceRepo.CreateExInvoice(ctx, invoice)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound, domain.ErrInsufficientStock, domain.ErrConflictingData:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return created, nil
+}
+
+func (e *exInvoiceService) ApproveExInvoice(ctx context.Context, id int, approverID int) (*domain.Invoice, error) {
+	invoice, err := e.imInvoiceRepo.GetExInvoiceByID(ctx, id)
 	if err != nil {
 		if err == domain.ErrDataNotFound {
 			return nil, err
@@ -38,42 +82,94 @@ func (e *exInvoiceService) CreateExInvoice(ctx context.Context, invoice *domain.
 		return nil, domain.ErrInternal
 	}
 
-	if len(inventory) < len(invoice.Details) {
-		return nil, domain.ErrInsufficientStock
+	if invoice.Status != domain.InvoicePendingApproval {
+		return nil, domain.ErrInvoiceNotPending
 	}
 
-	for _, detail := range invoice.Details {
-		isHaveItem := false
-		for _, item := range inventory {
-			if item.RiceID != detail.RiceID {
-				continue
-			}
+	e.l.Lock(invoice.WarehouseID)
+	defer e.l.UnLock(invoice.WarehouseID)
 
-			isInsufficientStock := item.Quantity < detail.Quantity
-			if isInsufficientStock {
-				return nil, domain.ErrInsufficientStock
-			}
+	err = e.checkStock(ctx, invoice)
+	if err != nil {
+		return nil, err
+	}
 
-			isHaveItem = true
+	approved, err := e.imInvoiceRepo.ApproveExInvoice(ctx, id, approverID)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound, domain.ErrInvoiceNotPending, domain.ErrInsufficientStock:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
 		}
+	}
+
+	return approved, nil
+}
 
-		if !isHaveItem {
-			return nil, domain.ErrInsufficientStock
+func (e *exInvoiceService) RejectExInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	rejected, err := e.imInvoiceRepo.RejectExInvoice(ctx, id, reason)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound, domain.ErrInvoiceNotPending:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
 		}
 	}
 
-	invoice.CalcTotalPrice()
-	created, err := e.imInvoiceRepo.CreateExInvoice(ctx, invoice)
+	return rejected, nil
+}
+
+// checkStock check the warehouse has enough available stock for the lines of the invoice
+func (e *exInvoiceService) checkStock(ctx context.Context, invoice *domain.Invoice) error {
+	inventory, err := e.warehouseRepo.GetInventory(ctx, invoice.WarehouseID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	if !hasEnoughStock(inventory, invoice.Details) {
+		return domain.ErrInsufficientStock
+	}
+	return nil
+}
+
+func (e *exInvoiceService) CancelExInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	invoice, err := e.imInvoiceRepo.GetExInvoiceByID(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if invoice.Status == domain.InvoiceCancelled {
+		return nil, domain.ErrInvoiceCancelled
+	}
+	if invoice.Status != domain.InvoiceCompleted {
+		return nil, domain.ErrInvoiceNotCompleted
+	}
+	if invoice.PaidAmount > 0 {
+		return nil, domain.ErrInvoicePaid
+	}
+
+	e.l.Lock(invoice.WarehouseID)
+	defer e.l.UnLock(invoice.WarehouseID)
+
+	cancelled, err := e.imInvoiceRepo.CancelExInvoice(ctx, id, reason)
 	if err != nil {
 		switch err {
-		case domain.ErrDataNotFound:
-			return nil, domain.ErrDataNotFound
+		case domain.ErrDataNotFound, domain.ErrInvoiceCancelled, domain.ErrInvoiceNotCompleted, domain.ErrInvoicePaid, domain.ErrTransferInvoice:
+			return nil, err
 		default:
 			return nil, domain.ErrInternal
 		}
 	}
 
-	return created, nil
+	return cancelled, nil
 }
 
 func (e *exInvoiceService) GetExInvoiceByID(ctx context.Context, id int) (*domain.Invoice, error) {
@@ -112,3 +208,17 @@ func (e *exInvoiceService) GetListExInvoices(ctx context.Context, warehouseID in
 
 	return invoice, nil
 }
+
+func (e *exInvoiceService) GetListExInvoicesWithAssociations(ctx context.Context, warehouseID int, start *time.Time, end *time.Time, afterID, limit int) ([]domain.Invoice, error) {
+	invoices, err := e.imInvoiceRepo.GetListExInvoicesWithAssociations(ctx, warehouseID, start, end, afterID, limit)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, domain.ErrDataNotFound
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return invoices, nil
+}
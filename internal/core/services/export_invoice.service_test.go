@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/mapmutex"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	mockRepo "github.com/tommjj/ql-kho-lua/internal/core/services/mock"
+)
+
+func TestExInvoiceServiceImplements(t *testing.T) {
+	assert.Implements(t, (*ports.IExportInvoiceService)(nil), new(exInvoiceService))
+}
+
+func TestCreateExInvoice_FailLotAllocation(t *testing.T) {
+	tests := []struct {
+		name string
+		lots []domain.LotAllocation
+	}{
+		{"less than quantity", []domain.LotAllocation{{LotID: 1, Quantity: 4}}},
+		{"more than quantity", []domain.LotAllocation{{LotID: 1, Quantity: 4}, {LotID: 2, Quantity: 7}}},
+		{"duplicate lot", []domain.LotAllocation{{LotID: 1, Quantity: 5}, {LotID: 1, Quantity: 5}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoiceRepo := new(mockRepo.MockExportInvoiceRepository)
+			warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+			service := NewExInvoicesService(invoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{})
+			_, err := service.CreateExInvoice(context.TODO(), &domain.Invoice{
+				WarehouseID: 2,
+				Details:     []domain.InvoiceItem{{RiceID: 1, Quantity: 10, Price: 10, Lots: tt.lots}},
+			})
+			assert.Equal(t, domain.ErrInvalidLotAllocation, err)
+			warehouseRepo.AssertNotCalled(t, "GetInventory")
+		})
+	}
+}
+
+func TestCreateExInvoice_FailLotInsufficient(t *testing.T) {
+	invoiceRepo := new(mockRepo.MockExportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	warehouseRepo.On("GetInventory", mock.Anything, 2).Return([]domain.WarehouseItem{{RiceID: 1, Quantity: 100}}, nil)
+	invoiceRepo.On("CreateExInvoice", mock.Anything, mock.Anything).Return(nil, domain.ErrInsufficientStock)
+
+	service := NewExInvoicesService(invoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{})
+	_, err := service.CreateExInvoice(context.TODO(), &domain.Invoice{
+		WarehouseID: 2,
+		Details:     []domain.InvoiceItem{{RiceID: 1, Quantity: 10, Price: 10, Lots: []domain.LotAllocation{{LotID: 3, Quantity: 10}}}},
+	})
+	assert.Equal(t, domain.ErrInsufficientStock, err)
+}
+
+func TestCreateExInvoice_FailStockReserved(t *testing.T) {
+	exInvoiceRepo := new(mockRepo.MockExportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	warehouseRepo.On("GetInventory", mock.Anything, 2).Return([]domain.WarehouseItem{{RiceID: 1, Quantity: 100, Reserved: 1}}, nil)
+
+	service := NewExInvoicesService(exInvoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{})
+	_, err := service.CreateExInvoice(context.TODO(), &domain.Invoice{
+		WarehouseID: 2,
+		Details:     []domain.InvoiceItem{{RiceID: 1, Quantity: 100, Price: 10}},
+	})
+	assert.Equal(t, domain.ErrInsufficientStock, err)
+
+	exInvoiceRepo.AssertNotCalled(t, "CreateExInvoice", mock.Anything, mock.Anything)
+}
+
+func TestCreateExInvoice_PendingApproval(t *testing.T) {
+	exInvoiceRepo := new(mockRepo.MockExportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	exInvoiceRepo.On("CreateExInvoice", mock.Anything, mock.MatchedBy(func(invoice *domain.Invoice) bool {
+		return invoice.Status == domain.InvoicePendingApproval
+	})).Return(&domain.Invoice{ID: 1, Status: domain.InvoicePendingApproval}, nil)
+
+	// the stock is not checked before approval, the warehouse holds less than the invoice
+	service := NewExInvoicesService(exInvoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{Quantity: 50})
+	created, err := service.CreateExInvoice(context.TODO(), &domain.Invoice{
+		WarehouseID: 2,
+		Details:     []domain.InvoiceItem{{RiceID: 1, Quantity: 100, Price: 10}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, domain.InvoicePendingApproval, created.Status)
+
+	exInvoiceRepo.AssertExpectations(t)
+	warehouseRepo.AssertNotCalled(t, "GetInventory", mock.Anything, mock.Anything)
+}
+
+func TestApproveExInvoice_Success(t *testing.T) {
+	exInvoiceRepo := new(mockRepo.MockExportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	exInvoiceRepo.On("GetExInvoiceByID", mock.Anything, 1).Return(&domain.Invoice{
+		ID:          1,
+		WarehouseID: 2,
+		Status:      domain.InvoicePendingApproval,
+		Details:     []domain.InvoiceItem{{RiceID: 1, Quantity: 100, Price: 10}},
+	}, nil)
+	warehouseRepo.On("GetInventory", mock.Anything, 2).Return([]domain.WarehouseItem{{RiceID: 1, Quantity: 100}}, nil)
+	exInvoiceRepo.On("ApproveExInvoice", mock.Anything, 1, 9).Return(&domain.Invoice{ID: 1, Status: domain.InvoiceCompleted}, nil)
+
+	service := NewExInvoicesService(exInvoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{Quantity: 50})
+	approved, err := service.ApproveExInvoice(context.TODO(), 1, 9)
+	assert.Nil(t, err)
+	assert.Equal(t, domain.InvoiceCompleted, approved.Status)
+
+	exInvoiceRepo.AssertExpectations(t)
+}
+
+func TestApproveExInvoice_FailInsufficientStock(t *testing.T) {
+	exInvoiceRepo := new(mockRepo.MockExportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	exInvoiceRepo.On("GetExInvoiceByID", mock.Anything, 1).Return(&domain.Invoice{
+		ID:          1,
+		WarehouseID: 2,
+		Status:      domain.InvoicePendingApproval,
+		Details:     []domain.InvoiceItem{{RiceID: 1, Quantity: 100, Price: 10}},
+	}, nil)
+	warehouseRepo.On("GetInventory", mock.Anything, 2).Return([]domain.WarehouseItem{{RiceID: 1, Quantity: 99}}, nil)
+
+	service := NewExInvoicesService(exInvoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{})
+	_, err := service.ApproveExInvoice(context.TODO(), 1, 9)
+	assert.Equal(t, domain.ErrInsufficientStock, err)
+
+	exInvoiceRepo.AssertNotCalled(t, "ApproveExInvoice", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApproveExInvoice_FailNotPending(t *testing.T) {
+	exInvoiceRepo := new(mockRepo.MockExportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	exInvoiceRepo.On("GetExInvoiceByID", mock.Anything, 1).Return(&domain.Invoice{ID: 1, Status: domain.InvoiceCompleted}, nil)
+
+	service := NewExInvoicesService(exInvoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{})
+	_, err := service.ApproveExInvoice(context.TODO(), 1, 9)
+	assert.Equal(t, domain.ErrInvoiceNotPending, err)
+
+	warehouseRepo.AssertNotCalled(t, "GetInventory", mock.Anything, mock.Anything)
+}
+
+func TestCancelExInvoice_FailPendingApproval(t *testing.T) {
+	exInvoiceRepo := new(mockRepo.MockExportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	exInvoiceRepo.On("GetExInvoiceByID", mock.Anything, 1).Return(&domain.Invoice{ID: 1, Status: domain.InvoicePendingApproval}, nil)
+
+	service := NewExInvoicesService(exInvoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{})
+	_, err := service.CancelExInvoice(context.TODO(), 1, "wrong quantity")
+	assert.Equal(t, domain.ErrInvoiceNotCompleted, err)
+
+	exInvoiceRepo.AssertNotCalled(t, "CancelExInvoice", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateExInvoice_PartnerRole(t *testing.T) {
+	tests := []struct {
+		role domain.PartnerRole
+		err  error
+	}{
+		{domain.PartnerCustomer, nil},
+		{domain.PartnerBoth, nil},
+		{domain.PartnerSupplier, domain.ErrInvalidPartnerRole},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			exInvoiceRepo := new(mockRepo.MockExportInvoiceRepository)
+			warehouseRepo := new(mockRepo.MockWarehouseRepository)
+			customerRepo := new(mockRepo.MockCustomerRepository)
+
+			customerRepo.On("GetCustomerByID", mock.Anything, 3).Return(&domain.Customer{ID: 3, Role: tt.role}, nil)
+			warehouseRepo.On("GetInventory", mock.Anything, 2).Return([]domain.WarehouseItem{{RiceID: 1, Quantity: 100}}, nil)
+			exInvoiceRepo.On("CreateExInvoice", mock.Anything, mock.Anything).Return(&domain.Invoice{ID: 1}, nil)
+
+			service := NewExInvoicesService(exInvoiceRepo, warehouseRepo, customerRepo, &mapmutex.Mapmutex{}, domain.ApprovalRule{})
+			_, err := service.CreateExInvoice(context.TODO(), &domain.Invoice{
+				WarehouseID: 2,
+				CustomerID:  3,
+				Details:     []domain.InvoiceItem{{RiceID: 1, Quantity: 100, Price: 10}},
+			})
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
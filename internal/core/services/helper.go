@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+// hasEnoughStock is a helper func check if inventory has enough available stock for every item,
+// stock reserved by sales orders is not available
+func hasEnoughStock(inventory []domain.WarehouseItem, items []domain.InvoiceItem) bool {
+	stock := make(map[int]int, len(inventory))
+	for _, item := range inventory {
+		stock[item.RiceID] = item.Available()
+	}
+
+	for _, item := range items {
+		if stock[item.RiceID] < item.Quantity {
+			return false
+		}
+	}
+
+	return true
+}
+
+// releaseReservation is a helper func give back the stock a sales order holds to the available stock of the inventory
+func releaseReservation(inventory []domain.WarehouseItem, items []domain.InvoiceItem) []domain.WarehouseItem {
+	reserved := make(map[int]int, len(items))
+	for _, item := range items {
+		reserved[item.RiceID] += item.Quantity
+	}
+
+	released := make([]domain.WarehouseItem, 0, len(inventory))
+	for _, item := range inventory {
+		item.Reserved -= reserved[item.RiceID]
+		released = append(released, item)
+	}
+	return released
+}
+
+// validateLots check the lots created by the lines of an import invoice,
+// a lot can not expire before it is harvested
+func validateLots(items []domain.InvoiceItem) error {
+	for _, item := range items {
+		lot := item.Lot
+		if lot == nil || lot.HarvestDate == nil || lot.ExpiryDate == nil {
+			continue
+		}
+		if lot.ExpiryDate.Before(*lot.HarvestDate) {
+			return domain.ErrInvalidDateRange
+		}
+	}
+	return nil
+}
+
+// validateLotAllocations check the lots named by the lines of an export invoice add up to the line quantity,
+// a line without lots is taken first-expiry-first-out
+func validateLotAllocations(items []domain.InvoiceItem) error {
+	for _, item := range items {
+		if len(item.Lots) == 0 {
+			continue
+		}
+
+		total := 0
+		seen := make(map[int]bool, len(item.Lots))
+		for _, v := range item.Lots {
+			if v.Quantity <= 0 || seen[v.LotID] {
+				return domain.ErrInvalidLotAllocation
+			}
+			seen[v.LotID] = true
+			total += v.Quantity
+		}
+		if total != item.Quantity {
+			return domain.ErrInvalidLotAllocation
+		}
+	}
+	return nil
+}
+
+// checkPartner check the partner of an invoice or order can act as role,
+// import invoices and purchase orders need a supplier, export invoices and sales orders a customer
+func checkPartner(ctx context.Context, customerRepo ports.ICustomerRepository, customerID int, role domain.PartnerRole) error {
+	customer, err := customerRepo.GetCustomerByID(ctx, customerID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	if !customer.HasRole(role) {
+		return domain.ErrInvalidPartnerRole
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+// idempotencyLockTimeout is how long a key stays claimed by a request that never finished,
+// after it a request with the key runs again
+const idempotencyLockTimeout = 5 * time.Minute
+
+type idempotencyService struct {
+	repo   ports.IIdempotencyRepository
+	window time.Duration
+}
+
+// NewIdempotencyService create the idempotency key service, responses are replayed for window
+func NewIdempotencyService(repo ports.IIdempotencyRepository, window time.Duration) ports.IIdempotencyService {
+	return &idempotencyService{
+		repo:   repo,
+		window: window,
+	}
+}
+
+func (i *idempotencyService) Begin(ctx context.Context, userID int, key string, fingerprint string) (*domain.IdempotencyRecord, error) {
+	now := time.Now()
+	record := &domain.IdempotencyRecord{
+		UserID:      userID,
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(i.window),
+	}
+
+	// the second attempt runs after an expired or abandoned record is deleted
+	for attempt := 0; attempt < 2; attempt++ {
+		created, err := i.repo.CreateIdempotencyRecord(ctx, record)
+		if err == nil {
+			return created, nil
+		}
+		if err != domain.ErrConflictingData {
+			return nil, domain.ErrInternal
+		}
+
+		existing, err := i.repo.GetIdempotencyRecord(ctx, userID, key)
+		if err == domain.ErrDataNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+
+		abandoned := !existing.Completed && existing.CreatedAt.Add(idempotencyLockTimeout).Before(now)
+		if existing.ExpiresAt.Before(now) || abandoned {
+			err = i.repo.DeleteIdempotencyRecord(ctx, existing.ID)
+			if err != nil && err != domain.ErrDataNotFound {
+				return nil, domain.ErrInternal
+			}
+			continue
+		}
+
+		if existing.Fingerprint != fingerprint {
+			return nil, domain.ErrIdempotencyKeyReused
+		}
+		if !existing.Completed {
+			return nil, domain.ErrIdempotencyInProgress
+		}
+		return existing, nil
+	}
+
+	// another request claimed the key again between the delete and the create
+	return nil, domain.ErrIdempotencyInProgress
+}
+
+func (i *idempotencyService) Complete(ctx context.Context, record *domain.IdempotencyRecord, statusCode int, response []byte) error {
+	err := i.repo.CompleteIdempotencyRecord(ctx, record.ID, statusCode, response)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	record.StatusCode = statusCode
+	record.Response = response
+	record.Completed = true
+	return nil
+}
+
+func (i *idempotencyService) Release(ctx context.Context, record *domain.IdempotencyRecord) error {
+	err := i.repo.DeleteIdempotencyRecord(ctx, record.ID)
+	if err != nil && err != domain.ErrDataNotFound {
+		return domain.ErrInternal
+	}
+	return nil
+}
+
+func (i *idempotencyService) DeleteExpired(ctx context.Context) (int64, error) {
+	deleted, err := i.repo.DeleteExpiredIdempotencyRecords(ctx, time.Now())
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+	return deleted, nil
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	mockRepo "github.com/tommjj/ql-kho-lua/internal/core/services/mock"
+)
+
+func TestIdempotencyServiceImplements(t *testing.T) {
+	assert.Implements(t, (*ports.IIdempotencyService)(nil), new(idempotencyService))
+}
+
+func TestBegin_NewKey(t *testing.T) {
+	repo := new(mockRepo.MockIdempotencyRepository)
+	repo.On("CreateIdempotencyRecord", context.TODO(), mock.Anything).Return(&domain.IdempotencyRecord{ID: 1}, nil)
+
+	service := NewIdempotencyService(repo, time.Hour)
+	record, err := service.Begin(context.TODO(), 2, "key-1", "abc")
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, record.ID)
+	assert.False(t, record.Completed)
+
+	created := repo.Calls[0].Arguments.Get(1).(*domain.IdempotencyRecord)
+	assert.Equal(t, 2, created.UserID)
+	assert.Equal(t, "key-1", created.Key)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), created.ExpiresAt, time.Minute)
+}
+
+func TestBegin_Replay(t *testing.T) {
+	now := time.Now()
+	existing := &domain.IdempotencyRecord{
+		ID: 1, UserID: 2, Key: "key-1", Fingerprint: "abc", StatusCode: 200, Response: []byte(`{"success":true}`),
+		Completed: true, CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour),
+	}
+
+	repo := new(mockRepo.MockIdempotencyRepository)
+	repo.On("CreateIdempotencyRecord", context.TODO(), mock.Anything).Return(nil, domain.ErrConflictingData)
+	repo.On("GetIdempotencyRecord", context.TODO(), 2, "key-1").Return(existing, nil)
+
+	service := NewIdempotencyService(repo, time.Hour)
+	record, err := service.Begin(context.TODO(), 2, "key-1", "abc")
+
+	assert.Nil(t, err)
+	assert.True(t, record.Completed)
+	assert.Equal(t, existing.Response, record.Response)
+}
+
+func TestBegin_Conflicts(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		existing domain.IdempotencyRecord
+		err      error
+	}{
+		{
+			name:     "reused with a different request",
+			existing: domain.IdempotencyRecord{ID: 1, Fingerprint: "other", Completed: true, CreatedAt: now, ExpiresAt: now.Add(time.Hour)},
+			err:      domain.ErrIdempotencyKeyReused,
+		},
+		{
+			name:     "first request still running",
+			existing: domain.IdempotencyRecord{ID: 1, Fingerprint: "abc", CreatedAt: now, ExpiresAt: now.Add(time.Hour)},
+			err:      domain.ErrIdempotencyInProgress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockRepo.MockIdempotencyRepository)
+			repo.On("CreateIdempotencyRecord", context.TODO(), mock.Anything).Return(nil, domain.ErrConflictingData)
+			repo.On("GetIdempotencyRecord", context.TODO(), 2, "key-1").Return(&tt.existing, nil)
+
+			service := NewIdempotencyService(repo, time.Hour)
+			_, err := service.Begin(context.TODO(), 2, "key-1", "abc")
+
+			assert.Equal(t, tt.err, err)
+			repo.AssertNotCalled(t, "DeleteIdempotencyRecord", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBegin_TakeOver(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		existing domain.IdempotencyRecord
+	}{
+		{
+			name:     "expired",
+			existing: domain.IdempotencyRecord{ID: 1, Fingerprint: "other", Completed: true, CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)},
+		},
+		{
+			name:     "abandoned",
+			existing: domain.IdempotencyRecord{ID: 1, Fingerprint: "abc", CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockRepo.MockIdempotencyRepository)
+			repo.On("CreateIdempotencyRecord", context.TODO(), mock.Anything).Return(nil, domain.ErrConflictingData).Once()
+			repo.On("CreateIdempotencyRecord", context.TODO(), mock.Anything).Return(&domain.IdempotencyRecord{ID: 2}, nil).Once()
+			repo.On("GetIdempotencyRecord", context.TODO(), 2, "key-1").Return(&tt.existing, nil)
+			repo.On("DeleteIdempotencyRecord", context.TODO(), 1).Return(nil)
+
+			service := NewIdempotencyService(repo, time.Hour)
+			record, err := service.Begin(context.TODO(), 2, "key-1", "abc")
+
+			assert.Nil(t, err)
+			assert.Equal(t, 2, record.ID)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBegin_RepositoryError(t *testing.T) {
+	repo := new(mockRepo.MockIdempotencyRepository)
+	repo.On("CreateIdempotencyRecord", context.TODO(), mock.Anything).Return(nil, errors.New("connection refused"))
+
+	service := NewIdempotencyService(repo, time.Hour)
+	_, err := service.Begin(context.TODO(), 2, "key-1", "abc")
+
+	assert.Equal(t, domain.ErrInternal, err)
+}
+
+func TestCompleteAndRelease(t *testing.T) {
+	repo := new(mockRepo.MockIdempotencyRepository)
+	repo.On("CompleteIdempotencyRecord", context.TODO(), 1, 200, []byte(`{}`)).Return(nil)
+	repo.On("DeleteIdempotencyRecord", context.TODO(), 3).Return(domain.ErrDataNotFound)
+
+	service := NewIdempotencyService(repo, time.Hour)
+
+	record := &domain.IdempotencyRecord{ID: 1}
+	assert.Nil(t, service.Complete(context.TODO(), record, 200, []byte(`{}`)))
+	assert.True(t, record.Completed)
+
+	// a key deleted by the cleanup job is already free
+	assert.Nil(t, service.Release(context.TODO(), &domain.IdempotencyRecord{ID: 3}))
+}
@@ -12,60 +12,183 @@ import (
 type imInvoiceService struct {
 	imInvoiceRepo ports.IImportInvoicesRepository
 	warehouseRepo ports.IWarehouseRepository
+	customerRepo  ports.ICustomerRepository
 	l             *mapmutex.Mapmutex
+	rule          domain.ApprovalRule
 }
 
 func NewImInvoicesService(
 	imInvoiceRepo ports.IImportInvoicesRepository,
 	warehouseRepo ports.IWarehouseRepository,
-	l *mapmutex.Mapmutex) ports.IImportInvoicesService {
+	customerRepo ports.ICustomerRepository,
+	l *mapmutex.Mapmutex,
+	rule domain.ApprovalRule) ports.IImportInvoicesService {
 	return &imInvoiceService{
 		imInvoiceRepo: imInvoiceRepo,
 		warehouseRepo: warehouseRepo,
+		customerRepo:  customerRepo,
 		l:             l,
+		rule:          rule,
 	}
 }
 
 func (i *imInvoiceService) CreateImInvoice(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error) {
+	err := validateLots(invoice.Details)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkPartner(ctx, i.customerRepo, invoice.CustomerID, domain.PartnerSupplier)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.CalcTotalPrice()
+	invoice.Status = domain.InvoiceCompleted
+	// an invoice above the approval rule adds no stock until it is approved, its capacity is checked then
+	if i.rule.Requires(invoice) {
+		invoice.Status = domain.InvoicePendingApproval
+	}
+
 	i.l.Lock(invoice.WarehouseID)
 	defer i.l.UnLock(invoice.WarehouseID)
 
-	store, err := i.warehouseRepo.GetWarehouseByID(ctx, invoice.WarehouseID)
+	if invoice.Status == domain.InvoiceCompleted {
+		err = i.checkCapacity(ctx, invoice)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := i.imInvoiceRepo.CreateImInvoice(ctx, invoice)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound, domain.ErrConflictingData:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return created, nil
+}
+
+func (i *imInvoiceService) ApproveImInvoice(ctx context.Context, id int, approverID int) (*domain.Invoice, error) {
+	invoice, err := i.imInvoiceRepo.GetImInvoiceByID(ctx, id)
 	if err != nil {
 		if err == domain.ErrDataNotFound {
 			return nil, err
 		}
+		return nil, domain.ErrInternal
+	}
+
+	if invoice.Status != domain.InvoicePendingApproval {
+		return nil, domain.ErrInvoiceNotPending
+	}
+
+	i.l.Lock(invoice.WarehouseID)
+	defer i.l.UnLock(invoice.WarehouseID)
+
+	err = i.checkCapacity(ctx, invoice)
+	if err != nil {
 		return nil, err
 	}
 
+	approved, err := i.imInvoiceRepo.ApproveImInvoice(ctx, id, approverID)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound, domain.ErrInvoiceNotPending:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return approved, nil
+}
+
+func (i *imInvoiceService) RejectImInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	rejected, err := i.imInvoiceRepo.RejectImInvoice(ctx, id, reason)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound, domain.ErrInvoiceNotPending:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return rejected, nil
+}
+
+// checkCapacity check the warehouse has room for the invoice on top of its used capacity
+func (i *imInvoiceService) checkCapacity(ctx context.Context, invoice *domain.Invoice) error {
+	store, err := i.warehouseRepo.GetWarehouseByID(ctx, invoice.WarehouseID)
+	if err != nil {
+		return err
+	}
+
 	used, err := i.warehouseRepo.GetUsedCapacityByID(ctx, invoice.WarehouseID)
 	if err != nil {
 		if err != domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	if (int(used) + invoice.Quantity()) > store.Capacity {
+		return domain.ErrWarehouseFull
+	}
+	return nil
+}
+
+func (i *imInvoiceService) CancelImInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	invoice, err := i.imInvoiceRepo.GetImInvoiceByID(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
 			return nil, err
 		}
 		return nil, domain.ErrInternal
 	}
 
-	var capacity int
-	for _, v := range invoice.Details {
-		capacity += v.Quantity
+	if invoice.Status == domain.InvoiceCancelled {
+		return nil, domain.ErrInvoiceCancelled
 	}
-	if (int(used) + capacity) > store.Capacity {
-		return nil, domain.ErrWarehouseFull
+	if invoice.Status != domain.InvoiceCompleted {
+		return nil, domain.ErrInvoiceNotCompleted
+	}
+	if invoice.PaidAmount > 0 {
+		return nil, domain.ErrInvoicePaid
 	}
 
-	invoice.CalcTotalPrice()
-	created, err := i.imInvoiceRepo.CreateImInvoice(ctx, invoice)
+	i.l.Lock(invoice.WarehouseID)
+	defer i.l.UnLock(invoice.WarehouseID)
+
+	// removing the imported rice must not push the stock below zero
+	inventory, err := i.warehouseRepo.GetInventory(ctx, invoice.WarehouseID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if !hasEnoughStock(inventory, invoice.Details) {
+		return nil, domain.ErrInsufficientStock
+	}
+
+	cancelled, err := i.imInvoiceRepo.CancelImInvoice(ctx, id, reason)
 	if err != nil {
 		switch err {
-		case domain.ErrDataNotFound:
-			return nil, domain.ErrDataNotFound
+		case domain.ErrDataNotFound, domain.ErrInvoiceCancelled, domain.ErrInvoiceNotCompleted, domain.ErrInvoicePaid, domain.ErrLotConsumed,
+			domain.ErrTransferInvoice:
+			return nil, err
 		default:
 			return nil, domain.ErrInternal
 		}
 	}
 
-	return created, nil
+	return cancelled, nil
 }
 
 func (i *imInvoiceService) GetImInvoiceByID(ctx context.Context, id int) (*domain.Invoice, error) {
@@ -104,3 +227,17 @@ func (i *imInvoiceService) GetListImInvoices(ctx context.Context, warehouseID in
 
 	return invoice, nil
 }
+
+func (i *imInvoiceService) GetListImInvoicesWithAssociations(ctx context.Context, warehouseID int, start *time.Time, end *time.Time, afterID, limit int) ([]domain.Invoice, error) {
+	invoices, err := i.imInvoiceRepo.GetListImInvoicesWithAssociations(ctx, warehouseID, start, end, afterID, limit)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, domain.ErrDataNotFound
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return invoices, nil
+}
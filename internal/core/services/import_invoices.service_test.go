@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/mapmutex"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	mockRepo "github.com/tommjj/ql-kho-lua/internal/core/services/mock"
+)
+
+func TestImInvoiceServiceImplements(t *testing.T) {
+	assert.Implements(t, (*ports.IImportInvoicesService)(nil), new(imInvoiceService))
+}
+
+// newTestPartnerRepo return a customer repository where every partner is both supplier and customer
+func newTestPartnerRepo() *mockRepo.MockCustomerRepository {
+	customerRepo := new(mockRepo.MockCustomerRepository)
+	customerRepo.On("GetCustomerByID", mock.Anything, mock.Anything).Return(&domain.Customer{ID: 1, Role: domain.PartnerBoth}, nil)
+	return customerRepo
+}
+
+func newCompletedImInvoice() *domain.Invoice {
+	return &domain.Invoice{
+		ID:          1,
+		WarehouseID: 2,
+		Status:      domain.InvoiceCompleted,
+		Details: []domain.InvoiceItem{
+			{RiceID: 1, Quantity: 100, Price: 10},
+			{RiceID: 2, Quantity: 50, Price: 20},
+		},
+	}
+}
+
+func TestCancelImInvoice_Success(t *testing.T) {
+	invoiceRepo := new(mockRepo.MockImportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	invoiceRepo.On("GetImInvoiceByID", mock.Anything, 1).Return(newCompletedImInvoice(), nil)
+	warehouseRepo.On("GetInventory", mock.Anything, 2).Return([]domain.WarehouseItem{
+		{RiceID: 1, Quantity: 100},
+		{RiceID: 2, Quantity: 80},
+	}, nil)
+	invoiceRepo.On("CancelImInvoice", mock.Anything, 1, "wrong quantity").
+		Return(&domain.Invoice{ID: 1, Status: domain.InvoiceCancelled}, nil)
+
+	service := NewImInvoicesService(invoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{})
+	invoice, err := service.CancelImInvoice(context.TODO(), 1, "wrong quantity")
+	assert.Nil(t, err)
+	assert.Equal(t, domain.InvoiceCancelled, invoice.Status)
+
+	invoiceRepo.AssertExpectations(t)
+	warehouseRepo.AssertExpectations(t)
+}
+
+func TestCancelImInvoice_FailNegativeStock(t *testing.T) {
+	invoiceRepo := new(mockRepo.MockImportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	invoiceRepo.On("GetImInvoiceByID", mock.Anything, 1).Return(newCompletedImInvoice(), nil)
+	warehouseRepo.On("GetInventory", mock.Anything, 2).Return([]domain.WarehouseItem{
+		{RiceID: 1, Quantity: 100},
+		{RiceID: 2, Quantity: 49},
+	}, nil)
+
+	service := NewImInvoicesService(invoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{})
+	_, err := service.CancelImInvoice(context.TODO(), 1, "wrong quantity")
+	assert.Equal(t, domain.ErrInsufficientStock, err)
+
+	invoiceRepo.AssertNotCalled(t, "CancelImInvoice", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCancelImInvoice_FailAlreadyCancelled(t *testing.T) {
+	invoiceRepo := new(mockRepo.MockImportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	invoice := newCompletedImInvoice()
+	invoice.Status = domain.InvoiceCancelled
+	invoiceRepo.On("GetImInvoiceByID", mock.Anything, 1).Return(invoice, nil)
+
+	service := NewImInvoicesService(invoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{})
+	_, err := service.CancelImInvoice(context.TODO(), 1, "wrong quantity")
+	assert.Equal(t, domain.ErrInvoiceCancelled, err)
+}
+
+func TestCancelImInvoice_FailPaid(t *testing.T) {
+	invoiceRepo := new(mockRepo.MockImportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	invoice := newCompletedImInvoice()
+	invoice.PaidAmount = 100
+	invoiceRepo.On("GetImInvoiceByID", mock.Anything, 1).Return(invoice, nil)
+
+	service := NewImInvoicesService(invoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{})
+	_, err := service.CancelImInvoice(context.TODO(), 1, "wrong quantity")
+
+	invoiceRepo.AssertNotCalled(t, "CancelImInvoice", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, domain.ErrInvoicePaid, err)
+}
+
+func TestCancelImInvoice_FailNotFound(t *testing.T) {
+	invoiceRepo := new(mockRepo.MockImportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	invoiceRepo.On("GetImInvoiceByID", mock.Anything, 1).Return(nil, domain.ErrDataNotFound)
+
+	service := NewImInvoicesService(invoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{})
+	_, err := service.CancelImInvoice(context.TODO(), 1, "wrong quantity")
+	assert.Equal(t, domain.ErrDataNotFound, err)
+}
+
+func TestCreateImInvoice_FailLotExpiresBeforeHarvest(t *testing.T) {
+	invoiceRepo := new(mockRepo.MockImportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	harvest := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	expiry := harvest.AddDate(0, -1, 0)
+
+	service := NewImInvoicesService(invoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{})
+	_, err := service.CreateImInvoice(context.TODO(), &domain.Invoice{
+		WarehouseID: 2,
+		Details: []domain.InvoiceItem{
+			{RiceID: 1, Quantity: 10, Price: 10, Lot: &domain.Lot{HarvestDate: &harvest, ExpiryDate: &expiry}},
+		},
+	})
+	assert.Equal(t, domain.ErrInvalidDateRange, err)
+
+	invoiceRepo.AssertNotCalled(t, "CreateImInvoice")
+	warehouseRepo.AssertNotCalled(t, "GetWarehouseByID")
+}
+
+func TestCancelImInvoice_FailLotConsumed(t *testing.T) {
+	invoiceRepo := new(mockRepo.MockImportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	invoiceRepo.On("GetImInvoiceByID", mock.Anything, 1).Return(newCompletedImInvoice(), nil)
+	warehouseRepo.On("GetInventory", mock.Anything, 2).Return([]domain.WarehouseItem{
+		{RiceID: 1, Quantity: 100},
+		{RiceID: 2, Quantity: 80},
+	}, nil)
+	invoiceRepo.On("CancelImInvoice", mock.Anything, 1, "wrong quantity").Return(nil, domain.ErrLotConsumed)
+
+	service := NewImInvoicesService(invoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{})
+	_, err := service.CancelImInvoice(context.TODO(), 1, "wrong quantity")
+	assert.Equal(t, domain.ErrLotConsumed, err)
+}
+
+func TestCreateImInvoice_PendingApproval(t *testing.T) {
+	invoiceRepo := new(mockRepo.MockImportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	invoiceRepo.On("CreateImInvoice", mock.Anything, mock.MatchedBy(func(invoice *domain.Invoice) bool {
+		return invoice.Status == domain.InvoicePendingApproval
+	})).Return(&domain.Invoice{ID: 1, Status: domain.InvoicePendingApproval}, nil)
+
+	invoice := newCompletedImInvoice()
+	invoice.Status = ""
+	// 100*10 + 50*20 is above the total price limit
+	service := NewImInvoicesService(invoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{TotalPrice: 1999})
+	_, err := service.CreateImInvoice(context.TODO(), invoice)
+	assert.Nil(t, err)
+
+	invoiceRepo.AssertExpectations(t)
+	warehouseRepo.AssertNotCalled(t, "GetUsedCapacityByID", mock.Anything, mock.Anything)
+}
+
+func TestApproveImInvoice_FailWarehouseFull(t *testing.T) {
+	invoiceRepo := new(mockRepo.MockImportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	invoice := newCompletedImInvoice()
+	invoice.Status = domain.InvoicePendingApproval
+	invoiceRepo.On("GetImInvoiceByID", mock.Anything, 1).Return(invoice, nil)
+	warehouseRepo.On("GetWarehouseByID", mock.Anything, 2).Return(&domain.Warehouse{ID: 2, Capacity: 500}, nil)
+	warehouseRepo.On("GetUsedCapacityByID", mock.Anything, 2).Return(int64(351), nil)
+
+	service := NewImInvoicesService(invoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{})
+	_, err := service.ApproveImInvoice(context.TODO(), 1, 9)
+	assert.Equal(t, domain.ErrWarehouseFull, err)
+
+	invoiceRepo.AssertNotCalled(t, "ApproveImInvoice", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApproveImInvoice_Success(t *testing.T) {
+	invoiceRepo := new(mockRepo.MockImportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	invoice := newCompletedImInvoice()
+	invoice.Status = domain.InvoicePendingApproval
+	invoiceRepo.On("GetImInvoiceByID", mock.Anything, 1).Return(invoice, nil)
+	warehouseRepo.On("GetWarehouseByID", mock.Anything, 2).Return(&domain.Warehouse{ID: 2, Capacity: 500}, nil)
+	warehouseRepo.On("GetUsedCapacityByID", mock.Anything, 2).Return(int64(350), nil)
+	invoiceRepo.On("ApproveImInvoice", mock.Anything, 1, 9).Return(&domain.Invoice{ID: 1, Status: domain.InvoiceCompleted}, nil)
+
+	service := NewImInvoicesService(invoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{})
+	approved, err := service.ApproveImInvoice(context.TODO(), 1, 9)
+	assert.Nil(t, err)
+	assert.Equal(t, domain.InvoiceCompleted, approved.Status)
+
+	invoiceRepo.AssertExpectations(t)
+}
+
+func TestRejectImInvoice_FailNotPending(t *testing.T) {
+	invoiceRepo := new(mockRepo.MockImportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	invoiceRepo.On("RejectImInvoice", mock.Anything, 1, "too large").Return(nil, domain.ErrInvoiceNotPending)
+
+	service := NewImInvoicesService(invoiceRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{})
+	_, err := service.RejectImInvoice(context.TODO(), 1, "too large")
+	assert.Equal(t, domain.ErrInvoiceNotPending, err)
+}
+
+func TestCreateImInvoice_FailPartnerNotSupplier(t *testing.T) {
+	invoiceRepo := new(mockRepo.MockImportInvoiceRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+	customerRepo := new(mockRepo.MockCustomerRepository)
+
+	customerRepo.On("GetCustomerByID", mock.Anything, 3).Return(&domain.Customer{ID: 3, Role: domain.PartnerCustomer}, nil)
+
+	invoice := newCompletedImInvoice()
+	invoice.CustomerID = 3
+	service := NewImInvoicesService(invoiceRepo, warehouseRepo, customerRepo, &mapmutex.Mapmutex{}, domain.ApprovalRule{})
+	_, err := service.CreateImInvoice(context.TODO(), invoice)
+	assert.Equal(t, domain.ErrInvalidPartnerRole, err)
+
+	warehouseRepo.AssertNotCalled(t, "GetWarehouseByID", mock.Anything, mock.Anything)
+	invoiceRepo.AssertNotCalled(t, "CreateImInvoice", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type MockAccessControlRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccessControlRepository) GetAccessLevel(ctx context.Context, warehouseID int, userID int) (domain.AccessLevel, error) {
+	args := m.Called(ctx, warehouseID, userID)
+	return args.Get(0).(domain.AccessLevel), args.Error(1)
+}
+
+func (m *MockAccessControlRepository) SetAccess(ctx context.Context, warehouseID int, userID int, level domain.AccessLevel) error {
+	args := m.Called(ctx, warehouseID, userID, level)
+	return args.Error(0)
+}
+
+func (m *MockAccessControlRepository) UpdateAccess(ctx context.Context, warehouseID int, userID int, level domain.AccessLevel) error {
+	args := m.Called(ctx, warehouseID, userID, level)
+	return args.Error(0)
+}
+
+func (m *MockAccessControlRepository) DelAccess(ctx context.Context, warehouseID int, userID int) error {
+	args := m.Called(ctx, warehouseID, userID)
+	return args.Error(0)
+}
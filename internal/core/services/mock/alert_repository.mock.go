@@ -0,0 +1,92 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type MockAlertRepository struct {
+	mock.Mock
+}
+
+func (m *MockAlertRepository) SetThreshold(ctx context.Context, threshold *domain.StockThreshold) (*domain.StockThreshold, error) {
+	args := m.Called(ctx, threshold)
+	if t, ok := args.Get(0).(*domain.StockThreshold); ok {
+		return t, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAlertRepository) GetThresholds(ctx context.Context, warehouseID int) ([]domain.StockThreshold, error) {
+	args := m.Called(ctx, warehouseID)
+	if t, ok := args.Get(0).([]domain.StockThreshold); ok {
+		return t, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAlertRepository) DeleteThreshold(ctx context.Context, warehouseID, riceID int) error {
+	args := m.Called(ctx, warehouseID, riceID)
+	return args.Error(0)
+}
+
+func (m *MockAlertRepository) GetLowStock(ctx context.Context) ([]domain.LowStock, error) {
+	args := m.Called(ctx)
+	if l, ok := args.Get(0).([]domain.LowStock); ok {
+		return l, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAlertRepository) GetNearCapacity(ctx context.Context, ratio float64) ([]domain.WarehouseUsage, error) {
+	args := m.Called(ctx, ratio)
+	if u, ok := args.Get(0).([]domain.WarehouseUsage); ok {
+		return u, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAlertRepository) GetExpiringLots(ctx context.Context, before time.Time) ([]domain.Lot, error) {
+	args := m.Called(ctx, before)
+	if l, ok := args.Get(0).([]domain.Lot); ok {
+		return l, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAlertRepository) CreateAlert(ctx context.Context, alert *domain.Alert) (bool, error) {
+	args := m.Called(ctx, alert)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAlertRepository) CountAlerts(ctx context.Context, filter domain.AlertFilter) (int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAlertRepository) GetListAlerts(ctx context.Context, filter domain.AlertFilter, limit, skip int) ([]domain.Alert, error) {
+	args := m.Called(ctx, filter, limit, skip)
+	if a, ok := args.Get(0).([]domain.Alert); ok {
+		return a, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAlertRepository) GetAlertByID(ctx context.Context, id int) (*domain.Alert, error) {
+	args := m.Called(ctx, id)
+	if a, ok := args.Get(0).(*domain.Alert); ok {
+		return a, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAlertRepository) AcknowledgeAlert(ctx context.Context, id, userID int) (*domain.Alert, error) {
+	args := m.Called(ctx, id, userID)
+	if a, ok := args.Get(0).(*domain.Alert); ok {
+		return a, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
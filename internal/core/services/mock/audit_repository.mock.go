@@ -0,0 +1,30 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) CreateAuditLog(ctx context.Context, log *domain.AuditLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) CountAuditLogs(ctx context.Context, filter domain.AuditFilter) (int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAuditRepository) GetListAuditLogs(ctx context.Context, filter domain.AuditFilter, limit, skip int) ([]domain.AuditLog, error) {
+	args := m.Called(ctx, filter, limit, skip)
+	if logs, ok := args.Get(0).([]domain.AuditLog); ok {
+		return logs, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,38 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type MockBulkImportRepository struct {
+	mock.Mock
+}
+
+// errorsArg return the row errors of a mocked call, nil when none are given
+func errorsArg(args mock.Arguments, i int) []error {
+	if errs, ok := args.Get(i).([]error); ok {
+		return errs
+	}
+	return nil
+}
+
+func (m *MockBulkImportRepository) CreateRiceBatch(ctx context.Context, rice []domain.Rice, commit bool) ([]domain.Rice, []error, error) {
+	args := m.Called(ctx, rice, commit)
+	created, _ := args.Get(0).([]domain.Rice)
+	return created, errorsArg(args, 1), args.Error(2)
+}
+
+func (m *MockBulkImportRepository) CreateCustomerBatch(ctx context.Context, customers []domain.Customer, commit bool) ([]domain.Customer, []error, error) {
+	args := m.Called(ctx, customers, commit)
+	created, _ := args.Get(0).([]domain.Customer)
+	return created, errorsArg(args, 1), args.Error(2)
+}
+
+func (m *MockBulkImportRepository) CreateWarehouseBatch(ctx context.Context, warehouses []domain.Warehouse, commit bool) ([]domain.Warehouse, []error, error) {
+	args := m.Called(ctx, warehouses, commit)
+	created, _ := args.Get(0).([]domain.Warehouse)
+	return created, errorsArg(args, 1), args.Error(2)
+}
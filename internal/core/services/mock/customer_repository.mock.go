@@ -0,0 +1,67 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type MockCustomerRepository struct {
+	mock.Mock
+}
+
+func (m *MockCustomerRepository) CreateCustomer(ctx context.Context, customer *domain.Customer) (*domain.Customer, error) {
+	args := m.Called(ctx, customer)
+	if customer, ok := args.Get(0).(*domain.Customer); ok {
+		return customer, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
+
+func (m *MockCustomerRepository) GetCustomerByID(ctx context.Context, id int) (*domain.Customer, error) {
+	args := m.Called(ctx, id)
+	if customer, ok := args.Get(0).(*domain.Customer); ok {
+		return customer, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
+
+func (m *MockCustomerRepository) CountCustomers(ctx context.Context, query string, role domain.PartnerRole) (int64, error) {
+	args := m.Called(ctx, query, role)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCustomerRepository) GetListCustomers(ctx context.Context, query string, role domain.PartnerRole, limit, skip int) ([]domain.Customer, error) {
+	args := m.Called(ctx, query, role, limit, skip)
+	if customers, ok := args.Get(0).([]domain.Customer); ok {
+		return customers, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
+
+func (m *MockCustomerRepository) GetListCustomersAfter(ctx context.Context, query string, role domain.PartnerRole, afterID, limit int) ([]domain.Customer, error) {
+	args := m.Called(ctx, query, role, afterID, limit)
+	if customers, ok := args.Get(0).([]domain.Customer); ok {
+		return customers, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
+
+func (m *MockCustomerRepository) UpdateCustomer(ctx context.Context, customer *domain.Customer) (*domain.Customer, error) {
+	args := m.Called(ctx, customer)
+	if customer, ok := args.Get(0).(*domain.Customer); ok {
+		return customer, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
+
+func (m *MockCustomerRepository) DeleteCustomer(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
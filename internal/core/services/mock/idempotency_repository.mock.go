@@ -0,0 +1,44 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepository) CreateIdempotencyRecord(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	args := m.Called(ctx, record)
+	if r, ok := args.Get(0).(*domain.IdempotencyRecord); ok {
+		return r, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) GetIdempotencyRecord(ctx context.Context, userID int, key string) (*domain.IdempotencyRecord, error) {
+	args := m.Called(ctx, userID, key)
+	if r, ok := args.Get(0).(*domain.IdempotencyRecord); ok {
+		return r, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) CompleteIdempotencyRecord(ctx context.Context, id int, statusCode int, response []byte) error {
+	args := m.Called(ctx, id, statusCode, response)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) DeleteIdempotencyRecord(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) DeleteExpiredIdempotencyRecords(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,90 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type MockImportInvoiceRepository struct {
+	mock.Mock
+}
+
+func (m *MockImportInvoiceRepository) CreateImInvoice(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error) {
+	args := m.Called(ctx, invoice)
+	if invoice, ok := args.Get(0).(*domain.Invoice); ok {
+		return invoice, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
+
+func (m *MockImportInvoiceRepository) CancelImInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	args := m.Called(ctx, id, reason)
+	if invoice, ok := args.Get(0).(*domain.Invoice); ok {
+		return invoice, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
+
+func (m *MockImportInvoiceRepository) ApproveImInvoice(ctx context.Context, id int, approverID int) (*domain.Invoice, error) {
+	args := m.Called(ctx, id, approverID)
+	if invoice, ok := args.Get(0).(*domain.Invoice); ok {
+		return invoice, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
+
+func (m *MockImportInvoiceRepository) RejectImInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	args := m.Called(ctx, id, reason)
+	if invoice, ok := args.Get(0).(*domain.Invoice); ok {
+		return invoice, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
+
+func (m *MockImportInvoiceRepository) GetImInvoiceByID(ctx context.Context, id int) (*domain.Invoice, error) {
+	args := m.Called(ctx, id)
+	if invoice, ok := args.Get(0).(*domain.Invoice); ok {
+		return invoice, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
+
+func (m *MockImportInvoiceRepository) GetImInvoiceWithAssociationsByID(ctx context.Context, id int) (*domain.Invoice, error) {
+	args := m.Called(ctx, id)
+	if invoice, ok := args.Get(0).(*domain.Invoice); ok {
+		return invoice, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
+
+func (m *MockImportInvoiceRepository) CountImInvoices(ctx context.Context, warehouseID int, start *time.Time, end *time.Time) (int64, error) {
+	args := m.Called(ctx, warehouseID, start, end)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockImportInvoiceRepository) GetListImInvoices(ctx context.Context, warehouseID int, start *time.Time, end *time.Time, skip, limit int) ([]domain.Invoice, error) {
+	args := m.Called(ctx, warehouseID, start, end, skip, limit)
+	if invoices, ok := args.Get(0).([]domain.Invoice); ok {
+		return invoices, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
+
+func (m *MockImportInvoiceRepository) GetListImInvoicesWithAssociations(ctx context.Context, warehouseID int, start *time.Time, end *time.Time, afterID, limit int) ([]domain.Invoice, error) {
+	args := m.Called(ctx, warehouseID, start, end, afterID, limit)
+	if invoices, ok := args.Get(0).([]domain.Invoice); ok {
+		return invoices, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
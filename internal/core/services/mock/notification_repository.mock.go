@@ -0,0 +1,72 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) CreateSubscription(ctx context.Context, sub *domain.Subscription) (*domain.Subscription, error) {
+	args := m.Called(ctx, sub)
+	if s, ok := args.Get(0).(*domain.Subscription); ok {
+		return s, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetSubscriptionsByUser(ctx context.Context, userID int) ([]domain.Subscription, error) {
+	args := m.Called(ctx, userID)
+	if s, ok := args.Get(0).([]domain.Subscription); ok {
+		return s, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetSubscriptionsByEvent(ctx context.Context, eventType domain.EventType) ([]domain.Subscription, error) {
+	args := m.Called(ctx, eventType)
+	if s, ok := args.Get(0).([]domain.Subscription); ok {
+		return s, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNotificationRepository) DeleteSubscription(ctx context.Context, id, userID int) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) CreateNotification(ctx context.Context, notification *domain.Notification) error {
+	args := m.Called(ctx, notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) CountNotifications(ctx context.Context, userID int, unread bool) (int64, error) {
+	args := m.Called(ctx, userID, unread)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetListNotifications(ctx context.Context, userID int, unread bool, limit, skip int) ([]domain.Notification, error) {
+	args := m.Called(ctx, userID, unread, limit, skip)
+	if n, ok := args.Get(0).([]domain.Notification); ok {
+		return n, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNotificationRepository) ReadNotification(ctx context.Context, id, userID int) (*domain.Notification, error) {
+	args := m.Called(ctx, id, userID)
+	if n, ok := args.Get(0).(*domain.Notification); ok {
+		return n, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNotificationRepository) CreateDelivery(ctx context.Context, delivery *domain.Delivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
@@ -0,0 +1,57 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type MockOrderRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderRepository) CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error) {
+	args := m.Called(ctx, order)
+	if o, ok := args.Get(0).(*domain.Order); ok {
+		return o, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOrderRepository) GetOrderByID(ctx context.Context, id int) (*domain.Order, error) {
+	args := m.Called(ctx, id)
+	if o, ok := args.Get(0).(*domain.Order); ok {
+		return o, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOrderRepository) CountOrders(ctx context.Context, filter domain.OrderFilter) (int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockOrderRepository) GetListOrders(ctx context.Context, filter domain.OrderFilter, limit, skip int) ([]domain.Order, error) {
+	args := m.Called(ctx, filter, limit, skip)
+	if o, ok := args.Get(0).([]domain.Order); ok {
+		return o, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOrderRepository) UpdateOrderStatus(ctx context.Context, id int, status domain.OrderStatus) (*domain.Order, error) {
+	args := m.Called(ctx, id, status)
+	if o, ok := args.Get(0).(*domain.Order); ok {
+		return o, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOrderRepository) ConvertOrder(ctx context.Context, order *domain.Order, invoice *domain.Invoice) (*domain.Invoice, error) {
+	args := m.Called(ctx, order, invoice)
+	if i, ok := args.Get(0).(*domain.Invoice); ok {
+		return i, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
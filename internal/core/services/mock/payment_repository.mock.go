@@ -0,0 +1,63 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type MockPaymentRepository struct {
+	mock.Mock
+}
+
+func (m *MockPaymentRepository) CreatePayment(ctx context.Context, payment *domain.Payment) (*domain.Payment, error) {
+	args := m.Called(ctx, payment)
+	if p, ok := args.Get(0).(*domain.Payment); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPaymentRepository) GetPaymentByID(ctx context.Context, id int) (*domain.Payment, error) {
+	args := m.Called(ctx, id)
+	if p, ok := args.Get(0).(*domain.Payment); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPaymentRepository) GetInvoiceWarehouseID(ctx context.Context, kind domain.InvoiceKind, invoiceID int) (int, error) {
+	args := m.Called(ctx, kind, invoiceID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPaymentRepository) CountPayments(ctx context.Context, filter domain.PaymentFilter) (int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPaymentRepository) GetListPayments(ctx context.Context, filter domain.PaymentFilter, limit, skip int) ([]domain.Payment, error) {
+	args := m.Called(ctx, filter, limit, skip)
+	if p, ok := args.Get(0).([]domain.Payment); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPaymentRepository) GetPartnerBalances(ctx context.Context, kind domain.InvoiceKind, warehouseID int) ([]domain.PartnerBalance, error) {
+	args := m.Called(ctx, kind, warehouseID)
+	if b, ok := args.Get(0).([]domain.PartnerBalance); ok {
+		return b, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPaymentRepository) GetOpenInvoices(ctx context.Context, kind domain.InvoiceKind, warehouseID int, at time.Time) ([]domain.OpenInvoice, error) {
+	args := m.Called(ctx, kind, warehouseID, at)
+	if i, ok := args.Get(0).([]domain.OpenInvoice); ok {
+		return i, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,45 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type MockReportRepository struct {
+	mock.Mock
+}
+
+func (m *MockReportRepository) GetStockMovement(ctx context.Context, warehouseID int, start, end time.Time) ([]domain.StockMovement, error) {
+	args := m.Called(ctx, warehouseID, start, end)
+	if movements, ok := args.Get(0).([]domain.StockMovement); ok {
+		return movements, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockReportRepository) GetRevenue(ctx context.Context, filter domain.InvoiceAnalyticsFilter) ([]domain.InvoiceTotal, error) {
+	args := m.Called(ctx, filter)
+	if totals, ok := args.Get(0).([]domain.InvoiceTotal); ok {
+		return totals, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockReportRepository) GetPurchases(ctx context.Context, filter domain.InvoiceAnalyticsFilter) ([]domain.InvoiceTotal, error) {
+	args := m.Called(ctx, filter)
+	if totals, ok := args.Get(0).([]domain.InvoiceTotal); ok {
+		return totals, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockReportRepository) GetStockLines(ctx context.Context, warehouseID int, end time.Time) ([]domain.StockLine, error) {
+	args := m.Called(ctx, warehouseID, end)
+	if lines, ok := args.Get(0).([]domain.StockLine); ok {
+		return lines, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
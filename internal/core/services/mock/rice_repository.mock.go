@@ -44,6 +44,15 @@ func (m *MockRiceRepository) GetListRice(ctx context.Context, query string, limi
 	}
 }
 
+func (m *MockRiceRepository) GetListRiceAfter(ctx context.Context, query string, afterID, limit int) ([]domain.Rice, error) {
+	args := m.Called(ctx, query, afterID, limit)
+	if rice, ok := args.Get(0).([]domain.Rice); ok {
+		return rice, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
+
 func (m *MockRiceRepository) UpdateRice(ctx context.Context, rice *domain.Rice) (*domain.Rice, error) {
 	args := m.Called(ctx, rice)
 	if rice, ok := args.Get(0).(*domain.Rice); ok {
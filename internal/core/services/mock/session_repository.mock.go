@@ -0,0 +1,57 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) CreateSession(ctx context.Context, session *domain.Session) (*domain.Session, error) {
+	args := m.Called(ctx, session)
+	if s, ok := args.Get(0).(*domain.Session); ok {
+		return s, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSessionRepository) GetSessionByKey(ctx context.Context, key string) (*domain.Session, error) {
+	args := m.Called(ctx, key)
+	if s, ok := args.Get(0).(*domain.Session); ok {
+		return s, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSessionRepository) GetSessionsByUserID(ctx context.Context, userID int) ([]domain.Session, error) {
+	args := m.Called(ctx, userID)
+	if s, ok := args.Get(0).([]domain.Session); ok {
+		return s, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSessionRepository) UpdateLastSeen(ctx context.Context, id int, lastSeenAt time.Time) error {
+	args := m.Called(ctx, id, lastSeenAt)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) SwapRefreshKey(ctx context.Context, id int, oldRefreshKey, newRefreshKey string, expiresAt time.Time) error {
+	args := m.Called(ctx, id, oldRefreshKey, newRefreshKey, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) DeleteSession(ctx context.Context, userID, id int) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,39 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type MockTransferRepository struct {
+	mock.Mock
+}
+
+func (m *MockTransferRepository) CreateTransfer(ctx context.Context, transfer *domain.Transfer) (*domain.Transfer, error) {
+	args := m.Called(ctx, transfer)
+	if transfer, ok := args.Get(0).(*domain.Transfer); ok {
+		return transfer, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
+
+func (m *MockTransferRepository) GetTransferByID(ctx context.Context, id int) (*domain.Transfer, error) {
+	args := m.Called(ctx, id)
+	if transfer, ok := args.Get(0).(*domain.Transfer); ok {
+		return transfer, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
+
+func (m *MockTransferRepository) ReverseTransfer(ctx context.Context, id int, reason string) (*domain.Transfer, error) {
+	args := m.Called(ctx, id, reason)
+	if transfer, ok := args.Get(0).(*domain.Transfer); ok {
+		return transfer, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
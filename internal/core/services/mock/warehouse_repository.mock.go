@@ -43,6 +43,15 @@ func (m *MockWarehouseRepository) GetListWarehouses(ctx context.Context, query s
 	}
 }
 
+func (m *MockWarehouseRepository) GetListWarehousesAfter(ctx context.Context, query string, afterID, limit int) ([]domain.Warehouse, error) {
+	args := m.Called(ctx, query, afterID, limit)
+	if warehouses, ok := args.Get(0).([]domain.Warehouse); ok {
+		return warehouses, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
+
 func (m *MockWarehouseRepository) CountAuthorizedWarehouses(ctx context.Context, userID int, query string) (int64, error) {
 	args := m.Called(ctx, userID, query)
 	return args.Get(0).(int64), args.Error(1)
@@ -57,6 +66,15 @@ func (m *MockWarehouseRepository) GetAuthorizedWarehouses(ctx context.Context, u
 	}
 }
 
+func (m *MockWarehouseRepository) GetAuthorizedWarehousesAfter(ctx context.Context, userID int, query string, afterID, limit int) ([]domain.Warehouse, error) {
+	args := m.Called(ctx, userID, query, afterID, limit)
+	if warehouses, ok := args.Get(0).([]domain.Warehouse); ok {
+		return warehouses, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
+
 func (m *MockWarehouseRepository) GetUsedCapacityByID(ctx context.Context, id int) (int64, error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(int64), args.Error(1)
@@ -71,6 +89,15 @@ func (m *MockWarehouseRepository) GetInventory(ctx context.Context, id int) ([]d
 	}
 }
 
+func (m *MockWarehouseRepository) GetInventoryByLot(ctx context.Context, id int) ([]domain.Lot, error) {
+	args := m.Called(ctx, id)
+	if lots, ok := args.Get(0).([]domain.Lot); ok {
+		return lots, args.Error(1)
+	} else {
+		return nil, args.Error(1)
+	}
+}
+
 func (m *MockWarehouseRepository) UpdateWarehouse(ctx context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error) {
 	args := m.Called(ctx, warehouse)
 	if warehouse, ok := args.Get(0).(*domain.Warehouse); ok {
@@ -0,0 +1,89 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) CreateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	args := m.Called(ctx, webhook)
+	if w, ok := args.Get(0).(*domain.Webhook); ok {
+		return w, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetListWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	args := m.Called(ctx)
+	if w, ok := args.Get(0).([]domain.Webhook); ok {
+		return w, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetWebhookByID(ctx context.Context, id int) (*domain.Webhook, error) {
+	args := m.Called(ctx, id)
+	if w, ok := args.Get(0).(*domain.Webhook); ok {
+		return w, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWebhookRepository) UpdateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	args := m.Called(ctx, webhook)
+	if w, ok := args.Get(0).(*domain.Webhook); ok {
+		return w, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWebhookRepository) DeleteWebhook(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) CreateOutboxMessages(ctx context.Context, msgs []domain.OutboxMessage) error {
+	args := m.Called(ctx, msgs)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetDueMessages(ctx context.Context, now time.Time, limit int) ([]domain.OutboxMessage, error) {
+	args := m.Called(ctx, now, limit)
+	if msgs, ok := args.Get(0).([]domain.OutboxMessage); ok {
+		return msgs, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWebhookRepository) UpdateOutboxMessage(ctx context.Context, msg *domain.OutboxMessage) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) CountOutboxMessages(ctx context.Context, filter domain.OutboxFilter) (int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetListOutboxMessages(ctx context.Context, filter domain.OutboxFilter, limit, skip int) ([]domain.OutboxMessage, error) {
+	args := m.Called(ctx, filter, limit, skip)
+	if msgs, ok := args.Get(0).([]domain.OutboxMessage); ok {
+		return msgs, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetOutboxMessageByID(ctx context.Context, id int) (*domain.OutboxMessage, error) {
+	args := m.Called(ctx, id)
+	if msg, ok := args.Get(0).(*domain.OutboxMessage); ok {
+		return msg, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
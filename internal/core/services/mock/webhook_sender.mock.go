@@ -0,0 +1,17 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type MockWebhookSender struct {
+	mock.Mock
+}
+
+func (m *MockWebhookSender) Send(ctx context.Context, webhook *domain.Webhook, msg *domain.OutboxMessage) error {
+	args := m.Called(ctx, webhook, msg)
+	return args.Error(0)
+}
@@ -0,0 +1,18 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+)
+
+type MockWeighbridgeRepository struct {
+	mock.Mock
+}
+
+func (m *MockWeighbridgeRepository) GetPostedWeighings(ctx context.Context, warehouseID int, tickets []string) ([]domain.PostedWeighing, error) {
+	args := m.Called(ctx, warehouseID, tickets)
+	posted, _ := args.Get(0).([]domain.PostedWeighing)
+	return posted, args.Error(1)
+}
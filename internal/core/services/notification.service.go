@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	"github.com/tommjj/ql-kho-lua/internal/core/utils"
+	"go.uber.org/zap"
+)
+
+type notificationService struct {
+	repo       ports.INotificationRepository
+	acc        ports.IAccessControlRepository
+	notifiers  map[domain.NotificationChannel]ports.INotifier
+	retryDelay time.Duration
+	retryTimes int
+}
+
+// NewNotificationService create the notification service, events are delivered through the given notifiers
+// and a failed delivery is retried retryTimes times, retryDelay apart
+func NewNotificationService(repo ports.INotificationRepository, acc ports.IAccessControlRepository,
+	retryDelay time.Duration, retryTimes int, notifiers ...ports.INotifier) ports.INotificationService {
+	channels := make(map[domain.NotificationChannel]ports.INotifier, len(notifiers))
+	for _, n := range notifiers {
+		channels[n.Channel()] = n
+	}
+
+	return &notificationService{
+		repo:       repo,
+		acc:        acc,
+		notifiers:  channels,
+		retryDelay: retryDelay,
+		retryTimes: retryTimes,
+	}
+}
+
+func (n *notificationService) Subscribe(ctx context.Context, sub *domain.Subscription) (*domain.Subscription, error) {
+	if _, ok := n.notifiers[sub.Channel]; !ok {
+		return nil, domain.ErrChannelUnavailable
+	}
+
+	if sub.Channel == domain.ChannelWebhook {
+		if sub.Target == "" {
+			return nil, domain.ErrWebhookTargetRequired
+		}
+	} else {
+		sub.Target = ""
+	}
+
+	created, err := n.repo.CreateSubscription(ctx, sub)
+	if err != nil {
+		switch err {
+		case domain.ErrConflictingData, domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return created, nil
+}
+
+func (n *notificationService) GetSubscriptions(ctx context.Context, userID int) ([]domain.Subscription, error) {
+	subs, err := n.repo.GetSubscriptionsByUser(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return subs, nil
+}
+
+func (n *notificationService) Unsubscribe(ctx context.Context, id, userID int) error {
+	err := n.repo.DeleteSubscription(ctx, id, userID)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return err
+		default:
+			return domain.ErrInternal
+		}
+	}
+
+	return nil
+}
+
+func (n *notificationService) Publish(ctx context.Context, event *domain.Event) []domain.Delivery {
+	subs, err := n.repo.GetSubscriptionsByEvent(ctx, event.Type)
+	if err != nil {
+		zap.L().Error("get subscriptions", zap.Error(err), zap.String("event_type", string(event.Type)))
+		return nil
+	}
+
+	deliveries := []domain.Delivery{}
+	for i := range subs {
+		sub := &subs[i]
+
+		notifier, ok := n.notifiers[sub.Channel]
+		if !ok || sub.User == nil || !n.canSee(ctx, sub.User, event.WarehouseID) {
+			continue
+		}
+		if sub.Channel == domain.ChannelEmail {
+			sub.Target = sub.User.Email
+		}
+
+		deliveries = append(deliveries, n.deliver(ctx, notifier, sub, event))
+	}
+
+	return deliveries
+}
+
+// canSee report whether the user can read the warehouse of an event
+func (n *notificationService) canSee(ctx context.Context, user *domain.User, warehouseID int) bool {
+	if user.Role == domain.Root {
+		return true
+	}
+
+	level, err := n.acc.GetAccessLevel(ctx, warehouseID, user.ID)
+	return err == nil && level.Allows(domain.ActionRead)
+}
+
+// deliver notify the subscription, retrying on error, and log the delivery
+func (n *notificationService) deliver(ctx context.Context, notifier ports.INotifier, sub *domain.Subscription, event *domain.Event) domain.Delivery {
+	delivery := domain.Delivery{
+		SubscriptionID: sub.ID,
+		EventType:      event.Type,
+		Channel:        sub.Channel,
+		Target:         sub.Target,
+	}
+
+	_, err := utils.Retry(func() (struct{}, error) {
+		delivery.Attempts++
+		err := notifier.Notify(ctx, sub, event)
+		if err != nil {
+			zap.L().Warn("deliver notification",
+				zap.Error(err),
+				zap.Int("subscription_id", sub.ID),
+				zap.String("channel", string(sub.Channel)),
+				zap.Int("attempt", delivery.Attempts),
+			)
+		}
+		return struct{}{}, err
+	}, n.retryDelay, n.retryTimes)
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	err = n.repo.CreateDelivery(ctx, &delivery)
+	if err != nil {
+		zap.L().Error("write delivery log", zap.Error(err), zap.Int("subscription_id", sub.ID))
+	}
+
+	return delivery
+}
+
+func (n *notificationService) CountNotifications(ctx context.Context, userID int, unread bool) (int64, error) {
+	count, err := n.repo.CountNotifications(ctx, userID, unread)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	return count, nil
+}
+
+func (n *notificationService) GetListNotifications(ctx context.Context, userID int, unread bool, limit, skip int) ([]domain.Notification, error) {
+	notifications, err := n.repo.GetListNotifications(ctx, userID, unread, limit, skip)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return notifications, nil
+}
+
+func (n *notificationService) ReadNotification(ctx context.Context, id, userID int) (*domain.Notification, error) {
+	notification, err := n.repo.ReadNotification(ctx, id, userID)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return notification, nil
+}
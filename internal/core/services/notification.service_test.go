@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	mockRepo "github.com/tommjj/ql-kho-lua/internal/core/services/mock"
+)
+
+// stubNotifier fail the first fails calls and record the subscriptions it notified
+type stubNotifier struct {
+	channel  domain.NotificationChannel
+	fails    int
+	calls    int
+	notified []domain.Subscription
+}
+
+func (s *stubNotifier) Channel() domain.NotificationChannel {
+	return s.channel
+}
+
+func (s *stubNotifier) Notify(ctx context.Context, sub *domain.Subscription, event *domain.Event) error {
+	s.calls++
+	if s.calls <= s.fails {
+		return errors.New("connection refused")
+	}
+	s.notified = append(s.notified, *sub)
+	return nil
+}
+
+func TestNotificationServiceImplements(t *testing.T) {
+	assert.Implements(t, (*ports.INotificationService)(nil), new(notificationService))
+}
+
+func TestSubscribe(t *testing.T) {
+	repo := new(mockRepo.MockNotificationRepository)
+	acc := new(mockRepo.MockAccessControlRepository)
+
+	sub := &domain.Subscription{UserID: 1, EventType: domain.EventLargeExport, Channel: domain.ChannelInApp, Target: "ignored"}
+	repo.On("CreateSubscription", context.TODO(), sub).Return(&domain.Subscription{ID: 1, UserID: 1}, nil)
+
+	service := NewNotificationService(repo, acc, 0, 0, &stubNotifier{channel: domain.ChannelInApp})
+
+	created, err := service.Subscribe(context.TODO(), sub)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, created.ID)
+	assert.Empty(t, sub.Target)
+
+	_, err = service.Subscribe(context.TODO(), &domain.Subscription{UserID: 1, Channel: domain.ChannelEmail})
+	assert.Equal(t, domain.ErrChannelUnavailable, err)
+
+	repo.AssertNumberOfCalls(t, "CreateSubscription", 1)
+}
+
+func TestSubscribe_WebhookTarget(t *testing.T) {
+	repo := new(mockRepo.MockNotificationRepository)
+	acc := new(mockRepo.MockAccessControlRepository)
+
+	service := NewNotificationService(repo, acc, 0, 0, &stubNotifier{channel: domain.ChannelWebhook})
+
+	_, err := service.Subscribe(context.TODO(), &domain.Subscription{UserID: 1, Channel: domain.ChannelWebhook})
+
+	repo.AssertNotCalled(t, "CreateSubscription")
+	assert.Equal(t, domain.ErrWebhookTargetRequired, err)
+}
+
+func TestPublish(t *testing.T) {
+	event := &domain.Event{Type: domain.EventLargeExport, WarehouseID: 5, Subject: "large export"}
+
+	repo := new(mockRepo.MockNotificationRepository)
+	repo.On("GetSubscriptionsByEvent", context.TODO(), domain.EventLargeExport).Return([]domain.Subscription{
+		{ID: 1, UserID: 1, Channel: domain.ChannelEmail, User: &domain.User{ID: 1, Role: domain.Root, Email: "root@mail.com"}},
+		{ID: 2, UserID: 2, Channel: domain.ChannelEmail, User: &domain.User{ID: 2, Role: domain.Member, Email: "member@mail.com"}},
+		{ID: 3, UserID: 3, Channel: domain.ChannelEmail, User: &domain.User{ID: 3, Role: domain.Member, Email: "other@mail.com"}},
+		// a deleted user
+		{ID: 4, UserID: 4, Channel: domain.ChannelEmail},
+		// no notifier for the channel
+		{ID: 5, UserID: 1, Channel: domain.ChannelWebhook, Target: "http://127.0.0.1", User: &domain.User{ID: 1, Role: domain.Root}},
+	}, nil)
+	repo.On("CreateDelivery", context.TODO(), mock.Anything).Return(nil)
+
+	acc := new(mockRepo.MockAccessControlRepository)
+	acc.On("GetAccessLevel", context.TODO(), 5, 2).Return(domain.AccessReadOnly, nil)
+	acc.On("GetAccessLevel", context.TODO(), 5, 3).Return(domain.AccessLevel(""), domain.ErrForbidden)
+
+	notifier := &stubNotifier{channel: domain.ChannelEmail}
+	service := NewNotificationService(repo, acc, 0, 2, notifier)
+
+	deliveries := service.Publish(context.TODO(), event)
+
+	repo.AssertNumberOfCalls(t, "CreateDelivery", 2)
+	if assert.Len(t, deliveries, 2) {
+		assert.Equal(t, 1, deliveries[0].SubscriptionID)
+		assert.Equal(t, "root@mail.com", deliveries[0].Target)
+		assert.Equal(t, 2, deliveries[1].SubscriptionID)
+		assert.Equal(t, "member@mail.com", deliveries[1].Target)
+		assert.Empty(t, deliveries[1].Error)
+	}
+	assert.Len(t, notifier.notified, 2)
+}
+
+func TestPublish_Retry(t *testing.T) {
+	event := &domain.Event{Type: domain.EventLowStock, WarehouseID: 1}
+	subs := []domain.Subscription{
+		{ID: 1, UserID: 1, Channel: domain.ChannelWebhook, Target: "http://127.0.0.1", User: &domain.User{ID: 1, Role: domain.Root}},
+	}
+
+	repo := new(mockRepo.MockNotificationRepository)
+	repo.On("GetSubscriptionsByEvent", context.TODO(), domain.EventLowStock).Return(subs, nil)
+	repo.On("CreateDelivery", context.TODO(), mock.Anything).Return(nil)
+	acc := new(mockRepo.MockAccessControlRepository)
+
+	// succeed on the third attempt
+	notifier := &stubNotifier{channel: domain.ChannelWebhook, fails: 2}
+	service := NewNotificationService(repo, acc, 0, 2, notifier)
+
+	deliveries := service.Publish(context.TODO(), event)
+	if assert.Len(t, deliveries, 1) {
+		assert.Equal(t, 3, deliveries[0].Attempts)
+		assert.Empty(t, deliveries[0].Error)
+	}
+
+	// give up after the retries
+	notifier = &stubNotifier{channel: domain.ChannelWebhook, fails: 10}
+	service = NewNotificationService(repo, acc, 0, 2, notifier)
+
+	deliveries = service.Publish(context.TODO(), event)
+	if assert.Len(t, deliveries, 1) {
+		assert.Equal(t, 3, deliveries[0].Attempts)
+		assert.Equal(t, "connection refused", deliveries[0].Error)
+	}
+}
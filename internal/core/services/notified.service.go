@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+// The notified services wrap a service and publish an event to its subscribers after each successful call.
+// Events are delivered in the background so the caller is not kept waiting for slow channels.
+
+// publish deliver the events in the background, the operation is already done so it does not stop with ctx
+func publish(ctx context.Context, notify ports.INotificationService, events ...*domain.Event) {
+	ctx = context.WithoutCancel(ctx)
+
+	go func() {
+		for _, event := range events {
+			notify.Publish(ctx, event)
+		}
+	}()
+}
+
+type notifiedImInvoiceService struct {
+	ports.IImportInvoicesService
+	notify ports.INotificationService
+}
+
+func NewNotifiedImInvoiceService(svc ports.IImportInvoicesService, notify ports.INotificationService) ports.IImportInvoicesService {
+	return &notifiedImInvoiceService{
+		IImportInvoicesService: svc,
+		notify:                 notify,
+	}
+}
+
+func (s *notifiedImInvoiceService) CreateImInvoice(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error) {
+	created, err := s.IImportInvoicesService.CreateImInvoice(ctx, invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	publish(ctx, s.notify, domain.NewInvoiceEvent(domain.EventImportInvoiceCreated, created))
+	return created, nil
+}
+
+type notifiedExInvoiceService struct {
+	ports.IExportInvoiceService
+	notify        ports.INotificationService
+	largeQuantity int
+}
+
+// NewNotifiedExInvoiceService wrap the export invoice service, an invoice is large when its quantity reach largeQuantity
+func NewNotifiedExInvoiceService(svc ports.IExportInvoiceService, notify ports.INotificationService, largeQuantity int) ports.IExportInvoiceService {
+	return &notifiedExInvoiceService{
+		IExportInvoiceService: svc,
+		notify:                notify,
+		largeQuantity:         largeQuantity,
+	}
+}
+
+func (s *notifiedExInvoiceService) CreateExInvoice(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error) {
+	created, err := s.IExportInvoiceService.CreateExInvoice(ctx, invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []*domain.Event{domain.NewInvoiceEvent(domain.EventExportInvoiceCreated, created)}
+	if created.Quantity() >= s.largeQuantity {
+		events = append(events, domain.NewInvoiceEvent(domain.EventLargeExport, created))
+	}
+
+	publish(ctx, s.notify, events...)
+	return created, nil
+}
+
+type notifiedOrderService struct {
+	ports.IOrderService
+	notify        ports.INotificationService
+	largeQuantity int
+}
+
+// NewNotifiedOrderService wrap the order service, converting an order publish the events of the invoice it creates
+func NewNotifiedOrderService(svc ports.IOrderService, notify ports.INotificationService, largeQuantity int) ports.IOrderService {
+	return &notifiedOrderService{
+		IOrderService: svc,
+		notify:        notify,
+		largeQuantity: largeQuantity,
+	}
+}
+
+func (s *notifiedOrderService) ConvertOrder(ctx context.Context, id int, userID int) (*domain.Invoice, error) {
+	order, err := s.IOrderService.GetOrderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.IOrderService.ConvertOrder(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Type == domain.PurchaseOrder {
+		publish(ctx, s.notify, domain.NewInvoiceEvent(domain.EventImportInvoiceCreated, created))
+		return created, nil
+	}
+
+	events := []*domain.Event{domain.NewInvoiceEvent(domain.EventExportInvoiceCreated, created)}
+	if created.Quantity() >= s.largeQuantity {
+		events = append(events, domain.NewInvoiceEvent(domain.EventLargeExport, created))
+	}
+
+	publish(ctx, s.notify, events...)
+	return created, nil
+}
+
+type notifiedAlertService struct {
+	ports.IAlertService
+	notify ports.INotificationService
+}
+
+func NewNotifiedAlertService(svc ports.IAlertService, notify ports.INotificationService) ports.IAlertService {
+	return &notifiedAlertService{
+		IAlertService: svc,
+		notify:        notify,
+	}
+}
+
+func (s *notifiedAlertService) CheckAlerts(ctx context.Context) ([]domain.Alert, error) {
+	alerts, err := s.IAlertService.CheckAlerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*domain.Event, 0, len(alerts))
+	for i := range alerts {
+		events = append(events, domain.NewAlertEvent(&alerts[i]))
+	}
+
+	publish(ctx, s.notify, events...)
+	return alerts, nil
+}
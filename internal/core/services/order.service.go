@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/mapmutex"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+type orderService struct {
+	orderRepo     ports.IOrderRepository
+	warehouseRepo ports.IWarehouseRepository
+	customerRepo  ports.ICustomerRepository
+	l             *mapmutex.Mapmutex
+	importRule    domain.ApprovalRule
+	exportRule    domain.ApprovalRule
+}
+
+// NewOrderService create an order service, the rules are the approval rules of the invoices the orders convert to
+func NewOrderService(
+	orderRepo ports.IOrderRepository,
+	warehouseRepo ports.IWarehouseRepository,
+	customerRepo ports.ICustomerRepository,
+	l *mapmutex.Mapmutex,
+	importRule, exportRule domain.ApprovalRule) ports.IOrderService {
+	return &orderService{
+		orderRepo:     orderRepo,
+		warehouseRepo: warehouseRepo,
+		customerRepo:  customerRepo,
+		l:             l,
+		importRule:    importRule,
+		exportRule:    exportRule,
+	}
+}
+
+func (o *orderService) CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error) {
+	err := checkPartner(ctx, o.customerRepo, order.CustomerID, order.Type.PartnerRole())
+	if err != nil {
+		return nil, err
+	}
+
+	order.Status = domain.OrderDraft
+	order.CalcTotalPrice()
+
+	created, err := o.orderRepo.CreateOrder(ctx, order)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return created, nil
+}
+
+func (o *orderService) GetOrderByID(ctx context.Context, id int) (*domain.Order, error) {
+	order, err := o.orderRepo.GetOrderByID(ctx, id)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return order, nil
+}
+
+func (o *orderService) CountOrders(ctx context.Context, filter domain.OrderFilter) (int64, error) {
+	count, err := o.orderRepo.CountOrders(ctx, filter)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	return count, nil
+}
+
+func (o *orderService) GetListOrders(ctx context.Context, filter domain.OrderFilter, limit, skip int) ([]domain.Order, error) {
+	orders, err := o.orderRepo.GetListOrders(ctx, filter, limit, skip)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return orders, nil
+}
+
+func (o *orderService) ConfirmOrder(ctx context.Context, id int) (*domain.Order, error) {
+	order, err := o.GetOrderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	o.l.Lock(order.WarehouseID)
+	defer o.l.UnLock(order.WarehouseID)
+
+	if !order.CanMoveTo(domain.OrderConfirmed) {
+		return nil, domain.ErrInvalidOrderStatus
+	}
+
+	if order.Type == domain.PurchaseOrder {
+		err = o.checkCapacity(ctx, order.WarehouseID, order.Quantity())
+	} else {
+		err = o.checkStock(ctx, order.WarehouseID, order.Details, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return o.updateStatus(ctx, id, domain.OrderConfirmed)
+}
+
+func (o *orderService) CancelOrder(ctx context.Context, id int) (*domain.Order, error) {
+	order, err := o.GetOrderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !order.CanMoveTo(domain.OrderCancelled) {
+		return nil, domain.ErrInvalidOrderStatus
+	}
+
+	return o.updateStatus(ctx, id, domain.OrderCancelled)
+}
+
+func (o *orderService) ConvertOrder(ctx context.Context, id int, userID int) (*domain.Invoice, error) {
+	order, err := o.GetOrderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	o.l.Lock(order.WarehouseID)
+	defer o.l.UnLock(order.WarehouseID)
+
+	if !order.CanMoveTo(domain.OrderConverted) {
+		return nil, domain.ErrInvalidOrderStatus
+	}
+
+	// the order already holds its capacity or stock, so only what it reserved has to be there
+	if order.Type == domain.PurchaseOrder {
+		err = o.checkCapacity(ctx, order.WarehouseID, 0)
+	} else {
+		err = o.checkStock(ctx, order.WarehouseID, order.Details, order.Details)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// an invoice above the approval rule still releases the reservation of the order,
+	// its capacity or stock is checked again when it is approved
+	invoice := order.Invoice(userID)
+	rule := o.exportRule
+	if order.Type == domain.PurchaseOrder {
+		rule = o.importRule
+	}
+	if rule.Requires(invoice) {
+		invoice.Status = domain.InvoicePendingApproval
+	}
+
+	invoice, err = o.orderRepo.ConvertOrder(ctx, order, invoice)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound, domain.ErrInvalidOrderStatus, domain.ErrInsufficientStock:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return invoice, nil
+}
+
+// checkCapacity check the warehouse has room for quantity more on top of its used capacity
+func (o *orderService) checkCapacity(ctx context.Context, warehouseID int, quantity int) error {
+	store, err := o.warehouseRepo.GetWarehouseByID(ctx, warehouseID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	used, err := o.warehouseRepo.GetUsedCapacityByID(ctx, warehouseID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	if (int(used) + quantity) > store.Capacity {
+		return domain.ErrWarehouseFull
+	}
+	return nil
+}
+
+// checkStock check the warehouse has enough available stock for the items,
+// the stock held by the released items is counted as available
+func (o *orderService) checkStock(ctx context.Context, warehouseID int, items, released []domain.InvoiceItem) error {
+	inventory, err := o.warehouseRepo.GetInventory(ctx, warehouseID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	if !hasEnoughStock(releaseReservation(inventory, released), items) {
+		return domain.ErrInsufficientStock
+	}
+	return nil
+}
+
+func (o *orderService) updateStatus(ctx context.Context, id int, status domain.OrderStatus) (*domain.Order, error) {
+	updated, err := o.orderRepo.UpdateOrderStatus(ctx, id, status)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound, domain.ErrInvalidOrderStatus:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/mapmutex"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	mockRepo "github.com/tommjj/ql-kho-lua/internal/core/services/mock"
+)
+
+func TestOrderServiceImplements(t *testing.T) {
+	assert.Implements(t, (*ports.IOrderService)(nil), new(orderService))
+}
+
+func newTestOrder(orderType domain.OrderType, status domain.OrderStatus) *domain.Order {
+	return &domain.Order{
+		ID:          1,
+		Type:        orderType,
+		WarehouseID: 2,
+		CustomerID:  1,
+		UserID:      1,
+		Status:      status,
+		Details: []domain.InvoiceItem{
+			{RiceID: 1, Quantity: 100, Price: 10},
+		},
+	}
+}
+
+func TestCreateOrder_Success(t *testing.T) {
+	orderRepo := new(mockRepo.MockOrderRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	orderRepo.On("CreateOrder", mock.Anything, mock.MatchedBy(func(order *domain.Order) bool {
+		return order.Status == domain.OrderDraft && order.TotalPrice == 1000
+	})).Return(&domain.Order{ID: 1}, nil)
+
+	service := NewOrderService(orderRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{}, domain.ApprovalRule{})
+	_, err := service.CreateOrder(context.TODO(), newTestOrder(domain.PurchaseOrder, ""))
+	assert.Nil(t, err)
+
+	orderRepo.AssertExpectations(t)
+	warehouseRepo.AssertNotCalled(t, "GetUsedCapacityByID", mock.Anything, mock.Anything)
+}
+
+func TestCreateOrder_FailPartnerRole(t *testing.T) {
+	orderRepo := new(mockRepo.MockOrderRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+	customerRepo := new(mockRepo.MockCustomerRepository)
+
+	// a sales order is placed with a supplier
+	customerRepo.On("GetCustomerByID", mock.Anything, 1).Return(&domain.Customer{ID: 1, Role: domain.PartnerSupplier}, nil)
+
+	service := NewOrderService(orderRepo, warehouseRepo, customerRepo, &mapmutex.Mapmutex{}, domain.ApprovalRule{}, domain.ApprovalRule{})
+	_, err := service.CreateOrder(context.TODO(), newTestOrder(domain.SalesOrder, ""))
+	assert.Equal(t, domain.ErrInvalidPartnerRole, err)
+
+	orderRepo.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+}
+
+func TestConfirmOrder_PurchaseReservesCapacity(t *testing.T) {
+	orderRepo := new(mockRepo.MockOrderRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	orderRepo.On("GetOrderByID", mock.Anything, 1).Return(newTestOrder(domain.PurchaseOrder, domain.OrderDraft), nil)
+	warehouseRepo.On("GetWarehouseByID", mock.Anything, 2).Return(&domain.Warehouse{ID: 2, Capacity: 500}, nil)
+	warehouseRepo.On("GetUsedCapacityByID", mock.Anything, 2).Return(int64(400), nil)
+	orderRepo.On("UpdateOrderStatus", mock.Anything, 1, domain.OrderConfirmed).
+		Return(newTestOrder(domain.PurchaseOrder, domain.OrderConfirmed), nil)
+
+	service := NewOrderService(orderRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{}, domain.ApprovalRule{})
+	order, err := service.ConfirmOrder(context.TODO(), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, domain.OrderConfirmed, order.Status)
+
+	orderRepo.AssertExpectations(t)
+	warehouseRepo.AssertExpectations(t)
+}
+
+func TestConfirmOrder_FailWarehouseFull(t *testing.T) {
+	orderRepo := new(mockRepo.MockOrderRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	orderRepo.On("GetOrderByID", mock.Anything, 1).Return(newTestOrder(domain.PurchaseOrder, domain.OrderDraft), nil)
+	warehouseRepo.On("GetWarehouseByID", mock.Anything, 2).Return(&domain.Warehouse{ID: 2, Capacity: 500}, nil)
+	warehouseRepo.On("GetUsedCapacityByID", mock.Anything, 2).Return(int64(401), nil)
+
+	service := NewOrderService(orderRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{}, domain.ApprovalRule{})
+	_, err := service.ConfirmOrder(context.TODO(), 1)
+	assert.Equal(t, domain.ErrWarehouseFull, err)
+
+	orderRepo.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConfirmOrder_SalesFailStockReserved(t *testing.T) {
+	orderRepo := new(mockRepo.MockOrderRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	orderRepo.On("GetOrderByID", mock.Anything, 1).Return(newTestOrder(domain.SalesOrder, domain.OrderDraft), nil)
+	// 150 in stock but 60 is held by another sales order
+	warehouseRepo.On("GetInventory", mock.Anything, 2).Return([]domain.WarehouseItem{{RiceID: 1, Quantity: 150, Reserved: 60}}, nil)
+
+	service := NewOrderService(orderRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{}, domain.ApprovalRule{})
+	_, err := service.ConfirmOrder(context.TODO(), 1)
+	assert.Equal(t, domain.ErrInsufficientStock, err)
+
+	orderRepo.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConfirmOrder_FailNotDraft(t *testing.T) {
+	orderRepo := new(mockRepo.MockOrderRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	orderRepo.On("GetOrderByID", mock.Anything, 1).Return(newTestOrder(domain.SalesOrder, domain.OrderConfirmed), nil)
+
+	service := NewOrderService(orderRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{}, domain.ApprovalRule{})
+	_, err := service.ConfirmOrder(context.TODO(), 1)
+	assert.Equal(t, domain.ErrInvalidOrderStatus, err)
+
+	warehouseRepo.AssertNotCalled(t, "GetInventory", mock.Anything, mock.Anything)
+}
+
+func TestCancelOrder(t *testing.T) {
+	tests := []struct {
+		status domain.OrderStatus
+		err    error
+	}{
+		{domain.OrderDraft, nil},
+		{domain.OrderConfirmed, nil},
+		{domain.OrderConverted, domain.ErrInvalidOrderStatus},
+		{domain.OrderCancelled, domain.ErrInvalidOrderStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			orderRepo := new(mockRepo.MockOrderRepository)
+			warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+			orderRepo.On("GetOrderByID", mock.Anything, 1).Return(newTestOrder(domain.PurchaseOrder, tt.status), nil)
+			orderRepo.On("UpdateOrderStatus", mock.Anything, 1, domain.OrderCancelled).
+				Return(newTestOrder(domain.PurchaseOrder, domain.OrderCancelled), nil)
+
+			service := NewOrderService(orderRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{}, domain.ApprovalRule{})
+			_, err := service.CancelOrder(context.TODO(), 1)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestConvertOrder_SalesUsesOwnReservation(t *testing.T) {
+	orderRepo := new(mockRepo.MockOrderRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	order := newTestOrder(domain.SalesOrder, domain.OrderConfirmed)
+	orderRepo.On("GetOrderByID", mock.Anything, 1).Return(order, nil)
+	// the 100 reserved is the order's own reservation
+	warehouseRepo.On("GetInventory", mock.Anything, 2).Return([]domain.WarehouseItem{{RiceID: 1, Quantity: 100, Reserved: 100}}, nil)
+	orderRepo.On("ConvertOrder", mock.Anything, order, mock.MatchedBy(func(invoice *domain.Invoice) bool {
+		return invoice.WarehouseID == 2 && invoice.UserID == 3 && invoice.TotalPrice == 1000
+	})).Return(&domain.Invoice{ID: 5}, nil)
+
+	service := NewOrderService(orderRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{}, domain.ApprovalRule{})
+	invoice, err := service.ConvertOrder(context.TODO(), 1, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, invoice.ID)
+
+	orderRepo.AssertExpectations(t)
+}
+
+func TestConvertOrder_SalesFailInsufficientStock(t *testing.T) {
+	orderRepo := new(mockRepo.MockOrderRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	orderRepo.On("GetOrderByID", mock.Anything, 1).Return(newTestOrder(domain.SalesOrder, domain.OrderConfirmed), nil)
+	warehouseRepo.On("GetInventory", mock.Anything, 2).Return([]domain.WarehouseItem{{RiceID: 1, Quantity: 120, Reserved: 150}}, nil)
+
+	service := NewOrderService(orderRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{}, domain.ApprovalRule{})
+	_, err := service.ConvertOrder(context.TODO(), 1, 3)
+	assert.Equal(t, domain.ErrInsufficientStock, err)
+
+	orderRepo.AssertNotCalled(t, "ConvertOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConvertOrder_PurchaseUsesOwnReservation(t *testing.T) {
+	orderRepo := new(mockRepo.MockOrderRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	order := newTestOrder(domain.PurchaseOrder, domain.OrderConfirmed)
+	orderRepo.On("GetOrderByID", mock.Anything, 1).Return(order, nil)
+	warehouseRepo.On("GetWarehouseByID", mock.Anything, 2).Return(&domain.Warehouse{ID: 2, Capacity: 500}, nil)
+	// the used capacity already holds the 100 reserved by the order
+	warehouseRepo.On("GetUsedCapacityByID", mock.Anything, 2).Return(int64(500), nil)
+	orderRepo.On("ConvertOrder", mock.Anything, order, mock.Anything).Return(&domain.Invoice{ID: 5}, nil)
+
+	service := NewOrderService(orderRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{}, domain.ApprovalRule{})
+	_, err := service.ConvertOrder(context.TODO(), 1, 3)
+	assert.Nil(t, err)
+
+	orderRepo.AssertExpectations(t)
+}
+
+func TestConvertOrder_FailNotConfirmed(t *testing.T) {
+	orderRepo := new(mockRepo.MockOrderRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	orderRepo.On("GetOrderByID", mock.Anything, 1).Return(newTestOrder(domain.PurchaseOrder, domain.OrderDraft), nil)
+
+	service := NewOrderService(orderRepo, warehouseRepo, newTestPartnerRepo(), &mapmutex.Mapmutex{}, domain.ApprovalRule{}, domain.ApprovalRule{})
+	_, err := service.ConvertOrder(context.TODO(), 1, 3)
+	assert.Equal(t, domain.ErrInvalidOrderStatus, err)
+
+	orderRepo.AssertNotCalled(t, "ConvertOrder", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+type paymentService struct {
+	paymentRepo ports.IPaymentRepository
+}
+
+func NewPaymentService(paymentRepo ports.IPaymentRepository) ports.IPaymentService {
+	return &paymentService{
+		paymentRepo: paymentRepo,
+	}
+}
+
+func (p *paymentService) CreatePayment(ctx context.Context, payment *domain.Payment) (*domain.Payment, error) {
+	if payment.PaidAt.IsZero() {
+		payment.PaidAt = time.Now()
+	}
+
+	created, err := p.paymentRepo.CreatePayment(ctx, payment)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound, domain.ErrInvoiceNotPayable, domain.ErrOverpayment:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return created, nil
+}
+
+func (p *paymentService) GetPaymentByID(ctx context.Context, id int) (*domain.Payment, error) {
+	payment, err := p.paymentRepo.GetPaymentByID(ctx, id)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return payment, nil
+}
+
+func (p *paymentService) GetInvoiceWarehouseID(ctx context.Context, kind domain.InvoiceKind, invoiceID int) (int, error) {
+	warehouseID, err := p.paymentRepo.GetInvoiceWarehouseID(ctx, kind, invoiceID)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return 0, err
+		default:
+			return 0, domain.ErrInternal
+		}
+	}
+
+	return warehouseID, nil
+}
+
+func (p *paymentService) CountPayments(ctx context.Context, filter domain.PaymentFilter) (int64, error) {
+	if filter.Start != nil && filter.End != nil && filter.Start.After(*filter.End) {
+		return 0, domain.ErrInvalidDateRange
+	}
+
+	count, err := p.paymentRepo.CountPayments(ctx, filter)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	return count, nil
+}
+
+func (p *paymentService) GetListPayments(ctx context.Context, filter domain.PaymentFilter, limit, skip int) ([]domain.Payment, error) {
+	if filter.Start != nil && filter.End != nil && filter.Start.After(*filter.End) {
+		return nil, domain.ErrInvalidDateRange
+	}
+
+	payments, err := p.paymentRepo.GetListPayments(ctx, filter, limit, skip)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return payments, nil
+}
+
+func (p *paymentService) GetBalances(ctx context.Context, kind domain.InvoiceKind, warehouseID int) ([]domain.PartnerBalance, error) {
+	balances, err := p.paymentRepo.GetPartnerBalances(ctx, kind, warehouseID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return balances, nil
+}
+
+func (p *paymentService) GetAging(ctx context.Context, kind domain.InvoiceKind, warehouseID int, at time.Time) (*domain.AgingReport, error) {
+	invoices, err := p.paymentRepo.GetOpenInvoices(ctx, kind, warehouseID, at)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return domain.AgeInvoices(invoices, at), nil
+}
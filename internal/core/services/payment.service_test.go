@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	mockRepo "github.com/tommjj/ql-kho-lua/internal/core/services/mock"
+)
+
+func TestPaymentServiceImplements(t *testing.T) {
+	assert.Implements(t, (*ports.IPaymentService)(nil), new(paymentService))
+}
+
+func TestCreatePayment(t *testing.T) {
+	paidAt := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	payment := &domain.Payment{
+		InvoiceKind: domain.InvoiceKindExport,
+		InvoiceID:   1,
+		UserID:      1,
+		Amount:      200,
+		Method:      domain.PaymentBankTransfer,
+		PaidAt:      paidAt,
+	}
+	created := &domain.Payment{ID: 1, InvoiceKind: domain.InvoiceKindExport, InvoiceID: 1, CustomerID: 2, Amount: 200, PaidAt: paidAt}
+
+	repo := new(mockRepo.MockPaymentRepository)
+	repo.On("CreatePayment", mock.Anything, payment).Return(created, nil)
+
+	service := NewPaymentService(repo)
+	res, err := service.CreatePayment(context.TODO(), payment)
+
+	repo.AssertExpectations(t)
+	assert.Nil(t, err)
+	assert.Equal(t, created, res)
+	assert.Equal(t, paidAt, payment.PaidAt)
+}
+
+func TestCreatePayment_DefaultPaidAt(t *testing.T) {
+	payment := &domain.Payment{InvoiceKind: domain.InvoiceKindImport, InvoiceID: 1, Amount: 100, Method: domain.PaymentCash}
+
+	repo := new(mockRepo.MockPaymentRepository)
+	repo.On("CreatePayment", mock.Anything, payment).Return(&domain.Payment{ID: 1}, nil)
+
+	service := NewPaymentService(repo)
+	_, err := service.CreatePayment(context.TODO(), payment)
+
+	assert.Nil(t, err)
+	assert.False(t, payment.PaidAt.IsZero())
+}
+
+func TestCreatePayment_RepoErr(t *testing.T) {
+	cases := []struct {
+		repoErr error
+		want    error
+	}{
+		{domain.ErrDataNotFound, domain.ErrDataNotFound},
+		{domain.ErrInvoiceNotPayable, domain.ErrInvoiceNotPayable},
+		{domain.ErrOverpayment, domain.ErrOverpayment},
+		{errors.New("db down"), domain.ErrInternal},
+	}
+
+	for _, c := range cases {
+		payment := &domain.Payment{InvoiceKind: domain.InvoiceKindExport, InvoiceID: 1, Amount: 100, Method: domain.PaymentCash}
+
+		repo := new(mockRepo.MockPaymentRepository)
+		repo.On("CreatePayment", mock.Anything, payment).Return(nil, c.repoErr)
+
+		service := NewPaymentService(repo)
+		_, err := service.CreatePayment(context.TODO(), payment)
+
+		assert.Equal(t, c.want, err)
+	}
+}
+
+func TestGetListPayments_InvalidRange(t *testing.T) {
+	start := time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	repo := new(mockRepo.MockPaymentRepository)
+
+	service := NewPaymentService(repo)
+	_, err := service.GetListPayments(context.TODO(), domain.PaymentFilter{Start: &start, End: &end}, 5, 1)
+
+	repo.AssertNotCalled(t, "GetListPayments")
+	assert.Equal(t, domain.ErrInvalidDateRange, err)
+}
+
+func TestGetAging(t *testing.T) {
+	at := time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC)
+	invoices := []domain.OpenInvoice{
+		{InvoiceID: 1, CustomerID: 1, CustomerName: "Ascalon", CompletedAt: at.AddDate(0, 0, -10), Outstanding: 100},
+		{InvoiceID: 2, CustomerID: 1, CustomerName: "Ascalon", CompletedAt: at.AddDate(0, 0, -100), Outstanding: 50},
+	}
+
+	repo := new(mockRepo.MockPaymentRepository)
+	repo.On("GetOpenInvoices", mock.Anything, domain.InvoiceKindExport, 2, at).Return(invoices, nil)
+
+	service := NewPaymentService(repo)
+	res, err := service.GetAging(context.TODO(), domain.InvoiceKindExport, 2, at)
+
+	repo.AssertExpectations(t)
+	assert.Nil(t, err)
+	assert.Len(t, res.Rows, 1)
+	assert.Equal(t, 100.0, res.Rows[0].Current)
+	assert.Equal(t, 50.0, res.Rows[0].Over90)
+	assert.Equal(t, 150.0, res.Total.Total)
+}
+
+func TestGetAging_RepoErr(t *testing.T) {
+	at := time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	repo := new(mockRepo.MockPaymentRepository)
+	repo.On("GetOpenInvoices", mock.Anything, domain.InvoiceKindImport, 0, at).Return(nil, errors.New("db down"))
+
+	service := NewPaymentService(repo)
+	_, err := service.GetAging(context.TODO(), domain.InvoiceKindImport, 0, at)
+
+	assert.Equal(t, domain.ErrInternal, err)
+}
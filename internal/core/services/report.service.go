@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+type reportService struct {
+	repo ports.IReportRepository
+}
+
+func NewReportService(repo ports.IReportRepository) ports.IReportService {
+	return &reportService{
+		repo: repo,
+	}
+}
+
+func (r *reportService) GetStockMovement(ctx context.Context, warehouseID int, start, end time.Time) ([]domain.StockMovement, error) {
+	if start.After(end) {
+		return nil, domain.ErrInvalidDateRange
+	}
+
+	movements, err := r.repo.GetStockMovement(ctx, warehouseID, start, end)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return movements, nil
+}
+
+// validateAnalyticsFilter default the period to day and check the filter
+func validateAnalyticsFilter(filter *domain.InvoiceAnalyticsFilter) error {
+	if filter.GroupBy == "" {
+		filter.GroupBy = domain.PeriodDay
+	}
+	if !filter.GroupBy.IsValid() {
+		return domain.ErrInvalidReportPeriod
+	}
+	if filter.Start != nil && filter.End != nil && filter.Start.After(*filter.End) {
+		return domain.ErrInvalidDateRange
+	}
+	return nil
+}
+
+func (r *reportService) GetRevenue(ctx context.Context, filter domain.InvoiceAnalyticsFilter) ([]domain.InvoiceTotal, error) {
+	err := validateAnalyticsFilter(&filter)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := r.repo.GetRevenue(ctx, filter)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return totals, nil
+}
+
+func (r *reportService) GetPurchases(ctx context.Context, filter domain.InvoiceAnalyticsFilter) ([]domain.InvoiceTotal, error) {
+	err := validateAnalyticsFilter(&filter)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := r.repo.GetPurchases(ctx, filter)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return totals, nil
+}
+
+// getCostBook replay the stock lines of a warehouse until end into a cost book,
+// visit is called with every export line and its cost
+func (r *reportService) getCostBook(ctx context.Context, warehouseID int, method domain.ValuationMethod, end time.Time,
+	visit func(line *domain.StockLine, cost float64)) (*domain.CostBook, []domain.StockLine, error) {
+	if !method.IsValid() {
+		return nil, nil, domain.ErrInvalidValuationMethod
+	}
+
+	lines, err := r.repo.GetStockLines(ctx, warehouseID, end)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, nil, err
+		default:
+			return nil, nil, domain.ErrInternal
+		}
+	}
+
+	book := domain.NewCostBook(method)
+	for i := range lines {
+		line := &lines[i]
+		if line.Quantity > 0 {
+			book.Import(line.RiceID, line.Quantity, line.Price)
+			continue
+		}
+
+		cost := book.Export(line.RiceID, -line.Quantity)
+		if visit != nil {
+			visit(line, cost)
+		}
+	}
+
+	return book, lines, nil
+}
+
+func (r *reportService) GetInventoryValuation(ctx context.Context, warehouseID int, method domain.ValuationMethod, at time.Time) ([]domain.InventoryValue, error) {
+	book, lines, err := r.getCostBook(ctx, warehouseID, method, at, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	values := []domain.InventoryValue{}
+	seen := make(map[int]bool)
+	for _, line := range lines {
+		if seen[line.RiceID] {
+			continue
+		}
+		seen[line.RiceID] = true
+
+		quantity := book.Quantity(line.RiceID)
+		if quantity == 0 {
+			continue
+		}
+
+		value := book.Value(line.RiceID)
+		values = append(values, domain.InventoryValue{
+			RiceID:   line.RiceID,
+			RiceName: line.RiceName,
+			Quantity: quantity,
+			UnitCost: value / float64(quantity),
+			Value:    value,
+		})
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].RiceID < values[j].RiceID
+	})
+
+	return values, nil
+}
+
+func (r *reportService) GetGrossMargin(ctx context.Context, warehouseID int, method domain.ValuationMethod, start, end time.Time) (*domain.GrossMarginReport, error) {
+	if start.After(end) {
+		return nil, domain.ErrInvalidDateRange
+	}
+
+	report := &domain.GrossMarginReport{
+		Method:  method,
+		Rice:    []domain.RiceMargin{},
+		Exports: []domain.ExportCost{},
+	}
+	rice := make(map[int]*domain.RiceMargin)
+	exports := make(map[int]*domain.ExportCost)
+	riceOrder := []int{}
+	exportOrder := []int{}
+
+	_, _, err := r.getCostBook(ctx, warehouseID, method, end, func(line *domain.StockLine, cost float64) {
+		// transfers leave the stock at cost but are not sales
+		if line.Transfer || line.CreatedAt.Before(start) {
+			return
+		}
+
+		quantity := -line.Quantity
+		revenue := float64(quantity) * line.Price
+
+		if _, ok := rice[line.RiceID]; !ok {
+			rice[line.RiceID] = &domain.RiceMargin{RiceID: line.RiceID, RiceName: line.RiceName}
+			riceOrder = append(riceOrder, line.RiceID)
+		}
+		rice[line.RiceID].Quantity += quantity
+		rice[line.RiceID].Revenue += revenue
+		rice[line.RiceID].COGS += cost
+
+		if _, ok := exports[line.InvoiceID]; !ok {
+			exports[line.InvoiceID] = &domain.ExportCost{InvoiceID: line.InvoiceID, CreatedAt: line.CreatedAt}
+			exportOrder = append(exportOrder, line.InvoiceID)
+		}
+		exports[line.InvoiceID].Revenue += revenue
+		exports[line.InvoiceID].COGS += cost
+
+		report.Revenue += revenue
+		report.COGS += cost
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Ints(riceOrder)
+	for _, id := range riceOrder {
+		v := rice[id]
+		v.Margin = v.Revenue - v.COGS
+		report.Rice = append(report.Rice, *v)
+	}
+	for _, id := range exportOrder {
+		report.Exports = append(report.Exports, *exports[id])
+	}
+	report.Margin = report.Revenue - report.COGS
+
+	return report, nil
+}
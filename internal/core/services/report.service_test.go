@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	mockRepo "github.com/tommjj/ql-kho-lua/internal/core/services/mock"
+)
+
+func TestReportServiceImplements(t *testing.T) {
+	assert.Implements(t, (*ports.IReportService)(nil), new(reportService))
+}
+
+func TestGetStockMovement(t *testing.T) {
+	start := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 9, 30, 23, 59, 59, 0, time.UTC)
+
+	movements := []domain.StockMovement{
+		{RiceID: 1, RiceName: "ST25", Opening: 10, Imported: 20, Exported: 5, Closing: 25},
+	}
+
+	repo := new(mockRepo.MockReportRepository)
+	repo.On("GetStockMovement", context.TODO(), 1, start, end).Return(movements, nil)
+
+	service := NewReportService(repo)
+	res, err := service.GetStockMovement(context.TODO(), 1, start, end)
+
+	repo.AssertExpectations(t)
+	assert.Nil(t, err)
+	assert.Equal(t, movements, res)
+}
+
+func TestGetStockMovement_InvalidRange(t *testing.T) {
+	start := time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	repo := new(mockRepo.MockReportRepository)
+
+	service := NewReportService(repo)
+	_, err := service.GetStockMovement(context.TODO(), 1, start, end)
+
+	repo.AssertNotCalled(t, "GetStockMovement")
+	assert.Equal(t, domain.ErrInvalidDateRange, err)
+}
+
+func TestGetStockMovement_RepoErr(t *testing.T) {
+	start := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	repo := new(mockRepo.MockReportRepository)
+	repo.On("GetStockMovement", context.TODO(), 1, start, end).Return(nil, domain.ErrDataNotFound)
+	repo.On("GetStockMovement", context.TODO(), 2, start, end).Return(nil, errors.New("db down"))
+
+	service := NewReportService(repo)
+
+	_, err := service.GetStockMovement(context.TODO(), 1, start, end)
+	assert.Equal(t, domain.ErrDataNotFound, err)
+
+	_, err = service.GetStockMovement(context.TODO(), 2, start, end)
+	assert.Equal(t, domain.ErrInternal, err)
+}
+
+func TestGetRevenue_DefaultPeriod(t *testing.T) {
+	totals := []domain.InvoiceTotal{
+		{Period: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), Amount: 1500, Quantity: 30, Invoices: 2},
+	}
+
+	repo := new(mockRepo.MockReportRepository)
+	repo.On("GetRevenue", context.TODO(), domain.InvoiceAnalyticsFilter{GroupBy: domain.PeriodDay, WarehouseID: 1}).Return(totals, nil)
+
+	service := NewReportService(repo)
+	res, err := service.GetRevenue(context.TODO(), domain.InvoiceAnalyticsFilter{WarehouseID: 1})
+
+	repo.AssertExpectations(t)
+	assert.Nil(t, err)
+	assert.Equal(t, totals, res)
+}
+
+func TestGetPurchases_InvalidFilter(t *testing.T) {
+	start := time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	repo := new(mockRepo.MockReportRepository)
+	service := NewReportService(repo)
+
+	_, err := service.GetPurchases(context.TODO(), domain.InvoiceAnalyticsFilter{GroupBy: "year"})
+	assert.Equal(t, domain.ErrInvalidReportPeriod, err)
+
+	_, err = service.GetPurchases(context.TODO(), domain.InvoiceAnalyticsFilter{Start: &start, End: &end})
+	assert.Equal(t, domain.ErrInvalidDateRange, err)
+
+	repo.AssertNotCalled(t, "GetPurchases")
+}
+
+func TestGetPurchases_RepoErr(t *testing.T) {
+	repo := new(mockRepo.MockReportRepository)
+	repo.On("GetPurchases", context.TODO(), domain.InvoiceAnalyticsFilter{GroupBy: domain.PeriodMonth}).Return(nil, errors.New("db down"))
+
+	service := NewReportService(repo)
+	_, err := service.GetPurchases(context.TODO(), domain.InvoiceAnalyticsFilter{GroupBy: domain.PeriodMonth})
+
+	assert.Equal(t, domain.ErrInternal, err)
+}
+
+func stockLines() []domain.StockLine {
+	day := func(d int) time.Time {
+		return time.Date(2024, 9, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	return []domain.StockLine{
+		{InvoiceID: 1, RiceID: 1, RiceName: "ST25", Quantity: 10, Price: 10, CreatedAt: day(1)},
+		{InvoiceID: 2, RiceID: 1, RiceName: "ST25", Quantity: 10, Price: 20, CreatedAt: day(2)},
+		{InvoiceID: 1, RiceID: 1, RiceName: "ST25", Quantity: -5, Price: 30, CreatedAt: day(3)},
+		{InvoiceID: 2, RiceID: 1, RiceName: "ST25", Quantity: -10, Price: 30, CreatedAt: day(4)},
+		{InvoiceID: 3, RiceID: 1, RiceName: "ST25", Quantity: -2, Price: 0, Transfer: true, CreatedAt: day(5)},
+	}
+}
+
+func TestGetInventoryValuation(t *testing.T) {
+	at := time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	repo := new(mockRepo.MockReportRepository)
+	repo.On("GetStockLines", context.TODO(), 1, at).Return(stockLines(), nil)
+
+	service := NewReportService(repo)
+
+	// fifo: 5 @10 and 10 @20 exported, 2 @20 transferred, 3 @20 left
+	values, err := service.GetInventoryValuation(context.TODO(), 1, domain.ValuationFIFO, at)
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.InventoryValue{{RiceID: 1, RiceName: "ST25", Quantity: 3, UnitCost: 20, Value: 60}}, values)
+
+	// weighted average: 20 @15, every export at 15
+	values, err = service.GetInventoryValuation(context.TODO(), 1, domain.ValuationWeightedAverage, at)
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.InventoryValue{{RiceID: 1, RiceName: "ST25", Quantity: 3, UnitCost: 15, Value: 45}}, values)
+
+	_, err = service.GetInventoryValuation(context.TODO(), 1, "lifo", at)
+	assert.Equal(t, domain.ErrInvalidValuationMethod, err)
+}
+
+func TestGetGrossMargin(t *testing.T) {
+	start := time.Date(2024, 9, 4, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	repo := new(mockRepo.MockReportRepository)
+	repo.On("GetStockLines", context.TODO(), 1, end).Return(stockLines(), nil)
+
+	service := NewReportService(repo)
+	report, err := service.GetGrossMargin(context.TODO(), 1, domain.ValuationFIFO, start, end)
+
+	// only the export of day 4 is in the period: 5 @10 and 5 @20, the transfer is not a sale
+	assert.Nil(t, err)
+	assert.Equal(t, 300.0, report.Revenue)
+	assert.Equal(t, 150.0, report.COGS)
+	assert.Equal(t, 150.0, report.Margin)
+	assert.Equal(t, []domain.RiceMargin{{RiceID: 1, RiceName: "ST25", Quantity: 10, Revenue: 300, COGS: 150, Margin: 150}}, report.Rice)
+	assert.Len(t, report.Exports, 1)
+	assert.Equal(t, 2, report.Exports[0].InvoiceID)
+}
@@ -68,6 +68,20 @@ func (r *riceService) GetListRice(ctx context.Context, query string, limit, skip
 	return rice, nil
 }
 
+func (r *riceService) GetListRiceAfter(ctx context.Context, query string, afterID, limit int) ([]domain.Rice, error) {
+	rice, err := r.repo.GetListRiceAfter(ctx, query, afterID, limit)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return rice, nil
+}
+
 func (r *riceService) UpdateRice(ctx context.Context, rice *domain.Rice) (*domain.Rice, error) {
 	_, err := r.repo.GetRiceByID(ctx, rice.ID)
 	if err != nil {
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+type stockLedgerService struct {
+	repo ports.IStockLedgerRepository
+}
+
+func NewStockLedgerService(repo ports.IStockLedgerRepository) ports.IStockLedgerService {
+	return &stockLedgerService{
+		repo: repo,
+	}
+}
+
+func (s *stockLedgerService) ReconcileStockBalances(ctx context.Context, apply bool) ([]domain.StockDrift, error) {
+	drifts, err := s.repo.ReconcileStockBalances(ctx, apply)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return drifts, nil
+}
+
+func (s *stockLedgerService) BackfillStockBalances(ctx context.Context) ([]domain.StockDrift, error) {
+	written, err := s.repo.BackfillStockBalances(ctx)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return written, nil
+}
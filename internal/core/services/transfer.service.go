@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/mapmutex"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+type transferService struct {
+	transferRepo  ports.ITransferRepository
+	warehouseRepo ports.IWarehouseRepository
+	reportRepo    ports.IReportRepository
+	l             *mapmutex.Mapmutex
+}
+
+func NewTransferService(
+	transferRepo ports.ITransferRepository,
+	warehouseRepo ports.IWarehouseRepository,
+	reportRepo ports.IReportRepository,
+	l *mapmutex.Mapmutex) ports.ITransferService {
+	return &transferService{
+		transferRepo:  transferRepo,
+		warehouseRepo: warehouseRepo,
+		reportRepo:    reportRepo,
+		l:             l,
+	}
+}
+
+// priceAtCost set the price of the lines of a transfer to the weighted average cost of the rice in the source warehouse
+func (t *transferService) priceAtCost(ctx context.Context, transfer *domain.Transfer) error {
+	lines, err := t.reportRepo.GetStockLines(ctx, transfer.FromWarehouseID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	book := domain.NewCostBook(domain.ValuationWeightedAverage)
+	for _, line := range lines {
+		if line.Quantity > 0 {
+			book.Import(line.RiceID, line.Quantity, line.Price)
+		} else {
+			book.Export(line.RiceID, -line.Quantity)
+		}
+	}
+
+	for i := range transfer.Details {
+		transfer.Details[i].Price = book.UnitCost(transfer.Details[i].RiceID)
+	}
+	return nil
+}
+
+// lockWarehouses lock both warehouses of a transfer and return the function unlocking them,
+// the lower warehouse id is always locked first so two opposite transfers can not deadlock
+func (t *transferService) lockWarehouses(from, to int) func() {
+	first, second := from, to
+	if first > second {
+		first, second = second, first
+	}
+	t.l.Lock(first)
+	t.l.Lock(second)
+	return func() {
+		t.l.UnLock(second)
+		t.l.UnLock(first)
+	}
+}
+
+func (t *transferService) CreateTransfer(ctx context.Context, transfer *domain.Transfer) (*domain.Transfer, error) {
+	if transfer.FromWarehouseID == transfer.ToWarehouseID {
+		return nil, domain.ErrSameWarehouseTransfer
+	}
+
+	err := validateLotAllocations(transfer.Details)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := t.lockWarehouses(transfer.FromWarehouseID, transfer.ToWarehouseID)
+	defer unlock()
+
+	inventory, err := t.warehouseRepo.GetInventory(ctx, transfer.FromWarehouseID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if !hasEnoughStock(inventory, transfer.Details) {
+		return nil, domain.ErrInsufficientStock
+	}
+
+	store, err := t.warehouseRepo.GetWarehouseByID(ctx, transfer.ToWarehouseID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	used, err := t.warehouseRepo.GetUsedCapacityByID(ctx, transfer.ToWarehouseID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	var capacity int
+	for _, v := range transfer.Details {
+		capacity += v.Quantity
+	}
+	if (int(used) + capacity) > store.Capacity {
+		return nil, domain.ErrWarehouseFull
+	}
+
+	err = t.priceAtCost(ctx, transfer)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	transfer.ExportInvoice, transfer.ImportInvoice = transfer.Invoices()
+
+	created, err := t.transferRepo.CreateTransfer(ctx, transfer)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound, domain.ErrInsufficientStock:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return created, nil
+}
+
+func (t *transferService) GetTransferByID(ctx context.Context, id int) (*domain.Transfer, error) {
+	transfer, err := t.transferRepo.GetTransferByID(ctx, id)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return transfer, nil
+}
+
+func (t *transferService) ReverseTransfer(ctx context.Context, id int, reason string) (*domain.Transfer, error) {
+	transfer, err := t.transferRepo.GetTransferByID(ctx, id)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	if transfer.ExportInvoice != nil && transfer.ExportInvoice.Status == domain.InvoiceCancelled {
+		return nil, domain.ErrTransferReversed
+	}
+
+	unlock := t.lockWarehouses(transfer.FromWarehouseID, transfer.ToWarehouseID)
+	defer unlock()
+
+	// the rice must still be in the destination and fit in the source again
+	inventory, err := t.warehouseRepo.GetInventory(ctx, transfer.ToWarehouseID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if !hasEnoughStock(inventory, transfer.Details) {
+		return nil, domain.ErrInsufficientStock
+	}
+
+	store, err := t.warehouseRepo.GetWarehouseByID(ctx, transfer.FromWarehouseID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	used, err := t.warehouseRepo.GetUsedCapacityByID(ctx, transfer.FromWarehouseID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	var capacity int
+	for _, v := range transfer.Details {
+		capacity += v.Quantity
+	}
+	if (int(used) + capacity) > store.Capacity {
+		return nil, domain.ErrWarehouseFull
+	}
+
+	reversed, err := t.transferRepo.ReverseTransfer(ctx, id, reason)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound, domain.ErrTransferReversed, domain.ErrLotConsumed:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return reversed, nil
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/mapmutex"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	mockRepo "github.com/tommjj/ql-kho-lua/internal/core/services/mock"
+)
+
+func TestTransferServiceImplements(t *testing.T) {
+	assert.Implements(t, (*ports.ITransferService)(nil), new(transferService))
+}
+
+func newTestTransfer(from, to int) *domain.Transfer {
+	return &domain.Transfer{
+		FromWarehouseID: from,
+		ToWarehouseID:   to,
+		UserID:          1,
+		Details: []domain.InvoiceItem{
+			{RiceID: 1, Quantity: 100},
+		},
+	}
+}
+
+func TestCreateTransfer_Success(t *testing.T) {
+	transferRepo := new(mockRepo.MockTransferRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+	reportRepo := new(mockRepo.MockReportRepository)
+
+	warehouseRepo.On("GetInventory", mock.Anything, 1).Return([]domain.WarehouseItem{{RiceID: 1, Quantity: 100}}, nil)
+	warehouseRepo.On("GetWarehouseByID", mock.Anything, 2).Return(&domain.Warehouse{ID: 2, Capacity: 500}, nil)
+	warehouseRepo.On("GetUsedCapacityByID", mock.Anything, 2).Return(int64(400), nil)
+	// 100 at 8 and 100 at 12 less 100 sold leave 100 at an average cost of 10
+	reportRepo.On("GetStockLines", mock.Anything, 1, mock.Anything).Return([]domain.StockLine{
+		{RiceID: 1, Quantity: 100, Price: 8},
+		{RiceID: 1, Quantity: 100, Price: 12},
+		{RiceID: 1, Quantity: -100, Price: 20},
+	}, nil)
+	transferRepo.On("CreateTransfer", mock.Anything, mock.MatchedBy(func(transfer *domain.Transfer) bool {
+		return transfer.ExportInvoice.WarehouseID == 1 && transfer.ImportInvoice.WarehouseID == 2 &&
+			transfer.ExportInvoice.CustomerID == 0 && transfer.ImportInvoice.CustomerID == 0 &&
+			transfer.ExportInvoice.TotalPrice == 1000 && transfer.ImportInvoice.TotalPrice == 1000
+	})).Return(&domain.Transfer{ID: 1}, nil)
+
+	service := NewTransferService(transferRepo, warehouseRepo, reportRepo, &mapmutex.Mapmutex{})
+	_, err := service.CreateTransfer(context.TODO(), newTestTransfer(1, 2))
+	assert.Nil(t, err)
+
+	transferRepo.AssertExpectations(t)
+	warehouseRepo.AssertExpectations(t)
+	reportRepo.AssertExpectations(t)
+}
+
+func TestCreateTransfer_FailSameWarehouse(t *testing.T) {
+	transferRepo := new(mockRepo.MockTransferRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	service := NewTransferService(transferRepo, warehouseRepo, new(mockRepo.MockReportRepository), &mapmutex.Mapmutex{})
+	_, err := service.CreateTransfer(context.TODO(), newTestTransfer(1, 1))
+	assert.Equal(t, domain.ErrSameWarehouseTransfer, err)
+}
+
+func TestCreateTransfer_FailInsufficientStock(t *testing.T) {
+	transferRepo := new(mockRepo.MockTransferRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	warehouseRepo.On("GetInventory", mock.Anything, 1).Return([]domain.WarehouseItem{{RiceID: 1, Quantity: 99}}, nil)
+
+	service := NewTransferService(transferRepo, warehouseRepo, new(mockRepo.MockReportRepository), &mapmutex.Mapmutex{})
+	_, err := service.CreateTransfer(context.TODO(), newTestTransfer(1, 2))
+	assert.Equal(t, domain.ErrInsufficientStock, err)
+
+	transferRepo.AssertNotCalled(t, "CreateTransfer", mock.Anything, mock.Anything)
+}
+
+func TestCreateTransfer_FailInvalidLotAllocation(t *testing.T) {
+	transferRepo := new(mockRepo.MockTransferRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	transfer := newTestTransfer(1, 2)
+	transfer.Details[0].Lots = []domain.LotAllocation{{LotID: 1, Quantity: 60}, {LotID: 2, Quantity: 30}}
+
+	service := NewTransferService(transferRepo, warehouseRepo, new(mockRepo.MockReportRepository), &mapmutex.Mapmutex{})
+	_, err := service.CreateTransfer(context.TODO(), transfer)
+	assert.Equal(t, domain.ErrInvalidLotAllocation, err)
+
+	warehouseRepo.AssertNotCalled(t, "GetInventory", mock.Anything, mock.Anything)
+	transferRepo.AssertNotCalled(t, "CreateTransfer", mock.Anything, mock.Anything)
+}
+
+func TestCreateTransfer_FailLotShortOfStock(t *testing.T) {
+	transferRepo := new(mockRepo.MockTransferRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+	reportRepo := new(mockRepo.MockReportRepository)
+
+	warehouseRepo.On("GetInventory", mock.Anything, 1).Return([]domain.WarehouseItem{{RiceID: 1, Quantity: 100}}, nil)
+	warehouseRepo.On("GetWarehouseByID", mock.Anything, 2).Return(&domain.Warehouse{ID: 2, Capacity: 500}, nil)
+	warehouseRepo.On("GetUsedCapacityByID", mock.Anything, 2).Return(int64(0), nil)
+	reportRepo.On("GetStockLines", mock.Anything, 1, mock.Anything).Return([]domain.StockLine{}, nil)
+	// the named lot holds less than the line takes
+	transferRepo.On("CreateTransfer", mock.Anything, mock.Anything).Return(nil, domain.ErrInsufficientStock)
+
+	transfer := newTestTransfer(1, 2)
+	transfer.Details[0].Lots = []domain.LotAllocation{{LotID: 1, Quantity: 100}}
+
+	service := NewTransferService(transferRepo, warehouseRepo, reportRepo, &mapmutex.Mapmutex{})
+	_, err := service.CreateTransfer(context.TODO(), transfer)
+	assert.Equal(t, domain.ErrInsufficientStock, err)
+
+	transferRepo.AssertExpectations(t)
+}
+
+func TestCreateTransfer_FailWarehouseFull(t *testing.T) {
+	transferRepo := new(mockRepo.MockTransferRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	warehouseRepo.On("GetInventory", mock.Anything, 1).Return([]domain.WarehouseItem{{RiceID: 1, Quantity: 100}}, nil)
+	warehouseRepo.On("GetWarehouseByID", mock.Anything, 2).Return(&domain.Warehouse{ID: 2, Capacity: 500}, nil)
+	warehouseRepo.On("GetUsedCapacityByID", mock.Anything, 2).Return(int64(401), nil)
+
+	service := NewTransferService(transferRepo, warehouseRepo, new(mockRepo.MockReportRepository), &mapmutex.Mapmutex{})
+	_, err := service.CreateTransfer(context.TODO(), newTestTransfer(1, 2))
+	assert.Equal(t, domain.ErrWarehouseFull, err)
+
+	transferRepo.AssertNotCalled(t, "CreateTransfer", mock.Anything, mock.Anything)
+}
+
+func TestCreateTransfer_OppositeDirectionsDoNotDeadlock(t *testing.T) {
+	transferRepo := new(mockRepo.MockTransferRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	warehouseRepo.On("GetInventory", mock.Anything, mock.Anything).Return([]domain.WarehouseItem{{RiceID: 1, Quantity: 100}}, nil)
+	warehouseRepo.On("GetWarehouseByID", mock.Anything, mock.Anything).Return(&domain.Warehouse{Capacity: 1000}, nil)
+	warehouseRepo.On("GetUsedCapacityByID", mock.Anything, mock.Anything).Return(int64(0), nil)
+	transferRepo.On("CreateTransfer", mock.Anything, mock.Anything).Return(&domain.Transfer{}, nil)
+	reportRepo := new(mockRepo.MockReportRepository)
+	reportRepo.On("GetStockLines", mock.Anything, mock.Anything, mock.Anything).Return([]domain.StockLine{}, nil)
+
+	service := NewTransferService(transferRepo, warehouseRepo, reportRepo, &mapmutex.Mapmutex{})
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i%2 == 0 {
+				service.CreateTransfer(context.TODO(), newTestTransfer(1, 2))
+			} else {
+				service.CreateTransfer(context.TODO(), newTestTransfer(2, 1))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func newTestReverseTransfer() *domain.Transfer {
+	details := []domain.InvoiceItem{{RiceID: 1, Quantity: 100, Price: 10}}
+	return &domain.Transfer{
+		ID:              1,
+		FromWarehouseID: 1,
+		ToWarehouseID:   2,
+		Details:         details,
+		ExportInvoice:   &domain.Invoice{ID: 3, WarehouseID: 1, Status: domain.InvoiceCompleted, Details: details},
+		ImportInvoice:   &domain.Invoice{ID: 4, WarehouseID: 2, Status: domain.InvoiceCompleted, Details: details},
+	}
+}
+
+func TestReverseTransfer_Success(t *testing.T) {
+	transferRepo := new(mockRepo.MockTransferRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	transferRepo.On("GetTransferByID", mock.Anything, 1).Return(newTestReverseTransfer(), nil)
+	warehouseRepo.On("GetInventory", mock.Anything, 2).Return([]domain.WarehouseItem{{RiceID: 1, Quantity: 100}}, nil)
+	warehouseRepo.On("GetWarehouseByID", mock.Anything, 1).Return(&domain.Warehouse{ID: 1, Capacity: 500}, nil)
+	warehouseRepo.On("GetUsedCapacityByID", mock.Anything, 1).Return(int64(400), nil)
+	transferRepo.On("ReverseTransfer", mock.Anything, 1, "wrong warehouse").Return(&domain.Transfer{ID: 1}, nil)
+
+	service := NewTransferService(transferRepo, warehouseRepo, new(mockRepo.MockReportRepository), &mapmutex.Mapmutex{})
+	_, err := service.ReverseTransfer(context.TODO(), 1, "wrong warehouse")
+	assert.Nil(t, err)
+
+	transferRepo.AssertExpectations(t)
+	warehouseRepo.AssertExpectations(t)
+}
+
+func TestReverseTransfer_FailReversed(t *testing.T) {
+	transferRepo := new(mockRepo.MockTransferRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	transfer := newTestReverseTransfer()
+	transfer.ExportInvoice.Status = domain.InvoiceCancelled
+	transferRepo.On("GetTransferByID", mock.Anything, 1).Return(transfer, nil)
+
+	service := NewTransferService(transferRepo, warehouseRepo, new(mockRepo.MockReportRepository), &mapmutex.Mapmutex{})
+	_, err := service.ReverseTransfer(context.TODO(), 1, "wrong warehouse")
+	assert.Equal(t, domain.ErrTransferReversed, err)
+
+	transferRepo.AssertNotCalled(t, "ReverseTransfer", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReverseTransfer_FailSourceFull(t *testing.T) {
+	transferRepo := new(mockRepo.MockTransferRepository)
+	warehouseRepo := new(mockRepo.MockWarehouseRepository)
+
+	transferRepo.On("GetTransferByID", mock.Anything, 1).Return(newTestReverseTransfer(), nil)
+	warehouseRepo.On("GetInventory", mock.Anything, 2).Return([]domain.WarehouseItem{{RiceID: 1, Quantity: 100}}, nil)
+	warehouseRepo.On("GetWarehouseByID", mock.Anything, 1).Return(&domain.Warehouse{ID: 1, Capacity: 500}, nil)
+	warehouseRepo.On("GetUsedCapacityByID", mock.Anything, 1).Return(int64(401), nil)
+
+	service := NewTransferService(transferRepo, warehouseRepo, new(mockRepo.MockReportRepository), &mapmutex.Mapmutex{})
+	_, err := service.ReverseTransfer(context.TODO(), 1, "wrong warehouse")
+	assert.Equal(t, domain.ErrWarehouseFull, err)
+
+	transferRepo.AssertNotCalled(t, "ReverseTransfer", mock.Anything, mock.Anything, mock.Anything)
+}
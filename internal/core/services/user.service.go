@@ -98,6 +98,39 @@ func (us *userService) GetListUsers(ctx context.Context, q string, limit, skip i
 	return user, nil
 }
 
+func (us *userService) GetListUsersAfter(ctx context.Context, q string, afterID, limit int) ([]domain.User, error) {
+	users, err := us.repo.GetListUsersAfter(ctx, q, afterID, limit)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return users, nil
+}
+
+func (us *userService) CountAuthorizedUsers(ctx context.Context, warehouseID int, q string) (int64, error) {
+	count, err := us.repo.CountAuthorizedUsers(ctx, warehouseID, q)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	return count, nil
+}
+
+func (us *userService) GetAuthorizedUsers(ctx context.Context, warehouseID int, q string, limit, skip int) ([]domain.User, error) {
+	users, err := us.repo.GetAuthorizedUsers(ctx, warehouseID, q, limit, skip)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return users, nil
+}
+
 func (us *userService) UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
 	_, err := us.repo.GetUserByID(ctx, user.ID)
 	if err != nil {
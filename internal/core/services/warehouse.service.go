@@ -81,6 +81,20 @@ func (w *warehouseService) GetListWarehouses(ctx context.Context, query string,
 	return list, nil
 }
 
+func (w *warehouseService) GetListWarehousesAfter(ctx context.Context, query string, afterID, limit int) ([]domain.Warehouse, error) {
+	list, err := w.repo.GetListWarehousesAfter(ctx, query, afterID, limit)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return list, nil
+}
+
 func (w *warehouseService) CountAuthorizedWarehouses(ctx context.Context, userID int, query string) (int64, error) {
 	count, err := w.repo.CountAuthorizedWarehouses(ctx, userID, query)
 	if err != nil {
@@ -104,6 +118,20 @@ func (w *warehouseService) GetAuthorizedWarehouses(ctx context.Context, userID i
 	return list, nil
 }
 
+func (w *warehouseService) GetAuthorizedWarehousesAfter(ctx context.Context, userID int, query string, afterID, limit int) ([]domain.Warehouse, error) {
+	list, err := w.repo.GetAuthorizedWarehousesAfter(ctx, userID, query, afterID, limit)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return list, nil
+}
+
 func (w *warehouseService) GetUsedCapacityByID(ctx context.Context, id int) (int64, error) {
 	usedCapacity, err := w.repo.GetUsedCapacityByID(ctx, id)
 	if err != nil {
@@ -127,6 +155,17 @@ func (w *warehouseService) GetInventory(ctx context.Context, id int) ([]domain.W
 	return inventory, nil
 }
 
+func (w *warehouseService) GetInventoryByLot(ctx context.Context, id int) ([]domain.Lot, error) {
+	lots, err := w.repo.GetInventoryByLot(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	return lots, nil
+}
+
 func (w *warehouseService) UpdateWarehouse(ctx context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error) {
 	current, err := w.repo.GetWarehouseByID(ctx, warehouse.ID)
 	if err != nil {
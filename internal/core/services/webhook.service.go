@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+// outboxBatchSize is the most outbox messages sent in one delivery run
+const outboxBatchSize = 100
+
+type webhookService struct {
+	repo        ports.IWebhookRepository
+	sender      ports.IWebhookSender
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+}
+
+// NewWebhookService create the webhook service, a message is dead after maxAttempts failed attempts
+// and the wait between two attempts doubles from backoff up to maxBackoff
+func NewWebhookService(repo ports.IWebhookRepository, sender ports.IWebhookSender,
+	maxAttempts int, backoff, maxBackoff time.Duration) ports.IWebhookService {
+	return &webhookService{
+		repo:        repo,
+		sender:      sender,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		maxBackoff:  maxBackoff,
+	}
+}
+
+// newWebhookSecret generate a random secret to sign webhook payloads
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (w *webhookService) CreateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	webhook.Secret = secret
+	webhook.Active = true
+
+	created, err := w.repo.CreateWebhook(ctx, webhook)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return created, nil
+}
+
+func (w *webhookService) GetListWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	webhooks, err := w.repo.GetListWebhooks(ctx)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return webhooks, nil
+}
+
+func (w *webhookService) GetWebhookByID(ctx context.Context, id int) (*domain.Webhook, error) {
+	webhook, err := w.repo.GetWebhookByID(ctx, id)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return webhook, nil
+}
+
+func (w *webhookService) UpdateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	updated, err := w.repo.UpdateWebhook(ctx, webhook)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return updated, nil
+}
+
+func (w *webhookService) DeleteWebhook(ctx context.Context, id int) error {
+	err := w.repo.DeleteWebhook(ctx, id)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return err
+		default:
+			return domain.ErrInternal
+		}
+	}
+
+	return nil
+}
+
+func (w *webhookService) Emit(ctx context.Context, event domain.WebhookEvent, data any) {
+	// the change is already done, so the event is queued even if the request has been cancelled
+	ctx = context.WithoutCancel(ctx)
+
+	webhooks, err := w.repo.GetListWebhooks(ctx)
+	if err != nil {
+		zap.L().Error("get webhooks", zap.Error(err), zap.String("event", string(event)))
+		return
+	}
+
+	now := time.Now()
+	payload := domain.WebhookPayload{
+		ID:        uuid.NewString(),
+		Event:     event,
+		CreatedAt: now,
+		Data:      data,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		zap.L().Error("marshal webhook payload", zap.Error(err), zap.String("event", string(event)))
+		return
+	}
+
+	msgs := []domain.OutboxMessage{}
+	for _, v := range webhooks {
+		if !v.Subscribed(event) {
+			continue
+		}
+		msgs = append(msgs, domain.OutboxMessage{
+			WebhookID:     v.ID,
+			EventID:       payload.ID,
+			Event:         event,
+			Payload:       body,
+			Status:        domain.OutboxPending,
+			NextAttemptAt: now,
+		})
+	}
+
+	err = w.repo.CreateOutboxMessages(ctx, msgs)
+	if err != nil {
+		zap.L().Error("write webhook outbox", zap.Error(err), zap.String("event", string(event)))
+	}
+}
+
+func (w *webhookService) DeliverDue(ctx context.Context) (int, error) {
+	msgs, err := w.repo.GetDueMessages(ctx, time.Now(), outboxBatchSize)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	delivered := 0
+	for i := range msgs {
+		msg := &msgs[i]
+
+		err := w.sender.Send(ctx, msg.Webhook, msg)
+		msg.Attempts++
+		now := time.Now()
+
+		if err == nil {
+			msg.Status = domain.OutboxDelivered
+			msg.DeliveredAt = &now
+			msg.LastError = ""
+			delivered++
+		} else {
+			msg.LastError = err.Error()
+			if msg.Attempts >= w.maxAttempts {
+				msg.Status = domain.OutboxDead
+			} else {
+				msg.NextAttemptAt = now.Add(domain.WebhookBackoff(msg.Attempts, w.backoff, w.maxBackoff))
+			}
+
+			zap.L().Warn("deliver webhook",
+				zap.Error(err),
+				zap.Int("outbox_id", msg.ID),
+				zap.Int("webhook_id", msg.WebhookID),
+				zap.Int("attempt", msg.Attempts),
+				zap.String("status", string(msg.Status)),
+			)
+		}
+
+		err = w.repo.UpdateOutboxMessage(ctx, msg)
+		if err != nil {
+			return delivered, domain.ErrInternal
+		}
+	}
+
+	return delivered, nil
+}
+
+func (w *webhookService) CountOutboxMessages(ctx context.Context, filter domain.OutboxFilter) (int64, error) {
+	count, err := w.repo.CountOutboxMessages(ctx, filter)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	return count, nil
+}
+
+func (w *webhookService) GetListOutboxMessages(ctx context.Context, filter domain.OutboxFilter, limit, skip int) ([]domain.OutboxMessage, error) {
+	msgs, err := w.repo.GetListOutboxMessages(ctx, filter, limit, skip)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return msgs, nil
+}
+
+func (w *webhookService) RetryOutboxMessage(ctx context.Context, id int) (*domain.OutboxMessage, error) {
+	msg, err := w.repo.GetOutboxMessageByID(ctx, id)
+	if err != nil {
+		switch err {
+		case domain.ErrDataNotFound:
+			return nil, err
+		default:
+			return nil, domain.ErrInternal
+		}
+	}
+
+	if msg.Status != domain.OutboxDead {
+		return nil, domain.ErrOutboxNotDead
+	}
+
+	msg.Status = domain.OutboxPending
+	msg.Attempts = 0
+	msg.NextAttemptAt = time.Now()
+
+	err = w.repo.UpdateOutboxMessage(ctx, msg)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	mockRepo "github.com/tommjj/ql-kho-lua/internal/core/services/mock"
+)
+
+func TestWebhookServiceImplements(t *testing.T) {
+	assert.Implements(t, (*ports.IWebhookService)(nil), new(webhookService))
+}
+
+func TestCreateWebhook(t *testing.T) {
+	repo := new(mockRepo.MockWebhookRepository)
+	repo.On("CreateWebhook", context.TODO(), mock.Anything).Return(&domain.Webhook{ID: 1}, nil)
+
+	service := NewWebhookService(repo, new(mockRepo.MockWebhookSender), 5, time.Minute, time.Hour)
+
+	hook := &domain.Webhook{URL: "https://erp.example.com/hooks", Events: []domain.WebhookEvent{domain.WebhookStockChanged}}
+	_, err := service.CreateWebhook(context.TODO(), hook)
+
+	assert.Nil(t, err)
+	assert.True(t, hook.Active)
+	assert.Len(t, hook.Secret, 64)
+}
+
+func TestEmit(t *testing.T) {
+	repo := new(mockRepo.MockWebhookRepository)
+	// Emit detaches the context from the request, so it is matched with mock.Anything
+	repo.On("GetListWebhooks", mock.Anything).Return([]domain.Webhook{
+		{ID: 1, Active: true, Events: []domain.WebhookEvent{domain.WebhookRiceCreated}},
+		{ID: 2, Active: true, Events: []domain.WebhookEvent{domain.WebhookCustomerCreated}},
+		{ID: 3, Active: false, Events: []domain.WebhookEvent{domain.WebhookRiceCreated}},
+		{ID: 4, Active: true, Events: []domain.WebhookEvent{domain.WebhookStockChanged, domain.WebhookRiceCreated}},
+	}, nil)
+
+	var queued []domain.OutboxMessage
+	repo.On("CreateOutboxMessages", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		queued = args.Get(1).([]domain.OutboxMessage)
+	})
+
+	service := NewWebhookService(repo, new(mockRepo.MockWebhookSender), 5, time.Minute, time.Hour)
+	service.Emit(context.TODO(), domain.WebhookRiceCreated, &domain.Rice{ID: 9, Name: "ST25"})
+
+	if assert.Len(t, queued, 2) {
+		assert.Equal(t, 1, queued[0].WebhookID)
+		assert.Equal(t, 4, queued[1].WebhookID)
+		assert.Equal(t, queued[0].EventID, queued[1].EventID)
+		assert.Equal(t, domain.OutboxPending, queued[0].Status)
+
+		var payload map[string]any
+		assert.Nil(t, json.Unmarshal(queued[0].Payload, &payload))
+		assert.Equal(t, "rice.created", payload["event"])
+		assert.Equal(t, queued[0].EventID, payload["id"])
+	}
+}
+
+func TestDeliverDue(t *testing.T) {
+	hook := &domain.Webhook{ID: 1, URL: "https://erp.example.com/hooks", Active: true}
+	msgs := []domain.OutboxMessage{
+		{ID: 1, WebhookID: 1, Status: domain.OutboxPending, Webhook: hook},
+		{ID: 2, WebhookID: 1, Status: domain.OutboxPending, Attempts: 1, Webhook: hook},
+		{ID: 3, WebhookID: 1, Status: domain.OutboxPending, Attempts: 4, Webhook: hook},
+	}
+
+	repo := new(mockRepo.MockWebhookRepository)
+	repo.On("GetDueMessages", context.TODO(), mock.Anything, outboxBatchSize).Return(msgs, nil)
+	updated := map[int]domain.OutboxMessage{}
+	repo.On("UpdateOutboxMessage", context.TODO(), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		msg := args.Get(1).(*domain.OutboxMessage)
+		updated[msg.ID] = *msg
+	})
+
+	sender := new(mockRepo.MockWebhookSender)
+	sender.On("Send", context.TODO(), hook, mock.MatchedBy(func(m *domain.OutboxMessage) bool { return m.ID == 1 })).Return(nil)
+	sender.On("Send", context.TODO(), hook, mock.Anything).Return(errors.New("webhook responded 500 Internal Server Error"))
+
+	service := NewWebhookService(repo, sender, 5, time.Minute, time.Hour)
+
+	before := time.Now()
+	delivered, err := service.DeliverDue(context.TODO())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, delivered)
+
+	assert.Equal(t, domain.OutboxDelivered, updated[1].Status)
+	assert.NotNil(t, updated[1].DeliveredAt)
+
+	// the second failure waits twice the backoff
+	assert.Equal(t, domain.OutboxPending, updated[2].Status)
+	assert.Equal(t, 2, updated[2].Attempts)
+	assert.WithinDuration(t, before.Add(2*time.Minute), updated[2].NextAttemptAt, time.Second)
+
+	// the last attempt fails so the message is dead
+	assert.Equal(t, domain.OutboxDead, updated[3].Status)
+	assert.Equal(t, 5, updated[3].Attempts)
+	assert.Equal(t, "webhook responded 500 Internal Server Error", updated[3].LastError)
+}
+
+func TestRetryOutboxMessage(t *testing.T) {
+	repo := new(mockRepo.MockWebhookRepository)
+	repo.On("GetOutboxMessageByID", context.TODO(), 1).Return(&domain.OutboxMessage{ID: 1, Status: domain.OutboxDead, Attempts: 5}, nil)
+	repo.On("GetOutboxMessageByID", context.TODO(), 2).Return(&domain.OutboxMessage{ID: 2, Status: domain.OutboxDelivered}, nil)
+	repo.On("GetOutboxMessageByID", context.TODO(), 3).Return(nil, domain.ErrDataNotFound)
+	repo.On("UpdateOutboxMessage", context.TODO(), mock.Anything).Return(nil)
+
+	service := NewWebhookService(repo, new(mockRepo.MockWebhookSender), 5, time.Minute, time.Hour)
+
+	msg, err := service.RetryOutboxMessage(context.TODO(), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, domain.OutboxPending, msg.Status)
+	assert.Equal(t, 0, msg.Attempts)
+
+	_, err = service.RetryOutboxMessage(context.TODO(), 2)
+	assert.Equal(t, domain.ErrOutboxNotDead, err)
+
+	_, err = service.RetryOutboxMessage(context.TODO(), 3)
+	assert.Equal(t, domain.ErrDataNotFound, err)
+
+	repo.AssertNumberOfCalls(t, "UpdateOutboxMessage", 1)
+}
@@ -0,0 +1,389 @@
+package services
+
+import (
+	"context"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+// The webhook event services wrap a service and emit a webhook event after each successful mutating call.
+// Read methods are passed through to the wrapped service.
+
+type webhookCustomerService struct {
+	ports.ICustomerService
+	hooks ports.IWebhookService
+}
+
+func NewWebhookCustomerService(svc ports.ICustomerService, hooks ports.IWebhookService) ports.ICustomerService {
+	return &webhookCustomerService{
+		ICustomerService: svc,
+		hooks:            hooks,
+	}
+}
+
+func (s *webhookCustomerService) CreateCustomer(ctx context.Context, customer *domain.Customer) (*domain.Customer, error) {
+	created, err := s.ICustomerService.CreateCustomer(ctx, customer)
+	if err != nil {
+		return nil, err
+	}
+
+	s.hooks.Emit(ctx, domain.WebhookCustomerCreated, created)
+	return created, nil
+}
+
+func (s *webhookCustomerService) UpdateCustomer(ctx context.Context, customer *domain.Customer) (*domain.Customer, error) {
+	updated, err := s.ICustomerService.UpdateCustomer(ctx, customer)
+	if err != nil {
+		return nil, err
+	}
+
+	s.hooks.Emit(ctx, domain.WebhookCustomerUpdated, updated)
+	return updated, nil
+}
+
+func (s *webhookCustomerService) DeleteCustomer(ctx context.Context, id int) error {
+	err := s.ICustomerService.DeleteCustomer(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	s.hooks.Emit(ctx, domain.WebhookCustomerDeleted, domain.NewDeleted(id))
+	return nil
+}
+
+type webhookRiceService struct {
+	ports.IRiceService
+	hooks ports.IWebhookService
+}
+
+func NewWebhookRiceService(svc ports.IRiceService, hooks ports.IWebhookService) ports.IRiceService {
+	return &webhookRiceService{
+		IRiceService: svc,
+		hooks:        hooks,
+	}
+}
+
+func (s *webhookRiceService) CreateRice(ctx context.Context, rice *domain.Rice) (*domain.Rice, error) {
+	created, err := s.IRiceService.CreateRice(ctx, rice)
+	if err != nil {
+		return nil, err
+	}
+
+	s.hooks.Emit(ctx, domain.WebhookRiceCreated, created)
+	return created, nil
+}
+
+func (s *webhookRiceService) UpdateRice(ctx context.Context, rice *domain.Rice) (*domain.Rice, error) {
+	updated, err := s.IRiceService.UpdateRice(ctx, rice)
+	if err != nil {
+		return nil, err
+	}
+
+	s.hooks.Emit(ctx, domain.WebhookRiceUpdated, updated)
+	return updated, nil
+}
+
+func (s *webhookRiceService) DeleteRice(ctx context.Context, id int) error {
+	err := s.IRiceService.DeleteRice(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	s.hooks.Emit(ctx, domain.WebhookRiceDeleted, domain.NewDeleted(id))
+	return nil
+}
+
+type webhookWarehouseService struct {
+	ports.IWarehouseService
+	hooks ports.IWebhookService
+}
+
+func NewWebhookWarehouseService(svc ports.IWarehouseService, hooks ports.IWebhookService) ports.IWarehouseService {
+	return &webhookWarehouseService{
+		IWarehouseService: svc,
+		hooks:             hooks,
+	}
+}
+
+func (s *webhookWarehouseService) CreateWarehouse(ctx context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error) {
+	created, err := s.IWarehouseService.CreateWarehouse(ctx, warehouse)
+	if err != nil {
+		return nil, err
+	}
+
+	s.hooks.Emit(ctx, domain.WebhookWarehouseCreated, created)
+	return created, nil
+}
+
+func (s *webhookWarehouseService) UpdateWarehouse(ctx context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error) {
+	updated, err := s.IWarehouseService.UpdateWarehouse(ctx, warehouse)
+	if err != nil {
+		return nil, err
+	}
+
+	s.hooks.Emit(ctx, domain.WebhookWarehouseUpdated, updated)
+	return updated, nil
+}
+
+func (s *webhookWarehouseService) DeleteWarehouse(ctx context.Context, id int) error {
+	err := s.IWarehouseService.DeleteWarehouse(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	s.hooks.Emit(ctx, domain.WebhookWarehouseDeleted, domain.NewDeleted(id))
+	return nil
+}
+
+type webhookImInvoiceService struct {
+	ports.IImportInvoicesService
+	hooks ports.IWebhookService
+}
+
+func NewWebhookImInvoiceService(svc ports.IImportInvoicesService, hooks ports.IWebhookService) ports.IImportInvoicesService {
+	return &webhookImInvoiceService{
+		IImportInvoicesService: svc,
+		hooks:                  hooks,
+	}
+}
+
+func (s *webhookImInvoiceService) CreateImInvoice(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error) {
+	created, err := s.IImportInvoicesService.CreateImInvoice(ctx, invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	s.hooks.Emit(ctx, domain.WebhookImportInvoiceCreated, created)
+	// an invoice waiting for approval moves stock when it is approved
+	if created.Status == domain.InvoiceCompleted {
+		s.hooks.Emit(ctx, domain.WebhookStockChanged, domain.NewStockChanged("import_invoice", created, 1))
+	}
+	return created, nil
+}
+
+func (s *webhookImInvoiceService) CancelImInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	cancelled, err := s.IImportInvoicesService.CancelImInvoice(ctx, id, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	s.hooks.Emit(ctx, domain.WebhookImportInvoiceCancelled, cancelled)
+	s.hooks.Emit(ctx, domain.WebhookStockChanged, domain.NewStockChanged("import_invoice", cancelled, -1))
+	return cancelled, nil
+}
+
+func (s *webhookImInvoiceService) ApproveImInvoice(ctx context.Context, id int, approverID int) (*domain.Invoice, error) {
+	approved, err := s.IImportInvoicesService.ApproveImInvoice(ctx, id, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.hooks.Emit(ctx, domain.WebhookImportInvoiceApproved, approved)
+	s.hooks.Emit(ctx, domain.WebhookStockChanged, domain.NewStockChanged("import_invoice", approved, 1))
+	return approved, nil
+}
+
+func (s *webhookImInvoiceService) RejectImInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	rejected, err := s.IImportInvoicesService.RejectImInvoice(ctx, id, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	s.hooks.Emit(ctx, domain.WebhookImportInvoiceRejected, rejected)
+	return rejected, nil
+}
+
+type webhookExInvoiceService struct {
+	ports.IExportInvoiceService
+	hooks ports.IWebhookService
+}
+
+func NewWebhookExInvoiceService(svc ports.IExportInvoiceService, hooks ports.IWebhookService) ports.IExportInvoiceService {
+	return &webhookExInvoiceService{
+		IExportInvoiceService: svc,
+		hooks:                 hooks,
+	}
+}
+
+func (s *webhookExInvoiceService) CreateExInvoice(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error) {
+	created, err := s.IExportInvoiceService.CreateExInvoice(ctx, invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	s.hooks.Emit(ctx, domain.WebhookExportInvoiceCreated, created)
+	// an invoice waiting for approval moves stock when it is approved
+	if created.Status == domain.InvoiceCompleted {
+		s.hooks.Emit(ctx, domain.WebhookStockChanged, domain.NewStockChanged("export_invoice", created, -1))
+	}
+	return created, nil
+}
+
+func (s *webhookExInvoiceService) CancelExInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	cancelled, err := s.IExportInvoiceService.CancelExInvoice(ctx, id, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	s.hooks.Emit(ctx, domain.WebhookExportInvoiceCancelled, cancelled)
+	s.hooks.Emit(ctx, domain.WebhookStockChanged, domain.NewStockChanged("export_invoice", cancelled, 1))
+	return cancelled, nil
+}
+
+func (s *webhookExInvoiceService) ApproveExInvoice(ctx context.Context, id int, approverID int) (*domain.Invoice, error) {
+	approved, err := s.IExportInvoiceService.ApproveExInvoice(ctx, id, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.hooks.Emit(ctx, domain.WebhookExportInvoiceApproved, approved)
+	s.hooks.Emit(ctx, domain.WebhookStockChanged, domain.NewStockChanged("export_invoice", approved, -1))
+	return approved, nil
+}
+
+func (s *webhookExInvoiceService) RejectExInvoice(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	rejected, err := s.IExportInvoiceService.RejectExInvoice(ctx, id, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	s.hooks.Emit(ctx, domain.WebhookExportInvoiceRejected, rejected)
+	return rejected, nil
+}
+
+type webhookTransferService struct {
+	ports.ITransferService
+	hooks ports.IWebhookService
+}
+
+// NewWebhookTransferService wrap the transfer service, a transfer emits a stock change for each warehouse
+func NewWebhookTransferService(svc ports.ITransferService, hooks ports.IWebhookService) ports.ITransferService {
+	return &webhookTransferService{
+		ITransferService: svc,
+		hooks:            hooks,
+	}
+}
+
+func (s *webhookTransferService) CreateTransfer(ctx context.Context, transfer *domain.Transfer) (*domain.Transfer, error) {
+	created, err := s.ITransferService.CreateTransfer(ctx, transfer)
+	if err != nil {
+		return nil, err
+	}
+
+	exInvoice, imInvoice := created.Invoices()
+	for _, v := range []*domain.StockChanged{
+		domain.NewStockChanged("transfer", exInvoice, -1),
+		domain.NewStockChanged("transfer", imInvoice, 1),
+	} {
+		v.SourceID = created.ID
+		s.hooks.Emit(ctx, domain.WebhookStockChanged, v)
+	}
+	return created, nil
+}
+
+func (s *webhookTransferService) ReverseTransfer(ctx context.Context, id int, reason string) (*domain.Transfer, error) {
+	reversed, err := s.ITransferService.ReverseTransfer(ctx, id, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	exInvoice, imInvoice := reversed.Invoices()
+	for _, v := range []*domain.StockChanged{
+		domain.NewStockChanged("transfer", exInvoice, 1),
+		domain.NewStockChanged("transfer", imInvoice, -1),
+	} {
+		v.SourceID = reversed.ID
+		s.hooks.Emit(ctx, domain.WebhookStockChanged, v)
+	}
+	return reversed, nil
+}
+
+type webhookOrderService struct {
+	ports.IOrderService
+	hooks ports.IWebhookService
+}
+
+// NewWebhookOrderService wrap the order service, converting an order emits the events of the invoice it creates
+func NewWebhookOrderService(svc ports.IOrderService, hooks ports.IWebhookService) ports.IOrderService {
+	return &webhookOrderService{
+		IOrderService: svc,
+		hooks:         hooks,
+	}
+}
+
+func (s *webhookOrderService) ConvertOrder(ctx context.Context, id int, userID int) (*domain.Invoice, error) {
+	order, err := s.IOrderService.GetOrderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.IOrderService.ConvertOrder(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// an invoice waiting for approval moves stock when it is approved
+	moved := created.Status == domain.InvoiceCompleted
+	if order.Type == domain.PurchaseOrder {
+		s.hooks.Emit(ctx, domain.WebhookImportInvoiceCreated, created)
+		if moved {
+			s.hooks.Emit(ctx, domain.WebhookStockChanged, domain.NewStockChanged("import_invoice", created, 1))
+		}
+	} else {
+		s.hooks.Emit(ctx, domain.WebhookExportInvoiceCreated, created)
+		if moved {
+			s.hooks.Emit(ctx, domain.WebhookStockChanged, domain.NewStockChanged("export_invoice", created, -1))
+		}
+	}
+	return created, nil
+}
+
+// webhookBulkImportService emit a created event for every row a committed import created
+type webhookBulkImportService struct {
+	ports.IBulkImportService
+	hooks ports.IWebhookService
+}
+
+func NewWebhookBulkImportService(svc ports.IBulkImportService, hooks ports.IWebhookService) ports.IBulkImportService {
+	return &webhookBulkImportService{
+		IBulkImportService: svc,
+		hooks:              hooks,
+	}
+}
+
+func (s *webhookBulkImportService) ImportRice(ctx context.Context, rows []domain.ImportRow[domain.Rice], dryRun bool) (*domain.ImportResult[domain.Rice], error) {
+	result, err := s.IBulkImportService.ImportRice(ctx, rows, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result.Created {
+		s.hooks.Emit(ctx, domain.WebhookRiceCreated, &result.Created[i])
+	}
+	return result, nil
+}
+
+func (s *webhookBulkImportService) ImportCustomers(ctx context.Context, rows []domain.ImportRow[domain.Customer], dryRun bool) (*domain.ImportResult[domain.Customer], error) {
+	result, err := s.IBulkImportService.ImportCustomers(ctx, rows, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result.Created {
+		s.hooks.Emit(ctx, domain.WebhookCustomerCreated, &result.Created[i])
+	}
+	return result, nil
+}
+
+func (s *webhookBulkImportService) ImportWarehouses(ctx context.Context, rows []domain.ImportRow[domain.Warehouse], dryRun bool) (*domain.ImportResult[domain.Warehouse], error) {
+	result, err := s.IBulkImportService.ImportWarehouses(ctx, rows, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result.Created {
+		s.hooks.Emit(ctx, domain.WebhookWarehouseCreated, &result.Created[i])
+	}
+	return result, nil
+}
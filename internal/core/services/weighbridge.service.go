@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+)
+
+// weighbridgeNamePageSize is the number of rice or partners read per query while their names are indexed
+const weighbridgeNamePageSize = 500
+
+type weighbridgeService struct {
+	repo          ports.IWeighbridgeRepository
+	warehouseRepo ports.IWarehouseRepository
+	riceRepo      ports.IRiceRepository
+	customerRepo  ports.ICustomerRepository
+	imInvoice     ports.IImportInvoicesService
+	exInvoice     ports.IExportInvoiceService
+}
+
+// NewWeighbridgeService create the weighbridge import, weighings are posted through the invoice services
+// so they get the same checks, approvals and events as invoices created by hand
+func NewWeighbridgeService(
+	repo ports.IWeighbridgeRepository,
+	warehouseRepo ports.IWarehouseRepository,
+	riceRepo ports.IRiceRepository,
+	customerRepo ports.ICustomerRepository,
+	imInvoice ports.IImportInvoicesService,
+	exInvoice ports.IExportInvoiceService) ports.IWeighbridgeService {
+	return &weighbridgeService{
+		repo:          repo,
+		warehouseRepo: warehouseRepo,
+		riceRepo:      riceRepo,
+		customerRepo:  customerRepo,
+		imInvoice:     imInvoice,
+		exInvoice:     exInvoice,
+	}
+}
+
+// masterLookup find a rice or partner by id or by name without case, the names are read the first time one is looked up
+type masterLookup struct {
+	byID  func(id int) error
+	list  func(skip, limit int) (map[string]int, error)
+	ids   map[int]error
+	names map[string]int
+}
+
+func newMasterLookup(byID func(id int) error, list func(skip, limit int) (map[string]int, error)) *masterLookup {
+	return &masterLookup{
+		byID: byID,
+		list: list,
+		ids:  map[int]error{},
+	}
+}
+
+// find return the id of the value, domain.ErrDataNotFound when there is no such id or name
+func (l *masterLookup) find(value string) (int, error) {
+	if id, err := strconv.Atoi(value); err == nil {
+		err, ok := l.ids[id]
+		if !ok {
+			err = l.byID(id)
+			l.ids[id] = err
+		}
+		return id, err
+	}
+
+	if l.names == nil {
+		l.names = map[string]int{}
+		for skip := 1; ; skip++ {
+			page, err := l.list(skip, weighbridgeNamePageSize)
+			if err == domain.ErrDataNotFound {
+				break
+			}
+			if err != nil {
+				l.names = nil
+				return 0, err
+			}
+			for name, id := range page {
+				l.names[name] = id
+			}
+			if len(page) < weighbridgeNamePageSize {
+				break
+			}
+		}
+	}
+
+	id, ok := l.names[strings.ToLower(value)]
+	if !ok {
+		return 0, domain.ErrDataNotFound
+	}
+	return id, nil
+}
+
+func (w *weighbridgeService) newRiceLookup(ctx context.Context) *masterLookup {
+	return newMasterLookup(func(id int) error {
+		_, err := w.riceRepo.GetRiceByID(ctx, id)
+		return err
+	}, func(skip, limit int) (map[string]int, error) {
+		list, err := w.riceRepo.GetListRice(ctx, "", limit, skip)
+		if err != nil {
+			return nil, err
+		}
+		names := make(map[string]int, len(list))
+		for _, v := range list {
+			names[strings.ToLower(v.Name)] = v.ID
+		}
+		return names, nil
+	})
+}
+
+func (w *weighbridgeService) newPartnerLookup(ctx context.Context) *masterLookup {
+	return newMasterLookup(func(id int) error {
+		_, err := w.customerRepo.GetCustomerByID(ctx, id)
+		return err
+	}, func(skip, limit int) (map[string]int, error) {
+		list, err := w.customerRepo.GetListCustomers(ctx, "", "", limit, skip)
+		if err != nil {
+			return nil, err
+		}
+		names := make(map[string]int, len(list))
+		for _, v := range list {
+			names[strings.ToLower(v.Name)] = v.ID
+		}
+		return names, nil
+	})
+}
+
+func (w *weighbridgeService) ImportWeighings(ctx context.Context, warehouseID, userID int, rows []domain.ImportRow[domain.Weighing]) (*domain.WeighbridgeResult, error) {
+	_, err := w.warehouseRepo.GetWarehouseByID(ctx, warehouseID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	result := &domain.WeighbridgeResult{
+		Total:   len(rows),
+		Posted:  []domain.PostedWeighing{},
+		Skipped: []domain.PostedWeighing{},
+		Errors:  []domain.ImportRowError{},
+	}
+
+	tickets := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if len(row.Errors) == 0 {
+			tickets = append(tickets, row.Data.Ticket)
+		}
+	}
+	posted, err := w.getPosted(ctx, warehouseID, tickets)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	rice := w.newRiceLookup(ctx)
+	partners := w.newPartnerLookup(ctx)
+
+	for _, row := range rows {
+		if len(row.Errors) > 0 {
+			result.Errors = append(result.Errors, row.Errors...)
+			continue
+		}
+
+		weighing := row.Data
+		if p, ok := posted[weighing.Ticket]; ok {
+			p.Line = row.Line
+			result.Skipped = append(result.Skipped, p)
+			continue
+		}
+
+		partnerID, partnerErr := partners.find(weighing.Partner)
+		riceID, riceErr := rice.find(weighing.Rice)
+		for _, v := range []struct {
+			field string
+			err   error
+		}{{"partner", partnerErr}, {"rice", riceErr}} {
+			if v.err != nil && v.err != domain.ErrDataNotFound {
+				return nil, domain.ErrInternal
+			}
+			if v.err != nil {
+				result.Errors = append(result.Errors, domain.ImportRowError{Line: row.Line, Field: v.field, Message: "is not found"})
+			}
+		}
+		if partnerErr != nil || riceErr != nil {
+			continue
+		}
+
+		invoice := &domain.Invoice{
+			WarehouseID: warehouseID,
+			CustomerID:  partnerID,
+			UserID:      userID,
+			Reference:   weighing.Reference(warehouseID),
+			Details:     []domain.InvoiceItem{{RiceID: riceID, Quantity: weighing.Quantity, Price: weighing.Price}},
+		}
+
+		var created *domain.Invoice
+		if weighing.Kind == domain.InvoiceKindImport {
+			created, err = w.imInvoice.CreateImInvoice(ctx, invoice)
+		} else {
+			created, err = w.exInvoice.CreateExInvoice(ctx, invoice)
+		}
+
+		if err == domain.ErrConflictingData {
+			// the ticket was posted by an upload running at the same time
+			again, err := w.getPosted(ctx, warehouseID, []string{weighing.Ticket})
+			if err != nil {
+				return nil, domain.ErrInternal
+			}
+			p, ok := again[weighing.Ticket]
+			if !ok {
+				return nil, domain.ErrInternal
+			}
+			posted[weighing.Ticket] = p
+			p.Line = row.Line
+			result.Skipped = append(result.Skipped, p)
+			continue
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, newWeighingError(row.Line, weighing.Kind, err))
+			continue
+		}
+
+		p := domain.PostedWeighing{
+			Line:      row.Line,
+			Ticket:    weighing.Ticket,
+			Kind:      weighing.Kind,
+			InvoiceID: created.ID,
+			Status:    created.Status,
+		}
+		posted[weighing.Ticket] = p
+		result.Posted = append(result.Posted, p)
+	}
+
+	result.SortErrors()
+	return result, nil
+}
+
+// getPosted return the posted weighings of the tickets in the warehouse by ticket
+func (w *weighbridgeService) getPosted(ctx context.Context, warehouseID int, tickets []string) (map[string]domain.PostedWeighing, error) {
+	list, err := w.repo.GetPostedWeighings(ctx, warehouseID, tickets)
+	if err != nil {
+		return nil, err
+	}
+
+	posted := make(map[string]domain.PostedWeighing, len(list))
+	for _, v := range list {
+		posted[v.Ticket] = v
+	}
+	return posted, nil
+}
+
+// newWeighingError describe why a weighing was not posted as an invoice
+func newWeighingError(line int, kind domain.InvoiceKind, err error) domain.ImportRowError {
+	switch err {
+	case domain.ErrWarehouseFull, domain.ErrInsufficientStock:
+		return domain.ImportRowError{Line: line, Field: "quantity", Message: err.Error()}
+	case domain.ErrInvalidPartnerRole:
+		if kind == domain.InvoiceKindImport {
+			return domain.ImportRowError{Line: line, Field: "partner", Message: "is not a supplier"}
+		}
+		return domain.ImportRowError{Line: line, Field: "partner", Message: "is not a customer"}
+	case domain.ErrDataNotFound:
+		return domain.ImportRowError{Line: line, Message: "partner or rice is not found"}
+	default:
+		return domain.ImportRowError{Line: line, Message: "weighing could not be posted, upload the file again"}
+	}
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tommjj/ql-kho-lua/internal/core/domain"
+	"github.com/tommjj/ql-kho-lua/internal/core/mapmutex"
+	"github.com/tommjj/ql-kho-lua/internal/core/ports"
+	mockRepo "github.com/tommjj/ql-kho-lua/internal/core/services/mock"
+)
+
+func TestWeighbridgeServiceImplements(t *testing.T) {
+	assert.Implements(t, (*ports.IWeighbridgeService)(nil), new(weighbridgeService))
+}
+
+type weighbridgeTestRepos struct {
+	weighbridge *mockRepo.MockWeighbridgeRepository
+	warehouse   *mockRepo.MockWarehouseRepository
+	rice        *mockRepo.MockRiceRepository
+	customer    *mockRepo.MockCustomerRepository
+	imInvoice   *mockRepo.MockImportInvoiceRepository
+	exInvoice   *mockRepo.MockExportInvoiceRepository
+}
+
+// newTestWeighbridgeService return a weighbridge service posting through real invoice services on mocked repositories,
+// warehouse 2 holds 500 of 1000 and has 100 of rice 1 in stock
+func newTestWeighbridgeService() (ports.IWeighbridgeService, *weighbridgeTestRepos) {
+	repos := &weighbridgeTestRepos{
+		weighbridge: new(mockRepo.MockWeighbridgeRepository),
+		warehouse:   new(mockRepo.MockWarehouseRepository),
+		rice:        new(mockRepo.MockRiceRepository),
+		customer:    newTestPartnerRepo(),
+		imInvoice:   new(mockRepo.MockImportInvoiceRepository),
+		exInvoice:   new(mockRepo.MockExportInvoiceRepository),
+	}
+
+	repos.warehouse.On("GetWarehouseByID", mock.Anything, 2).Return(&domain.Warehouse{ID: 2, Capacity: 1000}, nil)
+	repos.warehouse.On("GetUsedCapacityByID", mock.Anything, 2).Return(int64(500), nil)
+	repos.warehouse.On("GetInventory", mock.Anything, 2).Return([]domain.WarehouseItem{{RiceID: 1, Quantity: 100}}, nil)
+	repos.rice.On("GetRiceByID", mock.Anything, 1).Return(&domain.Rice{ID: 1, Name: "ST25"}, nil)
+
+	l := &mapmutex.Mapmutex{}
+	imInvoice := NewImInvoicesService(repos.imInvoice, repos.warehouse, repos.customer, l, domain.ApprovalRule{})
+	exInvoice := NewExInvoicesService(repos.exInvoice, repos.warehouse, repos.customer, l, domain.ApprovalRule{})
+
+	return NewWeighbridgeService(repos.weighbridge, repos.warehouse, repos.rice, repos.customer, imInvoice, exInvoice), repos
+}
+
+func newTestWeighing(line int, ticket string, kind domain.InvoiceKind, quantity int) domain.ImportRow[domain.Weighing] {
+	return domain.ImportRow[domain.Weighing]{
+		Line: line,
+		Data: domain.Weighing{Ticket: ticket, Kind: kind, Partner: "1", Rice: "1", Quantity: quantity, Price: 10},
+	}
+}
+
+func TestImportWeighings_PostAndSkip(t *testing.T) {
+	service, repos := newTestWeighbridgeService()
+
+	repos.weighbridge.On("GetPostedWeighings", mock.Anything, 2, []string{"T1", "T2", "T3", "T2"}).Return([]domain.PostedWeighing{
+		{Ticket: "T1", Kind: domain.InvoiceKindImport, InvoiceID: 7, Status: domain.InvoiceCompleted},
+	}, nil)
+	repos.imInvoice.On("CreateImInvoice", mock.Anything, mock.MatchedBy(func(inv *domain.Invoice) bool {
+		return inv.Reference == "weighbridge:2:T2" && inv.WarehouseID == 2 && inv.UserID == 3 && inv.CustomerID == 1 &&
+			inv.Details[0].RiceID == 1 && inv.Details[0].Quantity == 400
+	})).Return(&domain.Invoice{ID: 8, Status: domain.InvoiceCompleted}, nil).Once()
+	repos.exInvoice.On("CreateExInvoice", mock.Anything, mock.MatchedBy(func(inv *domain.Invoice) bool {
+		return inv.Reference == "weighbridge:2:T3" && inv.Details[0].Quantity == 60
+	})).Return(&domain.Invoice{ID: 4, Status: domain.InvoiceCompleted}, nil).Once()
+
+	result, err := service.ImportWeighings(context.TODO(), 2, 3, []domain.ImportRow[domain.Weighing]{
+		newTestWeighing(2, "T1", domain.InvoiceKindImport, 100),
+		newTestWeighing(3, "T2", domain.InvoiceKindImport, 400),
+		newTestWeighing(4, "T3", domain.InvoiceKindExport, 60),
+		newTestWeighing(5, "T2", domain.InvoiceKindImport, 400),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, result.Total)
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, []domain.PostedWeighing{
+		{Line: 3, Ticket: "T2", Kind: domain.InvoiceKindImport, InvoiceID: 8, Status: domain.InvoiceCompleted},
+		{Line: 4, Ticket: "T3", Kind: domain.InvoiceKindExport, InvoiceID: 4, Status: domain.InvoiceCompleted},
+	}, result.Posted)
+	assert.Equal(t, []domain.PostedWeighing{
+		{Line: 2, Ticket: "T1", Kind: domain.InvoiceKindImport, InvoiceID: 7, Status: domain.InvoiceCompleted},
+		{Line: 5, Ticket: "T2", Kind: domain.InvoiceKindImport, InvoiceID: 8, Status: domain.InvoiceCompleted},
+	}, result.Skipped)
+
+	repos.imInvoice.AssertExpectations(t)
+	repos.exInvoice.AssertExpectations(t)
+}
+
+func TestImportWeighings_CapacityAndStock(t *testing.T) {
+	service, repos := newTestWeighbridgeService()
+
+	repos.weighbridge.On("GetPostedWeighings", mock.Anything, 2, mock.Anything).Return([]domain.PostedWeighing{}, nil)
+
+	result, err := service.ImportWeighings(context.TODO(), 2, 3, []domain.ImportRow[domain.Weighing]{
+		newTestWeighing(2, "T1", domain.InvoiceKindImport, 501),
+		newTestWeighing(3, "T2", domain.InvoiceKindExport, 101),
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, result.Posted)
+	assert.Equal(t, []domain.ImportRowError{
+		{Line: 2, Field: "quantity", Message: domain.ErrWarehouseFull.Error()},
+		{Line: 3, Field: "quantity", Message: domain.ErrInsufficientStock.Error()},
+	}, result.Errors)
+
+	repos.imInvoice.AssertNotCalled(t, "CreateImInvoice", mock.Anything, mock.Anything)
+	repos.exInvoice.AssertNotCalled(t, "CreateExInvoice", mock.Anything, mock.Anything)
+}
+
+func TestImportWeighings_Names(t *testing.T) {
+	service, repos := newTestWeighbridgeService()
+
+	repos.weighbridge.On("GetPostedWeighings", mock.Anything, 2, []string{"T1", "T2"}).Return([]domain.PostedWeighing{}, nil)
+	repos.rice.On("GetListRice", mock.Anything, "", weighbridgeNamePageSize, 1).Return([]domain.Rice{{ID: 1, Name: "ST25"}}, nil).Once()
+	repos.imInvoice.On("CreateImInvoice", mock.Anything, mock.MatchedBy(func(inv *domain.Invoice) bool {
+		return inv.Details[0].RiceID == 1
+	})).Return(&domain.Invoice{ID: 8, Status: domain.InvoiceCompleted}, nil).Once()
+
+	rows := []domain.ImportRow[domain.Weighing]{
+		newTestWeighing(2, "T1", domain.InvoiceKindImport, 10),
+		newTestWeighing(3, "T2", domain.InvoiceKindImport, 10),
+		{Line: 4, Errors: []domain.ImportRowError{{Line: 4, Field: "ticket", Message: "ticket is required"}}},
+	}
+	rows[0].Data.Rice = "st25"
+	rows[1].Data.Rice = "Jasmine"
+
+	result, err := service.ImportWeighings(context.TODO(), 2, 3, rows)
+	assert.Nil(t, err)
+	assert.Len(t, result.Posted, 1)
+	assert.Equal(t, []domain.ImportRowError{
+		{Line: 3, Field: "rice", Message: "is not found"},
+		{Line: 4, Field: "ticket", Message: "ticket is required"},
+	}, result.Errors)
+
+	repos.rice.AssertNumberOfCalls(t, "GetListRice", 1)
+}
+
+func TestImportWeighings_PostedAtTheSameTime(t *testing.T) {
+	service, repos := newTestWeighbridgeService()
+
+	repos.weighbridge.On("GetPostedWeighings", mock.Anything, 2, []string{"T1"}).Return([]domain.PostedWeighing{}, nil).Once()
+	repos.weighbridge.On("GetPostedWeighings", mock.Anything, 2, []string{"T1"}).Return([]domain.PostedWeighing{
+		{Ticket: "T1", Kind: domain.InvoiceKindImport, InvoiceID: 9, Status: domain.InvoiceCompleted},
+	}, nil).Once()
+	repos.imInvoice.On("CreateImInvoice", mock.Anything, mock.Anything).Return(nil, domain.ErrConflictingData)
+
+	result, err := service.ImportWeighings(context.TODO(), 2, 3, []domain.ImportRow[domain.Weighing]{
+		newTestWeighing(2, "T1", domain.InvoiceKindImport, 10),
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, result.Posted)
+	assert.Equal(t, []domain.PostedWeighing{
+		{Line: 2, Ticket: "T1", Kind: domain.InvoiceKindImport, InvoiceID: 9, Status: domain.InvoiceCompleted},
+	}, result.Skipped)
+}
+
+func TestImportWeighings_WarehouseNotFound(t *testing.T) {
+	service, repos := newTestWeighbridgeService()
+	repos.warehouse.On("GetWarehouseByID", mock.Anything, 5).Return(nil, domain.ErrDataNotFound)
+
+	_, err := service.ImportWeighings(context.TODO(), 5, 3, []domain.ImportRow[domain.Weighing]{
+		newTestWeighing(2, "T1", domain.InvoiceKindImport, 10),
+	})
+	assert.Equal(t, domain.ErrDataNotFound, err)
+
+	repos.weighbridge.AssertNotCalled(t, "GetPostedWeighings", mock.Anything, mock.Anything, mock.Anything)
+}